@@ -0,0 +1,68 @@
+package admin
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/cli/node"
+	"golang.org/x/xerrors"
+)
+
+// registerer is the registry of the metrics. It allows the tests to use a
+// different one.
+var registerer prometheus.Registerer = prometheus.DefaultRegisterer
+
+// logAction is an action to change the level of the loggers.
+//
+// - implements node.ActionTemplate
+type logAction struct{}
+
+// Execute implements node.ActionTemplate. It sets the global level of the
+// loggers, which applies on top of the level of each logger.
+func (logAction) Execute(ctx node.Context) error {
+	level, err := zerolog.ParseLevel(ctx.Flags.String("level"))
+	if err != nil {
+		return xerrors.Errorf("invalid level: %v", err)
+	}
+
+	zerolog.SetGlobalLevel(level)
+
+	fmt.Fprintf(ctx.Out, "log level set to %s\n", level)
+
+	return nil
+}
+
+// metricsAction is an action to register or unregister the collectors of the
+// metrics.
+//
+// - implements node.ActionTemplate
+type metricsAction struct{}
+
+// Execute implements node.ActionTemplate. It registers the collectors of Dela,
+// or unregisters them when the disable flag is set.
+func (metricsAction) Execute(ctx node.Context) error {
+	if ctx.Flags.Bool("disable") {
+		for _, c := range dela.PromCollectors {
+			registerer.Unregister(c)
+		}
+
+		fmt.Fprintln(ctx.Out, "metrics disabled")
+
+		return nil
+	}
+
+	for _, c := range dela.PromCollectors {
+		err := registerer.Register(c)
+
+		_, already := err.(prometheus.AlreadyRegisteredError)
+		if err != nil && !already {
+			return xerrors.Errorf("failed to register: %v", err)
+		}
+	}
+
+	fmt.Fprintln(ctx.Out, "metrics enabled")
+
+	return nil
+}
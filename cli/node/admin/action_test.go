@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestLogAction_Execute(t *testing.T) {
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+
+	out := new(bytes.Buffer)
+	flags := make(node.FlagSet)
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    flags,
+		Out:      out,
+	}
+
+	flags["level"] = "warn"
+
+	action := logAction{}
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+	require.Equal(t, "log level set to warn\n", out.String())
+
+	flags["level"] = "abc"
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"invalid level: Unknown Level String: 'abc', defaulting to NoLevel")
+}
+
+func TestMetricsAction_Execute(t *testing.T) {
+	oldRegisterer := registerer
+	defer func() {
+		registerer = oldRegisterer
+	}()
+
+	registry := prometheus.NewRegistry()
+	registerer = registry
+
+	out := new(bytes.Buffer)
+	flags := make(node.FlagSet)
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    flags,
+		Out:      out,
+	}
+
+	action := metricsAction{}
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+
+	// Enabling twice is harmless.
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+
+	require.True(t, registry.Unregister(dela.PromCollectors[0]))
+	require.NoError(t, registry.Register(dela.PromCollectors[0]))
+
+	flags["disable"] = true
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.False(t, registry.Unregister(dela.PromCollectors[0]))
+
+	require.Equal(t, "metrics enabled\nmetrics enabled\nmetrics disabled\n", out.String())
+
+	registerer = badRegisterer{}
+	flags["disable"] = false
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to register"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type badRegisterer struct {
+	prometheus.Registerer
+}
+
+func (badRegisterer) Register(prometheus.Collector) error {
+	return fake.GetError()
+}
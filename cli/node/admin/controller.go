@@ -0,0 +1,56 @@
+// Package admin implements a controller to reconfigure a running node.
+//
+// The commands are sent to the daemon of the node through its UNIX socket,
+// which is only accessible to the user running the node.
+package admin
+
+import (
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/cli/node"
+)
+
+// miniController is a controller with the commands to change the logging and
+// the metrics of a running node.
+//
+// - implements node.Initializer
+type miniController struct{}
+
+// NewController returns a new controller for the administration of a node.
+func NewController() node.Initializer {
+	return miniController{}
+}
+
+// SetCommands implements node.Initializer. It sets the commands to change the
+// log level and to toggle the metrics.
+func (miniController) SetCommands(builder node.Builder) {
+	cmd := builder.SetCommand("admin")
+	cmd.SetDescription("reconfigure the running node")
+
+	sub := cmd.SetSubCommand("log")
+	sub.SetDescription("set the log level. The level cannot be lower than " +
+		"the one the node was started with, see LLVL")
+	sub.SetFlags(cli.StringFlag{
+		Name:     "level",
+		Usage:    "one of trace, debug, info, warn, error, disabled",
+		Required: true,
+	})
+	sub.SetAction(builder.MakeAction(logAction{}))
+
+	sub = cmd.SetSubCommand("metrics")
+	sub.SetDescription("register or unregister the collectors of the metrics")
+	sub.SetFlags(cli.BoolFlag{
+		Name:  "disable",
+		Usage: "unregister the collectors instead",
+	})
+	sub.SetAction(builder.MakeAction(metricsAction{}))
+}
+
+// OnStart implements node.Initializer. It does nothing.
+func (miniController) OnStart(cli.Flags, node.Injector) error {
+	return nil
+}
+
+// OnStop implements node.Initializer. It does nothing.
+func (miniController) OnStop(node.Injector) error {
+	return nil
+}
@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli/node"
+)
+
+func TestMiniController_SetCommands(t *testing.T) {
+	ctrl := NewController()
+
+	b := node.NewBuilder()
+	ctrl.SetCommands(b)
+}
+
+func TestMiniController_OnStart(t *testing.T) {
+	err := NewController().OnStart(node.FlagSet{}, node.NewInjector())
+	require.NoError(t, err)
+}
+
+func TestMiniController_OnStop(t *testing.T) {
+	err := NewController().OnStop(node.NewInjector())
+	require.NoError(t, err)
+}
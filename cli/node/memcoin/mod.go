@@ -24,6 +24,30 @@
 //  memcoin --config /tmp/node1 ordering roster add\
 //    --member $(memcoin --config /tmp/node3 ordering export)
 //
+//  # Store a large file off chain and reference its digest in a transaction,
+//  # so that the members fetch it.
+//  DIGEST=$(memcoin --config /tmp/node1 blob put --file /tmp/payload)
+//  memcoin --config /tmp/node1 pool add --key private.key\
+//    --args go.dedis.ch/dela.ContractArg --args go.dedis.ch/dela.Value\
+//    --args value:key --args payload --args value:value --args $DIGEST\
+//    --args value:command --args WRITE --args blob:digest --args $DIGEST
+//
+//  # Setup a DKG with the members of the chain, after each one listened. The
+//  # relays of the chain are refused, and the threshold is the one of the
+//  # chain if it was set up with --threshold.
+//  memcoin --config /tmp/node1 dkg listen
+//  memcoin --config /tmp/node2 dkg listen
+//  memcoin --config /tmp/node1 dkg setup\
+//    --authority $(cat /tmp/node1/dkgauthority)\
+//    --authority $(cat /tmp/node2/dkgauthority)
+//
+//  # Hand the secret over to a committee sampled out of the members every 10
+//  # blocks, on each member so that any of them can do it.
+//  memcoin --config /tmp/node1 dkg epochs --epochLength 10 --threshold 2\
+//    --committeeSize 2 --authority $(cat /tmp/node1/dkgauthority)\
+//    --authority $(cat /tmp/node2/dkgauthority)\
+//    --authority $(cat /tmp/node3/dkgauthority)
+//
 package main
 
 import (
@@ -32,9 +56,12 @@ import (
 	"os"
 
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/cli/node/admin"
 	access "go.dedis.ch/dela/contracts/access/controller"
 	cosipbft "go.dedis.ch/dela/core/ordering/cosipbft/controller"
+	blob "go.dedis.ch/dela/core/store/blob/controller"
 	db "go.dedis.ch/dela/core/store/kv/controller"
+	dkg "go.dedis.ch/dela/dkg/pedersen_bn256/controller"
 	pool "go.dedis.ch/dela/core/txn/pool/controller"
 	signed "go.dedis.ch/dela/core/txn/signed/controller"
 	mino "go.dedis.ch/dela/mino/minogrpc/controller"
@@ -64,10 +91,13 @@ func runWithCfg(args []string, cfg config) error {
 		db.NewController(),
 		mino.NewController(),
 		cosipbft.NewController(),
+		blob.NewController(),
 		signed.NewManagerController(),
 		pool.NewController(),
 		access.NewController(),
+		dkg.NewMinimal(),
 		proxy.NewController(),
+		admin.NewController(),
 	)
 
 	app := builder.Build()
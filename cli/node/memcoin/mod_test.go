@@ -108,7 +108,7 @@ func TestMemcoin_Scenario_SetupAndTransactions(t *testing.T) {
 	require.EqualError(t, err, "command error: transaction not found after timeout")
 
 	// Test a bad command.
-	err = runWithCfg([]string{os.Args[0], "ordering", "setup"}, cfg)
+	err = runWithCfg([]string{os.Args[0], "ordering", "roster", "add"}, cfg)
 	require.EqualError(t, err, `Required flag "member" not set`)
 }
 
@@ -158,6 +158,175 @@ func TestMemcoin_Scenario_RestartNode(t *testing.T) {
 	require.EqualError(t, err, "command error: transaction refused: duplicate in roster: 127.0.0.1:2210")
 }
 
+// This test creates a chain with two members, a relay and a threshold. It then
+// makes sure that the DKG of the node refuses the relay and another threshold,
+// and that it can be setup with the two members only.
+func TestMemcoin_Scenario_DKGRelay(t *testing.T) {
+	dir, err := os.MkdirTemp(os.TempDir(), "memcoin3")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	sigs := make(chan os.Signal)
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+
+	node1 := filepath.Join(dir, "node1")
+	node2 := filepath.Join(dir, "node2")
+	node3 := filepath.Join(dir, "node3")
+
+	cfg := config{Channel: sigs, Writer: io.Discard}
+
+	runNode(t, node1, cfg, 2310, &wg)
+	runNode(t, node2, cfg, 2311, &wg)
+	runNode(t, node3, cfg, 2312, &wg)
+
+	defer func() {
+		// Simulate a Ctrl+C
+		close(sigs)
+		wg.Wait()
+	}()
+
+	require.True(t, waitDaemon(t, []string{node1, node2, node3}), "daemon failed to start")
+
+	shareCert(t, node2, node1, "//127.0.0.1:2310")
+	shareCert(t, node3, node1, "//127.0.0.1:2310")
+
+	// Setup the chain with node 3 as a relay and a threshold of 2.
+	relay := getExport(t, node3)
+	relay[0] = "--relay"
+
+	args := append(append(append(
+		[]string{os.Args[0], "--config", node1, "ordering", "setup", "--threshold", "2"},
+		getExport(t, node1)...),
+		getExport(t, node2)...),
+		relay...,
+	)
+
+	err = run(args)
+	require.NoError(t, err)
+
+	for _, node := range []string{node1, node2, node3} {
+		err = runWithCfg([]string{os.Args[0], "--config", node, "dkg", "listen"}, cfg)
+		require.NoError(t, err)
+	}
+
+	// The setup reads the threshold of the chain.
+	args = []string{os.Args[0], "--config", node1, "dkg", "setup"}
+	args = append(args, getAuthority(t, node1)...)
+	args = append(args, getAuthority(t, node2)...)
+
+	err = runWithCfg(append(args, getAuthority(t, node3)...), cfg)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "127.0.0.1:2312 is a relay and cannot hold a key share")
+
+	err = runWithCfg(append(args, "--threshold", "1"), cfg)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "threshold 1 differs from the threshold 2 of the chain")
+
+	err = runWithCfg(args, cfg)
+	require.NoError(t, err)
+}
+
+// This test creates a chain with three nodes and a DKG with the first two. The
+// committees of the epochs are the last two nodes, and the first node does not
+// watch the epochs, so that the second one must hand the secret over after the
+// timeout. It then makes sure that the third node holds a share.
+func TestMemcoin_Scenario_DKGEpochs(t *testing.T) {
+	dir, err := os.MkdirTemp(os.TempDir(), "memcoin4")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	sigs := make(chan os.Signal)
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+
+	node1 := filepath.Join(dir, "node1")
+	node2 := filepath.Join(dir, "node2")
+	node3 := filepath.Join(dir, "node3")
+
+	cfg := config{Channel: sigs, Writer: io.Discard}
+
+	runNode(t, node1, cfg, 2410, &wg)
+	runNode(t, node2, cfg, 2411, &wg)
+	runNode(t, node3, cfg, 2412, &wg)
+
+	defer func() {
+		// Simulate a Ctrl+C
+		close(sigs)
+		wg.Wait()
+	}()
+
+	require.True(t, waitDaemon(t, []string{node1, node2, node3}), "daemon failed to start")
+
+	shareCert(t, node2, node1, "//127.0.0.1:2410")
+	shareCert(t, node3, node1, "//127.0.0.1:2410")
+
+	args := append(append(append(
+		[]string{os.Args[0], "--config", node1, "ordering", "setup"},
+		getExport(t, node1)...),
+		getExport(t, node2)...),
+		getExport(t, node3)...,
+	)
+
+	err = run(args)
+	require.NoError(t, err)
+
+	for _, node := range []string{node1, node2, node3} {
+		err = runWithCfg([]string{os.Args[0], "--config", node, "dkg", "listen"}, cfg)
+		require.NoError(t, err)
+	}
+
+	args = []string{os.Args[0], "--config", node1, "dkg", "setup", "--threshold", "2"}
+	args = append(args, getAuthority(t, node1)...)
+	args = append(args, getAuthority(t, node2)...)
+
+	err = runWithCfg(args, cfg)
+	require.NoError(t, err)
+
+	sign := []string{os.Args[0], "--config", node3, "dkg", "sign", "--message", "deadbeef"}
+
+	err = runWithCfg(sign, cfg)
+	require.Error(t, err)
+
+	for _, node := range []string{node2, node3} {
+		args = []string{
+			os.Args[0], "--config", node, "dkg", "epochs",
+			"--epochLength", "2", "--threshold", "2", "--timeout", "1s",
+		}
+		args = append(args, getAuthority(t, node2)...)
+		args = append(args, getAuthority(t, node3)...)
+
+		err = runWithCfg(args, cfg)
+		require.NoError(t, err)
+	}
+
+	// Create the blocks of the first epoch and the first block of the second.
+	args = append([]string{
+		os.Args[0],
+		"--config", node1, "ordering", "roster", "add",
+		"--wait", "60s"},
+		getExport(t, node1)...,
+	)
+
+	for i := 0; i < 3; i++ {
+		err = runWithCfg(args, config{})
+		require.EqualError(t, err, "command error: transaction refused: duplicate in roster: 127.0.0.1:2410")
+	}
+
+	for i := 0; i < 50; i++ {
+		err = runWithCfg(sign, cfg)
+		if err == nil {
+			break
+		}
+
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	require.NoError(t, err)
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
@@ -261,6 +430,13 @@ func getToken(t *testing.T, path string) []string {
 	return strings.Split(buffer.String(), " ")
 }
 
+func getAuthority(t *testing.T, path string) []string {
+	data, err := os.ReadFile(filepath.Join(path, "dkgauthority"))
+	require.NoError(t, err)
+
+	return []string{"--authority", string(data)}
+}
+
 func getExport(t *testing.T, path string) []string {
 	buffer := bytes.NewBufferString("--member ")
 	cfg := config{
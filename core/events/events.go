@@ -0,0 +1,224 @@
+// Package events defines a publish/subscribe service that announces the
+// progress of the ledger, so that components interested in new blocks or
+// released keys don't have to poll the storage.
+//
+// Subscribers receive the events through a buffered channel. When a subscriber
+// is not keeping up, the policy chosen at subscription decides what happens to
+// the new events.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.dedis.ch/dela/core"
+)
+
+// defaultBufferSize is the default capacity of a subscription channel.
+const defaultBufferSize = 16
+
+// Event is the interface implemented by every event published on the bus.
+type Event interface {
+	// GetIndex returns the index of the block the event relates to.
+	GetIndex() uint64
+}
+
+// BlockFinalized is published by the ordering service when a new block has
+// been committed. The time is the timestamp of the block agreed by the
+// consensus, or the zero time if the block has none.
+type BlockFinalized struct {
+	Index uint64
+	Time  time.Time
+}
+
+// GetIndex implements events.Event.
+func (e BlockFinalized) GetIndex() uint64 {
+	return e.Index
+}
+
+// KeyReleased is published when the decryption key of a label has been
+// recovered by the authority. The index is the one of the block of a block
+// label, or zero for the other labels.
+type KeyReleased struct {
+	Index uint64
+	Label []byte
+	Key   []byte
+}
+
+// GetIndex implements events.Event.
+func (e KeyReleased) GetIndex() uint64 {
+	return e.Index
+}
+
+// Policy defines what happens when an event is published while the channel of
+// a subscriber is full.
+type Policy byte
+
+const (
+	// DropNewest ignores the new event for that subscriber.
+	DropNewest Policy = iota
+
+	// DropOldest removes the oldest event of the buffer to make room for the
+	// new one.
+	DropOldest
+
+	// Block waits until the subscriber has room for the new event. A slow
+	// subscriber will slow down the publisher.
+	Block
+)
+
+// Service is the interface of an event bus.
+type Service interface {
+	// Publish announces the event to all the subscribers.
+	Publish(event Event)
+
+	// Subscribe returns a channel populated with the events published after
+	// the call. The channel is closed when the context is done.
+	Subscribe(ctx context.Context, opts ...SubscribeOption) <-chan Event
+}
+
+// subscribeTemplate is the set of parameters of a subscription.
+type subscribeTemplate struct {
+	size   int
+	policy Policy
+	filter func(Event) bool
+}
+
+// SubscribeOption is the type of option to set parameters of a subscription.
+type SubscribeOption func(*subscribeTemplate)
+
+// WithBufferSize sets the capacity of the subscription channel.
+func WithBufferSize(size int) SubscribeOption {
+	return func(tmpl *subscribeTemplate) {
+		tmpl.size = size
+	}
+}
+
+// WithPolicy sets the policy to apply when the subscriber is too slow.
+func WithPolicy(policy Policy) SubscribeOption {
+	return func(tmpl *subscribeTemplate) {
+		tmpl.policy = policy
+	}
+}
+
+// WithFilter sets a function that decides if an event should be delivered to
+// the subscriber.
+func WithFilter(filter func(Event) bool) SubscribeOption {
+	return func(tmpl *subscribeTemplate) {
+		tmpl.filter = filter
+	}
+}
+
+// Bus is an in-memory implementation of the event service.
+//
+// - implements events.Service
+type Bus struct {
+	watcher core.Observable
+}
+
+// NewBus creates a new empty bus.
+func NewBus() *Bus {
+	return &Bus{
+		watcher: core.NewWatcher(),
+	}
+}
+
+// Publish implements events.Service. It notifies the subscribers one after
+// each other according to their policy.
+func (b *Bus) Publish(event Event) {
+	b.watcher.Notify(event)
+}
+
+// Subscribe implements events.Service. It registers a new subscriber that will
+// be removed when the context is done.
+func (b *Bus) Subscribe(ctx context.Context, opts ...SubscribeOption) <-chan Event {
+	tmpl := subscribeTemplate{
+		size:   defaultBufferSize,
+		policy: DropNewest,
+	}
+
+	for _, opt := range opts {
+		opt(&tmpl)
+	}
+
+	obs := &observer{
+		ch:     make(chan Event, tmpl.size),
+		policy: tmpl.policy,
+		filter: tmpl.filter,
+		done:   ctx.Done(),
+	}
+
+	b.watcher.Add(obs)
+
+	go func() {
+		<-ctx.Done()
+		b.watcher.Remove(obs)
+
+		obs.Lock()
+		close(obs.ch)
+		obs.closed = true
+		obs.Unlock()
+	}()
+
+	return obs.ch
+}
+
+// observer is the subscriber registered to the watcher.
+//
+// - implements core.Observer
+type observer struct {
+	sync.Mutex
+
+	ch     chan Event
+	policy Policy
+	filter func(Event) bool
+	done   <-chan struct{}
+	closed bool
+}
+
+// NotifyCallback implements core.Observer. It delivers the event according to
+// the policy of the subscriber.
+func (obs *observer) NotifyCallback(event interface{}) {
+	evt, ok := event.(Event)
+	if !ok {
+		return
+	}
+
+	if obs.filter != nil && !obs.filter(evt) {
+		return
+	}
+
+	obs.Lock()
+	defer obs.Unlock()
+
+	if obs.closed {
+		return
+	}
+
+	switch obs.policy {
+	case Block:
+		select {
+		case obs.ch <- evt:
+		case <-obs.done:
+		}
+	case DropOldest:
+		for {
+			select {
+			case obs.ch <- evt:
+				return
+			default:
+			}
+
+			select {
+			case <-obs.ch:
+			default:
+			}
+		}
+	default:
+		select {
+		case obs.ch <- evt:
+		default:
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents_GetIndex(t *testing.T) {
+	require.Equal(t, uint64(1), BlockFinalized{Index: 1}.GetIndex())
+	require.Equal(t, uint64(2), KeyReleased{Index: 2}.GetIndex())
+}
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch1 := bus.Subscribe(ctx)
+	ch2 := bus.Subscribe(ctx)
+
+	bus.Publish(BlockFinalized{Index: 1})
+	bus.Publish(KeyReleased{Index: 1, Label: []byte{1}})
+
+	for _, ch := range []<-chan Event{ch1, ch2} {
+		require.Equal(t, BlockFinalized{Index: 1}, <-ch)
+		require.Equal(t, KeyReleased{Index: 1, Label: []byte{1}}, <-ch)
+	}
+}
+
+func TestBus_Subscribe(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := bus.Subscribe(ctx)
+
+	cancel()
+
+	_, more := <-ch
+	require.False(t, more)
+
+	// Publishing after the subscriber left must not panic.
+	bus.Publish(BlockFinalized{})
+}
+
+func TestBus_SubscribeWithFilter(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := bus.Subscribe(ctx, WithFilter(func(evt Event) bool {
+		_, ok := evt.(KeyReleased)
+		return ok
+	}))
+
+	bus.Publish(BlockFinalized{Index: 1})
+	bus.Publish(KeyReleased{Index: 2})
+
+	require.Equal(t, KeyReleased{Index: 2}, <-ch)
+	require.Len(t, ch, 0)
+}
+
+func TestBus_DropNewest(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := bus.Subscribe(ctx, WithBufferSize(2), WithPolicy(DropNewest))
+
+	for i := 0; i < 5; i++ {
+		bus.Publish(BlockFinalized{Index: uint64(i)})
+	}
+
+	require.Equal(t, uint64(0), (<-ch).GetIndex())
+	require.Equal(t, uint64(1), (<-ch).GetIndex())
+	require.Len(t, ch, 0)
+}
+
+func TestBus_DropOldest(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := bus.Subscribe(ctx, WithBufferSize(2), WithPolicy(DropOldest))
+
+	for i := 0; i < 5; i++ {
+		bus.Publish(BlockFinalized{Index: uint64(i)})
+	}
+
+	require.Equal(t, uint64(3), (<-ch).GetIndex())
+	require.Equal(t, uint64(4), (<-ch).GetIndex())
+	require.Len(t, ch, 0)
+}
+
+func TestBus_Block(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := bus.Subscribe(ctx, WithBufferSize(1), WithPolicy(Block))
+
+	done := make(chan struct{})
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			bus.Publish(BlockFinalized{Index: uint64(i)})
+		}
+		close(done)
+	}()
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, uint64(i), (<-ch).GetIndex())
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publisher is still blocked")
+	}
+
+	// A blocked publisher is released when the subscriber leaves.
+	bus.Publish(BlockFinalized{})
+
+	done = make(chan struct{})
+	go func() {
+		bus.Publish(BlockFinalized{})
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publisher is still blocked")
+	}
+}
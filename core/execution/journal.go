@@ -0,0 +1,165 @@
+package execution
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/store/kv"
+	"golang.org/x/xerrors"
+)
+
+// journalBucket is the name of the bucket where the diffs are persisted.
+var journalBucket = []byte("execution-journal")
+
+// Change is the new value of a key of the state. A nil value means that the
+// key is deleted.
+type Change struct {
+	Key   []byte
+	Value []byte
+}
+
+// Diff is the list of changes applied to the state by the block at the index.
+type Diff struct {
+	Index   uint64
+	Changes []Change
+}
+
+// Recorder is a snapshot that records the changes applied to the underlying
+// one. A key written several times appears once, with its last value, at the
+// position of its first write.
+//
+// - implements store.Snapshot
+type Recorder struct {
+	store.Snapshot
+
+	changes []Change
+	index   map[string]int
+}
+
+// NewRecorder returns a new recorder of the changes applied to the snapshot.
+func NewRecorder(snap store.Snapshot) *Recorder {
+	return &Recorder{
+		Snapshot: snap,
+		index:    make(map[string]int),
+	}
+}
+
+// Set implements store.Writable. It records the value of the key and writes it
+// to the snapshot.
+func (r *Recorder) Set(key, value []byte) error {
+	err := r.Snapshot.Set(key, value)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		value = []byte{}
+	}
+
+	r.record(key, append([]byte{}, value...))
+
+	return nil
+}
+
+// Delete implements store.Writable. It records the deletion of the key and
+// deletes it from the snapshot.
+func (r *Recorder) Delete(key []byte) error {
+	err := r.Snapshot.Delete(key)
+	if err != nil {
+		return err
+	}
+
+	r.record(key, nil)
+
+	return nil
+}
+
+// GetChanges returns the changes recorded so far.
+func (r *Recorder) GetChanges() []Change {
+	return append([]Change{}, r.changes...)
+}
+
+func (r *Recorder) record(key, value []byte) {
+	i, found := r.index[string(key)]
+	if found {
+		r.changes[i].Value = value
+		return
+	}
+
+	r.index[string(key)] = len(r.changes)
+	r.changes = append(r.changes, Change{
+		Key:   append([]byte{}, key...),
+		Value: value,
+	})
+}
+
+// Journal keeps the diff of the state of every block so that the evolution of
+// the state can be exported without running the chain again. Only the blocks
+// committed while the journal is in use have a diff.
+type Journal struct {
+	db kv.DB
+}
+
+// NewJournal creates a new journal persisted in the database.
+func NewJournal(db kv.DB) *Journal {
+	return &Journal{
+		db: db,
+	}
+}
+
+// Store persists the diff within the transaction, so that it is committed
+// atomically with the block.
+func (j *Journal) Store(tx kv.WritableTx, diff Diff) error {
+	bucket, err := tx.GetBucketOrCreate(journalBucket)
+	if err != nil {
+		return xerrors.Errorf("bucket: %v", err)
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return xerrors.Errorf("failed to encode diff: %v", err)
+	}
+
+	err = bucket.Set(makeKey(diff.Index), data)
+	if err != nil {
+		return xerrors.Errorf("failed to store diff: %v", err)
+	}
+
+	return nil
+}
+
+// Export calls the function with the diffs of the blocks from the index
+// onwards, in order. It stops at the first error returned by the function.
+func (j *Journal) Export(from uint64, fn func(Diff) error) error {
+	return j.db.View(func(tx kv.ReadableTx) error {
+		bucket := tx.GetBucket(journalBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(key, value []byte) error {
+			if binary.BigEndian.Uint64(key) < from {
+				return nil
+			}
+
+			var diff Diff
+
+			err := json.Unmarshal(value, &diff)
+			if err != nil {
+				return xerrors.Errorf("failed to decode diff: %v", err)
+			}
+
+			return fn(diff)
+		})
+	})
+}
+
+// makeKey returns the key of the index so that the diffs are sorted by index
+// in the bucket.
+func makeKey(index uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, index)
+
+	return key
+}
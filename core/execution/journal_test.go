@@ -0,0 +1,131 @@
+package execution
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestRecorder_Set(t *testing.T) {
+	snap := fake.NewSnapshot()
+	rec := NewRecorder(snap)
+
+	require.NoError(t, rec.Set([]byte("A"), []byte("1")))
+	require.NoError(t, rec.Set([]byte("B"), nil))
+	require.NoError(t, rec.Set([]byte("A"), []byte("2")))
+
+	value, err := snap.Get([]byte("A"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), value)
+
+	expected := []Change{
+		{Key: []byte("A"), Value: []byte("2")},
+		{Key: []byte("B"), Value: []byte{}},
+	}
+	require.Equal(t, expected, rec.GetChanges())
+
+	rec = NewRecorder(fake.NewBadSnapshot())
+
+	err = rec.Set([]byte("A"), []byte("1"))
+	require.EqualError(t, err, fake.GetError().Error())
+	require.Empty(t, rec.GetChanges())
+}
+
+func TestRecorder_Delete(t *testing.T) {
+	rec := NewRecorder(fake.NewSnapshot())
+
+	require.NoError(t, rec.Set([]byte("A"), []byte("1")))
+	require.NoError(t, rec.Delete([]byte("A")))
+	require.NoError(t, rec.Delete([]byte("B")))
+
+	expected := []Change{
+		{Key: []byte("A")},
+		{Key: []byte("B")},
+	}
+	require.Equal(t, expected, rec.GetChanges())
+
+	rec = NewRecorder(fake.NewBadSnapshot())
+
+	err := rec.Delete([]byte("A"))
+	require.EqualError(t, err, fake.GetError().Error())
+	require.Empty(t, rec.GetChanges())
+}
+
+func TestJournal_Export(t *testing.T) {
+	db, err := kv.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	journal := NewJournal(db)
+
+	err = journal.Export(0, func(Diff) error {
+		return fake.GetError()
+	})
+	require.NoError(t, err)
+
+	diffs := []Diff{
+		{Index: 0, Changes: []Change{{Key: []byte("A"), Value: []byte("1")}}},
+		{Index: 1, Changes: []Change{{Key: []byte("A")}}},
+		{Index: 2, Changes: []Change{}},
+	}
+
+	// The diffs are stored out of order to check that they are exported by
+	// index.
+	for _, i := range []int{2, 0, 1} {
+		err = db.Update(func(tx kv.WritableTx) error {
+			return journal.Store(tx, diffs[i])
+		})
+		require.NoError(t, err)
+	}
+
+	var exported []Diff
+
+	err = journal.Export(1, func(diff Diff) error {
+		exported = append(exported, diff)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, diffs[1:], exported)
+
+	err = journal.Export(0, func(Diff) error {
+		return fake.GetError()
+	})
+	require.EqualError(t, err, fake.GetError().Error())
+}
+
+func TestJournal_Store(t *testing.T) {
+	db := fake.NewBadDB()
+	journal := NewJournal(db)
+
+	err := db.Update(func(tx kv.WritableTx) error {
+		return journal.Store(tx, Diff{})
+	})
+	require.EqualError(t, err, fake.Err("bucket"))
+
+	db = fake.NewInMemoryDB()
+	db.SetBucket(journalBucket, fake.NewBadWriteBucket())
+
+	err = db.Update(func(tx kv.WritableTx) error {
+		return journal.Store(tx, Diff{})
+	})
+	require.EqualError(t, err, fake.Err("failed to store diff"))
+}
+
+func TestJournal_BadDiff_Export(t *testing.T) {
+	db := fake.NewInMemoryDB()
+
+	bucket := fake.NewBucket()
+	require.NoError(t, bucket.Set(makeKey(0), []byte("{")))
+
+	db.SetBucket(journalBucket, bucket)
+
+	err := NewJournal(db).Export(0, func(Diff) error {
+		return nil
+	})
+	require.Error(t, err)
+	require.Regexp(t, "^failed to decode diff: ", err.Error())
+}
@@ -0,0 +1,103 @@
+// Package beacon derives a random value for each block of the chain from its
+// collective signature.
+//
+// The commit signature of a forward link is produced by the roster only after
+// the block is accepted, so the randomness of a block cannot be known before
+// it is finalized. Like drand, the randomness is derived from the signature and
+// anyone can verify it with the roster of the block.
+//
+// The collective signature depends on the set of nodes that participated, so
+// the leader can bias the randomness by choosing which signatures to aggregate.
+// The bias is limited to the choice among the subsets of the roster that reach
+// the threshold.
+package beacon
+
+import (
+	"bytes"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/kdf"
+	"golang.org/x/xerrors"
+)
+
+// Beacon provides the randomness of the blocks of a chain.
+type Beacon struct {
+	blocks blockstore.BlockStore
+}
+
+// NewBeacon creates a new beacon that reads the blocks from the store.
+func NewBeacon(blocks blockstore.BlockStore) Beacon {
+	return Beacon{
+		blocks: blocks,
+	}
+}
+
+// GetRandomness returns the randomness of the block at the index.
+func (b Beacon) GetRandomness(index uint64) ([]byte, error) {
+	link, err := b.blocks.GetByIndex(index)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read block %d: %w", index, err)
+	}
+
+	randomness, err := Derive(link)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive: %v", err)
+	}
+
+	return randomness, nil
+}
+
+// RandomnessSize is the size in bytes of the randomness of a block.
+const RandomnessSize = 32
+
+// Derive returns the randomness of the forward link, which is derived from its
+// commit signature in the domain of the beacon.
+func Derive(link types.Link) ([]byte, error) {
+	sig := link.GetCommitSignature()
+	if sig == nil {
+		return nil, xerrors.New("missing commit signature")
+	}
+
+	data, err := sig.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal signature: %v", err)
+	}
+
+	randomness, err := kdf.Derive(data, kdf.BeaconRandomness, RandomnessSize)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive: %v", err)
+	}
+
+	return randomness, nil
+}
+
+// Verify checks that the commit signature of the link is valid for the roster
+// of the verifier, and that the randomness is derived from it.
+func Verify(link types.Link, randomness []byte, verifier crypto.Verifier) error {
+	if link.GetPrepareSignature() == nil || link.GetCommitSignature() == nil {
+		return xerrors.New("link is not signed")
+	}
+
+	msg, err := link.GetPrepareSignature().MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal signature: %v", err)
+	}
+
+	err = verifier.Verify(msg, link.GetCommitSignature())
+	if err != nil {
+		return xerrors.Errorf("invalid commit signature: %v", err)
+	}
+
+	expected, err := Derive(link)
+	if err != nil {
+		return xerrors.Errorf("failed to derive: %v", err)
+	}
+
+	if !bytes.Equal(expected, randomness) {
+		return xerrors.Errorf("mismatch randomness: %#x != %#x", randomness, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package beacon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/kdf"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestBeacon_GetRandomness(t *testing.T) {
+	blocks := blockstore.NewInMemory()
+	blocks.Store(makeLink(t, fake.Signature{}))
+
+	beacon := NewBeacon(blocks)
+
+	randomness, err := beacon.GetRandomness(0)
+	require.NoError(t, err)
+
+	expected, err := kdf.Derive([]byte{fake.SignatureByte}, kdf.BeaconRandomness, RandomnessSize)
+	require.NoError(t, err)
+	require.Equal(t, expected, randomness)
+
+	_, err = beacon.GetRandomness(1)
+	require.EqualError(t, err, "failed to read block 1: block not found: no block")
+	require.ErrorIs(t, err, blockstore.ErrNoBlock)
+
+	blocks = blockstore.NewInMemory()
+	blocks.Store(makeLink(t, fake.NewBadSignature()))
+
+	_, err = NewBeacon(blocks).GetRandomness(0)
+	require.EqualError(t, err, fake.Err("failed to derive: failed to marshal signature"))
+}
+
+func TestDerive(t *testing.T) {
+	_, err := Derive(makeLink(t, nil))
+	require.EqualError(t, err, "missing commit signature")
+}
+
+func TestVerify(t *testing.T) {
+	link := makeLink(t, fake.Signature{})
+
+	randomness, err := Derive(link)
+	require.NoError(t, err)
+
+	verifier := fake.NewVerifierWithExpectedMessage([]byte{fake.SignatureByte})
+
+	err = Verify(link, randomness, verifier)
+	require.NoError(t, err)
+
+	err = Verify(link, []byte("abc"), verifier)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "mismatch randomness: 0x616263 != ")
+
+	err = Verify(makeLink(t, nil), randomness, verifier)
+	require.EqualError(t, err, "link is not signed")
+
+	err = Verify(link, randomness, fake.NewBadVerifier())
+	require.EqualError(t, err, fake.Err("invalid commit signature"))
+
+	badLink := makeLinkWithSignatures(t, fake.NewBadSignature(), fake.Signature{})
+
+	err = Verify(badLink, randomness, verifier)
+	require.EqualError(t, err, fake.Err("failed to marshal signature"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeLink(t *testing.T, commit crypto.Signature) types.BlockLink {
+	return makeLinkWithSignatures(t, fake.Signature{}, commit)
+}
+
+func makeLinkWithSignatures(t *testing.T, prep, commit crypto.Signature) types.BlockLink {
+	block, err := types.NewBlock(simple.NewResult(nil))
+	require.NoError(t, err)
+
+	link, err := types.NewBlockLink(types.Digest{}, block, types.WithSignatures(prep, commit))
+	require.NoError(t, err)
+
+	return link
+}
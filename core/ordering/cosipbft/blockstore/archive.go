@@ -0,0 +1,253 @@
+// This file contains the implementation of a chain archive. It is a binary
+// file that contains the genesis block and the block links of a chain, so that
+// a chain can be kept after an experiment and verified offline.
+//
+// The archive starts with a header, followed by length-prefixed records. The
+// first record is the genesis block, then one record per block link. Each
+// record is followed by a checksum that includes the previous one, so that a
+// record that is modified, missing or out of order is detected. An empty
+// record ends the archive.
+
+package blockstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/json"
+	"golang.org/x/xerrors"
+)
+
+// archiveHeader identifies a chain archive and the version of the format.
+var archiveHeader = []byte("DELACHAIN\x01")
+
+// maxRecordSize is the maximum size of a record, to prevent a corrupted length
+// from allocating an arbitrary amount of memory.
+const maxRecordSize = 64 << 20
+
+// Archive is the content of a chain archive.
+type Archive struct {
+	Genesis types.Genesis
+	Links   []types.BlockLink
+}
+
+// WriteArchive writes the genesis block and the blocks of the store to the
+// writer. It returns the number of blocks written.
+func WriteArchive(w io.Writer, genesis types.Genesis, blocks BlockStore) (uint64, error) {
+	ctx := json.NewContext()
+
+	_, err := w.Write(archiveHeader)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to write header: %v", err)
+	}
+
+	aw := archiveWriter{w: w, sum: sha256.Sum256(archiveHeader)}
+
+	data, err := genesis.Serialize(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to serialize genesis: %v", err)
+	}
+
+	err = aw.write(data)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to write genesis: %v", err)
+	}
+
+	num := blocks.Len()
+
+	for index := uint64(0); index < num; index++ {
+		link, err := blocks.GetByIndex(index)
+		if err != nil {
+			return index, xerrors.Errorf("failed to read block %d: %v", index, err)
+		}
+
+		data, err := link.Serialize(ctx)
+		if err != nil {
+			return index, xerrors.Errorf("failed to serialize block %d: %v", index, err)
+		}
+
+		err = aw.write(data)
+		if err != nil {
+			return index, xerrors.Errorf("failed to write block %d: %v", index, err)
+		}
+	}
+
+	err = aw.write(nil)
+	if err != nil {
+		return num, xerrors.Errorf("failed to write end: %v", err)
+	}
+
+	return num, nil
+}
+
+// ReadArchive reads an archive and checks its integrity. The factories are used
+// to deserialize the genesis block and the block links.
+func ReadArchive(r io.Reader, genFac serde.Factory, linkFac types.LinkFactory) (Archive, error) {
+	ctx := json.NewContext()
+
+	header := make([]byte, len(archiveHeader))
+
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return Archive{}, xerrors.Errorf("failed to read header: %v", err)
+	}
+
+	if !bytes.Equal(header, archiveHeader) {
+		return Archive{}, xerrors.Errorf("invalid header %#x", header)
+	}
+
+	ar := archiveReader{r: r, sum: sha256.Sum256(archiveHeader)}
+
+	data, err := ar.read()
+	if err != nil {
+		return Archive{}, xerrors.Errorf("failed to read genesis: %v", err)
+	}
+
+	msg, err := genFac.Deserialize(ctx, data)
+	if err != nil {
+		return Archive{}, xerrors.Errorf("malformed genesis: %v", err)
+	}
+
+	genesis, ok := msg.(types.Genesis)
+	if !ok {
+		return Archive{}, xerrors.Errorf("invalid genesis '%T'", msg)
+	}
+
+	archive := Archive{Genesis: genesis}
+
+	for {
+		data, err := ar.read()
+		if err != nil {
+			return Archive{}, xerrors.Errorf("failed to read block %d: %v",
+				len(archive.Links), err)
+		}
+
+		if data == nil {
+			break
+		}
+
+		link, err := linkFac.BlockLinkOf(ctx, data)
+		if err != nil {
+			return Archive{}, xerrors.Errorf("malformed block %d: %v",
+				len(archive.Links), err)
+		}
+
+		archive.Links = append(archive.Links, link)
+	}
+
+	n, _ := io.ReadFull(r, make([]byte, 1))
+	if n > 0 {
+		return Archive{}, xerrors.New("unexpected data after the end")
+	}
+
+	return archive, nil
+}
+
+// Verify checks that the genesis block of the archive is the trusted one, that
+// the blocks form a chain that starts from it, and that each link is signed by
+// the roster of its time.
+func (a Archive) Verify(trusted types.Digest, fac crypto.VerifierFactory) error {
+	if a.Genesis.GetHash() != trusted {
+		return xerrors.Errorf("untrusted genesis: %v != %v", a.Genesis.GetHash(), trusted)
+	}
+
+	num := len(a.Links) - 1
+	if num < 0 {
+		return nil
+	}
+
+	prevs := make([]types.Link, num)
+	for i, link := range a.Links[:num] {
+		prevs[i] = link.Reduce()
+	}
+
+	chain := types.NewChain(a.Links[num], prevs)
+
+	err := chain.Verify(a.Genesis, a.Genesis.GetHash(), fac)
+	if err != nil {
+		return xerrors.Errorf("invalid chain: %v", err)
+	}
+
+	return nil
+}
+
+// archiveWriter writes the records of an archive.
+type archiveWriter struct {
+	w   io.Writer
+	sum [sha256.Size]byte
+}
+
+func (aw *archiveWriter) write(data []byte) error {
+	buffer := make([]byte, 4, 4+len(data)+sha256.Size)
+	binary.BigEndian.PutUint32(buffer, uint32(len(data)))
+
+	aw.sum = chainSum(aw.sum, data)
+
+	buffer = append(buffer, data...)
+	buffer = append(buffer, aw.sum[:]...)
+
+	_, err := aw.w.Write(buffer)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// archiveReader reads the records of an archive and verifies their checksum.
+type archiveReader struct {
+	r   io.Reader
+	sum [sha256.Size]byte
+}
+
+// read returns the next record, or nil if it is the end of the archive.
+func (ar *archiveReader) read() ([]byte, error) {
+	prefix := make([]byte, 4)
+
+	_, err := io.ReadFull(ar.r, prefix)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read length: %v", err)
+	}
+
+	length := binary.BigEndian.Uint32(prefix)
+	if length > maxRecordSize {
+		return nil, xerrors.Errorf("record is too big: %d", length)
+	}
+
+	record := make([]byte, int(length)+sha256.Size)
+
+	_, err = io.ReadFull(ar.r, record)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read record: %v", err)
+	}
+
+	data := record[:length]
+
+	ar.sum = chainSum(ar.sum, data)
+
+	if !bytes.Equal(ar.sum[:], record[length:]) {
+		return nil, xerrors.New("mismatch checksum")
+	}
+
+	if length == 0 {
+		return nil, nil
+	}
+
+	return data, nil
+}
+
+func chainSum(prev [sha256.Size]byte, data []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(prev[:])
+	h.Write(data)
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+
+	return sum
+}
@@ -0,0 +1,132 @@
+package blockstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestArchive_WriteRead(t *testing.T) {
+	genesis := makeGenesis(t)
+	blocks := makeBlocks(t, genesis, 3)
+
+	buffer := new(bytes.Buffer)
+
+	num, err := WriteArchive(buffer, genesis, blocks)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), num)
+
+	archive, err := ReadArchive(bytes.NewReader(buffer.Bytes()), makeFac(), makeBlockFac())
+	require.NoError(t, err)
+	require.Equal(t, genesis.GetHash(), archive.Genesis.GetHash())
+	require.Len(t, archive.Links, 3)
+
+	for i, link := range archive.Links {
+		require.Equal(t, uint64(i), link.GetBlock().GetIndex())
+		require.Equal(t, blocks.blocks[i].GetTo(), link.GetTo())
+	}
+
+	trusted := genesis.GetHash()
+
+	require.NoError(t, archive.Verify(trusted, fake.NewVerifierFactory(fake.Verifier{})))
+
+	err = archive.Verify(trusted, fake.NewBadVerifierFactory())
+	require.EqualError(t, err, fake.Err("invalid chain: verifier factory failed"))
+
+	// The genesis block of the archive must be the trusted one, even if the
+	// chain is valid.
+	err = archive.Verify(types.Digest{1}, fake.NewVerifierFactory(fake.Verifier{}))
+	require.EqualError(t, err, "untrusted genesis: "+trusted.String()+" != 01000000")
+
+	// An empty chain is valid.
+	buffer.Reset()
+	_, err = WriteArchive(buffer, genesis, NewInMemory())
+	require.NoError(t, err)
+
+	archive, err = ReadArchive(buffer, makeFac(), makeBlockFac())
+	require.NoError(t, err)
+	require.Empty(t, archive.Links)
+	require.NoError(t, archive.Verify(trusted, fake.NewBadVerifierFactory()))
+}
+
+func TestArchive_ReadCorrupted(t *testing.T) {
+	genesis := makeGenesis(t)
+
+	buffer := new(bytes.Buffer)
+	_, err := WriteArchive(buffer, genesis, makeBlocks(t, genesis, 2))
+	require.NoError(t, err)
+
+	data := buffer.Bytes()
+
+	read := func(data []byte) error {
+		_, err := ReadArchive(bytes.NewReader(data), makeFac(), makeBlockFac())
+		return err
+	}
+
+	err = read(nil)
+	require.EqualError(t, err, "failed to read header: EOF")
+
+	err = read([]byte("NOTACHAIN\x01"))
+	require.EqualError(t, err, "invalid header 0x4e4f5441434841494e01")
+
+	corrupted := append([]byte{}, data...)
+	corrupted[len(archiveHeader)+10] ^= 1
+	err = read(corrupted)
+	require.EqualError(t, err, "failed to read genesis: mismatch checksum")
+
+	err = read(data[:len(data)-sha256.Size-4])
+	require.EqualError(t, err, "failed to read block 2: failed to read length: EOF")
+
+	err = read(append(append([]byte{}, data...), 0))
+	require.EqualError(t, err, "unexpected data after the end")
+
+	corrupted = append([]byte{}, data...)
+	corrupted[len(archiveHeader)] = 0xff
+	err = read(corrupted)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read genesis: record is too big: ", err.Error())
+
+	_, err = ReadArchive(bytes.NewReader(data), fake.NewBadMessageFactory(), makeBlockFac())
+	require.EqualError(t, err, fake.Err("malformed genesis"))
+
+	_, err = ReadArchive(bytes.NewReader(data), fake.MessageFactory{}, makeBlockFac())
+	require.EqualError(t, err, "invalid genesis 'fake.Message'")
+
+	_, err = ReadArchive(bytes.NewReader(data), makeFac(), badLinkFac{})
+	require.EqualError(t, err, fake.Err("malformed block 0"))
+}
+
+func TestArchive_WriteFailures(t *testing.T) {
+	genesis := makeGenesis(t)
+
+	_, err := WriteArchive(fake.NewBadHash(), genesis, NewInMemory())
+	require.EqualError(t, err, fake.Err("failed to write header"))
+
+	blocks := makeBlocks(t, genesis, 1)
+	blocks.blocks[0] = badLink{}
+
+	_, err = WriteArchive(new(bytes.Buffer), genesis, blocks)
+	require.EqualError(t, err, fake.Err("failed to serialize block 0"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeBlocks(t *testing.T, genesis types.Genesis, n int) *InMemory {
+	store := NewInMemory()
+
+	prev := genesis.GetHash()
+
+	for i := 0; i < n; i++ {
+		link := makeLink(t, prev, types.WithIndex(uint64(i)))
+		require.NoError(t, store.Store(link))
+
+		prev = link.GetTo()
+	}
+
+	return store
+}
@@ -132,7 +132,7 @@ func TestGenesisDiskStore_Set(t *testing.T) {
 func makeFac() types.GenesisFactory {
 	authFac := authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 
-	return types.NewGenesisFactory(authFac)
+	return types.NewGenesisFactory(authFac, fake.PublicKeyFactory{})
 }
 
 func makeGenesis(t *testing.T) types.Genesis {
@@ -0,0 +1,149 @@
+// This file contains the implementation of a standalone proof that a block
+// belongs to a chain. Unlike a chain, the proof includes the genesis block so
+// that it can be verified without a node.
+
+package blockstore
+
+import (
+	gojson "encoding/json"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/json"
+	"golang.org/x/xerrors"
+)
+
+// ChainProof is a proof that a block belongs to the chain that starts with the
+// genesis block. It contains the forward links from the genesis block to the
+// block, which carry the roster changes along the way.
+type ChainProof struct {
+	Genesis types.Genesis
+	Chain   types.Chain
+}
+
+// proofJSON is the encoding of a proof.
+type proofJSON struct {
+	Genesis gojson.RawMessage
+	Chain   gojson.RawMessage
+}
+
+// GetProof returns the proof of the block at the index.
+func GetProof(genesis types.Genesis, blocks BlockStore, index uint64) (ChainProof, error) {
+	if index >= blocks.Len() {
+		return ChainProof{}, xerrors.Errorf("block %d not found: %w", index, ErrNoBlock)
+	}
+
+	prevs := make([]types.Link, index)
+
+	for i := uint64(0); i < index; i++ {
+		link, err := blocks.GetByIndex(i)
+		if err != nil {
+			return ChainProof{}, xerrors.Errorf("failed to read block %d: %v", i, err)
+		}
+
+		prevs[i] = link.Reduce()
+	}
+
+	last, err := blocks.GetByIndex(index)
+	if err != nil {
+		return ChainProof{}, xerrors.Errorf("failed to read block %d: %v", index, err)
+	}
+
+	proof := ChainProof{
+		Genesis: genesis,
+		Chain:   types.NewChain(last, prevs),
+	}
+
+	return proof, nil
+}
+
+// Encode returns the JSON representation of the proof.
+func (p ChainProof) Encode() ([]byte, error) {
+	ctx := json.NewContext()
+
+	genesis, err := p.Genesis.Serialize(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize genesis: %v", err)
+	}
+
+	chain, err := p.Chain.Serialize(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize chain: %v", err)
+	}
+
+	data, err := gojson.Marshal(proofJSON{Genesis: genesis, Chain: chain})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return data, nil
+}
+
+// Verify checks that the genesis block of the proof is the trusted one, that
+// the chain starts from it, and that each link is signed by the roster of its
+// time. The genesis block is part of the proof, so it can't be trusted on its
+// own.
+func (p ChainProof) Verify(trusted types.Digest, fac crypto.VerifierFactory) error {
+	if p.Genesis.GetHash() != trusted {
+		return xerrors.Errorf("untrusted genesis: %v != %v", p.Genesis.GetHash(), trusted)
+	}
+
+	err := p.Chain.Verify(p.Genesis, trusted, fac)
+	if err != nil {
+		return xerrors.Errorf("invalid chain: %v", err)
+	}
+
+	return nil
+}
+
+// DecodeProof returns the proof from its JSON representation. The factories
+// are used to deserialize the genesis block and the chain.
+func DecodeProof(data []byte, genFac serde.Factory,
+	chainFac types.ChainFactory) (ChainProof, error) {
+
+	ctx := json.NewContext()
+
+	var m proofJSON
+
+	err := gojson.Unmarshal(data, &m)
+	if err != nil {
+		return ChainProof{}, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	msg, err := genFac.Deserialize(ctx, m.Genesis)
+	if err != nil {
+		return ChainProof{}, xerrors.Errorf("malformed genesis: %v", err)
+	}
+
+	genesis, ok := msg.(types.Genesis)
+	if !ok {
+		return ChainProof{}, xerrors.Errorf("invalid genesis '%T'", msg)
+	}
+
+	chain, err := chainFac.ChainOf(ctx, m.Chain)
+	if err != nil {
+		return ChainProof{}, xerrors.Errorf("malformed chain: %v", err)
+	}
+
+	return ChainProof{Genesis: genesis, Chain: chain}, nil
+}
+
+// VerifyProof decodes and verifies a proof without the need of a node, from
+// the digest of the genesis block that the caller trusts. It returns the block
+// that is proven.
+func VerifyProof(data []byte, trusted types.Digest, genFac serde.Factory,
+	chainFac types.ChainFactory, fac crypto.VerifierFactory) (types.Block, error) {
+
+	proof, err := DecodeProof(data, genFac, chainFac)
+	if err != nil {
+		return types.Block{}, xerrors.Errorf("failed to decode proof: %v", err)
+	}
+
+	err = proof.Verify(trusted, fac)
+	if err != nil {
+		return types.Block{}, xerrors.Errorf("failed to verify proof: %v", err)
+	}
+
+	return proof.Chain.GetBlock(), nil
+}
@@ -0,0 +1,125 @@
+package blockstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestChainProof_EncodeVerify(t *testing.T) {
+	genesis := makeGenesis(t)
+	blocks := makeBlocks(t, genesis, 3)
+
+	chainFac := types.NewChainFactory(makeBlockFac())
+	verifierFac := fake.NewVerifierFactory(fake.Verifier{})
+
+	for index := uint64(0); index < 3; index++ {
+		proof, err := GetProof(genesis, blocks, index)
+		require.NoError(t, err)
+		require.Len(t, proof.Chain.GetLinks(), int(index)+1)
+
+		data, err := proof.Encode()
+		require.NoError(t, err)
+
+		block, err := VerifyProof(data, genesis.GetHash(), makeFac(), chainFac,
+			verifierFac)
+		require.NoError(t, err)
+		require.Equal(t, index, block.GetIndex())
+	}
+
+	data, err := getProofData(t, genesis, blocks, 1)
+	require.NoError(t, err)
+
+	_, err = VerifyProof(data, genesis.GetHash(), makeFac(), chainFac,
+		fake.NewBadVerifierFactory())
+	require.EqualError(t, err,
+		fake.Err("failed to verify proof: invalid chain: verifier factory failed"))
+
+	_, err = VerifyProof(nil, genesis.GetHash(), makeFac(), chainFac, verifierFac)
+	require.EqualError(t, err,
+		"failed to decode proof: failed to decode: unexpected end of JSON input")
+}
+
+func TestChainProof_Verify_ForeignGenesis(t *testing.T) {
+	genesis := makeGenesis(t)
+	blocks := makeBlocks(t, genesis, 2)
+
+	verifierFac := fake.NewVerifierFactory(fake.Verifier{})
+
+	proof, err := GetProof(genesis, blocks, 1)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify(genesis.GetHash(), verifierFac))
+
+	// A proof that is valid on its own is refused when it starts from another
+	// genesis block than the trusted one.
+	foreign, err := types.NewGenesis(genesis.GetRoster(),
+		types.WithGenesisRoot(types.Digest{1}))
+	require.NoError(t, err)
+
+	err = proof.Verify(foreign.GetHash(), verifierFac)
+	require.EqualError(t, err, "untrusted genesis: "+genesis.GetHash().String()+
+		" != "+foreign.GetHash().String())
+
+	data, err := proof.Encode()
+	require.NoError(t, err)
+
+	_, err = VerifyProof(data, foreign.GetHash(), makeFac(),
+		types.NewChainFactory(makeBlockFac()), verifierFac)
+	require.EqualError(t, err, "failed to verify proof: untrusted genesis: "+
+		genesis.GetHash().String()+" != "+foreign.GetHash().String())
+}
+
+func TestGetProof(t *testing.T) {
+	genesis := makeGenesis(t)
+	blocks := makeBlocks(t, genesis, 1)
+
+	_, err := GetProof(genesis, blocks, 1)
+	require.EqualError(t, err, "block 1 not found: no block")
+	require.ErrorIs(t, err, ErrNoBlock)
+}
+
+func TestChainProof_Encode(t *testing.T) {
+	genesis := makeGenesis(t)
+
+	proof, err := GetProof(genesis, makeBlocks(t, genesis, 1), 0)
+	require.NoError(t, err)
+
+	proof.Chain = types.NewChain(badLink{}, nil)
+
+	_, err = proof.Encode()
+	require.Error(t, err)
+	require.Regexp(t, "^failed to serialize chain: ", err.Error())
+}
+
+func TestDecodeProof(t *testing.T) {
+	genesis := makeGenesis(t)
+
+	data, err := getProofData(t, genesis, makeBlocks(t, genesis, 1), 0)
+	require.NoError(t, err)
+
+	chainFac := types.NewChainFactory(makeBlockFac())
+
+	_, err = DecodeProof(data, fake.NewBadMessageFactory(), chainFac)
+	require.EqualError(t, err, fake.Err("malformed genesis"))
+
+	_, err = DecodeProof(data, fake.MessageFactory{}, chainFac)
+	require.EqualError(t, err, "invalid genesis 'fake.Message'")
+
+	_, err = DecodeProof(data, makeFac(), types.NewChainFactory(badLinkFac{}))
+	require.Error(t, err)
+	require.Regexp(t, "^malformed chain: ", err.Error())
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func getProofData(t *testing.T, genesis types.Genesis, blocks BlockStore,
+	index uint64) ([]byte, error) {
+
+	proof, err := GetProof(genesis, blocks, index)
+	require.NoError(t, err)
+
+	return proof.Encode()
+}
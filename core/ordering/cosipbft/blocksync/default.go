@@ -14,6 +14,7 @@ import (
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blocksync/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/ordering/cosipbft/pbft"
 	otypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/crypto"
@@ -39,6 +40,7 @@ type defaultSync struct {
 	rpc    mino.RPC
 	pbftsm pbft.StateMachine
 	blocks blockstore.BlockStore
+	certs  *enrollment.Holder
 
 	latest      *uint64
 	catchUpLock *sync.Mutex
@@ -53,6 +55,16 @@ type SyncParam struct {
 	LinkFactory     otypes.LinkFactory
 	ChainFactory    otypes.ChainFactory
 	VerifierFactory crypto.VerifierFactory
+
+	// CertificateFactory deserializes the identity certificates of the
+	// leaders.
+	CertificateFactory enrollment.Factory
+
+	// Certificates holds the identity certificate of the node, which is sent
+	// to the participants when it leads a synchronization. When the genesis
+	// block has an authority, a participant only catches up with a leader
+	// that provides a valid certificate.
+	Certificates *enrollment.Holder
 }
 
 // NewSynchronizer creates a new block synchronizer.
@@ -71,13 +83,14 @@ func NewSynchronizer(param SyncParam) Synchronizer {
 		verifierFac: param.VerifierFactory,
 	}
 
-	fac := types.NewMessageFactory(param.LinkFactory, param.ChainFactory)
+	fac := types.NewMessageFactory(param.LinkFactory, param.ChainFactory, param.CertificateFactory)
 
 	s := defaultSync{
 		logger:      logger,
 		rpc:         mino.MustCreateRPC(param.Mino, "blocksync", h, fac),
 		pbftsm:      param.PBFT,
 		blocks:      param.Blocks,
+		certs:       param.Certificates,
 		latest:      &latest,
 		catchUpLock: h.catchUpLock,
 	}
@@ -118,7 +131,15 @@ func (s defaultSync) Sync(ctx context.Context, players mino.Players, cfg Config)
 		return xerrors.Errorf("failed to read chain: %v", err)
 	}
 
-	errs := sender.Send(types.NewSyncMessage(chain), iter2arr(players.AddressIterator())...)
+	var opts []types.SyncOption
+
+	if s.certs != nil && s.certs.Get() != nil {
+		opts = append(opts, types.WithCertificate(*s.certs.Get()))
+	}
+
+	msg := types.NewSyncMessage(chain, opts...)
+
+	errs := sender.Send(msg, iter2arr(players.AddressIterator())...)
 	for err := range errs {
 		if err != nil {
 			s.logger.Warn().Err(err).Msg("announcement failed")
@@ -234,6 +255,18 @@ func (h *handler) Stream(out mino.Sender, in mino.Receiver) error {
 		return xerrors.Errorf("reading genesis: %v", err)
 	}
 
+	if genesis.GetAuthority() != nil {
+		err = h.authenticate(m, orch, genesis.GetAuthority())
+		if err != nil {
+			return xerrors.Errorf("failed to authenticate: %v", err)
+		}
+	}
+
+	err = h.detectFork(genesis, m.GetChain(), orch)
+	if err != nil {
+		return xerrors.Errorf("failed to check fork: %w", err)
+	}
+
 	from := genesis.GetHash()
 
 	// We trust our storage, thus we won't check links on blocks we already
@@ -314,6 +347,28 @@ func (h *handler) waitAnnounce(ctx context.Context,
 	}
 }
 
+// authenticate returns nil if the announcement contains a certificate of the
+// authority for the leader, otherwise an error.
+func (h *handler) authenticate(m *types.SyncMessage, orch mino.Address,
+	ca crypto.PublicKey) error {
+
+	cert := m.GetCertificate()
+	if cert == nil {
+		return xerrors.New("missing certificate")
+	}
+
+	if !orch.Equal(cert.GetAddress()) {
+		return xerrors.Errorf("certificate of %v used by %v", cert.GetAddress(), orch)
+	}
+
+	err := cert.Verify(ca)
+	if err != nil {
+		return xerrors.Errorf("invalid certificate: %v", err)
+	}
+
+	return nil
+}
+
 func (h *handler) ack(out mino.Sender, orch mino.Address) error {
 	// Send the acknowledgement to the orchestrator that the blocks have been
 	// caught up.
@@ -12,11 +12,13 @@ import (
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blocksync/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/ordering/cosipbft/pbft"
 	otypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/txn/signed"
 	"go.dedis.ch/dela/core/validation/simple"
 	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/mino/minoch"
@@ -77,6 +79,53 @@ func TestDefaultSync_Basic(t *testing.T) {
 	}
 }
 
+func TestDefaultSync_Certificate(t *testing.T) {
+	n := 3
+	ca := bls.Generate()
+	holder := enrollment.NewHolder()
+
+	genesis, err := otypes.NewGenesis(authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner)),
+		otypes.WithAuthority(ca.GetPublicKey()))
+	require.NoError(t, err)
+
+	syncs, _, roster := makeNodes(t, n, func(i int, param *SyncParam) {
+		param.Genesis = blockstore.NewGenesisStore()
+		param.Genesis.Set(genesis)
+		param.CertificateFactory = enrollment.NewCertificateFactory(
+			param.Mino.GetAddressFactory(), fake.PublicKeyFactory{}, ca.GetSignatureFactory())
+
+		if i == 0 {
+			param.Certificates = holder
+		}
+	})
+
+	storeBlocks(t, syncs[0].blocks, 2, genesis.GetHash().Bytes()...)
+
+	// The leader is not enrolled yet, so that the participants refuse to catch
+	// up.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	err = syncs[0].Sync(ctx, roster, Config{MinSoft: n, MinHard: n})
+	require.NoError(t, err)
+
+	for i := 1; i < n; i++ {
+		require.Equal(t, uint64(0), syncs[i].blocks.Len(), strconv.Itoa(i))
+	}
+
+	cert, err := enrollment.Issue(ca, roster.AddressIterator().GetNext(), fake.PublicKey{})
+	require.NoError(t, err)
+
+	holder.Set(cert)
+
+	err = syncs[0].Sync(context.Background(), roster, Config{MinSoft: n, MinHard: n})
+	require.NoError(t, err)
+
+	for i := 1; i < n; i++ {
+		require.Equal(t, uint64(2), syncs[i].blocks.Len(), strconv.Itoa(i))
+	}
+}
+
 func TestDefaultSync_GetLatest(t *testing.T) {
 	latest := uint64(5)
 
@@ -246,6 +295,41 @@ func TestHandler_Stream(t *testing.T) {
 
 	err = handler.Stream(fake.NewBadSender(), recv)
 	require.EqualError(t, err, fake.Err("sending ack failed"))
+
+	// The leader must present a certificate when the chain has an authority.
+	genesis, err := otypes.NewGenesis(authority.New(nil, nil),
+		otypes.WithAuthority(fake.PublicKey{}))
+	require.NoError(t, err)
+
+	handler.genesis = blockstore.NewGenesisStore()
+	handler.genesis.Set(genesis)
+	recv = fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewSyncMessage(makeChain(t, 0))),
+	)
+
+	err = handler.Stream(fake.Sender{}, recv)
+	require.EqualError(t, err, "failed to authenticate: missing certificate")
+}
+
+func TestHandler_Authenticate(t *testing.T) {
+	ca := bls.Generate()
+
+	h := &handler{}
+
+	cert, err := enrollment.Issue(ca, fake.NewAddress(0), fake.PublicKey{})
+	require.NoError(t, err)
+
+	m := types.NewSyncMessage(makeChain(t, 0), types.WithCertificate(cert))
+
+	err = h.authenticate(&m, fake.NewAddress(0), ca.GetPublicKey())
+	require.NoError(t, err)
+
+	err = h.authenticate(&m, fake.NewAddress(1), ca.GetPublicKey())
+	require.EqualError(t, err, "certificate of fake.Address[0] used by fake.Address[1]")
+
+	err = h.authenticate(&m, fake.NewAddress(0), bls.Generate().GetPublicKey())
+	require.Error(t, err)
+	require.Regexp(t, "^invalid certificate: invalid signature: ", err.Error())
 }
 
 // -----------------------------------------------------------------------------
@@ -258,7 +342,9 @@ func makeChain(t *testing.T, index uint64) otypes.Chain {
 	return fakeChain{block: block}
 }
 
-func makeNodes(t *testing.T, n int) ([]defaultSync, otypes.Genesis, mino.Players) {
+func makeNodes(t *testing.T, n int,
+	tweaks ...func(int, *SyncParam)) ([]defaultSync, otypes.Genesis, mino.Players) {
+
 	manager := minoch.NewManager()
 
 	syncs := make([]defaultSync, n)
@@ -292,6 +378,10 @@ func makeNodes(t *testing.T, n int) ([]defaultSync, otypes.Genesis, mino.Players
 			VerifierFactory: fake.VerifierFactory{},
 		}
 
+		for _, tweak := range tweaks {
+			tweak(i, &param)
+		}
+
 		syncs[i] = NewSynchronizer(param).(defaultSync)
 	}
 
@@ -354,9 +444,14 @@ type fakeChain struct {
 	otypes.Chain
 
 	block otypes.Block
+	links []otypes.Link
 	err   error
 }
 
+func (c fakeChain) GetLinks() []otypes.Link {
+	return c.links
+}
+
 func (c fakeChain) GetBlock() otypes.Block {
 	return c.block
 }
@@ -0,0 +1,126 @@
+package blocksync
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.dedis.ch/dela"
+	otypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/mino"
+	"golang.org/x/xerrors"
+)
+
+var promForks = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "dela_cosipbft_forks_detected",
+	Help: "total number of conflicting blocks proven by peers",
+})
+
+func init() {
+	dela.PromCollectors = append(dela.PromCollectors, promForks)
+}
+
+// ForkError is the error returned when a peer announces a chain with a block
+// that conflicts with a block stored by the node, that is two different blocks
+// at the same index whose links are both valid.
+//
+// The blocks of the chain are final, so the fork choice is to keep the blocks
+// that are stored and to refuse the chain of the peer. A fork can only happen
+// when more than the tolerated number of participants misbehave, therefore the
+// operator is alerted and must investigate.
+type ForkError struct {
+	// Index is the index of the first conflicting block.
+	Index uint64
+
+	// Local is the digest of the block stored by the node.
+	Local otypes.Digest
+
+	// Remote is the digest of the block announced by the peer.
+	Remote otypes.Digest
+
+	// Peer is the address of the participant that announced the chain.
+	Peer mino.Address
+}
+
+// Error implements error. It returns a description of the fork.
+func (e ForkError) Error() string {
+	return fmt.Sprintf("fork at index %d: local block %v != %v from %v",
+		e.Index, e.Local, e.Remote, e.Peer)
+}
+
+// detectFork compares the chain announced by the peer with the stored blocks.
+// It returns a ForkError when the chain diverges with valid links, or an error
+// if the diverging links are invalid.
+func (h *handler) detectFork(genesis otypes.Genesis, chain otypes.Chain,
+	peer mino.Address) error {
+
+	links := chain.GetLinks()
+
+	common := uint64(len(links))
+	if h.blocks.Len() < common {
+		common = h.blocks.Len()
+	}
+
+	if common == 0 {
+		return nil
+	}
+
+	// The links are chained by the digest of the previous block, so the chains
+	// are only scanned when the last common block is different.
+	same, err := h.sameBlock(common-1, links[common-1])
+	if err != nil || same {
+		return err
+	}
+
+	for i := uint64(0); i < common; i++ {
+		same, err = h.sameBlock(i, links[i])
+		if err != nil {
+			return err
+		}
+
+		if same {
+			continue
+		}
+
+		// The links of the peer are verified from the divergence, so that a
+		// peer cannot raise an alert with links that are not signed.
+		err = chain.Verify(genesis, links[i].GetFrom(), h.verifierFac)
+		if err != nil {
+			return xerrors.Errorf("conflicting block at index %d with an "+
+				"invalid chain: %v", i, err)
+		}
+
+		local, err := h.blocks.GetByIndex(i)
+		if err != nil {
+			return xerrors.Errorf("failed to read block %d: %v", i, err)
+		}
+
+		fork := ForkError{
+			Index:  i,
+			Local:  local.GetTo(),
+			Remote: links[i].GetTo(),
+			Peer:   peer,
+		}
+
+		promForks.Inc()
+
+		h.logger.Error().
+			Uint64("index", fork.Index).
+			Stringer("local", fork.Local).
+			Stringer("remote", fork.Remote).
+			Stringer("peer", peer).
+			Msg("fork detected, keeping the local chain")
+
+		return fork
+	}
+
+	return nil
+}
+
+func (h *handler) sameBlock(index uint64, link otypes.Link) (bool, error) {
+	local, err := h.blocks.GetByIndex(index)
+	if err != nil {
+		return false, xerrors.Errorf("failed to read block %d: %v", index, err)
+	}
+
+	return local.GetFrom() == link.GetFrom() && local.GetTo() == link.GetTo(), nil
+}
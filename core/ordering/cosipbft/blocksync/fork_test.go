@@ -0,0 +1,129 @@
+package blocksync
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blocksync/types"
+	otypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"golang.org/x/xerrors"
+)
+
+func TestForkError_Error(t *testing.T) {
+	err := ForkError{Index: 2, Peer: fake.NewAddress(0)}
+
+	require.EqualError(t, err, "fork at index 2: local block 00000000 != "+
+		"00000000 from fake.Address[0]")
+}
+
+func TestHandler_DetectFork(t *testing.T) {
+	h := &handler{
+		blocks:      blockstore.NewInMemory(),
+		verifierFac: fake.VerifierFactory{},
+	}
+
+	storeBlocks(t, h.blocks, 3)
+
+	links := makeLinks(t, h.blocks)
+	peer := fake.NewAddress(0)
+
+	// The peer knows more blocks than the node.
+	err := h.detectFork(otypes.Genesis{}, fakeChain{links: links}, peer)
+	require.NoError(t, err)
+
+	// The peer knows less blocks than the node.
+	err = h.detectFork(otypes.Genesis{}, fakeChain{links: links[:1]}, peer)
+	require.NoError(t, err)
+
+	err = h.detectFork(otypes.Genesis{}, fakeChain{}, peer)
+	require.NoError(t, err)
+
+	// The peer announces a different block at index 1.
+	block, err := otypes.NewBlock(simple.NewResult(nil), otypes.WithIndex(1),
+		otypes.WithTreeRoot(otypes.Digest{1}))
+	require.NoError(t, err)
+
+	conflict, err := otypes.NewBlockLink(links[1].GetFrom(), block,
+		otypes.WithSignatures(fake.Signature{}, fake.Signature{}))
+	require.NoError(t, err)
+
+	forked := []otypes.Link{links[0], conflict}
+
+	err = h.detectFork(otypes.Genesis{}, fakeChain{links: forked}, peer)
+	require.Equal(t, ForkError{
+		Index:  1,
+		Local:  links[1].GetTo(),
+		Remote: block.GetHash(),
+		Peer:   peer,
+	}, err)
+
+	// A conflicting block without a valid chain is not a fork.
+	chain := fakeChain{links: forked, err: fake.GetError()}
+
+	err = h.detectFork(otypes.Genesis{}, chain, peer)
+	require.EqualError(t, err,
+		fake.Err("conflicting block at index 1 with an invalid chain"))
+
+	h.blocks = badBlockStore{}
+
+	err = h.detectFork(otypes.Genesis{}, fakeChain{links: links}, peer)
+	require.EqualError(t, err, fake.Err("failed to read block 2"))
+}
+
+func TestHandler_Fork_Stream(t *testing.T) {
+	latest := uint64(0)
+
+	h := &handler{
+		latest:      &latest,
+		catchUpLock: new(sync.Mutex),
+		genesis:     blockstore.NewGenesisStore(),
+		blocks:      blockstore.NewInMemory(),
+		verifierFac: fake.VerifierFactory{},
+	}
+	h.genesis.Set(otypes.Genesis{})
+	h.pbftsm = testSM{blocks: h.blocks}
+
+	storeBlocks(t, h.blocks, 1)
+
+	block, err := otypes.NewBlock(simple.NewResult(nil), otypes.WithIndex(0),
+		otypes.WithTreeRoot(otypes.Digest{1}))
+	require.NoError(t, err)
+
+	conflict, err := otypes.NewBlockLink(otypes.Digest{}, block,
+		otypes.WithSignatures(fake.Signature{}, fake.Signature{}))
+	require.NoError(t, err)
+
+	chain := fakeChain{block: block, links: []otypes.Link{conflict}}
+
+	recv := fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewSyncMessage(chain)),
+	)
+
+	err = h.Stream(fake.Sender{}, recv)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to check fork: fork at index 0: ", err.Error())
+
+	var fork ForkError
+	require.True(t, xerrors.As(err, &fork))
+	require.Equal(t, block.GetHash(), fork.Remote)
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeLinks(t *testing.T, blocks blockstore.BlockStore) []otypes.Link {
+	links := make([]otypes.Link, blocks.Len())
+
+	for i := range links {
+		link, err := blocks.GetByIndex(uint64(i))
+		require.NoError(t, err)
+
+		links[i] = link
+	}
+
+	return links
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"go.dedis.ch/dela/core/ordering/cosipbft/blocksync/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	otypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/serde"
 	"golang.org/x/xerrors"
@@ -15,7 +16,8 @@ func init() {
 
 // SyncMessageJSON is the JSON representation of a sync announcement.
 type SyncMessageJSON struct {
-	Chain json.RawMessage
+	Chain       json.RawMessage
+	Certificate json.RawMessage `json:",omitempty"`
 }
 
 // SyncRequestJSON is the JSON representation of a sync request.
@@ -60,6 +62,13 @@ func (fmt msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error
 			Chain: chain,
 		}
 
+		if in.GetCertificate() != nil {
+			sm.Certificate, err = in.GetCertificate().Serialize(ctx)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to encode certificate: %v", err)
+			}
+		}
+
 		m.Message = &sm
 	case types.SyncRequest:
 		req := SyncRequestJSON{
@@ -114,7 +123,25 @@ func (fmt msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, erro
 			return nil, xerrors.Errorf("failed to decode chain: %v", err)
 		}
 
-		return types.NewSyncMessage(chain), nil
+		var opts []types.SyncOption
+
+		if len(m.Message.Certificate) > 0 {
+			fac := ctx.GetFactory(types.CertificateKey{})
+
+			factory, ok := fac.(enrollment.Factory)
+			if !ok {
+				return nil, xerrors.Errorf("invalid certificate factory '%T'", fac)
+			}
+
+			cert, err := factory.CertificateOf(ctx, m.Message.Certificate)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to decode certificate: %v", err)
+			}
+
+			opts = append(opts, types.WithCertificate(cert))
+		}
+
+		return types.NewSyncMessage(chain, opts...), nil
 	}
 
 	if m.Request != nil {
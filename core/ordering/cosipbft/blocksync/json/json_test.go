@@ -5,11 +5,17 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blocksync/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	otypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/serde"
 )
 
+func init() {
+	enrollment.RegisterCertificateFormat(fake.GoodFormat, fakeCertFormat{})
+	enrollment.RegisterCertificateFormat(fake.BadFormat, fake.NewBadFormat())
+}
+
 func TestMsgFormat_Encode(t *testing.T) {
 	format := msgFormat{}
 
@@ -44,6 +50,41 @@ func TestMsgFormat_Encode(t *testing.T) {
 	require.EqualError(t, err, fake.Err("marshal failed"))
 }
 
+func TestMsgFormat_Certificate_Encode(t *testing.T) {
+	format := msgFormat{}
+
+	msg := types.NewSyncMessage(fakeChain{}, types.WithCertificate(enrollment.Certificate{}))
+
+	data, err := format.Encode(fake.NewContext(), msg)
+	require.NoError(t, err)
+	require.Equal(t, `{"Message":{"Chain":{},"Certificate":{}}}`, string(data))
+
+	_, err = format.Encode(fake.NewBadContext(), msg)
+	require.EqualError(t, err, fake.Err("failed to encode certificate: failed to encode"))
+}
+
+func TestMsgFormat_Certificate_Decode(t *testing.T) {
+	format := msgFormat{}
+
+	ctx := fake.NewContext()
+	ctx = serde.WithFactory(ctx, types.ChainKey{}, fakeChainFac{})
+	ctx = serde.WithFactory(ctx, types.CertificateKey{}, fakeCertFac{})
+
+	data := []byte(`{"Message":{"Chain":{},"Certificate":{}}}`)
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, types.NewSyncMessage(fakeChain{}, types.WithCertificate(enrollment.Certificate{})), msg)
+
+	ctx = serde.WithFactory(ctx, types.CertificateKey{}, fakeCertFac{err: fake.GetError()})
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err, fake.Err("failed to decode certificate"))
+
+	ctx = serde.WithFactory(ctx, types.CertificateKey{}, nil)
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err, "invalid certificate factory '<nil>'")
+}
+
 func TestMsgFormat_Decode(t *testing.T) {
 	format := msgFormat{}
 
@@ -113,6 +154,26 @@ func (fac fakeChainFac) ChainOf(serde.Context, []byte) (otypes.Chain, error) {
 	return fakeChain{}, fac.err
 }
 
+type fakeCertFormat struct{}
+
+func (fakeCertFormat) Encode(serde.Context, serde.Message) ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+func (fakeCertFormat) Decode(serde.Context, []byte) (serde.Message, error) {
+	return enrollment.Certificate{}, nil
+}
+
+type fakeCertFac struct {
+	enrollment.Factory
+
+	err error
+}
+
+func (fac fakeCertFac) CertificateOf(serde.Context, []byte) (enrollment.Certificate, error) {
+	return enrollment.Certificate{}, fac.err
+}
+
 type fakeLink struct {
 	otypes.BlockLink
 
@@ -7,6 +7,7 @@
 package types
 
 import (
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/dela/serde/registry"
@@ -22,18 +23,36 @@ func RegisterMessageFormat(f serde.Format, e serde.FormatEngine) {
 
 // SyncMessage is the announcement sent to the participants with the latest
 // index of the leader. The chain is provided to prove the validity of the
-// index.
+// index, and the identity certificate of the leader, if any, to authenticate
+// it.
 //
 // - implements serde.Message
 type SyncMessage struct {
 	chain types.Chain
+	cert  *enrollment.Certificate
+}
+
+// SyncOption is the type of option to set some fields of an announcement.
+type SyncOption func(*SyncMessage)
+
+// WithCertificate is an option to set the identity certificate of the leader.
+func WithCertificate(cert enrollment.Certificate) SyncOption {
+	return func(m *SyncMessage) {
+		m.cert = &cert
+	}
 }
 
 // NewSyncMessage creates a new announcement message.
-func NewSyncMessage(chain types.Chain) SyncMessage {
-	return SyncMessage{
+func NewSyncMessage(chain types.Chain, opts ...SyncOption) SyncMessage {
+	m := SyncMessage{
 		chain: chain,
 	}
+
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	return m
 }
 
 // GetChain returns the chain that proves the latest index.
@@ -41,6 +60,12 @@ func (m SyncMessage) GetChain() types.Chain {
 	return m.chain
 }
 
+// GetCertificate returns the identity certificate of the leader, or nil if it
+// is not set.
+func (m SyncMessage) GetCertificate() *enrollment.Certificate {
+	return m.cert
+}
+
 // GetLatestIndex returns the latest index.
 func (m SyncMessage) GetLatestIndex() uint64 {
 	return m.chain.GetBlock().GetIndex()
@@ -153,19 +178,26 @@ type LinkKey struct{}
 // ChainKey is the key of the chain factory.
 type ChainKey struct{}
 
+// CertificateKey is the key of the certificate factory.
+type CertificateKey struct{}
+
 // MessageFactory is a message factory for sync messages.
 //
 // - implements serde.Factory
 type MessageFactory struct {
 	linkFac  types.LinkFactory
 	chainFac types.ChainFactory
+	certFac  enrollment.Factory
 }
 
 // NewMessageFactory createsa new message factory.
-func NewMessageFactory(fac types.LinkFactory, chainFac types.ChainFactory) MessageFactory {
+func NewMessageFactory(fac types.LinkFactory, chainFac types.ChainFactory,
+	certFac enrollment.Factory) MessageFactory {
+
 	return MessageFactory{
 		linkFac:  fac,
 		chainFac: chainFac,
+		certFac:  certFac,
 	}
 }
 
@@ -176,6 +208,7 @@ func (fac MessageFactory) Deserialize(ctx serde.Context, data []byte) (serde.Mes
 
 	ctx = serde.WithFactory(ctx, LinkKey{}, fac.linkFac)
 	ctx = serde.WithFactory(ctx, ChainKey{}, fac.chainFac)
+	ctx = serde.WithFactory(ctx, CertificateKey{}, fac.certFac)
 
 	msg, err := format.Decode(ctx, data)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/validation/simple"
 	"go.dedis.ch/dela/internal/testing/fake"
@@ -23,6 +24,16 @@ func TestSyncMessage_GetChain(t *testing.T) {
 	require.NotNil(t, m.GetChain())
 }
 
+func TestSyncMessage_GetCertificate(t *testing.T) {
+	m := NewSyncMessage(makeChain(t, 5))
+	require.Nil(t, m.GetCertificate())
+
+	cert := enrollment.NewCertificate(fake.NewAddress(0), fake.PublicKey{}, fake.Signature{})
+
+	m = NewSyncMessage(makeChain(t, 5), WithCertificate(cert))
+	require.Equal(t, &cert, m.GetCertificate())
+}
+
 func TestSyncMessage_GetLatestIndex(t *testing.T) {
 	m := NewSyncMessage(makeChain(t, 5))
 
@@ -96,7 +107,10 @@ func TestMessageFactory_Deserialize(t *testing.T) {
 
 	linkFac := types.NewLinkFactory(nil, nil, nil)
 
-	fac := NewMessageFactory(linkFac, types.NewChainFactory(linkFac))
+	certFac := enrollment.NewCertificateFactory(fake.AddressFactory{}, fake.PublicKeyFactory{},
+		fake.SignatureFactory{})
+
+	fac := NewMessageFactory(linkFac, types.NewChainFactory(linkFac), certFac)
 
 	msg, err := fac.Deserialize(fake.NewContext(), nil)
 	require.NoError(t, err)
@@ -105,6 +119,9 @@ func TestMessageFactory_Deserialize(t *testing.T) {
 	factory := testCalls.Get(0, 0).(serde.Context).GetFactory(LinkKey{})
 	require.NotNil(t, factory)
 
+	factory = testCalls.Get(0, 0).(serde.Context).GetFactory(CertificateKey{})
+	require.NotNil(t, factory)
+
 	_, err = fac.Deserialize(fake.NewBadContext(), nil)
 	require.EqualError(t, err, fake.Err("decoding failed"))
 }
@@ -10,8 +10,11 @@ import (
 	"go.dedis.ch/dela/core/execution"
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/store"
 	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/dela/serde/json"
 	"golang.org/x/xerrors"
@@ -24,6 +27,10 @@ const (
 	// AuthorityArg is the key of the argument for the new authority.
 	AuthorityArg = "viewchange:authority"
 
+	// CertificateArg is the key of the argument for the identity certificate
+	// of the new member.
+	CertificateArg = "viewchange:certificate"
+
 	messageOnlyOne          = "only one view change per block is allowed"
 	messageArgMissing       = "authority not found in transaction"
 	messageStorageEmpty     = "authority not found in storage"
@@ -32,6 +39,9 @@ const (
 	messageStorageFailure   = "storage failure"
 	messageDuplicate        = "duplicate in roster"
 	messageUnauthorized     = "unauthorized identity"
+	messageCertMissing      = "certificate not found in transaction"
+	messageCertInvalid      = "invalid certificate"
+	messageCAMissing        = "certificate authority not found"
 )
 
 // RegisterContract registers the view change contract to the given execution
@@ -63,15 +73,34 @@ func NewManager(mgr txn.Manager) Manager {
 // Make creates a new transaction using the provided manager. It contains the
 // new roster that the transaction should apply.
 func (mgr Manager) Make(roster authority.Authority) (txn.Transaction, error) {
+	return mgr.make(roster)
+}
+
+// MakeWithCertificate creates a new transaction like Make, that also contains
+// the identity certificate of the new member.
+func (mgr Manager) MakeWithCertificate(roster authority.Authority,
+	cert enrollment.Certificate) (txn.Transaction, error) {
+
+	data, err := cert.Serialize(mgr.context)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize certificate: %v", err)
+	}
+
+	return mgr.make(roster, txn.Arg{Key: CertificateArg, Value: data})
+}
+
+func (mgr Manager) make(roster authority.Authority, args ...txn.Arg) (txn.Transaction, error) {
 	data, err := roster.Serialize(mgr.context)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to serialize roster: %v", err)
 	}
 
-	tx, err := mgr.manager.Make(
-		txn.Arg{Key: native.ContractArg, Value: []byte(ContractName)},
-		txn.Arg{Key: AuthorityArg, Value: data},
-	)
+	args = append([]txn.Arg{
+		{Key: native.ContractArg, Value: []byte(ContractName)},
+		{Key: AuthorityArg, Value: data},
+	}, args...)
+
+	tx, err := mgr.manager.Make(args...)
 	if err != nil {
 		return nil, xerrors.Errorf("creating transaction: %v", err)
 	}
@@ -79,6 +108,24 @@ func (mgr Manager) Make(roster authority.Authority) (txn.Transaction, error) {
 	return tx, nil
 }
 
+// ContractOption is the type of option to set some fields of the contract.
+type ContractOption func(*Contract)
+
+// AuthorityReader is the function that returns the public key of the
+// authority that issues the identity certificates of the chain, or nil if the
+// chain does not require them.
+type AuthorityReader func() (crypto.PublicKey, error)
+
+// WithAuthority sets the reader of the authority that issues the identity
+// certificates. When the chain has an authority, a new member must provide a
+// certificate that binds its address to its public key.
+func WithAuthority(read AuthorityReader, fac enrollment.Factory) ContractOption {
+	return func(c *Contract) {
+		c.readCA = read
+		c.certFac = fac
+	}
+}
+
 // Contract is a contract to update the roster at a given key in the storage. It
 // only allows one member change per transaction.
 //
@@ -89,17 +136,27 @@ type Contract struct {
 	accessKey []byte
 	access    access.Service
 	context   serde.Context
+	readCA    AuthorityReader
+	certFac   enrollment.Factory
 }
 
 // NewContract creates a new viewchange contract.
-func NewContract(rKey, aKey []byte, rFac authority.Factory, srvc access.Service) Contract {
-	return Contract{
+func NewContract(rKey, aKey []byte, rFac authority.Factory, srvc access.Service,
+	opts ...ContractOption) Contract {
+
+	c := Contract{
 		rosterKey: rKey,
 		rosterFac: rFac,
 		accessKey: aKey,
 		access:    srvc,
 		context:   json.NewContext(),
 	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
 }
 
 // Execute implements native.Contract. It looks for the roster in the
@@ -148,6 +205,11 @@ func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
 		}
 	}
 
+	err = c.checkNewMembers(step.Current, roster, changeset.GetNewAddresses())
+	if err != nil {
+		return err
+	}
+
 	creds := NewCreds(c.accessKey)
 
 	err = c.access.Match(snap, creds, step.Current.GetIdentity())
@@ -167,6 +229,65 @@ func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
 	return nil
 }
 
+// checkNewMembers returns nil if the chain has no authority, or if the
+// transaction contains a certificate of the authority for every new member,
+// otherwise an error.
+func (c Contract) checkNewMembers(tx txn.Transaction, roster authority.Authority,
+	addrs []mino.Address) error {
+
+	if c.readCA == nil || len(addrs) == 0 {
+		return nil
+	}
+
+	ca, err := c.readCA()
+	if err != nil {
+		reportErr(tx, xerrors.Errorf("reading authority: %v", err))
+
+		return xerrors.New(messageCAMissing)
+	}
+
+	if ca == nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		pubkey, _ := roster.GetPublicKey(addr)
+
+		err = c.checkCertificate(tx, ca, addr, pubkey)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCertificate returns nil if the transaction contains a certificate of
+// the authority for the address and the public key, otherwise an error.
+func (c Contract) checkCertificate(tx txn.Transaction, ca crypto.PublicKey,
+	addr mino.Address, pubkey crypto.PublicKey) error {
+
+	cert, err := c.certFac.CertificateOf(c.context, tx.GetArg(CertificateArg))
+	if err != nil {
+		reportErr(tx, xerrors.Errorf("incoming certificate: %v", err))
+
+		return xerrors.New(messageCertMissing)
+	}
+
+	if !cert.GetAddress().Equal(addr) || !cert.GetPublicKey().Equal(pubkey) {
+		return xerrors.Errorf("%s: not issued to %v", messageCertInvalid, addr)
+	}
+
+	err = cert.Verify(ca)
+	if err != nil {
+		reportErr(tx, xerrors.Errorf("certificate: %v", err))
+
+		return xerrors.Errorf("%s: %v", messageCertInvalid, addr)
+	}
+
+	return nil
+}
+
 // reportErr prints a log with the actual error while the transaction will
 // contain a simplified explanation.
 func reportErr(tx txn.Transaction, err error) {
@@ -8,9 +8,12 @@ import (
 	"go.dedis.ch/dela/core/execution"
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/store"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/serde"
 )
@@ -37,6 +40,23 @@ func TestNewTransaction(t *testing.T) {
 	require.EqualError(t, err, fake.Err("creating transaction"))
 }
 
+func TestNewTransaction_WithCertificate(t *testing.T) {
+	mgr := NewManager(signed.NewManager(fake.NewSigner(), nil))
+
+	cert := enrollment.NewCertificate(fake.NewAddress(1), fake.PublicKey{}, fake.Signature{})
+
+	tx, err := mgr.MakeWithCertificate(authority.New(nil, nil), cert)
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(tx.GetArg(AuthorityArg)))
+	require.NotEmpty(t, tx.GetArg(CertificateArg))
+
+	cert = enrollment.NewCertificate(fake.NewBadAddress(), fake.PublicKey{}, fake.Signature{})
+
+	_, err = mgr.MakeWithCertificate(authority.New(nil, nil), cert)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to serialize certificate: ", err.Error())
+}
+
 func TestContract_Execute(t *testing.T) {
 	fac := authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 
@@ -75,9 +95,74 @@ func TestContract_Execute(t *testing.T) {
 	require.EqualError(t, err, "unauthorized identity: fake.PublicKey")
 }
 
+func TestContract_Certificate_Execute(t *testing.T) {
+	ca := bls.Generate()
+
+	cert, err := enrollment.Issue(ca, fake.NewAddress(1), fake.PublicKey{})
+	require.NoError(t, err)
+
+	fac := authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+
+	contract := NewContract([]byte("roster"), []byte("access"), fac, fakeAccess{},
+		WithAuthority(readAuthority(ca.GetPublicKey(), nil), fakeCertFac{cert: cert}))
+
+	roster := `[{},{"Address":"AQAAAA=="}]`
+
+	err = contract.Execute(fakeStore{}, makeStep(t, roster))
+	require.NoError(t, err)
+
+	// A chain without authority does not require a certificate.
+	contract.readCA = readAuthority(nil, nil)
+	contract.certFac = fakeCertFac{err: fake.GetError()}
+	err = contract.Execute(fakeStore{}, makeStep(t, roster))
+	require.NoError(t, err)
+
+	contract.readCA = readAuthority(nil, fake.GetError())
+	err = contract.Execute(fakeStore{}, makeStep(t, roster))
+	require.EqualError(t, err, messageCAMissing)
+
+	contract.readCA = readAuthority(ca.GetPublicKey(), nil)
+
+	// Removing a member does not require a certificate.
+	err = contract.Execute(fakeStore{}, makeStep(t, "[]"))
+	require.NoError(t, err)
+
+	err = contract.Execute(fakeStore{}, makeStep(t, roster))
+	require.EqualError(t, err, messageCertMissing)
+
+	other, err := enrollment.Issue(ca, fake.NewAddress(2), fake.PublicKey{})
+	require.NoError(t, err)
+
+	contract.certFac = fakeCertFac{cert: other}
+	err = contract.Execute(fakeStore{}, makeStep(t, roster))
+	require.EqualError(t, err, "invalid certificate: not issued to fake.Address[1]")
+
+	contract.certFac = fakeCertFac{cert: cert}
+	contract.readCA = readAuthority(bls.Generate().GetPublicKey(), nil)
+	err = contract.Execute(fakeStore{}, makeStep(t, roster))
+	require.EqualError(t, err, "invalid certificate: fake.Address[1]")
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
+func readAuthority(ca crypto.PublicKey, err error) AuthorityReader {
+	return func() (crypto.PublicKey, error) {
+		return ca, err
+	}
+}
+
+type fakeCertFac struct {
+	enrollment.Factory
+
+	cert enrollment.Certificate
+	err  error
+}
+
+func (fac fakeCertFac) CertificateOf(serde.Context, []byte) (enrollment.Certificate, error) {
+	return fac.cert, fac.err
+}
+
 func makeStep(t *testing.T, arg string) execution.Step {
 	return execution.Step{Current: makeTx(t, arg)}
 }
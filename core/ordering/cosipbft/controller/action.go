@@ -6,22 +6,34 @@
 package controller
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/hex"
+	gojson "encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/execution"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
 	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/viewchange"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
+	"go.dedis.ch/dela/core/validation"
 	"go.dedis.ch/dela/cosi"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/proxy"
+	"go.dedis.ch/dela/serde/json"
 	"golang.org/x/xerrors"
 )
 
@@ -34,7 +46,17 @@ type Service interface {
 
 	GetRoster() (authority.Authority, error)
 
-	Setup(ctx context.Context, ca crypto.CollectiveAuthority) error
+	Setup(ctx context.Context, ca crypto.CollectiveAuthority, opts ...types.GenesisOption) error
+
+	Synchronize(ctx context.Context) error
+}
+
+// Discovery is the expected interface of the service that learns the roster
+// from the seed nodes.
+type Discovery interface {
+	Announce(ctx context.Context, seed mino.Address, seedKey crypto.PublicKey) (authority.Authority, error)
+
+	Fetch(ctx context.Context, seed mino.Address, seedKey crypto.PublicKey) (authority.Authority, error)
 }
 
 // SetupAction is an action to create a new chain with a list of participants.
@@ -43,25 +65,71 @@ type Service interface {
 type setupAction struct{}
 
 // Execute implements node.ActionTemplate. It reads the list of members and
-// request the setup to the service.
+// relays, adds the rosters of the seeds if any, and request the setup to the
+// service with the threshold, the ordering policy and the authority of the
+// chain.
 func (a setupAction) Execute(ctx node.Context) error {
-	roster, err := a.readMembers(ctx)
+	var srvc Service
+	err := ctx.Injector.Resolve(&srvc)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	roster, err := a.readMembers(ctx, "member")
 	if err != nil {
 		return xerrors.Errorf("failed to read roster: %v", err)
 	}
 
-	var srvc Service
-	err = ctx.Injector.Resolve(&srvc)
+	relays, err := a.readMembers(ctx, "relay")
 	if err != nil {
-		return xerrors.Errorf("injector: %v", err)
+		return xerrors.Errorf("failed to read relays: %v", err)
 	}
 
+	roster = union(roster, relays)
+
 	timeout := ctx.Flags.Duration("timeout")
 
 	setupCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	err = srvc.Setup(setupCtx, roster)
+	roster, err = a.readSeeds(ctx, setupCtx, roster)
+	if err != nil {
+		return xerrors.Errorf("failed to read seeds: %v", err)
+	}
+
+	if roster.Len() == 0 {
+		return xerrors.New("no member nor seed")
+	}
+
+	addrs := make([]mino.Address, 0, relays.Len())
+
+	iter := relays.AddressIterator()
+	for iter.HasNext() {
+		addrs = append(addrs, iter.GetNext())
+	}
+
+	opts := []types.GenesisOption{
+		types.WithRelays(addrs...),
+		types.WithThreshold(ctx.Flags.Int("threshold")),
+		types.WithOrdering(ctx.Flags.String("ordering")),
+	}
+
+	if ctx.Flags.String("authority") != "" {
+		var c cosi.CollectiveSigning
+		err = ctx.Injector.Resolve(&c)
+		if err != nil {
+			return xerrors.Errorf("injector: %v", err)
+		}
+
+		ca, err := makeAuthority(ctx.Flags.String("authority"), c.GetPublicKeyFactory())
+		if err != nil {
+			return xerrors.Errorf("invalid authority: %v", err)
+		}
+
+		opts = append(opts, types.WithAuthority(ca))
+	}
+
+	err = srvc.Setup(setupCtx, roster, opts...)
 	if err != nil {
 		return xerrors.Errorf("failed to setup: %v", err)
 	}
@@ -69,8 +137,9 @@ func (a setupAction) Execute(ctx node.Context) error {
 	return nil
 }
 
-func (a setupAction) readMembers(ctx node.Context) (authority.Authority, error) {
-	members := ctx.Flags.StringSlice("member")
+// readMembers returns the roster of the members of the flag.
+func (a setupAction) readMembers(ctx node.Context, flag string) (authority.Authority, error) {
+	members := ctx.Flags.StringSlice(flag)
 
 	addrs := make([]mino.Address, len(members))
 	pubkeys := make([]crypto.PublicKey, len(members))
@@ -88,6 +157,101 @@ func (a setupAction) readMembers(ctx node.Context) (authority.Authority, error)
 	return authority.New(addrs, pubkeys), nil
 }
 
+// readSeeds fetches the roster of each seed and returns the roster with the
+// members of the seeds that are missing.
+func (a setupAction) readSeeds(ctx node.Context, fetchCtx context.Context,
+	roster authority.Authority) (authority.Authority, error) {
+
+	seeds := ctx.Flags.StringSlice("seed")
+	if len(seeds) == 0 {
+		return roster, nil
+	}
+
+	var disc Discovery
+	err := ctx.Injector.Resolve(&disc)
+	if err != nil {
+		return nil, xerrors.Errorf("injector: %v", err)
+	}
+
+	for _, seed := range seeds {
+		addr, pubkey, err := decodeMember(ctx, seed)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode: %v", err)
+		}
+
+		other, err := disc.Fetch(fetchCtx, addr, pubkey)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to fetch roster of %v: %v", addr, err)
+		}
+
+		roster = union(roster, other)
+	}
+
+	return roster, nil
+}
+
+// AnnounceAction is an action to announce the node to the seeds, so that they
+// can provide it to the nodes setting up a chain.
+//
+// - implements node.ActionTemplate
+type announceAction struct{}
+
+// Execute implements node.ActionTemplate. It announces the node to each seed
+// and prints the size of the roster learnt from them.
+func (announceAction) Execute(ctx node.Context) error {
+	var disc Discovery
+	err := ctx.Injector.Resolve(&disc)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	timeout := ctx.Flags.Duration("timeout")
+
+	announceCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var roster authority.Authority = authority.New(nil, nil)
+
+	for _, seed := range ctx.Flags.StringSlice("seed") {
+		addr, pubkey, err := decodeMember(ctx, seed)
+		if err != nil {
+			return xerrors.Errorf("failed to decode: %v", err)
+		}
+
+		other, err := disc.Announce(announceCtx, addr, pubkey)
+		if err != nil {
+			return xerrors.Errorf("failed to announce to %v: %v", addr, err)
+		}
+
+		roster = union(roster, other)
+	}
+
+	fmt.Fprintf(ctx.Out, "✅ Announced to the seeds, %d member(s) known.\n", roster.Len())
+
+	return nil
+}
+
+// union returns the roster with the members of the other roster that it does
+// not contain yet.
+func union(roster, other authority.Authority) authority.Authority {
+	cset := authority.NewChangeSet()
+
+	addrIter := other.AddressIterator()
+	pkIter := other.PublicKeyIterator()
+
+	for addrIter.HasNext() && pkIter.HasNext() {
+		addr := addrIter.GetNext()
+		pubkey := pkIter.GetNext()
+
+		known, _ := roster.GetPublicKey(addr)
+		if known == nil {
+			cset.Add(addr, pubkey)
+		}
+	}
+
+	return roster.Apply(cset)
+}
+
 // ExportAction is an action to display a base64 string describing the node. It
 // can be used to transmit the identity of a node to another one.
 //
@@ -127,6 +291,355 @@ func (a exportAction) Execute(ctx node.Context) error {
 	return nil
 }
 
+// CertIssueAction is an action to issue the identity certificate of a member,
+// with the key of the node acting as the authority.
+//
+// - implements node.ActionTemplate
+type certIssueAction struct{}
+
+// Execute implements node.ActionTemplate. It signs the address and the public
+// key of the member and prints the base64 certificate.
+func (certIssueAction) Execute(ctx node.Context) error {
+	addr, pubkey, err := decodeMember(ctx, ctx.Flags.String("member"))
+	if err != nil {
+		return xerrors.Errorf("failed to decode member: %v", err)
+	}
+
+	var c cosi.CollectiveSigning
+	err = ctx.Injector.Resolve(&c)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	cert, err := enrollment.Issue(c.GetSigner(), addr, pubkey)
+	if err != nil {
+		return xerrors.Errorf("failed to issue: %v", err)
+	}
+
+	data, err := cert.Serialize(json.NewContext())
+	if err != nil {
+		return xerrors.Errorf("failed to serialize: %v", err)
+	}
+
+	fmt.Fprint(ctx.Out, base64.StdEncoding.EncodeToString(data))
+
+	return nil
+}
+
+// CertImportAction is an action to import the identity certificate issued to
+// the node. It is stored next to the private key so that it is loaded when the
+// node restarts.
+//
+// - implements node.ActionTemplate
+type certImportAction struct{}
+
+// Execute implements node.ActionTemplate. It checks that the certificate is
+// issued to the node, stores it and presents it from now on.
+func (certImportAction) Execute(ctx node.Context) error {
+	data, cert, err := decodeCertificate(ctx, ctx.Flags.String("certificate"))
+	if err != nil {
+		return xerrors.Errorf("failed to decode certificate: %v", err)
+	}
+
+	var m mino.Mino
+	err = ctx.Injector.Resolve(&m)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var c cosi.CollectiveSigning
+	err = ctx.Injector.Resolve(&c)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	if !m.GetAddress().Equal(cert.GetAddress()) ||
+		!c.GetSigner().GetPublicKey().Equal(cert.GetPublicKey()) {
+		return xerrors.Errorf("certificate issued to %v", cert.GetAddress())
+	}
+
+	var holder *enrollment.Holder
+	err = ctx.Injector.Resolve(&holder)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	path := filepath.Join(ctx.Flags.Path("config"), certificateFile)
+
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return xerrors.Errorf("failed to write file: %v", err)
+	}
+
+	holder.Set(cert)
+
+	fmt.Fprintln(ctx.Out, "✅ Certificate imported.")
+
+	return nil
+}
+
+// ChainExportAction is an action to write the chain of the node to an archive
+// file.
+//
+// - implements node.ActionTemplate
+type chainExportAction struct{}
+
+// Execute implements node.ActionTemplate. It writes the genesis block and the
+// blocks of the node to the file.
+func (chainExportAction) Execute(ctx node.Context) error {
+	var genstore blockstore.GenesisStore
+	err := ctx.Injector.Resolve(&genstore)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var blocks blockstore.BlockStore
+	err = ctx.Injector.Resolve(&blocks)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	genesis, err := genstore.Get()
+	if err != nil {
+		return xerrors.Errorf("failed to get genesis: %v", err)
+	}
+
+	path := ctx.Flags.String("out")
+
+	file, err := os.Create(path)
+	if err != nil {
+		return xerrors.Errorf("failed to create file: %v", err)
+	}
+
+	num, err := blockstore.WriteArchive(file, genesis, blocks)
+	if err != nil {
+		file.Close()
+		return xerrors.Errorf("failed to write archive: %v", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return xerrors.Errorf("failed to close file: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "exported %d block(s) to %s from genesis %x\n",
+		num, path, genesis.GetHash().Bytes())
+
+	return nil
+}
+
+// ChainVerifyAction is an action to verify an archive file created by the
+// export action. It checks the integrity of the file, that the genesis block is
+// the trusted one, and that the chain is signed by the roster defined in the
+// genesis block and its updates.
+//
+// - implements node.ActionTemplate
+type chainVerifyAction struct{}
+
+// Execute implements node.ActionTemplate. It reads and verifies the archive.
+func (chainVerifyAction) Execute(ctx node.Context) error {
+	var m mino.Mino
+	err := ctx.Injector.Resolve(&m)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var c cosi.CollectiveSigning
+	err = ctx.Injector.Resolve(&c)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var vs validation.Service
+	err = ctx.Injector.Resolve(&vs)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	trusted, err := decodeDigest(ctx.Flags.String("genesis"))
+	if err != nil {
+		return xerrors.Errorf("invalid genesis digest: %v", err)
+	}
+
+	genFac, linkFac := makeFactories(m, c, vs)
+
+	file, err := os.Open(ctx.Flags.String("in"))
+	if err != nil {
+		return xerrors.Errorf("failed to open file: %v", err)
+	}
+
+	defer file.Close()
+
+	archive, err := blockstore.ReadArchive(bufio.NewReader(file), genFac, linkFac)
+	if err != nil {
+		return xerrors.Errorf("failed to read archive: %v", err)
+	}
+
+	err = archive.Verify(trusted, c.GetVerifierFactory())
+	if err != nil {
+		return xerrors.Errorf("failed to verify archive: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "verified %d block(s) from genesis %v\n",
+		len(archive.Links), archive.Genesis.GetHash())
+
+	return nil
+}
+
+// decodeDigest returns the digest of its hexadecimal representation.
+func decodeDigest(str string) (types.Digest, error) {
+	var digest types.Digest
+
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		return digest, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	if len(data) != len(digest) {
+		return digest, xerrors.Errorf("%d bytes instead of %d", len(data), len(digest))
+	}
+
+	copy(digest[:], data)
+
+	return digest, nil
+}
+
+// ChainProofAction is an action to write the proof of a block to a file. The
+// proof includes the genesis block so that it can be verified without a node,
+// by a verifier that trusts the digest of the genesis block.
+//
+// - implements node.ActionTemplate
+type chainProofAction struct{}
+
+// Execute implements node.ActionTemplate. It writes the proof of the block at
+// the given index.
+func (chainProofAction) Execute(ctx node.Context) error {
+	var genstore blockstore.GenesisStore
+	err := ctx.Injector.Resolve(&genstore)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var blocks blockstore.BlockStore
+	err = ctx.Injector.Resolve(&blocks)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	genesis, err := genstore.Get()
+	if err != nil {
+		return xerrors.Errorf("failed to get genesis: %v", err)
+	}
+
+	index := uint64(ctx.Flags.Int("index"))
+
+	proof, err := blockstore.GetProof(genesis, blocks, index)
+	if err != nil {
+		return xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	data, err := proof.Encode()
+	if err != nil {
+		return xerrors.Errorf("failed to encode proof: %v", err)
+	}
+
+	path := ctx.Flags.String("out")
+
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return xerrors.Errorf("failed to write file: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "wrote the proof of block %d to %s from genesis %x\n",
+		index, path, genesis.GetHash().Bytes())
+
+	return nil
+}
+
+// StateExportAction is an action to write the diffs of the state of the blocks
+// to a file, one JSON object per line and per block.
+//
+// - implements node.ActionTemplate
+type stateExportAction struct{}
+
+// Execute implements node.ActionTemplate. It writes the diffs of the blocks
+// from the given index.
+func (stateExportAction) Execute(ctx node.Context) error {
+	var journal *execution.Journal
+	err := ctx.Injector.Resolve(&journal)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	path := ctx.Flags.String("out")
+
+	file, err := os.Create(path)
+	if err != nil {
+		return xerrors.Errorf("failed to create file: %v", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	enc := gojson.NewEncoder(writer)
+
+	num := 0
+
+	err = journal.Export(uint64(ctx.Flags.Int("from")), func(diff execution.Diff) error {
+		num++
+		return enc.Encode(diff)
+	})
+	if err != nil {
+		file.Close()
+		return xerrors.Errorf("failed to export: %v", err)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		file.Close()
+		return xerrors.Errorf("failed to write file: %v", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return xerrors.Errorf("failed to close file: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "exported the state of %d block(s) to %s\n", num, path)
+
+	return nil
+}
+
+// ExplorerAction is an action to register the handlers of the block explorer
+// on the proxy.
+//
+// - implements node.ActionTemplate
+type explorerAction struct{}
+
+// Execute implements node.ActionTemplate. It registers the handlers of the
+// blocks and the transactions.
+func (explorerAction) Execute(ctx node.Context) error {
+	var proxyhttp proxy.Proxy
+	err := ctx.Injector.Resolve(&proxyhttp)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var blocks blockstore.BlockStore
+	err = ctx.Injector.Resolve(&blocks)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	blocksPath := ctx.Flags.String("blocksPath")
+	txPath := ctx.Flags.String("txPath")
+
+	proxyhttp.RegisterHandler(blocksPath, blocksHandler(blocksPath, json.NewContext(), blocks))
+	proxyhttp.RegisterHandler(txPath, txHandler(txPath, json.NewContext(), blocks))
+
+	fmt.Fprintf(ctx.Out, "registered explorer handlers on %q and %q", blocksPath, txPath)
+
+	return nil
+}
+
 // RosterAddAction is an action to require a roster change in the change by
 // adding a new member.
 //
@@ -197,6 +710,35 @@ func (rosterAddAction) Execute(ctx node.Context) error {
 	return nil
 }
 
+// syncAction is an action to send the latest blocks to the participants of the
+// chain, so that a node behind can catch up.
+//
+// - implements node.ActionTemplate
+type syncAction struct{}
+
+// Execute implements node.ActionTemplate. It requests a synchronization to the
+// service and waits for it to be done.
+func (syncAction) Execute(ctx node.Context) error {
+	var srvc Service
+	err := ctx.Injector.Resolve(&srvc)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	syncCtx, cancel := context.WithTimeout(context.Background(),
+		ctx.Flags.Duration("timeout"))
+	defer cancel()
+
+	err = srvc.Synchronize(syncCtx)
+	if err != nil {
+		return xerrors.Errorf("failed to synchronize: %v", err)
+	}
+
+	fmt.Fprintln(ctx.Out, "✅ Participants synchronized.")
+
+	return nil
+}
+
 func prepareRosterTx(ctx node.Context, srvc Service) (txn.Transaction, error) {
 	roster, err := srvc.GetRoster()
 	if err != nil {
@@ -216,7 +758,24 @@ func prepareRosterTx(ctx node.Context, srvc Service) (txn.Transaction, error) {
 		return nil, xerrors.Errorf("txn manager: %v", err)
 	}
 
-	tx, err := viewchange.NewManager(mgr).Make(roster.Apply(cset))
+	vcMgr := viewchange.NewManager(mgr)
+
+	var tx txn.Transaction
+
+	str := ctx.Flags.String("certificate")
+	if str == "" {
+		tx, err = vcMgr.Make(roster.Apply(cset))
+	} else {
+		var cert enrollment.Certificate
+
+		_, cert, err = decodeCertificate(ctx, str)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode certificate: %v", err)
+		}
+
+		tx, err = vcMgr.MakeWithCertificate(roster.Apply(cset), cert)
+	}
+
 	if err != nil {
 		return nil, xerrors.Errorf("transaction: %v", err)
 	}
@@ -280,3 +839,31 @@ func decodeMember(ctx node.Context, str string) (mino.Address, crypto.PublicKey,
 
 	return addr, pubkey, nil
 }
+
+// decodeCertificate returns the raw data and the certificate of its base64
+// representation.
+func decodeCertificate(ctx node.Context, str string) ([]byte, enrollment.Certificate, error) {
+	data, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, enrollment.Certificate{}, xerrors.Errorf("base64: %v", err)
+	}
+
+	var m mino.Mino
+	err = ctx.Injector.Resolve(&m)
+	if err != nil {
+		return nil, enrollment.Certificate{}, xerrors.Errorf("injector: %v", err)
+	}
+
+	var c cosi.CollectiveSigning
+	err = ctx.Injector.Resolve(&c)
+	if err != nil {
+		return nil, enrollment.Certificate{}, xerrors.Errorf("injector: %v", err)
+	}
+
+	cert, err := makeCertificateFactory(m, c).CertificateOf(json.NewContext(), data)
+	if err != nil {
+		return nil, enrollment.Certificate{}, err
+	}
+
+	return data, cert, nil
+}
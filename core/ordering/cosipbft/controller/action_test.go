@@ -3,21 +3,35 @@ package controller
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/core/access"
+	"go.dedis.ch/dela/core/execution"
+	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/core/txn/pool/mem"
+	"go.dedis.ch/dela/core/txn/signed"
 	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/core/validation/simple"
 	"go.dedis.ch/dela/cosi"
+	"go.dedis.ch/dela/cosi/threshold"
 	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
 )
@@ -33,6 +47,40 @@ func TestSetupAction_Execute(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, calls.Len())
 	require.Equal(t, 2, calls.Get(0, 1).(mino.Players).Len())
+	require.Empty(t, calls.Get(0, 2).(types.Genesis).GetRelays())
+	require.Empty(t, calls.Get(0, 2).(types.Genesis).GetOrdering())
+	require.Equal(t, 0, calls.Get(0, 2).(types.Genesis).GetThreshold())
+
+	// The relays are added to the roster unless they are already members.
+	ctx.Flags.(node.FlagSet)["relay"] = []interface{}{"YQ==:YQ=="}
+	ctx.Flags.(node.FlagSet)["ordering"] = "beacon"
+	ctx.Flags.(node.FlagSet)["threshold"] = 1
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls.Len())
+	require.Equal(t, 2, calls.Get(1, 1).(mino.Players).Len())
+	require.Len(t, calls.Get(1, 2).(types.Genesis).GetRelays(), 1)
+	require.Equal(t, "beacon", calls.Get(1, 2).(types.Genesis).GetOrdering())
+	require.Equal(t, 1, calls.Get(1, 2).(types.Genesis).GetThreshold())
+	require.Nil(t, calls.Get(1, 2).(types.Genesis).GetAuthority())
+
+	ctx.Flags.(node.FlagSet)["authority"] = "YQ=="
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fake.PublicKey{}, calls.Get(2, 2).(types.Genesis).GetAuthority())
+
+	ctx.Flags.(node.FlagSet)["authority"] = "a"
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"invalid authority: base64 public key: illegal base64 data at input byte 0")
+
+	delete(ctx.Flags.(node.FlagSet), "authority")
+
+	ctx.Flags.(node.FlagSet)["relay"] = []interface{}{""}
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to read relays: failed to decode: invalid member base64 string")
 
 	ctx.Flags.(node.FlagSet)["member"] = []interface{}{""}
 	err = action.Execute(ctx)
@@ -45,9 +93,93 @@ func TestSetupAction_Execute(t *testing.T) {
 
 	ctx.Injector.Inject(fakeService{err: fake.GetError()})
 	err = action.Execute(ctx)
+	require.EqualError(t, err, "no member nor seed")
+
+	ctx = prepContext(nil)
+	ctx.Injector.Inject(fakeService{err: fake.GetError()})
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{"YQ==:YQ=="}
+	err = action.Execute(ctx)
 	require.EqualError(t, err, fake.Err("failed to setup"))
 }
 
+func TestSetupAction_Seeds_Execute(t *testing.T) {
+	action := setupAction{}
+
+	calls := &fake.Call{}
+	ctx := prepContext(calls)
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{"AAAAAA==:YQ=="}
+	ctx.Flags.(node.FlagSet)["seed"] = []interface{}{"AQAAAA==:YQ=="}
+
+	roster := authority.New(
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2)},
+		[]crypto.PublicKey{fake.PublicKey{}, fake.PublicKey{}, fake.PublicKey{}},
+	)
+
+	ctx.Injector.Inject(fakeDiscovery{roster: roster})
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, calls.Len())
+	require.Equal(t, 3, calls.Get(0, 1).(mino.Players).Len())
+
+	// Without members, the roster of the seeds is used.
+	delete(ctx.Flags.(node.FlagSet), "member")
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, calls.Get(1, 1).(mino.Players).Len())
+
+	ctx.Flags.(node.FlagSet)["seed"] = []interface{}{""}
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"failed to read seeds: failed to decode: invalid member base64 string")
+
+	ctx.Flags.(node.FlagSet)["seed"] = []interface{}{"AQAAAA==:YQ=="}
+	ctx.Injector.Inject(fakeDiscovery{err: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		fake.Err("failed to read seeds: failed to fetch roster of fake.Address[1]"))
+
+	ctx = prepContext(calls)
+	ctx.Flags.(node.FlagSet)["seed"] = []interface{}{"AQAAAA==:YQ=="}
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"failed to read seeds: injector: couldn't find dependency for 'controller.Discovery'")
+}
+
+func TestAnnounceAction_Execute(t *testing.T) {
+	action := announceAction{}
+
+	out := new(bytes.Buffer)
+
+	ctx := prepContext(nil)
+	ctx.Out = out
+	ctx.Flags.(node.FlagSet)["seed"] = []interface{}{"AAAAAA==:YQ==", "AQAAAA==:YQ=="}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'controller.Discovery'")
+
+	roster := authority.New(
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]crypto.PublicKey{fake.PublicKey{}, fake.PublicKey{}},
+	)
+
+	ctx.Injector.Inject(fakeDiscovery{roster: roster})
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "✅ Announced to the seeds, 2 member(s) known.\n", out.String())
+
+	ctx.Flags.(node.FlagSet)["seed"] = []interface{}{""}
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to decode: invalid member base64 string")
+
+	ctx.Flags.(node.FlagSet)["seed"] = []interface{}{"AQAAAA==:YQ=="}
+	ctx.Injector.Inject(fakeDiscovery{err: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to announce to fake.Address[1]"))
+}
+
 func TestExportAction_Execute(t *testing.T) {
 	action := exportAction{}
 
@@ -77,6 +209,333 @@ func TestExportAction_Execute(t *testing.T) {
 	require.EqualError(t, err, fake.Err("failed to marshal public key"))
 }
 
+func TestCertIssueAction_Execute(t *testing.T) {
+	action := certIssueAction{}
+
+	ctx := prepContext(nil)
+	ctx.Flags.(node.FlagSet)["member"] = "YQ==:YQ=="
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+
+	_, cert, err := decodeCertificate(ctx, buffer.String())
+	require.NoError(t, err)
+	require.Equal(t, fake.NewAddress(0), cert.GetAddress())
+	require.NoError(t, cert.Verify(fake.PublicKey{}))
+
+	ctx.Flags.(node.FlagSet)["member"] = "a"
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to decode member: invalid member base64 string")
+
+	ctx.Flags.(node.FlagSet)["member"] = "YQ==:YQ=="
+	ctx.Injector = node.NewInjector()
+	ctx.Injector.Inject(fake.Mino{})
+	ctx.Injector.Inject(fakeCosi{signer: fake.NewBadSigner()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to issue: failed to sign"))
+}
+
+func TestCertImportAction_Execute(t *testing.T) {
+	action := certImportAction{}
+
+	dir := t.TempDir()
+
+	ctx := prepContext(nil)
+	ctx.Flags.(node.FlagSet)["config"] = dir
+	ctx.Flags.(node.FlagSet)["certificate"] = issueCertificate(t, "YQ==:YQ==")
+
+	holder := enrollment.NewHolder()
+	ctx.Injector.Inject(holder)
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "✅ Certificate imported.\n", buffer.String())
+	require.NotNil(t, holder.Get())
+	require.FileExists(t, filepath.Join(dir, certificateFile))
+
+	certs, err := loadCertificate(ctx.Flags, makeCertificateFactory(fake.Mino{}, fakeCosi{}))
+	require.NoError(t, err)
+	require.Equal(t, holder.Get(), certs.Get())
+
+	ctx.Flags.(node.FlagSet)["certificate"] = "a"
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"failed to decode certificate: base64: illegal base64 data at input byte 0")
+
+	ctx.Flags.(node.FlagSet)["certificate"] = "bm90IGpzb24="
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to decode certificate: failed to decode: ", err.Error())
+
+	ctx.Flags.(node.FlagSet)["certificate"] = issueCertificate(t, "AQAAAA==:YQ==")
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "certificate issued to fake.Address[1]")
+
+	ctx.Flags.(node.FlagSet)["certificate"] = issueCertificate(t, "YQ==:YQ==")
+	ctx.Flags.(node.FlagSet)["config"] = filepath.Join(dir, "unknown")
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to write file: ", err.Error())
+
+	ctx = prepContext(nil)
+	ctx.Flags.(node.FlagSet)["certificate"] = issueCertificate(t, "YQ==:YQ==")
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for '*enrollment.Holder'")
+}
+
+func TestChainExportAction_Execute(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "chain.bin")
+
+	genstore := blockstore.NewGenesisStore()
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    node.FlagSet{"out": path},
+		Out:      new(bytes.Buffer),
+	}
+
+	action := chainExportAction{}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'blockstore.GenesisStore'")
+
+	ctx.Injector.Inject(genstore)
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'blockstore.BlockStore'")
+
+	ctx.Injector.Inject(blockstore.NewInMemory())
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to get genesis: missing genesis block")
+
+	genesis := makeGenesis(t)
+	require.NoError(t, genstore.Set(genesis))
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("exported 0 block(s) to %s from genesis %x\n",
+		path, genesis.GetHash().Bytes()), ctx.Out.(*bytes.Buffer).String())
+	require.FileExists(t, path)
+
+	ctx.Flags = node.FlagSet{"out": filepath.Join(dir, "unknown", "chain.bin")}
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to create file: ", err.Error())
+}
+
+func TestChainProofAction_Execute(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "proof.json")
+
+	genesis := makeGenesis(t)
+	genstore := blockstore.NewGenesisStore()
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    node.FlagSet{"out": path, "index": 0},
+		Out:      new(bytes.Buffer),
+	}
+
+	action := chainProofAction{}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'blockstore.GenesisStore'")
+
+	ctx.Injector.Inject(genstore)
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'blockstore.BlockStore'")
+
+	blocks := blockstore.NewInMemory()
+	ctx.Injector.Inject(blocks)
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to get genesis: missing genesis block")
+
+	require.NoError(t, genstore.Set(genesis))
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to get proof: block 0 not found: no block")
+
+	block, err := types.NewBlock(simple.NewResult(nil))
+	require.NoError(t, err)
+	link, err := types.NewBlockLink(genesis.GetHash(), block,
+		types.WithSignatures(fake.Signature{}, fake.Signature{}))
+	require.NoError(t, err)
+	require.NoError(t, blocks.Store(link))
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("wrote the proof of block 0 to %s from genesis %x\n",
+		path, genesis.GetHash().Bytes()),
+		ctx.Out.(*bytes.Buffer).String())
+	require.FileExists(t, path)
+
+	ctx.Flags = node.FlagSet{"out": filepath.Join(dir, "unknown", "proof.json")}
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to write file: ", err.Error())
+}
+
+func TestStateExportAction_Execute(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    node.FlagSet{"out": path, "from": 1},
+		Out:      new(bytes.Buffer),
+	}
+
+	action := stateExportAction{}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for '*execution.Journal'")
+
+	db, err := kv.New(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	journal := execution.NewJournal(db)
+	ctx.Injector.Inject(journal)
+
+	for i := uint64(0); i < 3; i++ {
+		err = db.Update(func(tx kv.WritableTx) error {
+			return journal.Store(tx, execution.Diff{Index: i})
+		})
+		require.NoError(t, err)
+	}
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "exported the state of 2 block(s) to "+path+"\n",
+		ctx.Out.(*bytes.Buffer).String())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "{\"Index\":1,\"Changes\":null}\n{\"Index\":2,\"Changes\":null}\n",
+		string(data))
+
+	ctx.Flags = node.FlagSet{"out": filepath.Join(dir, "unknown", "state.json")}
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to create file: ", err.Error())
+
+	bad := fake.NewInMemoryDB()
+	bucket := fake.NewBucket()
+	require.NoError(t, bucket.Set(make([]byte, 8), []byte("{")))
+	bad.SetBucket([]byte("execution-journal"), bucket)
+
+	ctx.Injector.Inject(execution.NewJournal(bad))
+	ctx.Flags = node.FlagSet{"out": path}
+
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to export: failed to decode diff: ", err.Error())
+}
+
+func TestChainVerifyAction_Execute(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "chain.bin")
+
+	genesis := makeGenesis(t)
+	genstore := blockstore.NewGenesisStore()
+	require.NoError(t, genstore.Set(genesis))
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    node.FlagSet{"out": path, "in": path},
+		Out:      io.Discard,
+	}
+
+	ctx.Injector.Inject(genstore)
+	ctx.Injector.Inject(blockstore.NewInMemory())
+
+	require.NoError(t, chainExportAction{}.Execute(ctx))
+
+	action := chainVerifyAction{}
+
+	trusted := hex.EncodeToString(genesis.GetHash().Bytes())
+
+	buffer := new(bytes.Buffer)
+	ctx = node.Context{
+		Injector: node.NewInjector(),
+		Flags:    node.FlagSet{"in": path, "genesis": trusted},
+		Out:      buffer,
+	}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'mino.Mino'")
+
+	ctx.Injector.Inject(fake.Mino{})
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'cosi.CollectiveSigning'")
+
+	ctx.Injector.Inject(threshold.NewThreshold(fake.Mino{}, bls.NewSigner()))
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'validation.Service'")
+
+	ctx.Injector.Inject(simple.NewService(native.NewExecution(), signed.NewTransactionFactory()))
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("verified 0 block(s) from genesis %v\n", genesis.GetHash()),
+		buffer.String())
+
+	// The archive of another chain is refused.
+	ctx.Flags = node.FlagSet{"in": path, "genesis": hex.EncodeToString(make([]byte, 32))}
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to verify archive: untrusted genesis: "+
+		genesis.GetHash().String()+" != 00000000")
+
+	ctx.Flags = node.FlagSet{"in": path, "genesis": "zz"}
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid genesis digest: failed to decode: ", err.Error())
+
+	ctx.Flags = node.FlagSet{"in": path, "genesis": "aabb"}
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "invalid genesis digest: 2 bytes instead of 32")
+
+	ctx.Flags = node.FlagSet{"in": path, "genesis": trusted}
+
+	// The links of the chain must be signed by the collective signing.
+	blocks := blockstore.NewInMemory()
+	block, err := types.NewBlock(simple.NewResult(nil))
+	require.NoError(t, err)
+	link, err := types.NewBlockLink(genesis.GetHash(), block,
+		types.WithSignatures(fake.Signature{}, fake.Signature{}))
+	require.NoError(t, err)
+	require.NoError(t, blocks.Store(link))
+
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	_, err = blockstore.WriteArchive(file, genesis, blocks)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read archive: malformed block 0: ", err.Error())
+
+	ctx.Flags = node.FlagSet{"in": filepath.Join(dir, "unknown.bin"), "genesis": trusted}
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to open file: ", err.Error())
+}
+
 func TestRosterAddAction_Execute(t *testing.T) {
 	action := rosterAddAction{}
 
@@ -146,6 +605,22 @@ func TestRosterAddAction_Execute(t *testing.T) {
 	require.EqualError(t, err, "transaction not found after timeout")
 }
 
+func TestRosterAddAction_Certificate_Execute(t *testing.T) {
+	action := rosterAddAction{}
+
+	ctx := prepContext(nil)
+	ctx.Flags.(node.FlagSet)["member"] = "YQ==:YQ=="
+	ctx.Flags.(node.FlagSet)["certificate"] = issueCertificate(t, "YQ==:YQ==")
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+
+	ctx.Flags.(node.FlagSet)["certificate"] = "a"
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "while preparing tx: failed to decode certificate: "+
+		"base64: illegal base64 data at input byte 0")
+}
+
 func TestDecodeMember(t *testing.T) {
 	ctx := prepContext(nil)
 
@@ -168,6 +643,60 @@ func TestDecodeMember(t *testing.T) {
 // -----------------------------------------------------------------------------
 // Utility functions
 
+func makeGenesis(t *testing.T) types.Genesis {
+	signer := bls.NewSigner()
+	roster := authority.New([]mino.Address{fake.NewAddress(0)},
+		[]crypto.PublicKey{signer.GetPublicKey()})
+
+	genesis, err := types.NewGenesis(roster)
+	require.NoError(t, err)
+
+	return genesis
+}
+
+func TestSyncAction_Execute(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    make(node.FlagSet),
+		Out:      out,
+	}
+
+	ctx.Flags.(node.FlagSet)["timeout"] = float64(time.Second)
+
+	action := syncAction{}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'controller.Service'")
+
+	ctx.Injector.Inject(fakeService{err: fake.GetError()})
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to synchronize"))
+
+	ctx.Injector.Inject(fakeService{})
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "✅ Participants synchronized.\n", out.String())
+}
+
+// issueCertificate returns the base64 certificate of the member issued by the
+// fake signer.
+func issueCertificate(t *testing.T, member string) string {
+	ctx := prepContext(nil)
+	ctx.Flags.(node.FlagSet)["member"] = member
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := certIssueAction{}.Execute(ctx)
+	require.NoError(t, err)
+
+	return buffer.String()
+}
+
 func prepContext(calls *fake.Call) node.Context {
 	ctx := node.Context{
 		Injector: node.NewInjector(),
@@ -199,8 +728,19 @@ func (s fakeService) GetRoster() (authority.Authority, error) {
 	return authority.New(nil, nil), s.err
 }
 
-func (s fakeService) Setup(ctx context.Context, ca crypto.CollectiveAuthority) error {
-	s.calls.Add(ctx, ca)
+func (s fakeService) Setup(ctx context.Context, ca crypto.CollectiveAuthority,
+	opts ...types.GenesisOption) error {
+
+	genesis, err := types.NewGenesis(authority.FromAuthority(ca), opts...)
+	if err != nil {
+		return err
+	}
+
+	s.calls.Add(ctx, ca, genesis)
+	return s.err
+}
+
+func (s fakeService) Synchronize(context.Context) error {
 	return s.err
 }
 
@@ -218,9 +758,27 @@ func (s fakeService) Close() error {
 	return s.err
 }
 
+type fakeDiscovery struct {
+	roster authority.Authority
+	err    error
+}
+
+func (d fakeDiscovery) Announce(context.Context, mino.Address,
+	crypto.PublicKey) (authority.Authority, error) {
+
+	return d.roster, d.err
+}
+
+func (d fakeDiscovery) Fetch(context.Context, mino.Address,
+	crypto.PublicKey) (authority.Authority, error) {
+
+	return d.roster, d.err
+}
+
 type fakeCosi struct {
 	cosi.CollectiveSigning
-	err bool
+	err    bool
+	signer crypto.Signer
 }
 
 func (c fakeCosi) GetPublicKeyFactory() crypto.PublicKeyFactory {
@@ -236,6 +794,10 @@ func (c fakeCosi) GetSigner() crypto.Signer {
 		return fake.NewSignerWithPublicKey(fake.NewBadPublicKey())
 	}
 
+	if c.signer != nil {
+		return c.signer
+	}
+
 	return fake.NewSigner()
 }
 
@@ -5,6 +5,8 @@ package controller
 
 import (
 	"encoding"
+	"encoding/base64"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -14,28 +16,40 @@ import (
 	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/core/access/darc"
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/execution"
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/beacon"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/viewchange"
+	"go.dedis.ch/dela/core/ordering/cosipbft/discovery"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/store/hashtree/binprefix"
 	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/core/txn/pool"
 	poolimpl "go.dedis.ch/dela/core/txn/pool/gossip"
 	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation"
 	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/cosi"
 	"go.dedis.ch/dela/cosi/threshold"
 	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/crypto/loader"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/mino/gossip"
+	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/dela/serde/json"
 	"golang.org/x/xerrors"
 )
 
-const privateKeyFile = "private.key"
+const (
+	privateKeyFile  = "private.key"
+	certificateFile = "certificate.json"
+)
 
 // valueAccessKey is the access key used for the value contract.
 var valueAccessKey = [32]byte{2}
@@ -62,6 +76,21 @@ func NewController() node.Initializer {
 // SetCommands implements node.Initializer. It sets the command to control the
 // service.
 func (miniController) SetCommands(builder node.Builder) {
+	builder.SetStartFlags(
+		cli.IntFlag{
+			Name: "fanout",
+			Usage: "if set, the blocks and the transactions are spread along a " +
+				"tree where a node sends to at most fanout participants, " +
+				"instead of to all of them",
+		},
+		cli.StringFlag{
+			Name: "authority",
+			Usage: "if set, base64 public key of the authority whose " +
+				"identity certificates admit the nodes announcing " +
+				"themselves to this node",
+		},
+	)
+
 	cmd := builder.SetCommand("ordering")
 	cmd.SetDescription("Ordering service administration")
 
@@ -74,17 +103,179 @@ func (miniController) SetCommands(builder node.Builder) {
 			Value: 20 * time.Second,
 		},
 		cli.StringSliceFlag{
-			Name:     "member",
-			Required: true,
-			Usage:    "one or several member of the new chain",
+			Name:  "member",
+			Usage: "one or several member of the new chain",
+		},
+		cli.StringSliceFlag{
+			Name: "seed",
+			Usage: "one or several seed, in the same format as a member, " +
+				"whose roster is added to the members of the new chain",
+		},
+		cli.StringSliceFlag{
+			Name: "relay",
+			Usage: "one or several member, in the same format, that takes " +
+				"part in the consensus but does not hold any key share",
+		},
+		cli.IntFlag{
+			Name: "threshold",
+			Usage: "if set, number of key shares required to release a key, " +
+				"that the DKG of the members uses",
+		},
+		cli.StringFlag{
+			Name: "ordering",
+			Usage: "policy that orders the transactions of a block, either " +
+				"'arrival' or 'beacon' to shuffle them with the signature of " +
+				"the previous block",
+			Value: cosipbft.ArrivalOrdering,
+		},
+		cli.StringFlag{
+			Name: "authority",
+			Usage: "if set, base64 public key of the authority that issues " +
+				"the identity certificates, that new members and leaders of " +
+				"a catch-up of the chain must present",
 		},
 	)
 	sub.SetAction(builder.MakeAction(setupAction{}))
 
+	sub = cmd.SetSubCommand("announce")
+	sub.SetDescription("Announce the node to the seeds")
+	sub.SetFlags(
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "maximum amount of time to announce",
+			Value: 20 * time.Second,
+		},
+		cli.StringSliceFlag{
+			Name:     "seed",
+			Required: true,
+			Usage:    "one or several seed to announce the node to",
+		},
+	)
+	sub.SetAction(builder.MakeAction(announceAction{}))
+
+	sub = cmd.SetSubCommand("sync")
+	sub.SetDescription("Send the latest blocks to the participants behind")
+	sub.SetFlags(
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "maximum amount of time to synchronize",
+			Value: 20 * time.Second,
+		},
+	)
+	sub.SetAction(builder.MakeAction(syncAction{}))
+
 	sub = cmd.SetSubCommand("export")
 	sub.SetDescription("Export the node information")
 	sub.SetAction(builder.MakeAction(exportAction{}))
 
+	sub = cmd.SetSubCommand("chain")
+	sub.SetDescription("Chain archive administration")
+
+	chainSub := sub.SetSubCommand("export")
+	chainSub.SetDescription("Write the chain to an archive file")
+	chainSub.SetFlags(
+		cli.StringFlag{
+			Name:     "out",
+			Required: true,
+			Usage:    "path of the archive file",
+		},
+	)
+	chainSub.SetAction(builder.MakeAction(chainExportAction{}))
+
+	chainSub = sub.SetSubCommand("verify")
+	chainSub.SetDescription("Verify the integrity and the signatures of an archive file")
+	chainSub.SetFlags(
+		cli.StringFlag{
+			Name:     "in",
+			Required: true,
+			Usage:    "path of the archive file",
+		},
+		cli.StringFlag{
+			Name:     "genesis",
+			Required: true,
+			Usage:    "hex-encoded digest of the trusted genesis block",
+		},
+	)
+	chainSub.SetAction(builder.MakeAction(chainVerifyAction{}))
+
+	chainSub = sub.SetSubCommand("proof")
+	chainSub.SetDescription("Write a standalone proof of a block to a file")
+	chainSub.SetFlags(
+		cli.IntFlag{
+			Name:     "index",
+			Required: true,
+			Usage:    "index of the block to prove",
+		},
+		cli.StringFlag{
+			Name:     "out",
+			Required: true,
+			Usage:    "path of the proof file",
+		},
+	)
+	chainSub.SetAction(builder.MakeAction(chainProofAction{}))
+
+	sub = cmd.SetSubCommand("state")
+	sub.SetDescription("State administration")
+
+	stateSub := sub.SetSubCommand("export")
+	stateSub.SetDescription("Write the diffs of the state of the blocks to a " +
+		"file, one JSON object per line")
+	stateSub.SetFlags(
+		cli.IntFlag{
+			Name:  "from",
+			Usage: "index of the first block to export",
+		},
+		cli.StringFlag{
+			Name:     "out",
+			Required: true,
+			Usage:    "path of the file",
+		},
+	)
+	stateSub.SetAction(builder.MakeAction(stateExportAction{}))
+
+	sub = cmd.SetSubCommand("explorer")
+	sub.SetDescription("Registers the read-only handlers of the blocks and " +
+		"the transactions on the proxy. The proxy must be started first.")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "blocksPath",
+			Usage: "the path prefix of the blocks handler",
+			Value: "/blocks/",
+		},
+		cli.StringFlag{
+			Name:  "txPath",
+			Usage: "the path prefix of the transactions handler",
+			Value: "/tx/",
+		},
+	)
+	sub.SetAction(builder.MakeAction(explorerAction{}))
+
+	sub = cmd.SetSubCommand("certificate")
+	sub.SetDescription("Identity certificate administration")
+
+	certSub := sub.SetSubCommand("issue")
+	certSub.SetDescription("Issue a certificate to a member, signed by the " +
+		"key of the node acting as the authority")
+	certSub.SetFlags(
+		cli.StringFlag{
+			Name:     "member",
+			Required: true,
+			Usage:    "base64 description of the member to enroll",
+		},
+	)
+	certSub.SetAction(builder.MakeAction(certIssueAction{}))
+
+	certSub = sub.SetSubCommand("import")
+	certSub.SetDescription("Import the certificate of the node")
+	certSub.SetFlags(
+		cli.StringFlag{
+			Name:     "certificate",
+			Required: true,
+			Usage:    "base64 certificate issued to the node",
+		},
+	)
+	certSub.SetAction(builder.MakeAction(certImportAction{}))
+
 	sub = cmd.SetSubCommand("roster")
 	sub.SetDescription("Roster administration")
 
@@ -96,6 +287,10 @@ func (miniController) SetCommands(builder node.Builder) {
 			Required: true,
 			Usage:    "base64 description of the member to add",
 		},
+		cli.StringFlag{
+			Name:  "certificate",
+			Usage: "base64 certificate of the member to add",
+		},
 		cli.DurationFlag{
 			Name:  "wait",
 			Usage: "wait for the transaction to be processed",
@@ -124,15 +319,31 @@ func (m miniController) OnStart(flags cli.Flags, inj node.Injector) error {
 	exec := native.NewExecution()
 	access := darc.NewService(json.NewContext())
 
-	rosterFac := authority.NewFactory(onet.GetAddressFactory(), cosi.GetPublicKeyFactory())
-	cosipbft.RegisterRosterContract(exec, rosterFac, access)
+	certFac := makeCertificateFactory(onet, cosi)
+
+	certs, err := loadCertificate(flags, certFac)
+	if err != nil {
+		return xerrors.Errorf("certificate: %v", err)
+	}
+
+	ca, err := makeAuthority(flags.String("authority"), cosi.GetPublicKeyFactory())
+	if err != nil {
+		return xerrors.Errorf("authority: %v", err)
+	}
+
+	srvcOpts := []cosipbft.ServiceOption{
+		cosipbft.WithPropagationFanout(flags.Int("fanout")),
+		cosipbft.WithEnrollment(certs),
+	}
 
 	value.RegisterContract(exec, value.NewContract(valueAccessKey[:], access))
 
 	txFac := signed.NewTransactionFactory()
 	vs := simple.NewService(exec, txFac)
 
-	pool, err := poolimpl.NewPool(gossip.NewFlat(onet.WithSegment("pool"), txFac))
+	fanout := flags.Int("fanout")
+
+	pool, err := poolimpl.NewPool(makeGossiper(onet.WithSegment("pool"), txFac, fanout))
 	if err != nil {
 		return xerrors.Errorf("pool: %v", err)
 	}
@@ -160,16 +371,20 @@ func (m miniController) OnStart(flags cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to load tree: %v", err)
 	}
 
-	genstore := blockstore.NewGenesisDiskStore(db, types.NewGenesisFactory(rosterFac))
+	genFac, linkFac := makeFactories(onet, cosi, vs)
+
+	genstore := blockstore.NewGenesisDiskStore(db, genFac)
 
 	err = genstore.Load()
 	if err != nil {
 		return xerrors.Errorf("failed to load genesis: %v", err)
 	}
 
-	blockFac := types.NewBlockFactory(vs.GetFactory())
-	csFac := authority.NewChangeSetFactory(onet.GetAddressFactory(), cosi.GetPublicKeyFactory())
-	linkFac := types.NewLinkFactory(blockFac, cosi.GetSignatureFactory(), csFac)
+	// The roster contract requires the certificates of the new members when
+	// the genesis block has an authority.
+	rosterFac := authority.NewFactory(onet.GetAddressFactory(), cosi.GetPublicKeyFactory())
+	cosipbft.RegisterRosterContract(exec, rosterFac, access,
+		viewchange.WithAuthority(readAuthority(genstore), certFac))
 
 	blocks := blockstore.NewDiskStore(db, linkFac)
 
@@ -178,12 +393,29 @@ func (m miniController) OnStart(flags cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to load blocks: %v", err)
 	}
 
-	srvc, err := cosipbft.NewService(param, cosipbft.WithGenesisStore(genstore), cosipbft.WithBlockStore(blocks))
+	journal := execution.NewJournal(db)
+
+	// The committed blocks are published on a bus shared with the other
+	// components of the node.
+	bus := events.NewBus()
+
+	srvcOpts = append(srvcOpts, cosipbft.WithGenesisStore(genstore),
+		cosipbft.WithBlockStore(blocks),
+		cosipbft.WithJournal(journal), cosipbft.WithEvents(bus))
+
+	srvc, err := cosipbft.NewService(param, srvcOpts...)
 	if err != nil {
 		return xerrors.Errorf("service: %v", err)
 	}
 
 	inj.Inject(srvc)
+	inj.Inject(discovery.NewService(onet, signer, ca, certs))
+	inj.Inject(certs)
+	inj.Inject(genstore)
+	inj.Inject(blocks)
+	inj.Inject(journal)
+	inj.Inject(bus)
+	inj.Inject(beacon.NewBeacon(blocks))
 	inj.Inject(cosi)
 	inj.Inject(pool)
 	inj.Inject(vs)
@@ -193,6 +425,79 @@ func (m miniController) OnStart(flags cli.Flags, inj node.Injector) error {
 	return nil
 }
 
+// makeGossiper returns the gossiper of the transactions. The rumors are spread
+// along a tree when a fanout is set, otherwise they are sent to every
+// participant and the peers are scored.
+func makeGossiper(m mino.Mino, f serde.Factory, fanout int) gossip.Gossiper {
+	if fanout > 0 {
+		return gossip.NewTree(m, f, fanout)
+	}
+
+	return gossip.NewFlat(m, f, gossip.WithScores(gossip.DefaultScoreConfig))
+}
+
+// makeAuthority returns the public key of the authority from its base64
+// representation, or nil if it is empty.
+func makeAuthority(str string, fac crypto.PublicKeyFactory) (crypto.PublicKey, error) {
+	if str == "" {
+		return nil, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, xerrors.Errorf("base64 public key: %v", err)
+	}
+
+	pubkey, err := fac.FromBytes(data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode public key: %v", err)
+	}
+
+	return pubkey, nil
+}
+
+// readAuthority returns the reader of the authority of the genesis block.
+func readAuthority(genstore blockstore.GenesisStore) viewchange.AuthorityReader {
+	return func() (crypto.PublicKey, error) {
+		genesis, err := genstore.Get()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read genesis: %v", err)
+		}
+
+		return genesis.GetAuthority(), nil
+	}
+}
+
+// makeCertificateFactory returns the factory of the identity certificates.
+// The authority is expected to use the same kind of key as the nodes.
+func makeCertificateFactory(onet mino.Mino, c cosi.CollectiveSigning) enrollment.Factory {
+	return enrollment.NewCertificateFactory(onet.GetAddressFactory(),
+		c.GetPublicKeyFactory(), c.GetSigner().GetSignatureFactory())
+}
+
+// loadCertificate returns a holder with the certificate imported by the node,
+// or an empty one if the node is not enrolled.
+func loadCertificate(flags cli.Flags, fac enrollment.Factory) (*enrollment.Holder, error) {
+	holder := enrollment.NewHolder()
+
+	data, err := os.ReadFile(filepath.Join(flags.Path("config"), certificateFile))
+	if os.IsNotExist(err) {
+		return holder, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read file: %v", err)
+	}
+
+	cert, err := fac.CertificateOf(json.NewContext(), data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	holder.Set(cert)
+
+	return holder, nil
+}
+
 // OnStop implements node.Initializer. It stops the service and the transaction
 // pool.
 func (miniController) OnStop(inj node.Injector) error {
@@ -221,6 +526,19 @@ func (miniController) OnStop(inj node.Injector) error {
 	return nil
 }
 
+// makeFactories returns the factories of the genesis block and of the block
+// links.
+func makeFactories(onet mino.Mino, c cosi.CollectiveSigning,
+	vs validation.Service) (types.GenesisFactory, types.LinkFactory) {
+
+	rosterFac := authority.NewFactory(onet.GetAddressFactory(), c.GetPublicKeyFactory())
+	blockFac := types.NewBlockFactory(vs.GetFactory())
+	csFac := authority.NewChangeSetFactory(onet.GetAddressFactory(), c.GetPublicKeyFactory())
+
+	return types.NewGenesisFactory(rosterFac, c.GetPublicKeyFactory()),
+		types.NewLinkFactory(blockFac, c.GetSignatureFactory(), csFac)
+}
+
 func (m miniController) getSigner(flags cli.Flags) (crypto.AggregateSigner, error) {
 	loader := loader.NewFileLoader(filepath.Join(flags.Path("config"), privateKeyFile))
 
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding"
+	"encoding/base64"
 	"os"
 	"path/filepath"
 	"testing"
@@ -9,9 +10,16 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/execution"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/core/txn/pool"
+	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino/gossip"
 )
 
 func TestMinimal_SetCommands(t *testing.T) {
@@ -36,6 +44,75 @@ func TestMinimal_OnStart(t *testing.T) {
 
 	err = m.OnStart(flags, inj)
 	require.NoError(t, err)
+
+	var journal *execution.Journal
+	require.NoError(t, inj.Resolve(&journal))
+}
+
+func TestReadAuthority(t *testing.T) {
+	genstore := blockstore.NewGenesisStore()
+
+	_, err := readAuthority(genstore)()
+	require.EqualError(t, err, "failed to read genesis: missing genesis block")
+
+	genesis, err := types.NewGenesis(authority.New(nil, nil), types.WithAuthority(fake.PublicKey{}))
+	require.NoError(t, err)
+
+	genstore.Set(genesis)
+
+	ca, err := readAuthority(genstore)()
+	require.NoError(t, err)
+	require.Equal(t, fake.PublicKey{}, ca)
+}
+
+func TestMinimal_Authority_OnStart(t *testing.T) {
+	flags, dir, clean := makeFlags(t)
+	defer clean()
+
+	pubkey, err := bls.NewSigner().GetPublicKey().MarshalBinary()
+	require.NoError(t, err)
+
+	flags.(node.FlagSet)["authority"] = base64.StdEncoding.EncodeToString(pubkey)
+
+	m := NewController().(miniController)
+
+	inj := node.NewInjector()
+	inj.Inject(fake.Mino{})
+	inj.Inject(fake.NewInMemoryDB())
+
+	err = m.OnStart(flags, inj)
+	require.NoError(t, err)
+
+	var holder *enrollment.Holder
+	require.NoError(t, inj.Resolve(&holder))
+	require.Nil(t, holder.Get())
+
+	flags.(node.FlagSet)["authority"] = "a"
+
+	err = m.OnStart(flags, inj)
+	require.EqualError(t, err,
+		"authority: base64 public key: illegal base64 data at input byte 0")
+
+	flags.(node.FlagSet)["authority"] = "YQ=="
+
+	err = m.OnStart(flags, inj)
+	require.Error(t, err)
+	require.Regexp(t, "^authority: failed to decode public key: ", err.Error())
+
+	err = os.WriteFile(filepath.Join(dir, certificateFile), []byte("a"), 0600)
+	require.NoError(t, err)
+
+	err = m.OnStart(flags, inj)
+	require.Error(t, err)
+	require.Regexp(t, "^certificate: failed to decode: ", err.Error())
+}
+
+func TestMakeGossiper(t *testing.T) {
+	gossiper := makeGossiper(fake.Mino{}, nil, 0)
+	require.IsType(t, &gossip.Flat{}, gossiper)
+
+	gossiper = makeGossiper(fake.Mino{}, nil, 3)
+	require.IsType(t, &gossip.Tree{}, gossiper)
 }
 
 func TestMinimal_MissingMino_OnStart(t *testing.T) {
@@ -0,0 +1,260 @@
+// This file contains the HTTP handlers of the block explorer. They give a
+// read-only access to the chain, with the JSON encoding of the messages, to
+// clients that don't speak mino.
+
+package controller
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultPageSize is the number of blocks returned when the limit is not
+	// specified.
+	defaultPageSize = 20
+
+	// maxPageSize is the maximum number of blocks returned in a page.
+	maxPageSize = 100
+)
+
+// blockPage is the reply of a list of blocks. Next is the index of the block
+// that follows the page.
+type blockPage struct {
+	Blocks []json.RawMessage
+	Next   uint64
+}
+
+// txReply is the reply of a transaction, with the index of the block it
+// belongs to.
+type txReply struct {
+	Index  uint64
+	Result json.RawMessage
+}
+
+// blocksHandler returns a handler that serves the blocks under the prefix:
+//   - <prefix>latest returns the latest block,
+//   - <prefix><index> returns the block at the index,
+//   - <prefix>?from=<index>&limit=<size> returns a page of blocks.
+func blocksHandler(prefix string, ctx serde.Context,
+	blocks blockstore.BlockStore) func(http.ResponseWriter, *http.Request) {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, prefix)
+
+		switch name {
+		case "":
+			serveBlockPage(w, r, ctx, blocks)
+		case "latest":
+			link, err := blocks.Last()
+			if err != nil {
+				replyError(w, http.StatusNotFound, err)
+				return
+			}
+
+			replyMessage(w, ctx, link)
+		default:
+			index, err := strconv.ParseUint(name, 10, 64)
+			if err != nil {
+				replyError(w, http.StatusBadRequest, xerrors.Errorf("invalid index: %v", err))
+				return
+			}
+
+			link, err := blocks.GetByIndex(index)
+			if err != nil {
+				replyError(w, http.StatusNotFound, err)
+				return
+			}
+
+			replyMessage(w, ctx, link)
+		}
+	}
+}
+
+func serveBlockPage(w http.ResponseWriter, r *http.Request, ctx serde.Context,
+	blocks blockstore.BlockStore) {
+
+	from, err := parseQuery(r, "from", 0)
+	if err != nil {
+		replyError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	limit, err := parseQuery(r, "limit", defaultPageSize)
+	if err != nil {
+		replyError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	page := blockPage{Blocks: []json.RawMessage{}, Next: from}
+
+	for ; page.Next < blocks.Len() && uint64(len(page.Blocks)) < limit; page.Next++ {
+		link, err := blocks.GetByIndex(page.Next)
+		if err != nil {
+			replyError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		data, err := link.Serialize(ctx)
+		if err != nil {
+			replyError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		page.Blocks = append(page.Blocks, data)
+	}
+
+	replyJSON(w, page)
+}
+
+// txHandler returns a handler that serves the result of a transaction from its
+// identifier encoded in hex, under the prefix. The transactions are found with
+// an index of the blocks.
+func txHandler(prefix string, ctx serde.Context,
+	blocks blockstore.BlockStore) func(http.ResponseWriter, *http.Request) {
+
+	txs := newTxIndex(blocks)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := hex.DecodeString(strings.TrimPrefix(r.URL.Path, prefix))
+		if err != nil {
+			replyError(w, http.StatusBadRequest, xerrors.Errorf("invalid identifier: %v", err))
+			return
+		}
+
+		if len(id) == 0 {
+			replyError(w, http.StatusBadRequest, xerrors.New("missing identifier"))
+			return
+		}
+
+		index, found, err := txs.Find(id)
+		if err != nil {
+			replyError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if !found {
+			replyError(w, http.StatusNotFound, xerrors.Errorf("transaction %#x not found", id))
+			return
+		}
+
+		link, err := blocks.GetByIndex(index)
+		if err != nil {
+			replyError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, res := range link.GetBlock().GetData().GetTransactionResults() {
+			if !bytes.Equal(res.GetTransaction().GetID(), id) {
+				continue
+			}
+
+			data, err := res.Serialize(ctx)
+			if err != nil {
+				replyError(w, http.StatusInternalServerError, err)
+				return
+			}
+
+			replyJSON(w, txReply{Index: index, Result: data})
+			return
+		}
+
+		replyError(w, http.StatusInternalServerError,
+			xerrors.Errorf("transaction %#x not in block %d", id, index))
+	}
+}
+
+// txIndex maps the identifiers of the transactions to the index of their
+// block. The blocks are indexed on demand, so that a lookup only reads the
+// blocks stored since the previous one.
+type txIndex struct {
+	sync.Mutex
+
+	blocks  blockstore.BlockStore
+	indices map[string]uint64
+	next    uint64
+}
+
+func newTxIndex(blocks blockstore.BlockStore) *txIndex {
+	return &txIndex{
+		blocks:  blocks,
+		indices: make(map[string]uint64),
+	}
+}
+
+// Find returns the index of the block of the transaction, or false if it is
+// not in a block.
+func (idx *txIndex) Find(id []byte) (uint64, bool, error) {
+	idx.Lock()
+	defer idx.Unlock()
+
+	for ; idx.next < idx.blocks.Len(); idx.next++ {
+		link, err := idx.blocks.GetByIndex(idx.next)
+		if err != nil {
+			return 0, false, xerrors.Errorf("failed to read block %d: %v", idx.next, err)
+		}
+
+		for _, res := range link.GetBlock().GetData().GetTransactionResults() {
+			idx.indices[string(res.GetTransaction().GetID())] = idx.next
+		}
+	}
+
+	index, found := idx.indices[string(id)]
+
+	return index, found, nil
+}
+
+func parseQuery(r *http.Request, key string, value uint64) (uint64, error) {
+	str := r.URL.Query().Get(key)
+	if str == "" {
+		return value, nil
+	}
+
+	value, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return 0, xerrors.Errorf("invalid %s: %v", key, err)
+	}
+
+	return value, nil
+}
+
+func replyMessage(w http.ResponseWriter, ctx serde.Context, msg serde.Message) {
+	data, err := msg.Serialize(ctx)
+	if err != nil {
+		replyError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func replyJSON(w http.ResponseWriter, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		replyError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func replyError(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	fmt.Fprint(w, err.Error())
+}
@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino/proxy"
+	"go.dedis.ch/dela/serde"
+)
+
+func TestExplorerAction_Execute(t *testing.T) {
+	action := explorerAction{}
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    node.FlagSet{"blocksPath": "/blocks/", "txPath": "/tx/"},
+		Out:      io.Discard,
+	}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'proxy.Proxy'")
+
+	prox := &fakeProxy{handlers: make(map[string]func(http.ResponseWriter, *http.Request))}
+	ctx.Injector.Inject(prox)
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'blockstore.BlockStore'")
+
+	ctx.Injector.Inject(blockstore.NewInMemory())
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, prox.handlers, 2)
+	require.Contains(t, prox.handlers, "/blocks/")
+	require.Contains(t, prox.handlers, "/tx/")
+}
+
+func TestBlocksHandler(t *testing.T) {
+	ctx := fake.NewContextWithFormat(serde.FormatJSON)
+	blocks, _ := makeExplorerBlocks(t, 3)
+
+	handler := blocksHandler("/blocks/", ctx, blocks)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get("/blocks/latest")
+	require.Equal(t, http.StatusOK, code)
+	require.Regexp(t, `"Index":2`, body)
+
+	code, body = get("/blocks/1")
+	require.Equal(t, http.StatusOK, code)
+	require.Regexp(t, `"Index":1`, body)
+
+	code, body = get("/blocks/3")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "block not found: no block", body)
+
+	code, body = get("/blocks/abc")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Regexp(t, "^invalid index: ", body)
+
+	var page blockPage
+
+	code, body = get("/blocks/?from=1&limit=1")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal([]byte(body), &page))
+	require.Len(t, page.Blocks, 1)
+	require.Equal(t, uint64(2), page.Next)
+
+	code, body = get("/blocks/")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal([]byte(body), &page))
+	require.Len(t, page.Blocks, 3)
+	require.Equal(t, uint64(3), page.Next)
+
+	code, body = get("/blocks/?from=5")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal([]byte(body), &page))
+	require.Empty(t, page.Blocks)
+	require.Equal(t, uint64(5), page.Next)
+
+	code, body = get("/blocks/?limit=x")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Regexp(t, "^invalid limit: ", body)
+
+	code, body = get("/blocks/?from=x")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Regexp(t, "^invalid from: ", body)
+
+	handler = blocksHandler("/blocks/", ctx, blockstore.NewInMemory())
+
+	code, body = get("/blocks/latest")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "store empty: no block", body)
+
+	handler = blocksHandler("/blocks/", fake.NewBadContext(), blocks)
+
+	code, _ = get("/blocks/latest")
+	require.Equal(t, http.StatusInternalServerError, code)
+
+	code, _ = get("/blocks/")
+	require.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestTxHandler(t *testing.T) {
+	ctx := fake.NewContextWithFormat(serde.FormatJSON)
+	blocks, ids := makeExplorerBlocks(t, 3)
+
+	handler := txHandler("/tx/", ctx, blocks)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get("/tx/" + ids[1])
+	require.Equal(t, http.StatusOK, code)
+
+	var reply txReply
+	require.NoError(t, json.Unmarshal([]byte(body), &reply))
+	require.Equal(t, uint64(1), reply.Index)
+	require.Regexp(t, `"Accepted":true`, string(reply.Result))
+
+	code, body = get("/tx/aabb")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "transaction 0xaabb not found", body)
+
+	code, body = get("/tx/zz")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Regexp(t, "^invalid identifier: ", body)
+
+	code, body = get("/tx/")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "missing identifier", body)
+
+	handler = txHandler("/tx/", ctx, badBlocks{BlockStore: blocks})
+
+	code, body = get("/tx/" + ids[0])
+	require.Equal(t, http.StatusInternalServerError, code)
+	require.Equal(t, fake.Err("failed to read block 0"), body)
+
+	handler = txHandler("/tx/", fake.NewBadContext(), blocks)
+
+	code, _ = get("/tx/" + ids[0])
+	require.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestTxIndex_Find(t *testing.T) {
+	blocks, ids := makeExplorerBlocks(t, 3)
+
+	idx := newTxIndex(blocks)
+
+	id, err := hex.DecodeString(ids[2])
+	require.NoError(t, err)
+
+	index, found, err := idx.Find(id)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint64(2), index)
+	require.Equal(t, uint64(3), idx.next)
+
+	_, found, err = idx.Find([]byte{0xaa})
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// The blocks already indexed are not read again.
+	idx.blocks = badBlocks{BlockStore: blocks}
+
+	index, found, err = idx.Find(id)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint64(2), index)
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+// makeExplorerBlocks returns a store of n blocks with one transaction each,
+// and the identifiers of the transactions encoded in hex.
+func makeExplorerBlocks(t *testing.T, n int) (blockstore.BlockStore, []string) {
+	signer := bls.NewSigner()
+	blocks := blockstore.NewInMemory()
+	ids := make([]string, n)
+
+	prev := types.Digest{}
+
+	for i := 0; i < n; i++ {
+		tx, err := signed.NewTransaction(uint64(i), signer.GetPublicKey())
+		require.NoError(t, err)
+		require.NoError(t, tx.Sign(signer))
+
+		ids[i] = hex.EncodeToString(tx.GetID())
+
+		res := simple.NewResult([]simple.TransactionResult{simple.NewTransactionResult(tx, true, "")})
+
+		block, err := types.NewBlock(res, types.WithIndex(uint64(i)))
+		require.NoError(t, err)
+
+		link, err := types.NewBlockLink(prev, block,
+			types.WithSignatures(fake.Signature{}, fake.Signature{}))
+		require.NoError(t, err)
+		require.NoError(t, blocks.Store(link))
+
+		prev = link.GetTo()
+	}
+
+	return blocks, ids
+}
+
+type badBlocks struct {
+	blockstore.BlockStore
+}
+
+func (badBlocks) GetByIndex(uint64) (types.BlockLink, error) {
+	return nil, fake.GetError()
+}
+
+type fakeProxy struct {
+	proxy.Proxy
+
+	handlers map[string]func(http.ResponseWriter, *http.Request)
+}
+
+func (p *fakeProxy) RegisterHandler(path string, handler func(http.ResponseWriter, *http.Request)) {
+	p.handlers[path] = handler
+}
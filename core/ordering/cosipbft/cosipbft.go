@@ -35,14 +35,18 @@ import (
 	"math"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/core/access"
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/execution"
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blocksync"
 	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/viewchange"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/ordering/cosipbft/pbft"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/store"
@@ -54,7 +58,9 @@ import (
 	"go.dedis.ch/dela/cosi"
 	"go.dedis.ch/dela/cosi/threshold"
 	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/internal/clock"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/broadcast"
 	"golang.org/x/xerrors"
 )
 
@@ -81,12 +87,36 @@ const (
 	RoundMaxWait = 5 * time.Minute
 
 	rpcName = "cosipbft"
+
+	propagationName = "cosipbftpropagation"
 )
 
+// defines prometheus metrics
+var (
+	promBlockInterval = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dela_cosipbft_block_interval_seconds",
+		Help:    "time elapsed between two finalized blocks",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60, 120},
+	})
+
+	promBlockTxs = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dela_cosipbft_transactions_finalized_block",
+		Help:    "number of transactions in a finalized block",
+		Buckets: []float64{0, 1, 2, 3, 5, 8, 13, 20, 30, 50, 100},
+	})
+)
+
+func init() {
+	dela.PromCollectors = append(dela.PromCollectors, promBlockInterval,
+		promBlockTxs)
+}
+
 // RegisterRosterContract registers the native smart contract to update the
 // roster to the given service.
-func RegisterRosterContract(exec *native.Service, rFac authority.Factory, srvc access.Service) {
-	contract := viewchange.NewContract(keyRoster[:], keyAccess[:], rFac, srvc)
+func RegisterRosterContract(exec *native.Service, rFac authority.Factory, srvc access.Service,
+	opts ...viewchange.ContractOption) {
+
+	contract := viewchange.NewContract(keyRoster[:], keyAccess[:], rFac, srvc, opts...)
 
 	viewchange.RegisterContract(exec, contract)
 }
@@ -100,6 +130,8 @@ type Service struct {
 
 	me          mino.Address
 	rpc         mino.RPC
+	propagation *broadcast.Tree
+	fanout      int
 	actor       cosi.Actor
 	val         validation.Service
 	verifierFac crypto.VerifierFactory
@@ -107,17 +139,25 @@ type Service struct {
 	timeoutRound             time.Duration
 	timeoutRoundAfterFailure time.Duration
 	transactionTimeout       time.Duration
+	clock                    clock.Clock
 
 	events      chan ordering.Event
+	bus         events.Service
 	closing     chan struct{}
 	closed      chan struct{}
 	failedRound bool
 }
 
 type serviceTemplate struct {
-	hashFac crypto.HashFactory
-	blocks  blockstore.BlockStore
-	genesis blockstore.GenesisStore
+	hashFac  crypto.HashFactory
+	blocks   blockstore.BlockStore
+	genesis  blockstore.GenesisStore
+	policies map[string]OrderingPolicy
+	fanout   int
+	certs    *enrollment.Holder
+	journal  *execution.Journal
+	bus      events.Service
+	clock    clock.Clock
 }
 
 // ServiceOption is the type of option to set some fields of the service.
@@ -144,6 +184,58 @@ func WithHashFactory(fac crypto.HashFactory) ServiceOption {
 	}
 }
 
+// WithOrderingPolicy is an option to register a policy that decides the order
+// of the transactions in the blocks, under the name that a genesis block uses
+// to select it. The arrival and the beacon policies are always registered.
+func WithOrderingPolicy(name string, policy OrderingPolicy) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.policies[name] = policy
+	}
+}
+
+// WithPropagationFanout is an option to propagate the finalized blocks along a
+// tree of the roster, where a node sends a block to at most fanout
+// participants. By default, the leader sends the block to every participant.
+func WithPropagationFanout(fanout int) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.fanout = fanout
+	}
+}
+
+// WithEnrollment is an option to set the holder of the identity certificate
+// of the node. The node presents the certificate, once set, when it leads a
+// catch-up, which is required when the genesis block has an authority.
+func WithEnrollment(holder *enrollment.Holder) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.certs = holder
+	}
+}
+
+// WithJournal is an option to persist the diff of the state of every block in
+// the journal, so that it can be exported.
+func WithJournal(journal *execution.Journal) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.journal = journal
+	}
+}
+
+// WithEvents is an option to publish the blocks committed by the service on the
+// event bus.
+func WithEvents(bus events.Service) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.bus = bus
+	}
+}
+
+// WithClock is an option to set the clock that measures the timeouts of the
+// rounds and the age of the transactions, and that sets the time of the
+// blocks.
+func WithClock(c clock.Clock) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.clock = c
+	}
+}
+
 // ServiceParam is the different components to provide to the service. All the
 // fields are mandatory and it will panic if any is nil.
 type ServiceParam struct {
@@ -162,6 +254,11 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 		hashFac: crypto.NewSha256Factory(),
 		genesis: blockstore.NewGenesisStore(),
 		blocks:  blockstore.NewInMemory(),
+		policies: map[string]OrderingPolicy{
+			ArrivalOrdering: NewArrivalPolicy(),
+			BeaconOrdering:  NewBeaconPolicy(),
+		},
+		clock: clock.NewReal(),
 	}
 
 	for _, opt := range opts {
@@ -173,6 +270,7 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 	proc.blocks = tmpl.blocks
 	proc.genesis = tmpl.genesis
 	proc.pool = param.Pool
+	proc.policies = tmpl.policies
 	proc.rosterFac = authority.NewFactory(param.Mino.GetAddressFactory(), param.Cosi.GetPublicKeyFactory())
 	proc.tree = blockstore.NewTreeCache(param.Tree)
 	proc.access = param.Access
@@ -188,6 +286,9 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 		Tree:            proc.tree,
 		AuthorityReader: proc.readRoster,
 		DB:              param.DB,
+		Journal:         tmpl.journal,
+		Clock:           tmpl.clock,
+		OrderVerifier:   proc.verifyOrder,
 	}
 
 	proc.pbftsm = pbft.NewStateMachine(pcparam)
@@ -197,14 +298,20 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 	linkFac := types.NewLinkFactory(blockFac, param.Cosi.GetSignatureFactory(), csFac)
 	chainFac := types.NewChainFactory(linkFac)
 
+	// The authority is expected to use the same kind of key as the nodes.
+	certFac := enrollment.NewCertificateFactory(param.Mino.GetAddressFactory(),
+		param.Cosi.GetPublicKeyFactory(), param.Cosi.GetSigner().GetSignatureFactory())
+
 	syncparam := blocksync.SyncParam{
-		Mino:            param.Mino,
-		Blocks:          tmpl.blocks,
-		Genesis:         tmpl.genesis,
-		PBFT:            proc.pbftsm,
-		LinkFactory:     linkFac,
-		ChainFactory:    chainFac,
-		VerifierFactory: param.Cosi.GetVerifierFactory(),
+		Mino:               param.Mino,
+		Blocks:             tmpl.blocks,
+		Genesis:            tmpl.genesis,
+		PBFT:               proc.pbftsm,
+		LinkFactory:        linkFac,
+		ChainFactory:       chainFac,
+		VerifierFactory:    param.Cosi.GetVerifierFactory(),
+		CertificateFactory: certFac,
+		Certificates:       tmpl.certs,
 	}
 
 	bs := blocksync.NewSynchronizer(syncparam)
@@ -212,7 +319,7 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 	proc.sync = bs
 
 	fac := types.NewMessageFactory(
-		types.NewGenesisFactory(proc.rosterFac),
+		types.NewGenesisFactory(proc.rosterFac, param.Cosi.GetPublicKeyFactory()),
 		blockFac,
 		param.Mino.GetAddressFactory(),
 		param.Cosi.GetSignatureFactory(),
@@ -230,17 +337,25 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 		processor:                proc,
 		me:                       param.Mino.GetAddress(),
 		rpc:                      mino.MustCreateRPC(param.Mino, rpcName, proc, fac),
+		fanout:                   tmpl.fanout,
 		actor:                    actor,
 		val:                      param.Validation,
 		verifierFac:              param.Cosi.GetVerifierFactory(),
 		timeoutRound:             DefaultRoundTimeout,
 		timeoutRoundAfterFailure: DefaultFailedRoundTimeout,
 		transactionTimeout:       DefaultTransactionTimeout,
+		clock:                    tmpl.clock,
 		events:                   make(chan ordering.Event, 1),
+		bus:                      tmpl.bus,
 		closing:                  make(chan struct{}),
 		closed:                   make(chan struct{}),
 	}
 
+	// The tree is created whatever the fanout so that a node can relay the
+	// blocks of a leader that uses it.
+	s.propagation = broadcast.NewTree(param.Mino, propagationName, proc, fac,
+		broadcast.WithFanout(tmpl.fanout))
+
 	// Pool will filter the transaction that are already accepted by this
 	// service.
 	param.Pool.AddFilter(poolFilter{tree: proc.tree, srvc: param.Validation})
@@ -258,9 +373,12 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 	return s, nil
 }
 
-// Setup creates a genesis block and sends it to the collective authority.
-func (s *Service) Setup(ctx context.Context, ca crypto.CollectiveAuthority) error {
-	err := s.storeGenesis(authority.FromAuthority(ca), nil)
+// Setup creates a genesis block and sends it to the collective authority. The
+// options set the relays and the ordering policy of the chain.
+func (s *Service) Setup(ctx context.Context, ca crypto.CollectiveAuthority,
+	opts ...types.GenesisOption) error {
+
+	err := s.storeGenesis(authority.FromAuthority(ca), nil, opts...)
 	if err != nil {
 		return xerrors.Errorf("creating genesis: %v", err)
 	}
@@ -284,6 +402,8 @@ func (s *Service) Setup(ctx context.Context, ca crypto.CollectiveAuthority) erro
 
 	s.logger.Info().
 		Int("roster", ca.Len()).
+		Int("relays", len(genesis.GetRelays())).
+		Str("ordering", genesis.GetOrdering()).
 		Stringer("digest", genesis.GetHash()).
 		Msg("new chain has been created")
 
@@ -320,11 +440,52 @@ func (s *Service) GetStore() store.Readable {
 	return s.tree.Get()
 }
 
+// IsRelay returns true if the address is a relay according to the genesis
+// block, i.e. a member that must not hold any key share. It returns false when
+// the chain is not yet created.
+func (s *Service) IsRelay(addr mino.Address) bool {
+	genesis, err := s.genesis.Get()
+	if err != nil {
+		return false
+	}
+
+	return genesis.IsRelay(addr)
+}
+
+// GetThreshold returns the number of key shares required to release a key
+// according to the genesis block. It returns zero when the chain is not yet
+// created or does not set it.
+func (s *Service) GetThreshold() int {
+	genesis, err := s.genesis.Get()
+	if err != nil {
+		return 0
+	}
+
+	return genesis.GetThreshold()
+}
+
 // GetRoster returns the current roster of the service.
 func (s *Service) GetRoster() (authority.Authority, error) {
 	return s.getCurrentRoster()
 }
 
+// Synchronize sends the latest blocks to the participants of the current
+// roster, so that the ones behind can catch up without waiting for the next
+// round of the leader.
+func (s *Service) Synchronize(ctx context.Context) error {
+	roster, err := s.getCurrentRoster()
+	if err != nil {
+		return xerrors.Errorf("read roster failed: %v", err)
+	}
+
+	err = s.sync.Sync(ctx, roster, blocksync.Config{MinHard: threshold.ByzantineThreshold(roster.Len())})
+	if err != nil {
+		return xerrors.Errorf("sync failed: %v", err)
+	}
+
+	return nil
+}
+
 // Watch implements ordering.Service. It returns a channel that will be
 // populated with new incoming blocks and some information about them. The
 // channel must be listened at all time and the context must be closed when
@@ -363,9 +524,23 @@ func (s *Service) watchBlocks() {
 
 	linkCh := s.blocks.Watch(ctx)
 
+	// The interval is only measured between blocks finalized while the
+	// service is running.
+	var last time.Time
+
 	for link := range linkCh {
+		now := s.clock.Now()
+		if !last.IsZero() {
+			promBlockInterval.Observe(now.Sub(last).Seconds())
+		}
+
+		last = now
+
+		results := link.GetBlock().GetData().GetTransactionResults()
+		promBlockTxs.Observe(float64(len(results)))
+
 		// 1. Remove the transactions from the pool to avoid duplicates.
-		for _, res := range link.GetBlock().GetData().GetTransactionResults() {
+		for _, res := range results {
 			err := s.pool.Remove(res.GetTransaction())
 			if err != nil {
 				s.logger.Err(err).Msg("removing transaction")
@@ -380,7 +555,7 @@ func (s *Service) watchBlocks() {
 
 		event := ordering.Event{
 			Index:        link.GetBlock().GetIndex(),
-			Transactions: link.GetBlock().GetData().GetTransactionResults(),
+			Transactions: results,
 		}
 
 		// 3. Notify the main loop that a new block has been created, but ignore
@@ -393,6 +568,13 @@ func (s *Service) watchBlocks() {
 		// 4. Notify the new block to potential listeners.
 		s.watcher.Notify(event)
 
+		if s.bus != nil {
+			s.bus.Publish(events.BlockFinalized{
+				Index: event.Index,
+				Time:  link.GetBlock().GetTimestamp(),
+			})
+		}
+
 		s.logger.Info().
 			Uint64("index", link.GetBlock().GetIndex()).
 			Stringer("root", link.GetBlock().GetTreeRoot()).
@@ -441,7 +623,11 @@ func (s *Service) main() error {
 	for {
 		// When a round failure occurs, it sleeps with a given backoff to give a
 		// chance to the system to recover without exhausting the resources.
-		time.Sleep(calculateBackoff(backoff))
+		select {
+		case <-s.clock.After(calculateBackoff(backoff)):
+		case <-s.closing:
+			return nil
+		}
 
 		select {
 		case <-s.closing:
@@ -529,7 +715,7 @@ func (s *Service) doLeaderRound(ctx context.Context, roster authority.Authority,
 func (s *Service) doFollowerRound(ctx context.Context, roster authority.Authority) error {
 	// A follower has to wait for the new block, or the round timeout, to proceed.
 	select {
-	case <-time.After(s.timeoutRound):
+	case <-s.clock.After(s.timeoutRound):
 		if !s.roundHasFailed() {
 			return nil
 		}
@@ -598,7 +784,7 @@ func (s *Service) roundHasFailed() bool {
 		return false
 	}
 
-	if time.Since(stats.OldestTx) > s.transactionTimeout {
+	if s.clock.Now().Sub(stats.OldestTx) > s.transactionTimeout {
 		s.logger.Warn().Msg("found a rotten transaction")
 		s.failedRound = true
 	}
@@ -631,6 +817,11 @@ func (s *Service) doPBFT(ctx context.Context) error {
 			return ctx.Err()
 		}
 
+		txs, err := s.orderTransactions(txs, uint64(s.blocks.Len()))
+		if err != nil {
+			return xerrors.Errorf("failed to order transactions: %v", err)
+		}
+
 		data, root, err := s.prepareData(txs)
 		if err != nil {
 			return xerrors.Errorf("failed to prepare data: %v", err)
@@ -640,6 +831,8 @@ func (s *Service) doPBFT(ctx context.Context) error {
 			data,
 			types.WithTreeRoot(root),
 			types.WithIndex(uint64(s.blocks.Len())),
+			types.WithTimestamp(s.clock.Now()),
+			types.WithCiphertextRoot(types.NewCiphertextRoot(txs)),
 			types.WithHashFactory(s.hashFactory))
 
 		if err != nil {
@@ -678,24 +871,43 @@ func (s *Service) doPBFT(ctx context.Context) error {
 	s.logger.Debug().Str("signature", fmt.Sprintf("%v", sig)).Msg("commit done")
 
 	// 3. Propagation phase
-	done := types.NewDone(id, sig)
-
-	resps, err := s.rpc.Call(ctx, done, roster)
+	err = s.propagate(ctx, types.NewDone(id, sig), roster)
 	if err != nil {
 		return xerrors.Errorf("propagation failed: %v", err)
 	}
 
-	for resp := range resps {
-		_, err = resp.GetMessageOrError()
+	// 4. Wake up new participants so that they can learn about the chain.
+	err = s.wakeUp(ctx, roster)
+	if err != nil {
+		return xerrors.Errorf("wake up failed: %v", err)
+	}
+
+	return nil
+}
+
+// propagate sends the finalized block to the roster, either directly or along
+// a tree when a fanout is set. A participant that cannot be reached is only
+// reported, as it can catch up with the synchronization.
+func (s *Service) propagate(ctx context.Context, done types.DoneMessage, roster authority.Authority) error {
+	if s.fanout > 0 {
+		err := s.propagation.Broadcast(ctx, done, roster)
 		if err != nil {
 			s.logger.Warn().Err(err).Msg("propagation failed")
 		}
+
+		return nil
 	}
 
-	// 4. Wake up new participants so that they can learn about the chain.
-	err = s.wakeUp(ctx, roster)
+	resps, err := s.rpc.Call(ctx, done, roster)
 	if err != nil {
-		return xerrors.Errorf("wake up failed: %v", err)
+		return xerrors.Errorf("failed to call: %v", err)
+	}
+
+	for resp := range resps {
+		_, err = resp.GetMessageOrError()
+		if err != nil {
+			s.logger.Warn().Err(err).Msg("propagation failed")
+		}
 	}
 
 	return nil
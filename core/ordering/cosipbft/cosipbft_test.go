@@ -9,15 +9,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/access/darc"
+	"go.dedis.ch/dela/core/events"
 	"go.dedis.ch/dela/core/execution"
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
 	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/viewchange"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
 	"go.dedis.ch/dela/core/ordering/cosipbft/pbft"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/store"
@@ -35,8 +38,10 @@ import (
 	"go.dedis.ch/dela/cosi/threshold"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/internal/clock"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/broadcast"
 	"go.dedis.ch/dela/mino/gossip"
 	"go.dedis.ch/dela/mino/minoch"
 	"go.dedis.ch/dela/serde"
@@ -76,6 +81,16 @@ func TestService_Scenario_Basic(t *testing.T) {
 
 	evt = waitEvent(t, events, 20*DefaultRoundTimeout)
 	require.Equal(t, uint64(2), evt.Index)
+
+	// The link of the roster change embeds the digest of the new roster, so
+	// that the new member can verify the chain when it catches up.
+	link, err := nodes[0].service.blocks.GetByIndex(2)
+	require.NoError(t, err)
+
+	rosterDigest, err := types.RosterDigestOf(ro)
+	require.NoError(t, err)
+	require.Equal(t, rosterDigest, link.GetRosterDigest())
+
 	for i := 0; i < 3; i++ {
 		err = nodes[1].pool.Add(makeTx(t, uint64(i+3), signer))
 		require.NoError(t, err)
@@ -94,6 +109,94 @@ func TestService_Scenario_Basic(t *testing.T) {
 	checkProof(t, proof.(Proof), nodes[0].service)
 }
 
+func TestService_Scenario_BeaconOrdering(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 4)
+	defer clean()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := nodes[0].service.Setup(ctx, ro, types.WithOrdering(BeaconOrdering))
+	require.NoError(t, err)
+
+	genesis, err := nodes[3].service.genesis.Get()
+	require.NoError(t, err)
+	require.Equal(t, BeaconOrdering, genesis.GetOrdering())
+
+	events := nodes[3].service.Watch(ctx)
+
+	// The members order the transactions of every proposal again, so that
+	// the blocks are accepted only if the leader shuffled them with the
+	// randomness of the previous block.
+	included := 0
+
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 4; j++ {
+			err = nodes[0].pool.Add(makeTx(t, 0, bls.NewSigner()))
+			require.NoError(t, err)
+		}
+
+		for included < 4*(i+1) {
+			evt := waitEvent(t, events, 20*DefaultRoundTimeout)
+			included += len(evt.Transactions)
+		}
+	}
+}
+
+func TestService_Scenario_Propagation(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 7, WithPropagationFanout(2))
+	defer clean()
+
+	signer := nodes[0].signer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
+
+	events := nodes[6].service.Watch(ctx)
+
+	for i := 0; i < 2; i++ {
+		err = nodes[1].pool.Add(makeTx(t, uint64(i), signer))
+		require.NoError(t, err)
+
+		evt := waitEvent(t, events, 20*DefaultRoundTimeout)
+		require.Equal(t, uint64(i), evt.Index)
+	}
+
+	for _, node := range nodes {
+		require.Equal(t, uint64(2), node.service.blocks.Len())
+	}
+}
+
+func TestService_Scenario_Events(t *testing.T) {
+	bus := events.NewBus()
+
+	nodes, ro, clean := makeAuthority(t, 3, WithEvents(bus))
+	defer clean()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	finalized := bus.Subscribe(ctx)
+
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
+
+	err = nodes[0].pool.Add(makeTx(t, 0, nodes[0].signer))
+	require.NoError(t, err)
+
+	select {
+	case evt := <-finalized:
+		require.Equal(t, uint64(0), evt.GetIndex())
+		// The time of the block is the one agreed by the consensus.
+		require.False(t, evt.(events.BlockFinalized).Time.IsZero())
+	case <-time.After(20 * DefaultRoundTimeout):
+		t.Fatal("no block published on the bus")
+	}
+}
+
 func TestService_Scenario_ViewChange(t *testing.T) {
 	nodes, ro, clean := makeAuthority(t, 4)
 	defer clean()
@@ -242,11 +345,14 @@ func TestService_New(t *testing.T) {
 		WithHashFactory(fake.NewHashFactory(&fake.Hash{})),
 		WithGenesisStore(genesis),
 		WithBlockStore(blockstore.NewInMemory()),
+		WithEnrollment(enrollment.NewHolder()),
+		WithClock(fake.NewClock(time.Unix(0, 0))),
 	}
 
 	srvc, err := NewService(param, opts...)
 	require.NoError(t, err)
 	require.NotNil(t, srvc)
+	require.Equal(t, time.Unix(0, 0), srvc.clock.Now())
 
 	<-srvc.closed
 
@@ -258,7 +364,7 @@ func TestService_New(t *testing.T) {
 func TestService_Setup(t *testing.T) {
 	rpc := fake.NewRPC()
 
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.rpc = rpc
 	srvc.hashFactory = crypto.NewSha256Factory()
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
@@ -270,7 +376,11 @@ func TestService_Setup(t *testing.T) {
 	a := fake.NewAuthority(3, fake.NewSigner)
 	ctx := context.Background()
 
-	err := srvc.Setup(ctx, a)
+	require.False(t, srvc.IsRelay(fake.NewAddress(2)))
+	require.Equal(t, 0, srvc.GetThreshold())
+
+	err := srvc.Setup(ctx, a, types.WithRelays(fake.NewAddress(2)),
+		types.WithThreshold(2), types.WithOrdering(BeaconOrdering))
 	require.NoError(t, err)
 
 	_, more := <-srvc.started
@@ -279,11 +389,17 @@ func TestService_Setup(t *testing.T) {
 	genesis, err := srvc.genesis.Get()
 	require.NoError(t, err)
 	require.Equal(t, 3, genesis.GetRoster().Len())
+	require.Equal(t, BeaconOrdering, genesis.GetOrdering())
+
+	require.True(t, srvc.IsRelay(fake.NewAddress(2)))
+	require.False(t, srvc.IsRelay(fake.NewAddress(1)))
+	require.Equal(t, 2, srvc.GetThreshold())
 }
 
 func TestService_AlreadySet_Setup(t *testing.T) {
 	srvc := &Service{
 		processor: newProcessor(),
+		clock:     clock.NewReal(),
 	}
 
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
@@ -304,6 +420,7 @@ func TestService_AlreadySet_Setup(t *testing.T) {
 func TestService_FailReadGenesis_Setup(t *testing.T) {
 	srvc := &Service{
 		processor: newProcessor(),
+		clock:     clock.NewReal(),
 	}
 
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
@@ -322,6 +439,7 @@ func TestService_FailReadGenesis_Setup(t *testing.T) {
 func TestService_FailPropagate_Setup(t *testing.T) {
 	srvc := &Service{
 		processor: newProcessor(),
+		clock:     clock.NewReal(),
 	}
 
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
@@ -341,6 +459,7 @@ func TestService_FailPropagate_Setup(t *testing.T) {
 func TestService_RequestFailure_Setup(t *testing.T) {
 	srvc := &Service{
 		processor: newProcessor(),
+		clock:     clock.NewReal(),
 	}
 
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
@@ -361,7 +480,7 @@ func TestService_RequestFailure_Setup(t *testing.T) {
 }
 
 func TestService_Main(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 	srvc.closing = make(chan struct{})
 	srvc.closed = make(chan struct{})
@@ -399,6 +518,23 @@ func TestService_Main(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_RoundHasFailed(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := fake.NewClock(now)
+
+	srvc := &Service{
+		processor:          newProcessor(),
+		transactionTimeout: time.Minute,
+		clock:              clock,
+	}
+	srvc.pool = fakePool{oldest: now}
+
+	require.False(t, srvc.roundHasFailed())
+
+	clock.Advance(time.Minute + time.Second)
+	require.True(t, srvc.roundHasFailed())
+}
+
 func TestService_DoRound(t *testing.T) {
 	rpc := fake.NewRPC()
 	ch := make(chan pbft.State)
@@ -410,8 +546,11 @@ func TestService_DoRound(t *testing.T) {
 		timeoutRound:             time.Millisecond,
 		timeoutRoundAfterFailure: time.Millisecond,
 		closing:                  make(chan struct{}),
+		clock:                    clock.NewReal(),
 	}
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.sync = fakeSync{}
 	srvc.pool = mem.NewPool()
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
@@ -460,9 +599,12 @@ func TestService_ViewchangeFailed_DoRound(t *testing.T) {
 		rpc:                      rpc,
 		timeoutRound:             time.Millisecond,
 		timeoutRoundAfterFailure: time.Millisecond,
+		clock:                    clock.NewReal(),
 	}
 
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.pool = mem.NewPool()
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
@@ -487,9 +629,12 @@ func TestService_FailPBFTExpire_DoRound(t *testing.T) {
 		rpc:                      rpc,
 		timeoutRound:             time.Millisecond,
 		timeoutRoundAfterFailure: time.Millisecond,
+		clock:                    clock.NewReal(),
 	}
 
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.pool = mem.NewPool()
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
@@ -514,9 +659,12 @@ func TestService_FailSendViews_DoRound(t *testing.T) {
 		rpc:                      fake.NewBadRPC(),
 		timeoutRound:             time.Millisecond,
 		timeoutRoundAfterFailure: time.Millisecond,
+		clock:                    clock.NewReal(),
 	}
 
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.pool = mem.NewPool()
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
@@ -537,6 +685,7 @@ func TestService_FailReadRoster_DoRound(t *testing.T) {
 		me:                       fake.NewAddress(1),
 		timeoutRound:             time.Millisecond,
 		timeoutRoundAfterFailure: time.Millisecond,
+		clock:                    clock.NewReal(),
 	}
 
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
@@ -555,6 +704,7 @@ func TestService_FailReadLeader_DoRound(t *testing.T) {
 		me:                       fake.NewAddress(1),
 		timeoutRound:             time.Millisecond,
 		timeoutRoundAfterFailure: time.Millisecond,
+		clock:                    clock.NewReal(),
 	}
 
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
@@ -574,9 +724,12 @@ func TestService_FailSync_DoRound(t *testing.T) {
 		me:                       fake.NewAddress(0),
 		timeoutRound:             time.Millisecond,
 		timeoutRoundAfterFailure: time.Millisecond,
+		clock:                    clock.NewReal(),
 	}
 
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 	srvc.pbftsm = fakeSM{}
@@ -596,9 +749,12 @@ func TestService_FailPBFT_DoRound(t *testing.T) {
 		timeoutRound:             DefaultRoundTimeout,
 		timeoutRoundAfterFailure: DefaultFailedRoundTimeout,
 		val:                      fakeValidation{err: fake.GetError()},
+		clock:                    clock.NewReal(),
 	}
 
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.pool = mem.NewPool()
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
@@ -618,7 +774,7 @@ func TestService_FailPBFT_DoRound(t *testing.T) {
 func TestService_DoPBFT(t *testing.T) {
 	rpc := fake.NewRPC()
 
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.val = fakeValidation{}
 	srvc.blocks = blockstore.NewInMemory()
@@ -649,7 +805,7 @@ func TestService_DoPBFT(t *testing.T) {
 }
 
 func TestService_ContextCanceld_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{err: fake.GetError()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
@@ -665,10 +821,13 @@ func TestService_ContextCanceld_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailValidation_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{err: fake.GetError()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.pool = mem.NewPool()
 
 	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
@@ -683,13 +842,15 @@ func TestService_FailValidation_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailCreateBlock_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
 	srvc.pool = mem.NewPool()
 	srvc.hashFactory = fake.NewHashFactory(fake.NewBadHash())
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 
 	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
 
@@ -702,13 +863,15 @@ func TestService_FailCreateBlock_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailPrepare_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{err: fake.GetError()}
 	srvc.pool = mem.NewPool()
 	srvc.hashFactory = crypto.NewSha256Factory()
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 
 	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
 
@@ -720,13 +883,15 @@ func TestService_FailPrepare_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailReadRoster_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{err: fake.GetError()})
 	srvc.pbftsm = fakeSM{}
 	srvc.pool = mem.NewPool()
 	srvc.hashFactory = crypto.NewSha256Factory()
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 
 	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
 
@@ -738,13 +903,15 @@ func TestService_FailReadRoster_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailPrepareSig_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
 	srvc.pool = mem.NewPool()
 	srvc.hashFactory = crypto.NewSha256Factory()
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.actor = fakeCosiActor{err: fake.GetError()}
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 
@@ -758,13 +925,15 @@ func TestService_FailPrepareSig_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailCommitSign_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
 	srvc.pool = mem.NewPool()
 	srvc.hashFactory = crypto.NewSha256Factory()
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.actor = fakeCosiActor{
 		err:     fake.GetError(),
 		counter: fake.NewCounter(1),
@@ -781,13 +950,15 @@ func TestService_FailCommitSign_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailPropagation_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
 	srvc.pool = mem.NewPool()
 	srvc.hashFactory = crypto.NewSha256Factory()
 	srvc.blocks = blockstore.NewInMemory()
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
 	srvc.actor = fakeCosiActor{}
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 	srvc.rpc = fake.NewBadRPC()
@@ -798,14 +969,35 @@ func TestService_FailPropagation_DoPBFT(t *testing.T) {
 	defer cancel()
 
 	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err, fake.Err("propagation failed"))
+	require.EqualError(t, err, fake.Err("propagation failed: failed to call"))
+}
+
+func TestService_Propagate(t *testing.T) {
+	srvc := &Service{
+		processor:   newProcessor(),
+		fanout:      1,
+		propagation: broadcast.NewTree(fake.Mino{}, "test", nil, nil),
+		clock:       clock.NewReal(),
+	}
+
+	logger := fake.NewLogger()
+	srvc.logger = logger.GetLogger()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A participant that is not reached is only reported.
+	err := srvc.propagate(ctx, types.NewDone(types.Digest{}, nil), authority.New(
+		[]mino.Address{fake.NewAddress(0)}, []crypto.PublicKey{fake.PublicKey{}}))
+	require.NoError(t, err)
+	require.True(t, logger.Has(zerolog.WarnLevel, "propagation failed"))
 }
 
 func TestService_FailWakeUp_DoPBFT(t *testing.T) {
 	rpc := fake.NewRPC()
 	rpc.Done()
 
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.val = fakeValidation{}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
@@ -815,7 +1007,10 @@ func TestService_FailWakeUp_DoPBFT(t *testing.T) {
 	srvc.actor = fakeCosiActor{}
 	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 	srvc.rpc = rpc
-	srvc.genesis = blockstore.NewGenesisStore()
+
+	// The genesis block is read to order the transactions, and then when
+	// waking up the new members.
+	srvc.genesis = fakeGenesisStore{errGet: fake.GetError(), counter: fake.NewCounter(1)}
 
 	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
 
@@ -823,13 +1018,13 @@ func TestService_FailWakeUp_DoPBFT(t *testing.T) {
 	defer cancel()
 
 	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err, "wake up failed: read genesis failed: missing genesis block")
+	require.EqualError(t, err, fake.Err("wake up failed: read genesis failed"))
 }
 
 func TestService_WakeUp(t *testing.T) {
 	rpc := fake.NewRPC()
 
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.genesis = blockstore.NewGenesisStore()
 	srvc.genesis.Set(types.Genesis{})
@@ -856,7 +1051,7 @@ func TestService_WakeUp(t *testing.T) {
 }
 
 func TestService_GetProof(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.blocks = blockstore.NewInMemory()
 	srvc.blocks.Store(makeBlock(t, types.Digest{}))
@@ -876,14 +1071,14 @@ func TestService_GetProof(t *testing.T) {
 }
 
 func TestService_GetStore(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 
 	require.IsType(t, fakeTree{}, srvc.GetStore())
 }
 
 func TestService_GetRoster(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.rosterFac = fakeRosterFac{}
 
@@ -892,6 +1087,27 @@ func TestService_GetRoster(t *testing.T) {
 	require.Equal(t, 3, roster.Len())
 }
 
+func TestService_Synchronize(t *testing.T) {
+	srvc := &Service{processor: newProcessor(), clock: clock.NewReal()}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.rosterFac = fakeRosterFac{}
+	srvc.sync = fakeSync{}
+
+	err := srvc.Synchronize(context.Background())
+	require.NoError(t, err)
+
+	srvc.sync = fakeSync{err: fake.GetError()}
+
+	err = srvc.Synchronize(context.Background())
+	require.EqualError(t, err, fake.Err("sync failed"))
+
+	srvc.rosterFac = badRosterFac{}
+
+	err = srvc.Synchronize(context.Background())
+	require.Error(t, err)
+	require.Regexp(t, "^read roster failed: ", err.Error())
+}
+
 func TestService_PoolFilter(t *testing.T) {
 	filter := poolFilter{
 		tree: blockstore.NewTreeCache(fakeTree{}),
@@ -976,7 +1192,7 @@ func waitEvent(t *testing.T, events <-chan ordering.Event, timeout time.Duration
 	}
 }
 
-func makeAuthority(t *testing.T, n int) ([]testNode, authority.Authority, func()) {
+func makeAuthority(t *testing.T, n int, opts ...ServiceOption) ([]testNode, authority.Authority, func()) {
 	manager := minoch.NewManager()
 
 	addrs := make([]mino.Address, n)
@@ -1027,7 +1243,7 @@ func makeAuthority(t *testing.T, n int) ([]testNode, authority.Authority, func()
 			DB:         db,
 		}
 
-		srv, err := NewService(param)
+		srv, err := NewService(param, opts...)
 		require.NoError(t, err)
 
 		nodes[i] = testNode{
@@ -1063,11 +1279,20 @@ func (fac badRosterFac) AuthorityOf(serde.Context, []byte) (authority.Authority,
 
 type fakePool struct {
 	pool.Pool
+
+	// oldest is the arrival time of the oldest transaction, or a long time
+	// ago if zero.
+	oldest time.Time
 }
 
 func (f fakePool) Stats() pool.Stats {
+	oldest := f.oldest
+	if oldest.IsZero() {
+		oldest = time.Now().Add(-100 * time.Hour)
+	}
+
 	return pool.Stats{
-		OldestTx: time.Now().Add(-100 * time.Hour),
+		OldestTx: oldest,
 		TxCount:  1,
 	}
 }
@@ -1155,3 +1380,14 @@ type fakeAccess struct {
 func (srvc fakeAccess) Grant(store.Snapshot, access.Credential, ...access.Identity) error {
 	return srvc.err
 }
+
+type fakePolicy struct {
+	seed []byte
+	err  error
+}
+
+func (p *fakePolicy) Order(txs []txn.Transaction, seed []byte) ([]txn.Transaction, error) {
+	p.seed = seed
+
+	return txs, p.err
+}
@@ -0,0 +1,325 @@
+// Package discovery implements the discovery of the roster through seed nodes.
+//
+// Instead of configuring every node with the full list of the participants, a
+// node announces itself to one or more seed nodes that it knows, and learns
+// the roster from them. A node announces its address with a signature of its
+// public key, so that a seed only accepts the participants that own their
+// key. A seed replies with a roster document, which is the roster it knows
+// signed by its own key, so that the document can be verified against the
+// key configured for the seed.
+//
+// Every node serves the discovery, so that any node of the network can be
+// used as a seed.
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/discovery/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+const rpcName = "discovery"
+
+// Service is the discovery service of a node. It keeps the roster of the
+// participants that announced themselves, and learns the roster of the seeds
+// it contacts.
+type Service struct {
+	sync.Mutex
+
+	me     mino.Address
+	signer crypto.Signer
+	ca     crypto.PublicKey
+	certs  *enrollment.Holder
+	roster authority.Authority
+	rpc    mino.RPC
+}
+
+// NewService creates a new discovery service for the node. The roster
+// initially contains only the node itself. The node admits the participants
+// that present a certificate issued by the authority, and refuses every
+// announcement if the authority is nil. The holder provides the certificate
+// of the node for its own announcements.
+func NewService(m mino.Mino, signer crypto.Signer, ca crypto.PublicKey,
+	certs *enrollment.Holder) *Service {
+
+	srvc := &Service{
+		me:     m.GetAddress(),
+		signer: signer,
+		ca:     ca,
+		certs:  certs,
+		roster: authority.New(
+			[]mino.Address{m.GetAddress()},
+			[]crypto.PublicKey{signer.GetPublicKey()},
+		),
+	}
+
+	rosterFac := authority.NewFactory(m.GetAddressFactory(), signer.GetPublicKeyFactory())
+
+	// The authority is expected to use the same kind of key as the nodes.
+	certFac := enrollment.NewCertificateFactory(m.GetAddressFactory(),
+		signer.GetPublicKeyFactory(), signer.GetSignatureFactory())
+
+	fac := types.NewMessageFactory(m.GetAddressFactory(), signer.GetPublicKeyFactory(),
+		signer.GetSignatureFactory(), rosterFac, certFac)
+
+	srvc.rpc = mino.MustCreateRPC(m, rpcName, handler{Service: srvc}, fac)
+
+	return srvc
+}
+
+// GetRoster returns the roster currently known by the node.
+func (s *Service) GetRoster() authority.Authority {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.roster
+}
+
+// Announce announces the node to the seed and returns the roster of the seed,
+// including the node, after verifying the signature of the document with the
+// public key of the seed. The roster of the node is updated accordingly. The
+// node must be enrolled.
+func (s *Service) Announce(ctx context.Context, seed mino.Address,
+	seedKey crypto.PublicKey) (authority.Authority, error) {
+
+	cert := s.certs.Get()
+	if cert == nil {
+		return nil, xerrors.New("node is not enrolled")
+	}
+
+	addr, err := s.me.MarshalText()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal address: %v", err)
+	}
+
+	sig, err := s.signer.Sign(addr)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	ann := types.NewAnnouncement(s.me, s.signer.GetPublicKey(), sig, *cert)
+
+	roster, err := s.request(ctx, seed, seedKey, ann)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to announce: %v", err)
+	}
+
+	return roster, nil
+}
+
+// Fetch returns the roster of the seed after verifying the signature of the
+// document with the public key of the seed. The roster of the node is updated
+// accordingly.
+func (s *Service) Fetch(ctx context.Context, seed mino.Address,
+	seedKey crypto.PublicKey) (authority.Authority, error) {
+
+	roster, err := s.request(ctx, seed, seedKey, types.NewRosterRequest())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fetch: %v", err)
+	}
+
+	return roster, nil
+}
+
+func (s *Service) request(ctx context.Context, seed mino.Address,
+	seedKey crypto.PublicKey, req serde.Message) (authority.Authority, error) {
+
+	resps, err := s.rpc.Call(ctx, req, mino.NewAddresses(seed))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to call: %v", err)
+	}
+
+	var resp mino.Response
+
+	select {
+	case <-ctx.Done():
+		return nil, xerrors.Errorf("context done: %v", ctx.Err())
+	case r, more := <-resps:
+		if !more {
+			return nil, xerrors.New("no reply")
+		}
+
+		resp = r
+	}
+
+	msg, err := resp.GetMessageOrError()
+	if err != nil {
+		return nil, xerrors.Errorf("seed replied with an error: %v", err)
+	}
+
+	doc, ok := msg.(types.RosterDocument)
+	if !ok {
+		return nil, xerrors.Errorf("unexpected reply '%T'", msg)
+	}
+
+	digest, err := fingerprint(doc.GetRoster())
+	if err != nil {
+		return nil, err
+	}
+
+	err = seedKey.Verify(digest, doc.GetSignature())
+	if err != nil {
+		return nil, xerrors.Errorf("invalid document: %v", err)
+	}
+
+	err = s.merge(doc.GetRoster())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to merge roster: %v", err)
+	}
+
+	return doc.GetRoster(), nil
+}
+
+// merge adds the participants of the roster that are not yet known. It
+// returns an error if a participant is known with a different public key.
+func (s *Service) merge(roster authority.Authority) error {
+	s.Lock()
+	defer s.Unlock()
+
+	cset := authority.NewChangeSet()
+
+	addrIter := roster.AddressIterator()
+	pkIter := roster.PublicKeyIterator()
+
+	for addrIter.HasNext() && pkIter.HasNext() {
+		addr := addrIter.GetNext()
+		pubkey := pkIter.GetNext()
+
+		known, _ := s.roster.GetPublicKey(addr)
+		if known == nil {
+			cset.Add(addr, pubkey)
+		} else if !known.Equal(pubkey) {
+			return xerrors.Errorf("mismatching public key for %v", addr)
+		}
+	}
+
+	s.roster = s.roster.Apply(cset)
+
+	return nil
+}
+
+// document returns the roster known by the node, signed by the node.
+func (s *Service) document() (types.RosterDocument, error) {
+	roster := s.GetRoster()
+
+	digest, err := fingerprint(roster)
+	if err != nil {
+		return types.RosterDocument{}, err
+	}
+
+	sig, err := s.signer.Sign(digest)
+	if err != nil {
+		return types.RosterDocument{}, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	return types.NewRosterDocument(roster, sig), nil
+}
+
+func fingerprint(roster authority.Authority) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+
+	err := roster.Fingerprint(buffer)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fingerprint roster: %v", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// handler processes the announcements and the requests for the roster.
+//
+// - implements mino.Handler
+type handler struct {
+	*Service
+	mino.UnsupportedHandler
+}
+
+// Process implements mino.Handler. It adds the participant of an announcement
+// to the roster, and replies with the roster document of the node.
+func (h handler) Process(req mino.Request) (serde.Message, error) {
+	switch msg := req.Message.(type) {
+	case types.Announcement:
+		err := h.add(req.Address, msg)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid announcement: %v", err)
+		}
+
+		dela.Logger.Info().
+			Stringer("addr", msg.GetAddress()).
+			Msg("participant announced")
+	case types.RosterRequest:
+	default:
+		return nil, xerrors.Errorf("unsupported message '%T'", req.Message)
+	}
+
+	doc, err := h.document()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create document: %v", err)
+	}
+
+	return doc, nil
+}
+
+func (h handler) add(from mino.Address, ann types.Announcement) error {
+	if !ann.GetAddress().Equal(from) {
+		return xerrors.Errorf("address %v does not match the sender %v",
+			ann.GetAddress(), from)
+	}
+
+	addr, err := ann.GetAddress().MarshalText()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal address: %v", err)
+	}
+
+	err = ann.GetPublicKey().Verify(addr, ann.GetSignature())
+	if err != nil {
+		return xerrors.Errorf("invalid signature: %v", err)
+	}
+
+	err = h.admit(ann)
+	if err != nil {
+		return xerrors.Errorf("not admitted: %v", err)
+	}
+
+	roster := authority.New(
+		[]mino.Address{ann.GetAddress()},
+		[]crypto.PublicKey{ann.GetPublicKey()},
+	)
+
+	return h.merge(roster)
+}
+
+// admit returns nil if the certificate of the announcement is issued by the
+// authority to the announced address and public key, otherwise an error.
+func (h handler) admit(ann types.Announcement) error {
+	if h.ca == nil {
+		return xerrors.New("no authority")
+	}
+
+	cert := ann.GetCertificate()
+	if cert.GetAddress() == nil || cert.GetPublicKey() == nil {
+		return xerrors.New("missing certificate")
+	}
+
+	if !cert.GetAddress().Equal(ann.GetAddress()) ||
+		!cert.GetPublicKey().Equal(ann.GetPublicKey()) {
+
+		return xerrors.New("certificate is issued to another participant")
+	}
+
+	err := cert.Verify(h.ca)
+	if err != nil {
+		return xerrors.Errorf("invalid certificate: %v", err)
+	}
+
+	return nil
+}
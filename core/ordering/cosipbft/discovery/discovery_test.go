@@ -0,0 +1,307 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/discovery/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/minoch"
+)
+
+func TestService_Scenario(t *testing.T) {
+	manager := minoch.NewManager()
+
+	ca := bls.Generate()
+
+	n := 5
+	srvcs := make([]*Service, n)
+	signers := make([]crypto.Signer, n)
+
+	for i := range srvcs {
+		m := minoch.MustCreate(manager, fmt.Sprintf("node%d", i))
+
+		signers[i] = bls.Generate()
+
+		cert, err := enrollment.Issue(ca, m.GetAddress(), signers[i].GetPublicKey())
+		require.NoError(t, err)
+
+		certs := enrollment.NewHolder()
+		certs.Set(cert)
+
+		srvcs[i] = NewService(m, signers[i], ca.GetPublicKey(), certs)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	seed := srvcs[0].me
+	seedKey := signers[0].GetPublicKey()
+
+	for _, srvc := range srvcs[1:] {
+		_, err := srvc.Announce(ctx, seed, seedKey)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, n, srvcs[0].GetRoster().Len())
+
+	// The first node to announce itself only knows the seed and itself, until
+	// it fetches the roster again.
+	require.Equal(t, 2, srvcs[1].GetRoster().Len())
+
+	roster, err := srvcs[1].Fetch(ctx, seed, seedKey)
+	require.NoError(t, err)
+	require.Equal(t, n, roster.Len())
+	require.Equal(t, n, srvcs[1].GetRoster().Len())
+
+	for i, signer := range signers {
+		pubkey, _ := roster.GetPublicKey(srvcs[i].me)
+		require.NotNil(t, pubkey)
+		require.True(t, pubkey.Equal(signer.GetPublicKey()))
+	}
+
+	// A document signed by another key than the one of the seed is refused.
+	_, err = srvcs[2].Fetch(ctx, seed, signers[1].GetPublicKey())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to fetch: invalid document: ")
+
+	// A node enrolled by another authority is not admitted.
+	m := minoch.MustCreate(manager, "intruder")
+	signer := bls.Generate()
+
+	cert, err := enrollment.Issue(bls.Generate(), m.GetAddress(), signer.GetPublicKey())
+	require.NoError(t, err)
+
+	certs := enrollment.NewHolder()
+	certs.Set(cert)
+
+	_, err = NewService(m, signer, ca.GetPublicKey(), certs).Announce(ctx, seed, seedKey)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not admitted: invalid certificate: ")
+	require.Equal(t, n, srvcs[0].GetRoster().Len())
+}
+
+func TestService_Announce(t *testing.T) {
+	srvc := &Service{
+		me:     fake.NewBadAddress(),
+		signer: fake.NewSigner(),
+		certs:  enrollment.NewHolder(),
+	}
+
+	_, err := srvc.Announce(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, "node is not enrolled")
+
+	srvc.certs.Set(enrollment.NewCertificate(fake.NewAddress(0), fake.PublicKey{},
+		fake.Signature{}))
+
+	_, err = srvc.Announce(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+
+	srvc.me = fake.NewAddress(0)
+	srvc.signer = fake.NewBadSigner()
+
+	_, err = srvc.Announce(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, fake.Err("failed to sign"))
+
+	srvc.signer = fake.NewSigner()
+	srvc.rpc = fake.NewBadRPC()
+
+	_, err = srvc.Announce(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, fake.Err("failed to announce: failed to call"))
+}
+
+func TestService_Fetch(t *testing.T) {
+	srvc := &Service{
+		signer: fake.NewSigner(),
+		roster: authority.New(nil, nil),
+		rpc:    fake.NewBadRPC(),
+	}
+
+	_, err := srvc.Fetch(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, fake.Err("failed to fetch: failed to call"))
+
+	rpc := fake.NewRPC()
+	rpc.Done()
+	srvc.rpc = rpc
+
+	_, err = srvc.Fetch(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, "failed to fetch: no reply")
+
+	srvc.rpc = fake.NewRPC()
+
+	_, err = srvc.Fetch(fake.ContextWithImmediateCancel(), fake.NewAddress(0),
+		fake.PublicKey{})
+	require.EqualError(t, err, "failed to fetch: context done: context canceled")
+
+	// The seed does not reply before the context is canceled.
+	rpc = fake.NewRPC()
+	srvc.rpc = rpc
+
+	ctx, cancel := fake.ContextCancelAfterCalls(rpc.Calls, 1)
+	defer cancel()
+
+	_, err = srvc.Fetch(ctx, fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, "failed to fetch: context done: context canceled")
+	require.Equal(t, 1, rpc.Calls.Len())
+
+	rpc = fake.NewRPC()
+	rpc.SendResponseWithError(fake.NewAddress(0), fake.GetError())
+	srvc.rpc = rpc
+
+	_, err = srvc.Fetch(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, fake.Err("failed to fetch: seed replied with an error"))
+
+	rpc = fake.NewRPC()
+	rpc.SendResponse(fake.NewAddress(0), fake.Message{})
+	srvc.rpc = rpc
+
+	_, err = srvc.Fetch(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, "failed to fetch: unexpected reply 'fake.Message'")
+
+	bad := authority.New([]mino.Address{fake.NewBadAddress()}, []crypto.PublicKey{fake.PublicKey{}})
+
+	rpc = fake.NewRPC()
+	rpc.SendResponse(fake.NewAddress(0), types.NewRosterDocument(bad, fake.Signature{}))
+	srvc.rpc = rpc
+
+	_, err = srvc.Fetch(context.Background(), fake.NewAddress(0), fake.PublicKey{})
+	require.EqualError(t, err, fake.Err("failed to fetch: failed to fingerprint roster: couldn't marshal address"))
+
+	roster := authority.New([]mino.Address{fake.NewAddress(1)}, []crypto.PublicKey{fake.PublicKey{}})
+
+	rpc = fake.NewRPC()
+	rpc.SendResponse(fake.NewAddress(0), types.NewRosterDocument(roster, fake.Signature{}))
+	srvc.rpc = rpc
+
+	_, err = srvc.Fetch(context.Background(), fake.NewAddress(0), fake.NewInvalidPublicKey())
+	require.EqualError(t, err, fake.Err("failed to fetch: invalid document"))
+}
+
+func TestService_Merge(t *testing.T) {
+	srvc := &Service{
+		roster: authority.New([]mino.Address{fake.NewAddress(0)}, []crypto.PublicKey{fake.PublicKey{}}),
+	}
+
+	roster := authority.New(
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]crypto.PublicKey{fake.PublicKey{}, fake.PublicKey{}},
+	)
+
+	err := srvc.merge(roster)
+	require.NoError(t, err)
+	require.Equal(t, 2, srvc.GetRoster().Len())
+
+	roster = authority.New([]mino.Address{fake.NewAddress(1)}, []crypto.PublicKey{bls.Generate().GetPublicKey()})
+
+	err = srvc.merge(roster)
+	require.EqualError(t, err, "mismatching public key for fake.Address[1]")
+	require.Equal(t, 2, srvc.GetRoster().Len())
+}
+
+func TestHandler_Process(t *testing.T) {
+	ca := bls.Generate()
+	signer := bls.Generate()
+
+	h := handler{
+		Service: &Service{
+			signer: fake.NewSigner(),
+			ca:     ca.GetPublicKey(),
+			roster: authority.New(nil, nil),
+		},
+	}
+
+	from := fake.NewAddress(1)
+
+	addr, err := from.MarshalText()
+	require.NoError(t, err)
+
+	sig, err := signer.Sign(addr)
+	require.NoError(t, err)
+
+	cert, err := enrollment.Issue(ca, from, signer.GetPublicKey())
+	require.NoError(t, err)
+
+	ann := types.NewAnnouncement(from, signer.GetPublicKey(), sig, cert)
+
+	resp, err := h.Process(mino.Request{Address: from, Message: ann})
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.(types.RosterDocument).GetRoster().Len())
+
+	// Announcing again is idempotent.
+	resp, err = h.Process(mino.Request{Address: from, Message: ann})
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.(types.RosterDocument).GetRoster().Len())
+
+	resp, err = h.Process(mino.Request{Message: types.NewRosterRequest()})
+	require.NoError(t, err)
+	require.Equal(t, h.GetRoster(), resp.(types.RosterDocument).GetRoster())
+
+	_, err = h.Process(mino.Request{Message: fake.Message{}})
+	require.EqualError(t, err, "unsupported message 'fake.Message'")
+
+	// A node cannot announce another address than its own.
+	_, err = h.Process(mino.Request{Address: fake.NewAddress(2), Message: ann})
+	require.EqualError(t, err, "invalid announcement: address fake.Address[1] "+
+		"does not match the sender fake.Address[2]")
+
+	other := bls.Generate()
+	ann = types.NewAnnouncement(from, other.GetPublicKey(), sig, cert)
+
+	_, err = h.Process(mino.Request{Address: from, Message: ann})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid announcement: invalid signature: ")
+
+	sig, err = other.Sign(addr)
+	require.NoError(t, err)
+
+	ann = types.NewAnnouncement(from, other.GetPublicKey(), sig, enrollment.Certificate{})
+
+	_, err = h.Process(mino.Request{Address: from, Message: ann})
+	require.EqualError(t, err, "invalid announcement: not admitted: missing certificate")
+
+	ann = types.NewAnnouncement(from, other.GetPublicKey(), sig, cert)
+
+	_, err = h.Process(mino.Request{Address: from, Message: ann})
+	require.EqualError(t, err, "invalid announcement: not admitted: "+
+		"certificate is issued to another participant")
+
+	cert, err = enrollment.Issue(other, from, other.GetPublicKey())
+	require.NoError(t, err)
+
+	ann = types.NewAnnouncement(from, other.GetPublicKey(), sig, cert)
+
+	_, err = h.Process(mino.Request{Address: from, Message: ann})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid announcement: not admitted: invalid certificate: ")
+
+	cert, err = enrollment.Issue(ca, from, other.GetPublicKey())
+	require.NoError(t, err)
+
+	ann = types.NewAnnouncement(from, other.GetPublicKey(), sig, cert)
+
+	_, err = h.Process(mino.Request{Address: from, Message: ann})
+	require.EqualError(t, err, "invalid announcement: mismatching public key for fake.Address[1]")
+
+	h.ca = nil
+
+	_, err = h.Process(mino.Request{Address: from, Message: ann})
+	require.EqualError(t, err, "invalid announcement: not admitted: no authority")
+
+	ann = types.NewAnnouncement(fake.NewBadAddress(), other.GetPublicKey(), sig, cert)
+
+	_, err = h.Process(mino.Request{Address: fake.NewBadAddress(), Message: ann})
+	require.EqualError(t, err, fake.Err("invalid announcement: failed to marshal address"))
+
+	h.signer = fake.NewBadSigner()
+
+	_, err = h.Process(mino.Request{Message: types.NewRosterRequest()})
+	require.EqualError(t, err, fake.Err("failed to create document: failed to sign"))
+}
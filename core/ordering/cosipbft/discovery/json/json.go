@@ -0,0 +1,206 @@
+package json
+
+import (
+	"encoding/json"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/discovery/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+func init() {
+	types.RegisterMessageFormat(serde.FormatJSON, msgFormat{})
+}
+
+// AnnouncementJSON is the JSON representation of an announcement.
+type AnnouncementJSON struct {
+	Address     []byte
+	PublicKey   json.RawMessage
+	Signature   json.RawMessage
+	Certificate json.RawMessage
+}
+
+// RosterRequestJSON is the JSON representation of a request for the roster.
+type RosterRequestJSON struct{}
+
+// RosterDocumentJSON is the JSON representation of a document of the roster.
+type RosterDocumentJSON struct {
+	Roster    json.RawMessage
+	Signature json.RawMessage
+}
+
+// MessageJSON is the JSON representation of a discovery message.
+type MessageJSON struct {
+	Announcement *AnnouncementJSON   `json:",omitempty"`
+	Request      *RosterRequestJSON  `json:",omitempty"`
+	Document     *RosterDocumentJSON `json:",omitempty"`
+}
+
+// MsgFormat is the format engine to encode and decode discovery messages.
+//
+// - implements serde.FormatEngine
+type msgFormat struct{}
+
+// Encode implements serde.FormatEngine. It returns the JSON data of the message
+// if appropriate, otherwise an error.
+func (msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	var m MessageJSON
+
+	switch in := msg.(type) {
+	case types.Announcement:
+		addr, err := in.GetAddress().MarshalText()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal address: %v", err)
+		}
+
+		pubkey, err := in.GetPublicKey().Serialize(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to serialize public key: %v", err)
+		}
+
+		sig, err := in.GetSignature().Serialize(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to serialize signature: %v", err)
+		}
+
+		cert, err := in.GetCertificate().Serialize(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to serialize certificate: %v", err)
+		}
+
+		m.Announcement = &AnnouncementJSON{
+			Address:     addr,
+			PublicKey:   pubkey,
+			Signature:   sig,
+			Certificate: cert,
+		}
+	case types.RosterRequest:
+		m.Request = &RosterRequestJSON{}
+	case types.RosterDocument:
+		roster, err := in.GetRoster().Serialize(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to serialize roster: %v", err)
+		}
+
+		sig, err := in.GetSignature().Serialize(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to serialize signature: %v", err)
+		}
+
+		m.Document = &RosterDocumentJSON{
+			Roster:    roster,
+			Signature: sig,
+		}
+	default:
+		return nil, xerrors.Errorf("unsupported message '%T'", msg)
+	}
+
+	data, err := ctx.Marshal(m)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal failed: %v", err)
+	}
+
+	return data, nil
+}
+
+// Decode implements serde.FormatEngine. It returns the message associated to
+// the data if appropriate, otherwise an error.
+func (msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	m := MessageJSON{}
+	err := ctx.Unmarshal(data, &m)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal failed: %v", err)
+	}
+
+	switch {
+	case m.Announcement != nil:
+		return decodeAnnouncement(ctx, m.Announcement)
+	case m.Request != nil:
+		return types.NewRosterRequest(), nil
+	case m.Document != nil:
+		return decodeDocument(ctx, m.Document)
+	}
+
+	return nil, xerrors.New("message is empty")
+}
+
+func decodeAnnouncement(ctx serde.Context, m *AnnouncementJSON) (serde.Message, error) {
+	factory := ctx.GetFactory(types.AddrKey{})
+
+	addrFac, ok := factory.(mino.AddressFactory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid address factory '%T'", factory)
+	}
+
+	factory = ctx.GetFactory(types.PublicKeyKey{})
+
+	pkFac, ok := factory.(crypto.PublicKeyFactory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid public key factory '%T'", factory)
+	}
+
+	pubkey, err := pkFac.PublicKeyOf(ctx, m.PublicKey)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize public key: %v", err)
+	}
+
+	sig, err := decodeSignature(ctx, m.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	factory = ctx.GetFactory(types.CertificateKey{})
+
+	certFac, ok := factory.(enrollment.Factory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid certificate factory '%T'", factory)
+	}
+
+	cert, err := certFac.CertificateOf(ctx, m.Certificate)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize certificate: %v", err)
+	}
+
+	return types.NewAnnouncement(addrFac.FromText(m.Address), pubkey, sig, cert), nil
+}
+
+func decodeDocument(ctx serde.Context, m *RosterDocumentJSON) (serde.Message, error) {
+	factory := ctx.GetFactory(types.RosterKey{})
+
+	rosterFac, ok := factory.(authority.Factory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid roster factory '%T'", factory)
+	}
+
+	roster, err := rosterFac.AuthorityOf(ctx, m.Roster)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize roster: %v", err)
+	}
+
+	sig, err := decodeSignature(ctx, m.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewRosterDocument(roster, sig), nil
+}
+
+func decodeSignature(ctx serde.Context, data []byte) (crypto.Signature, error) {
+	factory := ctx.GetFactory(types.SignatureKey{})
+
+	sigFac, ok := factory.(crypto.SignatureFactory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid signature factory '%T'", factory)
+	}
+
+	sig, err := sigFac.SignatureOf(ctx, data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize signature: %v", err)
+	}
+
+	return sig, nil
+}
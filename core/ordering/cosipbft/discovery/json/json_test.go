@@ -0,0 +1,214 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/discovery/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/serde"
+)
+
+func init() {
+	enrollment.RegisterCertificateFormat(fake.GoodFormat, fakeCertFormat{})
+	enrollment.RegisterCertificateFormat(fake.BadFormat, fake.NewBadFormat())
+}
+
+func TestMsgFormat_Announcement_Encode(t *testing.T) {
+	format := msgFormat{}
+
+	ctx := fake.NewContext()
+
+	cert := enrollment.Certificate{}
+
+	ann := types.NewAnnouncement(fake.NewAddress(1), fake.PublicKey{}, fake.Signature{}, cert)
+
+	data, err := format.Encode(ctx, ann)
+	require.NoError(t, err)
+	require.Equal(t, `{"Announcement":{"Address":"AQAAAA==","PublicKey":{},"Signature":{},`+
+		`"Certificate":{}}}`, string(data))
+
+	_, err = format.Encode(ctx, fake.Message{})
+	require.EqualError(t, err, "unsupported message 'fake.Message'")
+
+	ann = types.NewAnnouncement(fake.NewBadAddress(), fake.PublicKey{}, fake.Signature{}, cert)
+	_, err = format.Encode(ctx, ann)
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+
+	ann = types.NewAnnouncement(fake.NewAddress(1), fake.NewBadPublicKey(), fake.Signature{}, cert)
+	_, err = format.Encode(ctx, ann)
+	require.EqualError(t, err, fake.Err("failed to serialize public key"))
+
+	ann = types.NewAnnouncement(fake.NewAddress(1), fake.PublicKey{}, fake.NewBadSignature(), cert)
+	_, err = format.Encode(ctx, ann)
+	require.EqualError(t, err, fake.Err("failed to serialize signature"))
+
+	ann = types.NewAnnouncement(fake.NewAddress(1), fake.PublicKey{}, fake.Signature{}, cert)
+	_, err = format.Encode(fake.NewBadContext(), ann)
+	require.EqualError(t, err, fake.Err("failed to serialize certificate: failed to encode"))
+
+	_, err = format.Encode(fake.NewBadContext(), types.NewRosterRequest())
+	require.EqualError(t, err, fake.Err("marshal failed"))
+}
+
+func TestMsgFormat_RosterRequest_Encode(t *testing.T) {
+	format := msgFormat{}
+
+	data, err := format.Encode(fake.NewContext(), types.NewRosterRequest())
+	require.NoError(t, err)
+	require.Equal(t, `{"Request":{}}`, string(data))
+}
+
+func TestMsgFormat_RosterDocument_Encode(t *testing.T) {
+	format := msgFormat{}
+
+	ctx := fake.NewContext()
+
+	doc := types.NewRosterDocument(fakeRoster{}, fake.Signature{})
+
+	data, err := format.Encode(ctx, doc)
+	require.NoError(t, err)
+	require.Equal(t, `{"Document":{"Roster":{},"Signature":{}}}`, string(data))
+
+	doc = types.NewRosterDocument(fakeRoster{err: fake.GetError()}, fake.Signature{})
+	_, err = format.Encode(ctx, doc)
+	require.EqualError(t, err, fake.Err("failed to serialize roster"))
+
+	doc = types.NewRosterDocument(fakeRoster{}, fake.NewBadSignature())
+	_, err = format.Encode(ctx, doc)
+	require.EqualError(t, err, fake.Err("failed to serialize signature"))
+}
+
+func TestMsgFormat_Decode(t *testing.T) {
+	format := msgFormat{}
+
+	ctx := fake.NewContext()
+	ctx = serde.WithFactory(ctx, types.AddrKey{}, fake.AddressFactory{})
+	ctx = serde.WithFactory(ctx, types.PublicKeyKey{}, fake.PublicKeyFactory{})
+	ctx = serde.WithFactory(ctx, types.SignatureKey{}, fake.SignatureFactory{})
+	ctx = serde.WithFactory(ctx, types.RosterKey{}, fakeRosterFactory{})
+	ctx = serde.WithFactory(ctx, types.CertificateKey{}, fakeCertFactory{})
+
+	msg, err := format.Decode(ctx, []byte(`{"Announcement":{"Address":"AQAAAA=="}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewAnnouncement(fake.NewAddress(1), fake.PublicKey{},
+		fake.Signature{}, enrollment.Certificate{}), msg)
+
+	msg, err = format.Decode(ctx, []byte(`{"Request":{}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewRosterRequest(), msg)
+
+	msg, err = format.Decode(ctx, []byte(`{"Document":{}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewRosterDocument(fakeRoster{}, fake.Signature{}), msg)
+
+	_, err = format.Decode(ctx, []byte(`{}`))
+	require.EqualError(t, err, "message is empty")
+
+	_, err = format.Decode(fake.NewBadContext(), []byte(`{}`))
+	require.EqualError(t, err, fake.Err("unmarshal failed"))
+}
+
+func TestMsgFormat_Announcement_Decode(t *testing.T) {
+	format := msgFormat{}
+
+	data := []byte(`{"Announcement":{}}`)
+
+	_, err := format.Decode(fake.NewContext(), data)
+	require.EqualError(t, err, "invalid address factory '<nil>'")
+
+	ctx := serde.WithFactory(fake.NewContext(), types.AddrKey{}, fake.AddressFactory{})
+
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err, "invalid public key factory '<nil>'")
+
+	badCtx := serde.WithFactory(ctx, types.PublicKeyKey{}, fake.NewBadPublicKeyFactory())
+
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, fake.Err("failed to deserialize public key"))
+
+	ctx = serde.WithFactory(ctx, types.PublicKeyKey{}, fake.PublicKeyFactory{})
+
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err, "invalid signature factory '<nil>'")
+
+	badCtx = serde.WithFactory(ctx, types.SignatureKey{}, fake.NewBadSignatureFactory())
+
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, fake.Err("failed to deserialize signature"))
+
+	ctx = serde.WithFactory(ctx, types.SignatureKey{}, fake.SignatureFactory{})
+
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err, "invalid certificate factory '<nil>'")
+
+	ctx = serde.WithFactory(ctx, types.CertificateKey{}, fakeCertFactory{err: fake.GetError()})
+
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err, fake.Err("failed to deserialize certificate"))
+}
+
+func TestMsgFormat_RosterDocument_Decode(t *testing.T) {
+	format := msgFormat{}
+
+	data := []byte(`{"Document":{}}`)
+
+	_, err := format.Decode(fake.NewContext(), data)
+	require.EqualError(t, err, "invalid roster factory '<nil>'")
+
+	ctx := serde.WithFactory(fake.NewContext(), types.RosterKey{},
+		fakeRosterFactory{err: fake.GetError()})
+
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err, fake.Err("failed to deserialize roster"))
+
+	ctx = serde.WithFactory(ctx, types.RosterKey{}, fakeRosterFactory{})
+
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err, "invalid signature factory '<nil>'")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type fakeRoster struct {
+	authority.Authority
+
+	err error
+}
+
+func (r fakeRoster) Serialize(serde.Context) ([]byte, error) {
+	return []byte(`{}`), r.err
+}
+
+type fakeRosterFactory struct {
+	authority.Factory
+
+	err error
+}
+
+func (f fakeRosterFactory) AuthorityOf(serde.Context, []byte) (authority.Authority, error) {
+	return fakeRoster{}, f.err
+}
+
+type fakeCertFormat struct{}
+
+func (fakeCertFormat) Encode(serde.Context, serde.Message) ([]byte, error) {
+	return []byte(`{}`), nil
+}
+
+func (fakeCertFormat) Decode(serde.Context, []byte) (serde.Message, error) {
+	return enrollment.Certificate{}, nil
+}
+
+type fakeCertFactory struct {
+	enrollment.Factory
+
+	err error
+}
+
+func (f fakeCertFactory) CertificateOf(serde.Context, []byte) (enrollment.Certificate, error) {
+	return enrollment.Certificate{}, f.err
+}
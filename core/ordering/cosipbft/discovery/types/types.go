@@ -0,0 +1,202 @@
+// Package types implements the messages of the discovery of the roster.
+//
+// The messages have been implemented in this isolated package so that it does
+// not create cycle imports when importing the serde formats.
+package types
+
+import (
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/registry"
+	"golang.org/x/xerrors"
+)
+
+var msgFormats = registry.NewSimpleRegistry()
+
+// RegisterMessageFormat registers the engine for the provided format.
+func RegisterMessageFormat(c serde.Format, f serde.FormatEngine) {
+	msgFormats.Register(c, f)
+}
+
+// Announcement is the message sent by a node to a seed to be added to its
+// roster. The signature of the address proves that the node owns the public
+// key, and the identity certificate issued by the authority is the credential
+// that admits the node.
+//
+// - implements serde.Message
+type Announcement struct {
+	addr      mino.Address
+	pubkey    crypto.PublicKey
+	signature crypto.Signature
+	cert      enrollment.Certificate
+}
+
+// NewAnnouncement creates a new announcement of the address and the public key.
+func NewAnnouncement(addr mino.Address, pubkey crypto.PublicKey, sig crypto.Signature,
+	cert enrollment.Certificate) Announcement {
+
+	return Announcement{
+		addr:      addr,
+		pubkey:    pubkey,
+		signature: sig,
+		cert:      cert,
+	}
+}
+
+// GetAddress returns the address of the node.
+func (a Announcement) GetAddress() mino.Address {
+	return a.addr
+}
+
+// GetPublicKey returns the public key of the node.
+func (a Announcement) GetPublicKey() crypto.PublicKey {
+	return a.pubkey
+}
+
+// GetSignature returns the signature of the address.
+func (a Announcement) GetSignature() crypto.Signature {
+	return a.signature
+}
+
+// GetCertificate returns the identity certificate of the node.
+func (a Announcement) GetCertificate() enrollment.Certificate {
+	return a.cert
+}
+
+// Serialize implements serde.Message. It returns the serialized data of the
+// announcement.
+func (a Announcement) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, a)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return data, nil
+}
+
+// RosterRequest is the message sent to a seed to get its roster.
+//
+// - implements serde.Message
+type RosterRequest struct{}
+
+// NewRosterRequest creates a new request for the roster.
+func NewRosterRequest() RosterRequest {
+	return RosterRequest{}
+}
+
+// Serialize implements serde.Message. It returns the serialized data of the
+// request.
+func (req RosterRequest) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, req)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return data, nil
+}
+
+// RosterDocument is the roster known by a seed, signed by the seed so that it
+// can be relayed without being tampered with.
+//
+// - implements serde.Message
+type RosterDocument struct {
+	roster    authority.Authority
+	signature crypto.Signature
+}
+
+// NewRosterDocument creates a new document of the roster.
+func NewRosterDocument(roster authority.Authority, sig crypto.Signature) RosterDocument {
+	return RosterDocument{
+		roster:    roster,
+		signature: sig,
+	}
+}
+
+// GetRoster returns the roster of the document.
+func (doc RosterDocument) GetRoster() authority.Authority {
+	return doc.roster
+}
+
+// GetSignature returns the signature of the seed.
+func (doc RosterDocument) GetSignature() crypto.Signature {
+	return doc.signature
+}
+
+// Serialize implements serde.Message. It returns the serialized data of the
+// document.
+func (doc RosterDocument) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, doc)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return data, nil
+}
+
+// AddrKey is the key of the address factory.
+type AddrKey struct{}
+
+// PublicKeyKey is the key of the public key factory.
+type PublicKeyKey struct{}
+
+// SignatureKey is the key of the signature factory.
+type SignatureKey struct{}
+
+// RosterKey is the key of the roster factory.
+type RosterKey struct{}
+
+// CertificateKey is the key of the certificate factory.
+type CertificateKey struct{}
+
+// MessageFactory is the factory to deserialize the messages of the discovery.
+//
+// - implements serde.Factory
+type MessageFactory struct {
+	addrFac   mino.AddressFactory
+	pkFac     crypto.PublicKeyFactory
+	sigFac    crypto.SignatureFactory
+	rosterFac authority.Factory
+	certFac   enrollment.Factory
+}
+
+// NewMessageFactory creates a new message factory.
+func NewMessageFactory(addrFac mino.AddressFactory, pkFac crypto.PublicKeyFactory,
+	sigFac crypto.SignatureFactory, rosterFac authority.Factory,
+	certFac enrollment.Factory) MessageFactory {
+
+	return MessageFactory{
+		addrFac:   addrFac,
+		pkFac:     pkFac,
+		sigFac:    sigFac,
+		rosterFac: rosterFac,
+		certFac:   certFac,
+	}
+}
+
+// Deserialize implements serde.Factory. It returns the message of the data if
+// appropriate, otherwise an error.
+func (f MessageFactory) Deserialize(ctx serde.Context, data []byte) (serde.Message, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	ctx = serde.WithFactory(ctx, AddrKey{}, f.addrFac)
+	ctx = serde.WithFactory(ctx, PublicKeyKey{}, f.pkFac)
+	ctx = serde.WithFactory(ctx, SignatureKey{}, f.sigFac)
+	ctx = serde.WithFactory(ctx, RosterKey{}, f.rosterFac)
+	ctx = serde.WithFactory(ctx, CertificateKey{}, f.certFac)
+
+	msg, err := format.Decode(ctx, data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	return msg, nil
+}
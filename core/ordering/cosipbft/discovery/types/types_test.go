@@ -0,0 +1,83 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func init() {
+	RegisterMessageFormat(fake.GoodFormat, fake.Format{Msg: RosterRequest{}})
+	RegisterMessageFormat(fake.BadFormat, fake.NewBadFormat())
+}
+
+func TestAnnouncement_Getters(t *testing.T) {
+	cert := enrollment.NewCertificate(fake.NewAddress(0), fake.PublicKey{}, fake.Signature{})
+
+	ann := NewAnnouncement(fake.NewAddress(0), fake.PublicKey{}, fake.Signature{}, cert)
+
+	require.Equal(t, fake.NewAddress(0), ann.GetAddress())
+	require.Equal(t, fake.PublicKey{}, ann.GetPublicKey())
+	require.Equal(t, fake.Signature{}, ann.GetSignature())
+	require.Equal(t, cert, ann.GetCertificate())
+}
+
+func TestAnnouncement_Serialize(t *testing.T) {
+	ann := NewAnnouncement(fake.NewAddress(0), fake.PublicKey{}, fake.Signature{},
+		enrollment.Certificate{})
+
+	data, err := ann.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = ann.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("failed to encode"))
+}
+
+func TestRosterRequest_Serialize(t *testing.T) {
+	req := NewRosterRequest()
+
+	data, err := req.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = req.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("failed to encode"))
+}
+
+func TestRosterDocument_Getters(t *testing.T) {
+	roster := authority.FromAuthority(fake.NewAuthority(2, fake.NewSigner))
+
+	doc := NewRosterDocument(roster, fake.Signature{})
+
+	require.Equal(t, roster, doc.GetRoster())
+	require.Equal(t, fake.Signature{}, doc.GetSignature())
+}
+
+func TestRosterDocument_Serialize(t *testing.T) {
+	doc := NewRosterDocument(authority.New(nil, nil), fake.Signature{})
+
+	data, err := doc.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = doc.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("failed to encode"))
+}
+
+func TestMessageFactory_Deserialize(t *testing.T) {
+	fac := NewMessageFactory(fake.AddressFactory{}, fake.PublicKeyFactory{},
+		fake.SignatureFactory{}, authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{}),
+		enrollment.NewCertificateFactory(fake.AddressFactory{}, fake.PublicKeyFactory{},
+			fake.SignatureFactory{}))
+
+	msg, err := fac.Deserialize(fake.NewContext(), nil)
+	require.NoError(t, err)
+	require.Equal(t, RosterRequest{}, msg)
+
+	_, err = fac.Deserialize(fake.NewBadContext(), nil)
+	require.EqualError(t, err, fake.Err("failed to decode"))
+}
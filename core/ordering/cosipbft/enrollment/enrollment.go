@@ -0,0 +1,229 @@
+// Package enrollment implements the identity certificates of the nodes.
+//
+// A certificate binds the address of a node to its public key, and is signed
+// by an authority, the CA, that the participants of the chain agree on. A node
+// enrolls by giving its address and public key to the CA, which issues a
+// certificate that the node then presents when it is added to the roster, or
+// when it leads the catch-up of other participants.
+package enrollment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/registry"
+	"golang.org/x/xerrors"
+)
+
+// domain separates the signatures of the certificates from the other
+// signatures of the authority.
+const domain = "dela/v1/identity-certificate"
+
+var certFormats = registry.NewSimpleRegistry()
+
+// RegisterCertificateFormat registers the engine for the provided format.
+func RegisterCertificateFormat(c serde.Format, f serde.FormatEngine) {
+	certFormats.Register(c, f)
+}
+
+// Certificate is the binding of the address of a node to its public key,
+// signed by the authority.
+//
+// - implements serde.Message
+type Certificate struct {
+	addr      mino.Address
+	pubkey    crypto.PublicKey
+	signature crypto.Signature
+}
+
+// NewCertificate creates a new certificate from its components.
+func NewCertificate(addr mino.Address, pubkey crypto.PublicKey, sig crypto.Signature) Certificate {
+	return Certificate{
+		addr:      addr,
+		pubkey:    pubkey,
+		signature: sig,
+	}
+}
+
+// Issue returns the certificate of the address and the public key signed by
+// the authority.
+func Issue(ca crypto.Signer, addr mino.Address, pubkey crypto.PublicKey) (Certificate, error) {
+	digest, err := makeDigest(addr, pubkey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	sig, err := ca.Sign(digest)
+	if err != nil {
+		return Certificate{}, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	return NewCertificate(addr, pubkey, sig), nil
+}
+
+// GetAddress returns the address of the node.
+func (c Certificate) GetAddress() mino.Address {
+	return c.addr
+}
+
+// GetPublicKey returns the public key of the node.
+func (c Certificate) GetPublicKey() crypto.PublicKey {
+	return c.pubkey
+}
+
+// GetSignature returns the signature of the authority.
+func (c Certificate) GetSignature() crypto.Signature {
+	return c.signature
+}
+
+// Verify returns nil if the certificate is signed by the authority, otherwise
+// an error.
+func (c Certificate) Verify(ca crypto.PublicKey) error {
+	digest, err := makeDigest(c.addr, c.pubkey)
+	if err != nil {
+		return err
+	}
+
+	err = ca.Verify(digest, c.signature)
+	if err != nil {
+		return xerrors.Errorf("invalid signature: %v", err)
+	}
+
+	return nil
+}
+
+// Serialize implements serde.Message. It returns the serialized data of the
+// certificate.
+func (c Certificate) Serialize(ctx serde.Context) ([]byte, error) {
+	format := certFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, c)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return data, nil
+}
+
+// makeDigest returns the message signed by the authority. The address is
+// prefixed by its length so that it cannot overlap the public key.
+func makeDigest(addr mino.Address, pubkey crypto.PublicKey) ([]byte, error) {
+	rawAddr, err := addr.MarshalText()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal address: %v", err)
+	}
+
+	rawKey, err := pubkey.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal public key: %v", err)
+	}
+
+	buffer := new(bytes.Buffer)
+	buffer.WriteString(domain)
+
+	length := make([]byte, 8)
+	binary.LittleEndian.PutUint64(length, uint64(len(rawAddr)))
+
+	buffer.Write(length)
+	buffer.Write(rawAddr)
+	buffer.Write(rawKey)
+
+	return buffer.Bytes(), nil
+}
+
+// AddrKey is the key of the address factory.
+type AddrKey struct{}
+
+// PublicKeyKey is the key of the public key factory.
+type PublicKeyKey struct{}
+
+// SignatureKey is the key of the signature factory.
+type SignatureKey struct{}
+
+// Factory is the interface of the certificate factory.
+type Factory interface {
+	serde.Factory
+
+	CertificateOf(serde.Context, []byte) (Certificate, error)
+}
+
+// certFactory is the factory to deserialize the certificates.
+//
+// - implements enrollment.Factory
+type certFactory struct {
+	addrFac mino.AddressFactory
+	pkFac   crypto.PublicKeyFactory
+	sigFac  crypto.SignatureFactory
+}
+
+// NewCertificateFactory creates a new certificate factory. The signature
+// factory must be the one of the authority.
+func NewCertificateFactory(addrFac mino.AddressFactory, pkFac crypto.PublicKeyFactory,
+	sigFac crypto.SignatureFactory) Factory {
+
+	return certFactory{
+		addrFac: addrFac,
+		pkFac:   pkFac,
+		sigFac:  sigFac,
+	}
+}
+
+// Deserialize implements serde.Factory. It returns the certificate of the
+// data if appropriate, otherwise an error.
+func (f certFactory) Deserialize(ctx serde.Context, data []byte) (serde.Message, error) {
+	return f.CertificateOf(ctx, data)
+}
+
+// CertificateOf implements enrollment.Factory. It returns the certificate of
+// the data if appropriate, otherwise an error.
+func (f certFactory) CertificateOf(ctx serde.Context, data []byte) (Certificate, error) {
+	format := certFormats.Get(ctx.GetFormat())
+
+	ctx = serde.WithFactory(ctx, AddrKey{}, f.addrFac)
+	ctx = serde.WithFactory(ctx, PublicKeyKey{}, f.pkFac)
+	ctx = serde.WithFactory(ctx, SignatureKey{}, f.sigFac)
+
+	msg, err := format.Decode(ctx, data)
+	if err != nil {
+		return Certificate{}, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	cert, ok := msg.(Certificate)
+	if !ok {
+		return Certificate{}, xerrors.Errorf("invalid certificate of type '%T'", msg)
+	}
+
+	return cert, nil
+}
+
+// Holder keeps the certificate of a node, which can be set while the node is
+// running.
+type Holder struct {
+	sync.Mutex
+
+	cert *Certificate
+}
+
+// NewHolder creates a new holder without certificate.
+func NewHolder() *Holder {
+	return &Holder{}
+}
+
+// Get returns the certificate of the node, or nil if it is not enrolled.
+func (h *Holder) Get() *Certificate {
+	h.Lock()
+	defer h.Unlock()
+
+	return h.cert
+}
+
+// Set sets the certificate of the node.
+func (h *Holder) Set(cert Certificate) {
+	h.Lock()
+	h.cert = &cert
+	h.Unlock()
+}
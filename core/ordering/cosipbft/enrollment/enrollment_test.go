@@ -0,0 +1,88 @@
+package enrollment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+// serdeMessageFormat is a format that decodes to a message which is not a
+// certificate.
+const serdeMessageFormat = "MSG"
+
+func init() {
+	RegisterCertificateFormat(fake.GoodFormat, fake.Format{Msg: Certificate{}})
+	RegisterCertificateFormat(fake.BadFormat, fake.NewBadFormat())
+	RegisterCertificateFormat(serdeMessageFormat, fake.Format{Msg: fake.Message{}})
+}
+
+func TestCertificate_Issue(t *testing.T) {
+	ca := bls.Generate()
+	node := bls.Generate()
+
+	cert, err := Issue(ca, fake.NewAddress(1), node.GetPublicKey())
+	require.NoError(t, err)
+	require.Equal(t, fake.NewAddress(1), cert.GetAddress())
+	require.Equal(t, node.GetPublicKey(), cert.GetPublicKey())
+	require.NotNil(t, cert.GetSignature())
+
+	require.NoError(t, cert.Verify(ca.GetPublicKey()))
+
+	// The certificate is not signed by the node.
+	err = cert.Verify(node.GetPublicKey())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid signature: ")
+
+	// Changing the address breaks the binding.
+	forged := NewCertificate(fake.NewAddress(2), cert.GetPublicKey(), cert.GetSignature())
+	require.Error(t, forged.Verify(ca.GetPublicKey()))
+
+	_, err = Issue(fake.NewBadSigner(), fake.NewAddress(1), node.GetPublicKey())
+	require.EqualError(t, err, fake.Err("failed to sign"))
+
+	_, err = Issue(ca, fake.NewBadAddress(), node.GetPublicKey())
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+
+	_, err = Issue(ca, fake.NewAddress(1), fake.NewBadPublicKey())
+	require.EqualError(t, err, fake.Err("failed to marshal public key"))
+
+	err = NewCertificate(fake.NewBadAddress(), fake.PublicKey{}, fake.Signature{}).Verify(fake.PublicKey{})
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+}
+
+func TestCertificate_Serialize(t *testing.T) {
+	cert := NewCertificate(fake.NewAddress(0), fake.PublicKey{}, fake.Signature{})
+
+	data, err := cert.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = cert.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("failed to encode"))
+}
+
+func TestCertFactory_Deserialize(t *testing.T) {
+	fac := NewCertificateFactory(fake.AddressFactory{}, fake.PublicKeyFactory{}, fake.SignatureFactory{})
+
+	msg, err := fac.Deserialize(fake.NewContext(), nil)
+	require.NoError(t, err)
+	require.Equal(t, Certificate{}, msg)
+
+	_, err = fac.CertificateOf(fake.NewBadContext(), nil)
+	require.EqualError(t, err, fake.Err("failed to decode"))
+
+	_, err = fac.CertificateOf(fake.NewContextWithFormat(serdeMessageFormat), nil)
+	require.EqualError(t, err, "invalid certificate of type 'fake.Message'")
+}
+
+func TestHolder_GetSet(t *testing.T) {
+	holder := NewHolder()
+	require.Nil(t, holder.Get())
+
+	cert := NewCertificate(fake.NewAddress(0), fake.PublicKey{}, fake.Signature{})
+
+	holder.Set(cert)
+	require.Equal(t, &cert, holder.Get())
+}
@@ -0,0 +1,107 @@
+package json
+
+import (
+	"encoding/json"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+func init() {
+	enrollment.RegisterCertificateFormat(serde.FormatJSON, certFormat{})
+}
+
+// CertificateJSON is the JSON representation of an identity certificate.
+type CertificateJSON struct {
+	Address   []byte
+	PublicKey json.RawMessage
+	Signature json.RawMessage
+}
+
+// CertFormat is the format engine to encode and decode certificates.
+//
+// - implements serde.FormatEngine
+type certFormat struct{}
+
+// Encode implements serde.FormatEngine. It returns the JSON data of the
+// certificate if appropriate, otherwise an error.
+func (certFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	cert, ok := msg.(enrollment.Certificate)
+	if !ok {
+		return nil, xerrors.Errorf("unsupported message '%T'", msg)
+	}
+
+	addr, err := cert.GetAddress().MarshalText()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal address: %v", err)
+	}
+
+	pubkey, err := cert.GetPublicKey().Serialize(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize public key: %v", err)
+	}
+
+	sig, err := cert.GetSignature().Serialize(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize signature: %v", err)
+	}
+
+	m := CertificateJSON{
+		Address:   addr,
+		PublicKey: pubkey,
+		Signature: sig,
+	}
+
+	data, err := ctx.Marshal(m)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal failed: %v", err)
+	}
+
+	return data, nil
+}
+
+// Decode implements serde.FormatEngine. It returns the certificate of the data
+// if appropriate, otherwise an error.
+func (certFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	m := CertificateJSON{}
+	err := ctx.Unmarshal(data, &m)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal failed: %v", err)
+	}
+
+	factory := ctx.GetFactory(enrollment.AddrKey{})
+
+	addrFac, ok := factory.(mino.AddressFactory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid address factory '%T'", factory)
+	}
+
+	factory = ctx.GetFactory(enrollment.PublicKeyKey{})
+
+	pkFac, ok := factory.(crypto.PublicKeyFactory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid public key factory '%T'", factory)
+	}
+
+	pubkey, err := pkFac.PublicKeyOf(ctx, m.PublicKey)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize public key: %v", err)
+	}
+
+	factory = ctx.GetFactory(enrollment.SignatureKey{})
+
+	sigFac, ok := factory.(crypto.SignatureFactory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid signature factory '%T'", factory)
+	}
+
+	sig, err := sigFac.SignatureOf(ctx, m.Signature)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize signature: %v", err)
+	}
+
+	return enrollment.NewCertificate(addrFac.FromText(m.Address), pubkey, sig), nil
+}
@@ -0,0 +1,84 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/enrollment"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/serde"
+)
+
+func TestCertFormat_Encode(t *testing.T) {
+	format := certFormat{}
+
+	ctx := fake.NewContext()
+
+	cert := enrollment.NewCertificate(fake.NewAddress(1), fake.PublicKey{}, fake.Signature{})
+
+	data, err := format.Encode(ctx, cert)
+	require.NoError(t, err)
+	require.Equal(t, `{"Address":"AQAAAA==","PublicKey":{},"Signature":{}}`, string(data))
+
+	_, err = format.Encode(ctx, fake.Message{})
+	require.EqualError(t, err, "unsupported message 'fake.Message'")
+
+	cert = enrollment.NewCertificate(fake.NewBadAddress(), fake.PublicKey{}, fake.Signature{})
+	_, err = format.Encode(ctx, cert)
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+
+	cert = enrollment.NewCertificate(fake.NewAddress(1), fake.NewBadPublicKey(), fake.Signature{})
+	_, err = format.Encode(ctx, cert)
+	require.EqualError(t, err, fake.Err("failed to serialize public key"))
+
+	cert = enrollment.NewCertificate(fake.NewAddress(1), fake.PublicKey{}, fake.NewBadSignature())
+	_, err = format.Encode(ctx, cert)
+	require.EqualError(t, err, fake.Err("failed to serialize signature"))
+
+	cert = enrollment.NewCertificate(fake.NewAddress(1), fake.PublicKey{}, fake.Signature{})
+	_, err = format.Encode(fake.NewBadContext(), cert)
+	require.EqualError(t, err, fake.Err("marshal failed"))
+}
+
+func TestCertFormat_Decode(t *testing.T) {
+	format := certFormat{}
+
+	ctx := fake.NewContext()
+	ctx = serde.WithFactory(ctx, enrollment.AddrKey{}, fake.AddressFactory{})
+	ctx = serde.WithFactory(ctx, enrollment.PublicKeyKey{}, fake.PublicKeyFactory{})
+	ctx = serde.WithFactory(ctx, enrollment.SignatureKey{}, fake.SignatureFactory{})
+
+	data := []byte(`{"Address":"AQAAAA==","PublicKey":{},"Signature":{}}`)
+
+	cert, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, enrollment.NewCertificate(fake.NewAddress(1), fake.PublicKey{}, fake.Signature{}), cert)
+
+	_, err = format.Decode(fake.NewBadContext(), data)
+	require.EqualError(t, err, fake.Err("unmarshal failed"))
+
+	badCtx := fake.NewContext()
+
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, "invalid address factory '<nil>'")
+
+	badCtx = serde.WithFactory(badCtx, enrollment.AddrKey{}, fake.AddressFactory{})
+
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, "invalid public key factory '<nil>'")
+
+	badCtx = serde.WithFactory(badCtx, enrollment.PublicKeyKey{}, fake.NewBadPublicKeyFactory())
+
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, fake.Err("failed to deserialize public key"))
+
+	badCtx = serde.WithFactory(badCtx, enrollment.PublicKeyKey{}, fake.PublicKeyFactory{})
+
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, "invalid signature factory '<nil>'")
+
+	badCtx = serde.WithFactory(badCtx, enrollment.SignatureKey{}, fake.NewBadSignatureFactory())
+
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, fake.Err("failed to deserialize signature"))
+}
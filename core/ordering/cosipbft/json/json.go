@@ -2,6 +2,7 @@ package json
 
 import (
 	"encoding/json"
+	"time"
 
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
@@ -22,15 +23,21 @@ func init() {
 
 // GenesisJSON is the JSON message for a genesis block.
 type GenesisJSON struct {
-	Roster   json.RawMessage
-	TreeRoot []byte
+	Roster    json.RawMessage
+	TreeRoot  []byte
+	Relays    []int           `json:",omitempty"`
+	Threshold int             `json:",omitempty"`
+	Ordering  string          `json:",omitempty"`
+	Authority json.RawMessage `json:",omitempty"`
 }
 
 // BlockJSON is the JSON message for a block.
 type BlockJSON struct {
-	Index    uint64
-	TreeRoot []byte
-	Data     json.RawMessage
+	Index          uint64
+	TreeRoot       []byte
+	Timestamp      int64  `json:",omitempty"`
+	CiphertextRoot []byte `json:",omitempty"`
+	Data           json.RawMessage
 }
 
 // LinkJSON is the JSON message for a link.
@@ -40,6 +47,7 @@ type LinkJSON struct {
 	PrepareSignature json.RawMessage
 	CommitSignature  json.RawMessage
 	ChangeSet        json.RawMessage
+	Roster           []byte          `json:",omitempty"`
 	Block            json.RawMessage `json:",omitempty"`
 }
 
@@ -109,8 +117,23 @@ func (f genesisFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, err
 	}
 
 	m := GenesisJSON{
-		Roster:   roster,
-		TreeRoot: genesis.GetRoot().Bytes(),
+		Roster:    roster,
+		TreeRoot:  genesis.GetRoot().Bytes(),
+		Ordering:  genesis.GetOrdering(),
+		Threshold: genesis.GetThreshold(),
+	}
+
+	// The relays are identified by their index in the roster.
+	for _, addr := range genesis.GetRelays() {
+		_, index := genesis.GetRoster().GetPublicKey(addr)
+		m.Relays = append(m.Relays, index)
+	}
+
+	if genesis.GetAuthority() != nil {
+		m.Authority, err = genesis.GetAuthority().Serialize(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to serialize authority: %v", err)
+		}
 	}
 
 	data, err := ctx.Marshal(m)
@@ -145,7 +168,47 @@ func (f genesisFormat) Decode(ctx serde.Context, data []byte) (serde.Message, er
 	root := types.Digest{}
 	copy(root[:], m.TreeRoot)
 
-	opts := []types.GenesisOption{types.WithGenesisRoot(root)}
+	opts := []types.GenesisOption{
+		types.WithGenesisRoot(root),
+		types.WithOrdering(m.Ordering),
+		types.WithThreshold(m.Threshold),
+	}
+
+	if len(m.Relays) > 0 {
+		members := make([]mino.Address, 0, roster.Len())
+
+		iter := roster.AddressIterator()
+		for iter.HasNext() {
+			members = append(members, iter.GetNext())
+		}
+
+		relays := make([]mino.Address, len(m.Relays))
+		for i, index := range m.Relays {
+			if index < 0 || index >= len(members) {
+				return nil, xerrors.Errorf("invalid relay index %d", index)
+			}
+
+			relays[i] = members[index]
+		}
+
+		opts = append(opts, types.WithRelays(relays...))
+	}
+
+	if len(m.Authority) > 0 {
+		factory = ctx.GetFactory(types.PublicKeyKey{})
+
+		pkFac, ok := factory.(crypto.PublicKeyFactory)
+		if !ok {
+			return nil, xerrors.Errorf("invalid public key factory '%T'", factory)
+		}
+
+		pubkey, err := pkFac.PublicKeyOf(ctx, m.Authority)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to deserialize authority: %v", err)
+		}
+
+		opts = append(opts, types.WithAuthority(pubkey))
+	}
 
 	if f.hashFac != nil {
 		opts = append(opts, types.WithGenesisHashFactory(f.hashFac))
@@ -180,9 +243,14 @@ func (f blockFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error
 	}
 
 	m := BlockJSON{
-		Index:    block.GetIndex(),
-		TreeRoot: block.GetTreeRoot().Bytes(),
-		Data:     blockdata,
+		Index:          block.GetIndex(),
+		TreeRoot:       block.GetTreeRoot().Bytes(),
+		CiphertextRoot: block.GetCiphertextRoot(),
+		Data:           blockdata,
+	}
+
+	if !block.GetTimestamp().IsZero() {
+		m.Timestamp = block.GetTimestamp().UnixNano()
 	}
 
 	data, err := ctx.Marshal(m)
@@ -222,6 +290,14 @@ func (f blockFormat) Decode(ctx serde.Context, data []byte) (serde.Message, erro
 		types.WithIndex(m.Index),
 	}
 
+	if m.Timestamp != 0 {
+		opts = append(opts, types.WithTimestamp(time.Unix(0, m.Timestamp)))
+	}
+
+	if m.CiphertextRoot != nil {
+		opts = append(opts, types.WithCiphertextRoot(m.CiphertextRoot))
+	}
+
 	if f.hashFac != nil {
 		opts = append(opts, types.WithHashFactory(f.hashFac))
 	}
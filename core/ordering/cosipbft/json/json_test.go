@@ -3,13 +3,21 @@ package json
 import (
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
+
+	_ "go.dedis.ch/dela/core/txn/signed/json"
+	_ "go.dedis.ch/dela/core/validation/simple/json"
+	_ "go.dedis.ch/dela/crypto/bls/json"
 )
 
 func init() {
@@ -72,6 +80,102 @@ func TestGenesisFormat_Decode(t *testing.T) {
 	require.Contains(t, err.Error(), "creating genesis: fingerprint failed: ")
 }
 
+func TestGenesisFormat_Relays(t *testing.T) {
+	format := genesisFormat{}
+
+	ro := fakeRoster{Authority: authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))}
+
+	genesis, err := types.NewGenesis(ro, types.WithRelays(fake.NewAddress(2)))
+	require.NoError(t, err)
+
+	ctx := serde.WithFactory(fake.NewContext(), types.RosterKey{}, fakeRosterFac{roster: ro})
+
+	data, err := format.Encode(ctx, genesis)
+	require.NoError(t, err)
+	require.Regexp(t, `"Relays":\[2\]}$`, string(data))
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, genesis.GetHash(), msg.(types.Genesis).GetHash())
+	require.True(t, msg.(types.Genesis).IsRelay(fake.NewAddress(2)))
+
+	_, err = format.Decode(ctx, []byte(`{"Relays":[3]}`))
+	require.EqualError(t, err, "invalid relay index 3")
+}
+
+func TestGenesisFormat_Threshold(t *testing.T) {
+	format := genesisFormat{}
+
+	ro := fakeRoster{Authority: authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))}
+
+	genesis, err := types.NewGenesis(ro, types.WithThreshold(2))
+	require.NoError(t, err)
+
+	ctx := serde.WithFactory(fake.NewContext(), types.RosterKey{}, fakeRosterFac{roster: ro})
+
+	data, err := format.Encode(ctx, genesis)
+	require.NoError(t, err)
+	require.Regexp(t, `"Threshold":2}$`, string(data))
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, genesis.GetHash(), msg.(types.Genesis).GetHash())
+	require.Equal(t, 2, msg.(types.Genesis).GetThreshold())
+
+	_, err = format.Decode(ctx, []byte(`{"Threshold":4}`))
+	require.EqualError(t, err, "creating genesis: threshold 4 is not between "+
+		"1 and the 3 members that hold a share")
+}
+
+func TestGenesisFormat_Ordering(t *testing.T) {
+	format := genesisFormat{}
+
+	ro := fakeRoster{Authority: authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))}
+
+	genesis, err := types.NewGenesis(ro, types.WithOrdering("beacon"))
+	require.NoError(t, err)
+
+	ctx := serde.WithFactory(fake.NewContext(), types.RosterKey{}, fakeRosterFac{roster: ro})
+
+	data, err := format.Encode(ctx, genesis)
+	require.NoError(t, err)
+	require.Regexp(t, `"Ordering":"beacon"}$`, string(data))
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, genesis.GetHash(), msg.(types.Genesis).GetHash())
+	require.Equal(t, "beacon", msg.(types.Genesis).GetOrdering())
+}
+
+func TestGenesisFormat_Authority(t *testing.T) {
+	format := genesisFormat{}
+
+	ro := fakeRoster{Authority: authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))}
+
+	genesis, err := types.NewGenesis(ro, types.WithAuthority(fake.PublicKey{}))
+	require.NoError(t, err)
+
+	ctx := serde.WithFactory(fake.NewContext(), types.RosterKey{}, fakeRosterFac{roster: ro})
+	ctx = serde.WithFactory(ctx, types.PublicKeyKey{}, fake.PublicKeyFactory{})
+
+	data, err := format.Encode(ctx, genesis)
+	require.NoError(t, err)
+	require.Regexp(t, `"Authority":{}}$`, string(data))
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, genesis.GetHash(), msg.(types.Genesis).GetHash())
+	require.Equal(t, fake.PublicKey{}, msg.(types.Genesis).GetAuthority())
+
+	badCtx := serde.WithFactory(ctx, types.PublicKeyKey{}, nil)
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, "invalid public key factory '<nil>'")
+
+	badCtx = serde.WithFactory(ctx, types.PublicKeyKey{}, fake.NewBadPublicKeyFactory())
+	_, err = format.Decode(badCtx, data)
+	require.EqualError(t, err, fake.Err("failed to deserialize authority"))
+}
+
 func TestBlockFormat_Encode(t *testing.T) {
 	format := blockFormat{}
 
@@ -84,6 +188,20 @@ func TestBlockFormat_Encode(t *testing.T) {
 	require.NoError(t, err)
 	require.Regexp(t, `{"Index":0,"TreeRoot":"[^"]+","Data":{}}`, string(data))
 
+	block, err = types.NewBlock(fakeResult{}, types.WithTimestamp(time.Unix(0, 42)))
+	require.NoError(t, err)
+
+	data, err = format.Encode(ctx, block)
+	require.NoError(t, err)
+	require.Regexp(t, `{"Index":0,"TreeRoot":"[^"]+","Timestamp":42,"Data":{}}`, string(data))
+
+	block, err = types.NewBlock(fakeResult{}, types.WithCiphertextRoot([]byte{1}))
+	require.NoError(t, err)
+
+	data, err = format.Encode(ctx, block)
+	require.NoError(t, err)
+	require.Regexp(t, `{"Index":0,"TreeRoot":"[^"]+","CiphertextRoot":"AQ==","Data":{}}`, string(data))
+
 	_, err = format.Encode(ctx, fake.Message{})
 	require.EqualError(t, err, "invalid block 'fake.Message'")
 
@@ -110,6 +228,20 @@ func TestBlockFormat_Decode(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, block, msg)
 
+	block, err = types.NewBlock(fakeResult{}, types.WithTimestamp(time.Unix(0, 42)))
+	require.NoError(t, err)
+
+	msg, err = format.Decode(ctx, []byte(`{"Timestamp":42}`))
+	require.NoError(t, err)
+	require.Equal(t, block, msg)
+
+	block, err = types.NewBlock(fakeResult{}, types.WithCiphertextRoot([]byte{1}))
+	require.NoError(t, err)
+
+	msg, err = format.Decode(ctx, []byte(`{"CiphertextRoot":"AQ=="}`))
+	require.NoError(t, err)
+	require.Equal(t, block, msg)
+
 	_, err = format.Decode(fake.NewBadContext(), []byte(`{}`))
 	require.EqualError(t, err, fake.Err("failed to unmarshal"))
 
@@ -271,9 +403,51 @@ func TestMsgFormat_Decode(t *testing.T) {
 	require.EqualError(t, err, "message is empty")
 }
 
+func FuzzBlockFormat_Decode(f *testing.F) {
+	format := blockFormat{}
+
+	ctx := fake.NewContextWithFormat(serde.FormatJSON)
+	ctx = serde.WithFactory(ctx, types.DataKey{},
+		simple.NewResultFactory(signed.NewTransactionFactory()))
+
+	f.Add(makeBlockCorpus(f, format, ctx))
+	f.Add([]byte(`{}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg, err := format.Decode(ctx, data)
+		if err != nil {
+			return
+		}
+
+		// Whatever is accepted by the decoder must be encoded back.
+		_, err = format.Encode(ctx, msg)
+		require.NoError(t, err)
+	})
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
+// makeBlockCorpus returns the encoding of a block with a signed transaction,
+// which is used as the seed corpus of the fuzzer.
+func makeBlockCorpus(f *testing.F, format blockFormat, ctx serde.Context) []byte {
+	signer := bls.NewSigner()
+
+	tx, err := signed.NewTransaction(1, signer.GetPublicKey(), signed.WithArg("key", []byte("value")))
+	require.NoError(f, err)
+	require.NoError(f, tx.Sign(signer))
+
+	res := simple.NewResult([]simple.TransactionResult{simple.NewTransactionResult(tx, true, "")})
+
+	block, err := types.NewBlock(res, types.WithIndex(1))
+	require.NoError(f, err)
+
+	data, err := format.Encode(ctx, block)
+	require.NoError(f, err)
+
+	return data
+}
+
 type fakeRoster struct {
 	authority.Authority
 
@@ -291,10 +465,15 @@ func (fakeRoster) Fingerprint(io.Writer) error {
 type fakeRosterFac struct {
 	authority.Factory
 
-	err error
+	roster authority.Authority
+	err    error
 }
 
 func (fac fakeRosterFac) AuthorityOf(serde.Context, []byte) (authority.Authority, error) {
+	if fac.roster != nil {
+		return fac.roster, fac.err
+	}
+
 	return fakeRoster{}, fac.err
 }
 
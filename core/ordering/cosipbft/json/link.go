@@ -75,6 +75,11 @@ func (fmt linkFormat) encodeLink(ctx serde.Context, link types.Link, m *LinkJSON
 	m.CommitSignature = commit
 	m.ChangeSet = changeset
 
+	roster := link.GetRosterDigest()
+	if roster != (types.Digest{}) {
+		m.Roster = roster.Bytes()
+	}
+
 	return nil
 }
 
@@ -105,9 +110,13 @@ func (fmt linkFormat) Decode(ctx serde.Context, data []byte) (serde.Message, err
 	from := types.Digest{}
 	copy(from[:], m.From)
 
+	roster := types.Digest{}
+	copy(roster[:], m.Roster)
+
 	opts := []types.LinkOption{
 		types.WithSignatures(prepare, commit),
 		types.WithChangeSet(changeset),
+		types.WithRosterDigest(roster),
 	}
 
 	if fmt.hashFac != nil {
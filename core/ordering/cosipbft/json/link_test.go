@@ -34,6 +34,10 @@ func TestLinkFormat_Encode(t *testing.T) {
 		`"CommitSignature":{},"ChangeSet":{},"Block":{}}`
 	require.Regexp(t, re, string(data))
 
+	data, err = format.Encode(ctx, makeLink(t, types.WithRosterDigest(types.Digest{3})))
+	require.NoError(t, err)
+	require.Regexp(t, `"ChangeSet":{},"Roster":"AwAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}`, string(data))
+
 	_, err = format.Encode(ctx, fake.Message{})
 	require.EqualError(t, err, "unsupported message 'fake.Message'")
 
@@ -72,6 +76,10 @@ func TestLinkFormat_Decode(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, makeBlockLink(t), msg)
 
+	msg, err = format.Decode(ctx, []byte(`{"From":[1],"To":[2],"Roster":[3]}`))
+	require.NoError(t, err)
+	require.Equal(t, makeLink(t, types.WithRosterDigest(types.Digest{3})), msg)
+
 	_, err = format.Decode(fake.NewBadContext(), []byte(`{}`))
 	require.EqualError(t, err, fake.Err("failed to unmarshal"))
 
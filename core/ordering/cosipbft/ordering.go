@@ -0,0 +1,180 @@
+// This file contains the policies that decide the order of the transactions of
+// a block proposal.
+
+package cosipbft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/crypto/kdf"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ArrivalOrdering is the name of the policy that keeps the order of
+	// arrival. It is the policy of a chain whose genesis block does not name
+	// one.
+	ArrivalOrdering = "arrival"
+
+	// BeaconOrdering is the name of the policy that shuffles the transactions
+	// with the randomness of the previous block.
+	BeaconOrdering = "beacon"
+)
+
+// OrderingPolicy decides the order of the transactions of a block proposal.
+// Whatever the policy, the transactions of an identity are kept in the order
+// of the pool, which is sorted by nonce.
+//
+// The members verify that the transactions of a proposal are in the order of
+// the policy by ordering them again, so that a policy must return the same
+// order whatever the order of the input, as long as the transactions of an
+// identity are sorted by nonce. Only the arrival order can't be verified.
+type OrderingPolicy interface {
+	// Order returns the transactions in the order they must appear in the
+	// block. The seed is derived from the previous block and cannot be
+	// predicted before it is finalized. It is empty for the first block.
+	Order(txs []txn.Transaction, seed []byte) ([]txn.Transaction, error)
+}
+
+// arrivalPolicy keeps the order of arrival in the pool.
+//
+// - implements cosipbft.OrderingPolicy
+type arrivalPolicy struct{}
+
+// NewArrivalPolicy returns a policy that orders the transactions in the order
+// they arrived in the pool. It is the default policy.
+func NewArrivalPolicy() OrderingPolicy {
+	return arrivalPolicy{}
+}
+
+// Order implements cosipbft.OrderingPolicy. It returns the transactions as
+// they are.
+func (arrivalPolicy) Order(txs []txn.Transaction, seed []byte) ([]txn.Transaction, error) {
+	return txs, nil
+}
+
+// FeeFunc is the function that returns the fee paid by a transaction.
+type FeeFunc func(tx txn.Transaction) uint64
+
+// feePolicy orders the transactions by decreasing fee.
+//
+// - implements cosipbft.OrderingPolicy
+type feePolicy struct {
+	fee FeeFunc
+}
+
+// NewFeePolicy returns a policy that gives the priority to the transactions
+// that pay the highest fee. Transactions with the same fee are sorted by
+// identifier.
+func NewFeePolicy(fee FeeFunc) OrderingPolicy {
+	return feePolicy{fee: fee}
+}
+
+// Order implements cosipbft.OrderingPolicy. It sorts the transactions by
+// decreasing fee.
+func (p feePolicy) Order(txs []txn.Transaction, seed []byte) ([]txn.Transaction, error) {
+	ordered := make([]txn.Transaction, len(txs))
+	copy(ordered, txs)
+
+	sort.Slice(ordered, func(i, j int) bool {
+		fi, fj := p.fee(ordered[i]), p.fee(ordered[j])
+		if fi != fj {
+			return fi > fj
+		}
+
+		return bytes.Compare(ordered[i].GetID(), ordered[j].GetID()) < 0
+	})
+
+	ordered, err := keepIdentityOrder(txs, ordered)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to keep nonce order: %v", err)
+	}
+
+	return ordered, nil
+}
+
+// beaconPolicy shuffles the transactions with the seed.
+//
+// - implements cosipbft.OrderingPolicy
+type beaconPolicy struct{}
+
+// NewBeaconPolicy returns a policy that shuffles the transactions with the
+// randomness of the previous block given by the beacon, so that neither the
+// leader nor the senders can choose the position of a transaction.
+func NewBeaconPolicy() OrderingPolicy {
+	return beaconPolicy{}
+}
+
+// Order implements cosipbft.OrderingPolicy. It shuffles the transactions
+// deterministically from the seed. They are sorted by identifier beforehand,
+// so that the order of the input does not matter.
+func (beaconPolicy) Order(txs []txn.Transaction, seed []byte) ([]txn.Transaction, error) {
+	ordered := make([]txn.Transaction, len(txs))
+	copy(ordered, txs)
+
+	sort.Slice(ordered, func(i, j int) bool {
+		return bytes.Compare(ordered[i].GetID(), ordered[j].GetID()) < 0
+	})
+
+	digest, err := kdf.Derive(seed, kdf.OrderingSeed, 8)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive seed: %v", err)
+	}
+
+	source := rand.NewSource(int64(binary.LittleEndian.Uint64(digest)))
+
+	rand.New(source).Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+
+	ordered, err = keepIdentityOrder(txs, ordered)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to keep nonce order: %v", err)
+	}
+
+	return ordered, nil
+}
+
+// keepIdentityOrder assigns the positions of the ordered list to the
+// transactions of each identity in their original order, so that a policy
+// never moves a transaction before one of the same identity with a lower
+// nonce.
+func keepIdentityOrder(original, ordered []txn.Transaction) ([]txn.Transaction, error) {
+	queues := make(map[string][]txn.Transaction)
+
+	for _, tx := range original {
+		key, err := identityKey(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		queues[key] = append(queues[key], tx)
+	}
+
+	result := make([]txn.Transaction, len(ordered))
+
+	for i, tx := range ordered {
+		key, err := identityKey(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = queues[key][0]
+		queues[key] = queues[key][1:]
+	}
+
+	return result, nil
+}
+
+func identityKey(tx txn.Transaction) (string, error) {
+	data, err := tx.GetIdentity().MarshalText()
+	if err != nil {
+		return "", xerrors.Errorf("failed to marshal identity: %v", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,116 @@
+package cosipbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/access"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestArrivalPolicy_Order(t *testing.T) {
+	txs := makeOrderingTxs(t)
+
+	ordered, err := NewArrivalPolicy().Order(txs, []byte("seed"))
+	require.NoError(t, err)
+	require.Equal(t, txs, ordered)
+}
+
+func TestFeePolicy_Order(t *testing.T) {
+	alice := bls.NewSigner()
+	bob := bls.NewSigner()
+
+	txs := []txn.Transaction{
+		makeTx(t, 0, alice),
+		makeTx(t, 0, bob),
+		makeTx(t, 1, alice),
+		makeTx(t, 1, bob),
+	}
+
+	// Bob's first transaction pays the highest fee, and Alice's second one
+	// pays more than her first one but must stay after it.
+	fees := map[string]uint64{
+		string(txs[1].GetID()): 10,
+		string(txs[2].GetID()): 5,
+		string(txs[3].GetID()): 1,
+	}
+
+	policy := NewFeePolicy(func(tx txn.Transaction) uint64 {
+		return fees[string(tx.GetID())]
+	})
+
+	ordered, err := policy.Order(txs, nil)
+	require.NoError(t, err)
+	require.Equal(t, []txn.Transaction{txs[1], txs[0], txs[3], txs[2]}, ordered)
+
+	// The order is the same when the members order the block again.
+	again, err := policy.Order(ordered, nil)
+	require.NoError(t, err)
+	require.Equal(t, ordered, again)
+
+	txs = append(txs, badIdentityTx{})
+
+	_, err = policy.Order(txs, nil)
+	require.EqualError(t, err, fake.Err(
+		"failed to keep nonce order: failed to marshal identity"))
+}
+
+func TestBeaconPolicy_Order(t *testing.T) {
+	txs := makeOrderingTxs(t)
+
+	policy := NewBeaconPolicy()
+
+	ordered, err := policy.Order(txs, []byte("seed"))
+	require.NoError(t, err)
+	require.Len(t, ordered, len(txs))
+	require.ElementsMatch(t, txs, ordered)
+
+	again, err := policy.Order(ordered, []byte("seed"))
+	require.NoError(t, err)
+	require.Equal(t, ordered, again)
+
+	other, err := policy.Order(txs, []byte("another seed"))
+	require.NoError(t, err)
+	require.NotEqual(t, ordered, other)
+
+	_, err = policy.Order(append(txs, badIdentityTx{}), nil)
+	require.EqualError(t, err, fake.Err(
+		"failed to keep nonce order: failed to marshal identity"))
+}
+
+func TestKeepIdentityOrder(t *testing.T) {
+	alice := bls.NewSigner()
+
+	txs := []txn.Transaction{makeTx(t, 0, alice), makeTx(t, 1, alice)}
+
+	ordered, err := keepIdentityOrder(txs, []txn.Transaction{txs[1], txs[0]})
+	require.NoError(t, err)
+	require.Equal(t, txs, ordered)
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeOrderingTxs(t *testing.T) []txn.Transaction {
+	txs := make([]txn.Transaction, 20)
+
+	for i := range txs {
+		txs[i] = makeTx(t, 0, bls.NewSigner())
+	}
+
+	return txs
+}
+
+type badIdentityTx struct {
+	txn.Transaction
+}
+
+func (badIdentityTx) GetIdentity() access.Identity {
+	return fake.NewBadPublicKey()
+}
+
+func (badIdentityTx) GetID() []byte {
+	return nil
+}
@@ -14,13 +14,17 @@
 package pbft
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/core"
+	"go.dedis.ch/dela/core/execution"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
@@ -29,6 +33,7 @@ import (
 	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/core/validation"
 	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/internal/clock"
 	"go.dedis.ch/dela/mino"
 	"golang.org/x/xerrors"
 )
@@ -100,6 +105,18 @@ const (
 	ViewChangeState
 )
 
+const (
+	// medianWindow is the number of last blocks used to compute the median time
+	// that the timestamp of a new block must not precede.
+	medianWindow = 11
+
+	// maxClockDrift is how far from the local clock the timestamp of a block
+	// is allowed to be, ahead or behind. A block needs the signatures of a
+	// threshold of the participants, so that its timestamp is close to the
+	// clocks of the peers of the leader.
+	maxClockDrift = 10 * time.Second
+)
+
 func init() {
 	dela.PromCollectors = append(dela.PromCollectors, promBlocks, promTxs,
 		promRejectedTxs, promLeader)
@@ -162,6 +179,8 @@ type round struct {
 	tree       hashtree.StagingTree
 	prepareSig crypto.Signature
 	changeset  authority.ChangeSet
+	roster     types.Digest
+	changes    []execution.Change
 	committed  bool
 	prevViews  map[mino.Address]View
 	views      map[mino.Address]View
@@ -175,6 +194,10 @@ type round struct {
 // authority for a given tree.
 type AuthorityReader func(tree hashtree.Tree) (authority.Authority, error)
 
+// OrderVerifier is a function to help the state machine to verify that the
+// transactions of a block are in the order of the chain.
+type OrderVerifier func(block types.Block) error
+
 // pbftsm is an implementation of a state machine to perform PBFT rounds.
 //
 // - implements pbft.Statemachine
@@ -189,7 +212,10 @@ type pbftsm struct {
 	genesis    blockstore.GenesisStore
 	tree       blockstore.TreeCache
 	authReader AuthorityReader
+	verifyOrd  OrderVerifier
 	db         kv.DB
+	journal    *execution.Journal
+	clock      clock.Clock
 
 	// verifierFac creates a verifier for the aggregated signature.
 	verifierFac crypto.VerifierFactory
@@ -212,10 +238,26 @@ type StateMachineParam struct {
 	Tree            blockstore.TreeCache
 	AuthorityReader AuthorityReader
 	DB              kv.DB
+
+	// Journal, if any, persists the diff of the state of every block.
+	Journal *execution.Journal
+
+	// Clock, if any, is the clock to check the time of the blocks, otherwise
+	// the clock of the system is used.
+	Clock clock.Clock
+
+	// OrderVerifier, if any, verifies the order of the transactions of the
+	// proposals.
+	OrderVerifier OrderVerifier
 }
 
 // NewStateMachine returns a new state machine.
 func NewStateMachine(param StateMachineParam) StateMachine {
+	c := param.Clock
+	if c == nil {
+		c = clock.NewReal()
+	}
+
 	return &pbftsm{
 		logger:      param.Logger,
 		watcher:     core.NewWatcher(),
@@ -227,8 +269,11 @@ func NewStateMachine(param StateMachineParam) StateMachine {
 		genesis:     param.Genesis,
 		tree:        param.Tree,
 		db:          param.DB,
+		journal:     param.Journal,
 		state:       NoneState,
 		authReader:  param.AuthorityReader,
+		verifyOrd:   param.OrderVerifier,
+		clock:       c,
 	}
 }
 
@@ -324,6 +369,11 @@ func (m *pbftsm) Prepare(from mino.Address, block types.Block) (types.Digest, er
 
 	m.round.threshold = calculateThreshold(roster.Len())
 
+	err = m.verifyDrift(block)
+	if err != nil {
+		return id, xerrors.Errorf("invalid timestamp: %v", err)
+	}
+
 	err = m.verifyPrepare(m.tree.Get(), block, &m.round, roster)
 	if err != nil {
 		return id, err
@@ -622,12 +672,90 @@ func (m *pbftsm) Watch(ctx context.Context) <-chan State {
 	return ch
 }
 
+// verifyTimestamp checks that the timestamp of the block is not before the
+// median of the last blocks.
+func (m *pbftsm) verifyTimestamp(block types.Block) error {
+	timestamp := block.GetTimestamp()
+
+	median, err := m.getMedianTime()
+	if err != nil {
+		return xerrors.Errorf("couldn't get median time: %v", err)
+	}
+
+	if timestamp.Before(median) {
+		return xerrors.Errorf("'%v' is before the median time '%v'", timestamp, median)
+	}
+
+	return nil
+}
+
+// verifyDrift checks that the timestamp of a proposal is not too far from the
+// local clock. It is not checked for the blocks of a catch-up, which can be
+// much older.
+func (m *pbftsm) verifyDrift(block types.Block) error {
+	timestamp := block.GetTimestamp()
+	now := m.clock.Now()
+
+	if timestamp.After(now.Add(maxClockDrift)) {
+		return xerrors.Errorf("'%v' is too far ahead of '%v'", timestamp, now)
+	}
+
+	if timestamp.Before(now.Add(-maxClockDrift)) {
+		return xerrors.Errorf("'%v' is too far behind '%v'", timestamp, now)
+	}
+
+	return nil
+}
+
+// getMedianTime returns the median of the timestamps of the last blocks, or
+// the zero time if the chain is empty.
+func (m *pbftsm) getMedianTime() (time.Time, error) {
+	length := m.blocks.Len()
+
+	first := uint64(0)
+	if length > medianWindow {
+		first = length - medianWindow
+	}
+
+	times := make([]time.Time, 0, length-first)
+
+	for i := first; i < length; i++ {
+		link, err := m.blocks.GetByIndex(i)
+		if err != nil {
+			return time.Time{}, xerrors.Errorf("failed to read block %d: %v", i, err)
+		}
+
+		times = append(times, link.GetBlock().GetTimestamp())
+	}
+
+	if len(times) == 0 {
+		return time.Time{}, nil
+	}
+
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+
+	return times[len(times)/2], nil
+}
+
 func (m *pbftsm) verifyPrepare(tree hashtree.Tree, block types.Block, r *round, ro authority.Authority) error {
+	var recorder *execution.Recorder
+
+	if m.verifyOrd != nil {
+		err := m.verifyOrd(block)
+		if err != nil {
+			return xerrors.Errorf("invalid order: %v", err)
+		}
+	}
+
 	stageTree, err := tree.Stage(func(snap store.Snapshot) error {
 		txs := block.GetTransactions()
 		rejected := 0
 
-		res, err := m.val.Validate(snap, txs)
+		recorder = execution.NewRecorder(snap)
+
+		res, err := m.val.Validate(recorder, txs)
 		if err != nil {
 			return xerrors.Errorf("validation failed: %v", err)
 		}
@@ -657,10 +785,21 @@ func (m *pbftsm) verifyPrepare(tree hashtree.Tree, block types.Block, r *round,
 		return xerrors.Errorf("mismatch tree root '%v' != '%v'", root, block.GetTreeRoot())
 	}
 
+	ctRoot := types.NewCiphertextRoot(block.GetTransactions())
+	if !bytes.Equal(ctRoot, block.GetCiphertextRoot()) {
+		return xerrors.Errorf("mismatch ciphertext root '%x' != '%x'",
+			ctRoot, block.GetCiphertextRoot())
+	}
+
 	if m.blocks.Len() != block.GetIndex() {
 		return xerrors.Errorf("mismatch index %d != %d", block.GetIndex(), m.blocks.Len())
 	}
 
+	err = m.verifyTimestamp(block)
+	if err != nil {
+		return xerrors.Errorf("invalid timestamp: %v", err)
+	}
+
 	lastID, err := m.getLatestID()
 	if err != nil {
 		return xerrors.Errorf("couldn't get latest digest: %v", err)
@@ -674,8 +813,21 @@ func (m *pbftsm) verifyPrepare(tree hashtree.Tree, block types.Block, r *round,
 	}
 
 	changeset := ro.Diff(roster)
+
+	// The digest of the new roster is signed with the link so that the chain
+	// proves which roster signs the next blocks.
+	rosterDigest := types.Digest{}
+
+	if changeset.NumChanges() > 0 {
+		rosterDigest, err = types.RosterDigestOf(ro.Apply(changeset))
+		if err != nil {
+			return xerrors.Errorf("failed to digest next roster: %v", err)
+		}
+	}
+
 	opts := []types.LinkOption{
 		types.WithChangeSet(changeset),
+		types.WithRosterDigest(rosterDigest),
 		types.WithLinkHashFactory(m.hashFac),
 	}
 
@@ -686,8 +838,10 @@ func (m *pbftsm) verifyPrepare(tree hashtree.Tree, block types.Block, r *round,
 
 	r.id = link.GetHash()
 	r.tree = stageTree
+	r.changes = recorder.GetChanges()
 	r.block = block
 	r.changeset = changeset
+	r.roster = rosterDigest
 
 	return nil
 }
@@ -750,6 +904,7 @@ func (m *pbftsm) verifyFinalize(r *round, sig crypto.Signature, ro authority.Aut
 		opts := []types.LinkOption{
 			types.WithSignatures(r.prepareSig, sig),
 			types.WithChangeSet(r.changeset),
+			types.WithRosterDigest(r.roster),
 			types.WithLinkHashFactory(m.hashFac),
 		}
 
@@ -763,6 +918,16 @@ func (m *pbftsm) verifyFinalize(r *round, sig crypto.Signature, ro authority.Aut
 			return xerrors.Errorf("store block: %v", err)
 		}
 
+		// 3. Persist the diff of the state with the block.
+		if m.journal != nil {
+			diff := execution.Diff{Index: r.block.GetIndex(), Changes: r.changes}
+
+			err = m.journal.Store(txn, diff)
+			if err != nil {
+				return xerrors.Errorf("store diff: %v", err)
+			}
+		}
+
 		// Only release the tree cache at the very end of the transaction, so
 		// that a call to get the tree will hold until the block is stored.
 		txn.OnCommit(func() {
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core"
@@ -21,6 +22,7 @@ import (
 	"go.dedis.ch/dela/core/validation/simple"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/internal/clock"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
 )
@@ -96,6 +98,16 @@ func TestStateMachine_GetCommit(t *testing.T) {
 	require.Equal(t, block, block)
 }
 
+func TestNewStateMachine(t *testing.T) {
+	sm := NewStateMachine(StateMachineParam{}).(*pbftsm)
+	require.Equal(t, clock.NewReal(), sm.clock)
+
+	now := time.Unix(1000, 0)
+
+	sm = NewStateMachine(StateMachineParam{Clock: fake.NewClock(now)}).(*pbftsm)
+	require.Equal(t, now, sm.clock.Now())
+}
+
 func TestStateMachine_Prepare(t *testing.T) {
 	tree, db, clean := makeTree(t)
 	defer clean()
@@ -118,7 +130,8 @@ func TestStateMachine_Prepare(t *testing.T) {
 	root := types.Digest{}
 	copy(root[:], tree.GetRoot())
 
-	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root), types.WithIndex(0))
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root),
+		types.WithIndex(0), types.WithTimestamp(time.Now()))
 	require.NoError(t, err)
 
 	from := fake.NewAddress(0)
@@ -172,6 +185,7 @@ func TestStateMachine_FailedValidation_Prepare(t *testing.T) {
 		tree:       blockstore.NewTreeCache(tree),
 		db:         db,
 		authReader: goodReader,
+		clock:      clock.NewReal(),
 	}
 
 	link := makeLink(t)
@@ -190,9 +204,11 @@ func TestStateMachine_MismatchTreeRoot_Prepare(t *testing.T) {
 		tree:       blockstore.NewTreeCache(tree),
 		db:         db,
 		authReader: goodReader,
+		clock:      clock.NewReal(),
 	}
 
-	other, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(types.Digest{}))
+	other, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(types.Digest{}),
+		types.WithTimestamp(time.Now()))
 	require.NoError(t, err)
 
 	sm.val = unacceptedTxsValidation{}
@@ -200,6 +216,72 @@ func TestStateMachine_MismatchTreeRoot_Prepare(t *testing.T) {
 	require.EqualError(t, err, "mismatch tree root '71b6c1d5' != '00000000'")
 }
 
+func TestStateMachine_MismatchCiphertextRoot_Prepare(t *testing.T) {
+	tree, db, clean := makeTree(t)
+	defer clean()
+
+	sm := &pbftsm{
+		state:      InitialState,
+		val:        simple.NewService(fakeExec{}, nil),
+		tree:       blockstore.NewTreeCache(tree),
+		db:         db,
+		authReader: goodReader,
+		clock:      clock.NewReal(),
+	}
+
+	root := types.Digest{}
+	copy(root[:], tree.GetRoot())
+
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root),
+		types.WithCiphertextRoot([]byte{1}), types.WithTimestamp(time.Now()))
+	require.NoError(t, err)
+
+	_, err = sm.Prepare(fake.NewAddress(0), block)
+	require.EqualError(t, err, "mismatch ciphertext root '' != '01'")
+}
+
+func TestStateMachine_InvalidOrder_Prepare(t *testing.T) {
+	tree, db, clean := makeTree(t)
+	defer clean()
+
+	sm := &pbftsm{
+		state:      InitialState,
+		val:        simple.NewService(fakeExec{}, nil),
+		tree:       blockstore.NewTreeCache(tree),
+		db:         db,
+		authReader: goodReader,
+		clock:      clock.NewReal(),
+		verifyOrd: func(types.Block) error {
+			return fake.GetError()
+		},
+	}
+
+	link := makeLink(t)
+
+	_, err := sm.Prepare(fake.NewAddress(0), link.GetBlock())
+	require.EqualError(t, err, fake.Err("invalid order"))
+}
+
+func TestStateMachine_InvalidTimestamp_Prepare(t *testing.T) {
+	tree, _, clean := makeTree(t)
+	defer clean()
+
+	sm := &pbftsm{
+		state:      InitialState,
+		tree:       blockstore.NewTreeCache(tree),
+		authReader: goodReader,
+		clock:      fake.NewClock(time.Unix(1000, 0)),
+	}
+
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTimestamp(time.Unix(1020, 0)))
+	require.NoError(t, err)
+
+	_, err = sm.Prepare(fake.NewAddress(0), block)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid timestamp: .* is too far ahead of ", err.Error())
+	require.Equal(t, InitialState, sm.state)
+}
+
 func TestStateMachine_MissingGenesis_Prepare(t *testing.T) {
 	tree, db, clean := makeTree(t)
 	defer clean()
@@ -212,12 +294,14 @@ func TestStateMachine_MissingGenesis_Prepare(t *testing.T) {
 		authReader: goodReader,
 		genesis:    blockstore.NewGenesisStore(),
 		blocks:     blockstore.NewInMemory(),
+		clock:      clock.NewReal(),
 	}
 
 	root := types.Digest{}
 	copy(root[:], tree.GetRoot())
 
-	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root))
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root),
+		types.WithTimestamp(time.Now()))
 	require.NoError(t, err)
 
 	_, err = sm.Prepare(fake.NewAddress(0), block)
@@ -273,6 +357,7 @@ func TestStateMachine_FailReadRosterInStageTree_Prepare(t *testing.T) {
 		blocks:  blockstore.NewInMemory(),
 		hashFac: crypto.NewSha256Factory(),
 		watcher: core.NewWatcher(),
+		clock:   clock.NewReal(),
 	}
 
 	sm.genesis.Set(types.Genesis{})
@@ -280,7 +365,8 @@ func TestStateMachine_FailReadRosterInStageTree_Prepare(t *testing.T) {
 	root := types.Digest{}
 	copy(root[:], tree.GetRoot())
 
-	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root))
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root),
+		types.WithTimestamp(time.Now()))
 	require.NoError(t, err)
 
 	// Failure to read the roster of the staging tree.
@@ -302,6 +388,7 @@ func TestStateMachine_FailCreateLink_Prepare(t *testing.T) {
 		blocks:     blockstore.NewInMemory(),
 		hashFac:    fake.NewHashFactory(fake.NewBadHash()),
 		watcher:    core.NewWatcher(),
+		clock:      clock.NewReal(),
 	}
 
 	sm.genesis.Set(types.Genesis{})
@@ -309,7 +396,8 @@ func TestStateMachine_FailCreateLink_Prepare(t *testing.T) {
 	root := types.Digest{}
 	copy(root[:], tree.GetRoot())
 
-	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root))
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root),
+		types.WithTimestamp(time.Now()))
 	require.NoError(t, err)
 
 	_, err = sm.Prepare(fake.NewAddress(0), block)
@@ -660,6 +748,84 @@ func TestStateMachine_verifyViews(t *testing.T) {
 	require.EqualError(t, err, fake.Err("failed to read roster"))
 }
 
+func TestStateMachine_verifyTimestamp(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	sm := &pbftsm{
+		blocks: blockstore.NewInMemory(),
+		clock:  fake.NewClock(now),
+	}
+
+	prev := types.Digest{}
+
+	for i, sec := range []int64{990, 980, 995} {
+		block, err := types.NewBlock(simple.NewResult(nil),
+			types.WithIndex(uint64(i)), types.WithTimestamp(time.Unix(sec, 0)))
+		require.NoError(t, err)
+
+		link, err := types.NewBlockLink(prev, block)
+		require.NoError(t, err)
+
+		require.NoError(t, sm.blocks.Store(link))
+		prev = block.GetHash()
+	}
+
+	makeBlock := func(sec int64) types.Block {
+		block, err := types.NewBlock(simple.NewResult(nil),
+			types.WithTimestamp(time.Unix(sec, 0)))
+		require.NoError(t, err)
+
+		return block
+	}
+
+	// The median is 990, so a block can be before the previous one at 995.
+	require.NoError(t, sm.verifyTimestamp(makeBlock(990)))
+	require.NoError(t, sm.verifyTimestamp(makeBlock(994)))
+
+	err := sm.verifyTimestamp(makeBlock(989))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is before the median time")
+
+	// The clock is not used, so that the old blocks of a catch-up are valid.
+	sm.clock = fake.NewClock(time.Unix(5000, 0))
+	require.NoError(t, sm.verifyTimestamp(makeBlock(995)))
+
+	sm.blocks = badBlockStore{length: 1}
+	err = sm.verifyTimestamp(makeBlock(1000))
+	require.EqualError(t, err,
+		fake.Err("couldn't get median time: failed to read block 0"))
+}
+
+func TestStateMachine_verifyDrift(t *testing.T) {
+	sm := &pbftsm{
+		clock: fake.NewClock(time.Unix(1000, 0)),
+	}
+
+	makeBlock := func(sec int64) types.Block {
+		block, err := types.NewBlock(simple.NewResult(nil),
+			types.WithTimestamp(time.Unix(sec, 0)))
+		require.NoError(t, err)
+
+		return block
+	}
+
+	require.NoError(t, sm.verifyDrift(makeBlock(990)))
+	require.NoError(t, sm.verifyDrift(makeBlock(1010)))
+
+	err := sm.verifyDrift(makeBlock(1011))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is too far ahead of")
+
+	err = sm.verifyDrift(makeBlock(989))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is too far behind")
+
+	// A block without a timestamp is refused.
+	err = sm.verifyDrift(makeBlock(0))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is too far behind")
+}
+
 func TestStateMachine_AcceptAll(t *testing.T) {
 	ro := authority.FromAuthority(fake.NewAuthority(4, fake.NewSigner))
 
@@ -813,6 +979,64 @@ func TestStateMachine_CatchUp(t *testing.T) {
 	require.EqualError(t, err, fake.Err("finalize failed: couldn't marshal signature"))
 }
 
+func TestStateMachine_Journal_CatchUp(t *testing.T) {
+	tree, db, clean := makeTree(t)
+	defer clean()
+
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	journal := execution.NewJournal(db)
+
+	param := StateMachineParam{
+		Validation:      writeValidation{},
+		VerifierFactory: fake.VerifierFactory{},
+		Blocks:          blockstore.NewInMemory(),
+		Genesis:         blockstore.NewGenesisStore(),
+		Tree:            blockstore.NewTreeCache(tree),
+		AuthorityReader: func(hashtree.Tree) (authority.Authority, error) {
+			return ro, nil
+		},
+		DB:      db,
+		Journal: journal,
+	}
+
+	param.Genesis.Set(types.Genesis{})
+
+	next, err := tree.Stage(func(snap store.Snapshot) error {
+		return snap.Set([]byte("key"), []byte("value"))
+	})
+	require.NoError(t, err)
+
+	root := types.Digest{}
+	copy(root[:], next.GetRoot())
+
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root), types.WithIndex(0))
+	require.NoError(t, err)
+
+	link, err := types.NewBlockLink(types.Digest{}, block,
+		types.WithSignatures(fake.Signature{}, fake.Signature{}),
+		types.WithChangeSet(authority.NewChangeSet()))
+	require.NoError(t, err)
+
+	sm := NewStateMachine(param).(*pbftsm)
+
+	err = sm.CatchUp(link)
+	require.NoError(t, err)
+
+	var diffs []execution.Diff
+	err = journal.Export(0, func(diff execution.Diff) error {
+		diffs = append(diffs, diff)
+		return nil
+	})
+	require.NoError(t, err)
+
+	expected := execution.Diff{
+		Index:   0,
+		Changes: []execution.Change{{Key: []byte("key"), Value: []byte("value")}},
+	}
+	require.Equal(t, []execution.Diff{expected}, diffs)
+}
+
 // checks that the tentative leader is set in case the tentative round is equal
 // to the proposed block.
 func TestStateMachine_CatchUp_Tentative_Leader_Accept(t *testing.T) {
@@ -898,7 +1122,7 @@ func makeTree(t *testing.T) (hashtree.Tree, kv.DB, func()) {
 }
 
 func makeLink(t *testing.T) types.BlockLink {
-	block, err := types.NewBlock(simple.NewResult(nil))
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTimestamp(time.Now()))
 	require.NoError(t, err)
 
 	link, err := types.NewBlockLink(types.Digest{}, block)
@@ -923,6 +1147,15 @@ func (v badValidation) Validate(store.Snapshot, []txn.Transaction) (validation.R
 	return nil, fake.GetError()
 }
 
+// writeValidation is a validation service that writes a value to the store.
+type writeValidation struct {
+	validation.Service
+}
+
+func (v writeValidation) Validate(snap store.Snapshot, txs []txn.Transaction) (validation.Result, error) {
+	return simple.NewResult(nil), snap.Set([]byte("key"), []byte("value"))
+}
+
 type unacceptedTxsValidation struct {
 	validation.Service
 }
@@ -950,6 +1183,10 @@ func (s badBlockStore) Last() (types.BlockLink, error) {
 	return nil, fake.GetError()
 }
 
+func (s badBlockStore) GetByIndex(uint64) (types.BlockLink, error) {
+	return nil, fake.GetError()
+}
+
 func (s badBlockStore) Store(types.BlockLink) error {
 	return fake.GetError()
 }
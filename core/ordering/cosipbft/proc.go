@@ -7,12 +7,14 @@
 package cosipbft
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/rs/zerolog"
 	"go.dedis.ch/dela/core"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/beacon"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blocksync"
 	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/viewchange"
@@ -20,6 +22,7 @@ import (
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/store"
 	"go.dedis.ch/dela/core/store/hashtree"
+	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/mino"
@@ -46,6 +49,7 @@ type processor struct {
 	sync        blocksync.Synchronizer
 	tree        blockstore.TreeCache
 	pool        pool.Pool
+	policies    map[string]OrderingPolicy
 	watcher     core.Observable
 	rosterFac   authority.Factory
 	hashFactory crypto.HashFactory
@@ -61,6 +65,10 @@ type processor struct {
 func newProcessor() *processor {
 	return &processor{
 		watcher: core.NewWatcher(),
+		policies: map[string]OrderingPolicy{
+			ArrivalOrdering: NewArrivalPolicy(),
+			BeaconOrdering:  NewBeaconPolicy(),
+		},
 		context: json.NewContext(),
 		started: make(chan struct{}),
 	}
@@ -145,9 +153,13 @@ func (h *processor) Process(req mino.Request) (serde.Message, error) {
 			return nil, nil
 		}
 
-		root := msg.GetGenesis().GetRoot()
+		genesis := msg.GetGenesis()
+		root := genesis.GetRoot()
 
-		return nil, h.storeGenesis(msg.GetGenesis().GetRoster(), &root)
+		return nil, h.storeGenesis(genesis.GetRoster(), &root,
+			types.WithRelays(genesis.GetRelays()...),
+			types.WithOrdering(genesis.GetOrdering()),
+			types.WithAuthority(genesis.GetAuthority()))
 	case types.DoneMessage:
 		err := h.pbftsm.Finalize(msg.GetID(), msg.GetSignature())
 		if err != nil {
@@ -189,7 +201,9 @@ func (h *processor) readRoster(tree hashtree.Tree) (authority.Authority, error)
 	return roster, nil
 }
 
-func (h *processor) storeGenesis(roster authority.Authority, match *types.Digest) error {
+func (h *processor) storeGenesis(roster authority.Authority, match *types.Digest,
+	opts ...types.GenesisOption) error {
+
 	value, err := roster.Serialize(h.context)
 	if err != nil {
 		return xerrors.Errorf("failed to serialize roster: %v", err)
@@ -219,11 +233,20 @@ func (h *processor) storeGenesis(roster authority.Authority, match *types.Digest
 		return xerrors.Errorf("mismatch tree root '%v' != '%v'", match, root)
 	}
 
-	genesis, err := types.NewGenesis(roster, types.WithGenesisRoot(root))
+	opts = append(opts, types.WithGenesisRoot(root))
+
+	genesis, err := types.NewGenesis(roster, opts...)
 	if err != nil {
 		return xerrors.Errorf("creating genesis: %v", err)
 	}
 
+	// A member that doesn't know the policy of the chain could neither propose
+	// nor verify a block.
+	_, err = h.getPolicy(genesis)
+	if err != nil {
+		return xerrors.Errorf("invalid ordering: %v", err)
+	}
+
 	err = stageTree.Commit()
 	if err != nil {
 		return xerrors.Errorf("tree commit failed: %v", err)
@@ -255,3 +278,71 @@ func (h *processor) makeAccess(store store.Snapshot, roster authority.Authority)
 
 	return nil
 }
+
+// getPolicy returns the ordering policy selected by the genesis block.
+func (h *processor) getPolicy(genesis types.Genesis) (OrderingPolicy, error) {
+	name := genesis.GetOrdering()
+	if name == "" {
+		name = ArrivalOrdering
+	}
+
+	policy, found := h.policies[name]
+	if !found {
+		return nil, xerrors.Errorf("unknown policy '%s'", name)
+	}
+
+	return policy, nil
+}
+
+// orderTransactions orders the transactions of the block at the index with
+// the policy of the chain. The seed is the randomness of the previous block.
+// The genesis block is not signed, so the first block is ordered without a
+// seed.
+func (h *processor) orderTransactions(txs []txn.Transaction,
+	index uint64) ([]txn.Transaction, error) {
+
+	genesis, err := h.genesis.Get()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read genesis: %v", err)
+	}
+
+	policy, err := h.getPolicy(genesis)
+	if err != nil {
+		return nil, err
+	}
+
+	var seed []byte
+
+	if index > 0 {
+		seed, err = beacon.NewBeacon(h.blocks).GetRandomness(index - 1)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read randomness: %v", err)
+		}
+	}
+
+	ordered, err := policy.Order(txs, seed)
+	if err != nil {
+		return nil, xerrors.Errorf("policy failed: %v", err)
+	}
+
+	return ordered, nil
+}
+
+// verifyOrder returns an error if the transactions of the block are not in the
+// order of the policy of the chain, which is found by ordering them again.
+func (h *processor) verifyOrder(block types.Block) error {
+	txs := block.GetTransactions()
+
+	ordered, err := h.orderTransactions(txs, block.GetIndex())
+	if err != nil {
+		return xerrors.Errorf("failed to order: %v", err)
+	}
+
+	for i, tx := range ordered {
+		if !bytes.Equal(tx.GetID(), txs[i].GetID()) {
+			return xerrors.Errorf("transaction %d is out of order", i)
+		}
+	}
+
+	return nil
+}
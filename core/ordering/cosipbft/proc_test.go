@@ -12,6 +12,7 @@ import (
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/store"
 	"go.dedis.ch/dela/core/store/hashtree"
+	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/validation/simple"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/internal/testing/fake"
@@ -82,7 +83,9 @@ func TestProcessor_GenesisMessage_Process(t *testing.T) {
 
 	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
 
-	genesis, err := types.NewGenesis(ro, types.WithGenesisRoot(root))
+	genesis, err := types.NewGenesis(ro, types.WithGenesisRoot(root),
+		types.WithRelays(fake.NewAddress(1)), types.WithOrdering(BeaconOrdering),
+		types.WithAuthority(fake.PublicKey{}))
 	require.NoError(t, err)
 
 	req := mino.Request{
@@ -93,7 +96,20 @@ func TestProcessor_GenesisMessage_Process(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, msg)
 
+	stored, err := proc.genesis.Get()
+	require.NoError(t, err)
+	require.Equal(t, genesis.GetHash(), stored.GetHash())
+	require.Equal(t, fake.PublicKey{}, stored.GetAuthority())
+
+	unknown, err := types.NewGenesis(ro, types.WithGenesisRoot(root),
+		types.WithOrdering("unknown"))
+	require.NoError(t, err)
+
 	proc.genesis = blockstore.NewGenesisStore()
+	_, err = proc.Process(mino.Request{Message: types.NewGenesisMessage(unknown)})
+	require.EqualError(t, err, "invalid ordering: unknown policy 'unknown'")
+	require.False(t, proc.genesis.Exists())
+
 	proc.context = fake.NewContext()
 	_, err = proc.Process(req)
 	require.Error(t, err)
@@ -161,6 +177,85 @@ func TestProcessor_ViewMessage_Process(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestProcessor_OrderTransactions(t *testing.T) {
+	policy := &fakePolicy{}
+
+	genesis, err := types.NewGenesis(authority.New(nil, nil), types.WithOrdering("fake"))
+	require.NoError(t, err)
+
+	proc := newProcessor()
+	proc.policies["fake"] = policy
+	proc.genesis = blockstore.NewGenesisStore()
+	proc.genesis.Set(genesis)
+	proc.blocks = blockstore.NewInMemory()
+
+	txs := []txn.Transaction{makeTx(t, 0, fake.NewSigner())}
+
+	ordered, err := proc.orderTransactions(txs, 0)
+	require.NoError(t, err)
+	require.Equal(t, txs, ordered)
+	require.Nil(t, policy.seed)
+
+	opts := []types.LinkOption{types.WithSignatures(fake.Signature{}, fake.Signature{})}
+	proc.blocks.Store(makeBlock(t, types.Digest{}, opts...))
+
+	_, err = proc.orderTransactions(txs, 1)
+	require.NoError(t, err)
+	require.Len(t, policy.seed, 32)
+
+	policy.err = fake.GetError()
+	_, err = proc.orderTransactions(txs, 1)
+	require.EqualError(t, err, fake.Err("policy failed"))
+
+	opts = []types.LinkOption{types.WithSignatures(fake.Signature{}, fake.NewBadSignature())}
+	proc.blocks = blockstore.NewInMemory()
+	proc.blocks.Store(makeBlock(t, types.Digest{}, opts...))
+
+	_, err = proc.orderTransactions(txs, 1)
+	require.EqualError(t, err, fake.Err("failed to read randomness: "+
+		"failed to derive: failed to marshal signature"))
+
+	delete(proc.policies, "fake")
+	_, err = proc.orderTransactions(txs, 0)
+	require.EqualError(t, err, "unknown policy 'fake'")
+
+	proc.genesis = blockstore.NewGenesisStore()
+	_, err = proc.orderTransactions(txs, 0)
+	require.EqualError(t, err, "failed to read genesis: missing genesis block")
+}
+
+func TestProcessor_VerifyOrder(t *testing.T) {
+	genesis, err := types.NewGenesis(authority.New(nil, nil),
+		types.WithOrdering(BeaconOrdering))
+	require.NoError(t, err)
+
+	proc := newProcessor()
+	proc.genesis = blockstore.NewGenesisStore()
+	proc.genesis.Set(genesis)
+	proc.blocks = blockstore.NewInMemory()
+
+	opts := []types.LinkOption{types.WithSignatures(fake.Signature{}, fake.Signature{})}
+	proc.blocks.Store(makeBlock(t, types.Digest{}, opts...))
+
+	txs := makeOrderingTxs(t)
+
+	ordered, err := proc.orderTransactions(txs, 1)
+	require.NoError(t, err)
+
+	err = proc.verifyOrder(makeOrderedBlock(t, 1, ordered))
+	require.NoError(t, err)
+
+	// A leader that moves a transaction forward is caught by the members.
+	ordered[0], ordered[1] = ordered[1], ordered[0]
+
+	err = proc.verifyOrder(makeOrderedBlock(t, 1, ordered))
+	require.EqualError(t, err, "transaction 0 is out of order")
+
+	err = proc.verifyOrder(makeOrderedBlock(t, 2, ordered))
+	require.EqualError(t, err, "failed to order: failed to read randomness: "+
+		"failed to read block 1: block not found: no block")
+}
+
 func TestProcessor_Unsupported_Process(t *testing.T) {
 	proc := newProcessor()
 
@@ -173,6 +268,18 @@ func TestProcessor_Unsupported_Process(t *testing.T) {
 // -----------------------------------------------------------------------------
 // Utility functions
 
+func makeOrderedBlock(t *testing.T, index uint64, txs []txn.Transaction) types.Block {
+	results := make([]simple.TransactionResult, len(txs))
+	for i, tx := range txs {
+		results[i] = simple.NewTransactionResult(tx, true, "")
+	}
+
+	block, err := types.NewBlock(simple.NewResult(results), types.WithIndex(index))
+	require.NoError(t, err)
+
+	return block
+}
+
 func makeBlock(t *testing.T, from types.Digest, opts ...types.LinkOption) types.BlockLink {
 	block, err := types.NewBlock(simple.NewResult(nil))
 	require.NoError(t, err)
@@ -307,8 +414,9 @@ func (t fakeTree) Commit() error {
 type fakeGenesisStore struct {
 	blockstore.GenesisStore
 
-	errGet error
-	errSet error
+	errGet  error
+	errSet  error
+	counter *fake.Counter
 }
 
 func (s fakeGenesisStore) Exists() bool {
@@ -316,6 +424,11 @@ func (s fakeGenesisStore) Exists() bool {
 }
 
 func (s fakeGenesisStore) Get() (types.Genesis, error) {
+	if s.counter != nil && !s.counter.Done() {
+		s.counter.Decrease()
+		return types.Genesis{}, nil
+	}
+
 	return types.Genesis{}, s.errGet
 }
 
@@ -9,11 +9,14 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"time"
 
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/validation"
 	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/merkle"
+	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/dela/serde/registry"
 	"golang.org/x/xerrors"
@@ -24,6 +27,21 @@ var (
 	blockFormats   = registry.NewSimpleRegistry()
 )
 
+// CiphertextArg is the argument of a transaction that holds a ciphertext. The
+// ciphertexts of a block are committed in the header of the block.
+const CiphertextArg = "f3b:ciphertext"
+
+// The optional fields of the fingerprint of a block are preceded by a tag and
+// their length, so that a field can't be mistaken for another one.
+const (
+	ciphertextRootTag byte = 1
+	timestampTag      byte = 2
+	orderingTag       byte = 3
+	authorityTag      byte = 4
+	relaysTag         byte = 5
+	thresholdTag      byte = 6
+)
+
 // RegisterGenesisFormat registers the engine for the provided format.
 func RegisterGenesisFormat(f serde.Format, e serde.FormatEngine) {
 	genesisFormats.Register(f, e)
@@ -51,13 +69,31 @@ func (d Digest) Bytes() []byte {
 }
 
 // Genesis is the very first block of a chain. It contains the initial roster
-// and tree root.
+// and tree root, the members of the roster that are relays, the threshold of
+// the key shares, the ordering policy and the authority of the chain.
 //
 // - implements serde.Message
 type Genesis struct {
 	digest   Digest
 	roster   authority.Authority
 	treeRoot Digest
+
+	// relays are the members that take part in the consensus but do not hold
+	// any key share.
+	relays []mino.Address
+
+	// threshold is the number of key shares required to release a key, or
+	// zero if the chain does not set it.
+	threshold int
+
+	// ordering is the name of the policy that orders the transactions of the
+	// blocks. It is the same for every member, so that they can verify the
+	// order of a proposal.
+	ordering string
+
+	// authority is the public key of the authority that issues the identity
+	// certificates of the chain, or nil if the chain does not require them.
+	authority crypto.PublicKey
 }
 
 type genesisTemplate struct {
@@ -82,6 +118,39 @@ func WithGenesisHashFactory(fac crypto.HashFactory) GenesisOption {
 	}
 }
 
+// WithRelays is an option to set the members of the roster that take part in
+// the consensus but do not hold any key share.
+func WithRelays(addrs ...mino.Address) GenesisOption {
+	return func(tmpl *genesisTemplate) {
+		tmpl.relays = addrs
+	}
+}
+
+// WithThreshold is an option to set the number of key shares required to
+// release a key. It must not be above the number of members that are not
+// relays.
+func WithThreshold(threshold int) GenesisOption {
+	return func(tmpl *genesisTemplate) {
+		tmpl.threshold = threshold
+	}
+}
+
+// WithOrdering is an option to set the name of the ordering policy of the
+// chain.
+func WithOrdering(name string) GenesisOption {
+	return func(tmpl *genesisTemplate) {
+		tmpl.ordering = name
+	}
+}
+
+// WithAuthority is an option to set the public key of the authority that
+// issues the identity certificates of the chain.
+func WithAuthority(pubkey crypto.PublicKey) GenesisOption {
+	return func(tmpl *genesisTemplate) {
+		tmpl.authority = pubkey
+	}
+}
+
 // NewGenesis creates a new genesis block with the provided roster.
 func NewGenesis(ro authority.Authority, opts ...GenesisOption) (Genesis, error) {
 	tmpl := genesisTemplate{
@@ -96,6 +165,21 @@ func NewGenesis(ro authority.Authority, opts ...GenesisOption) (Genesis, error)
 		opt(&tmpl)
 	}
 
+	for _, addr := range tmpl.relays {
+		_, index := ro.GetPublicKey(addr)
+		if index < 0 {
+			return tmpl.Genesis, xerrors.Errorf("relay %v is not in the roster", addr)
+		}
+	}
+
+	if tmpl.threshold != 0 {
+		holders := ro.Len() - len(tmpl.relays)
+		if tmpl.threshold < 0 || tmpl.threshold > holders {
+			return tmpl.Genesis, xerrors.Errorf("threshold %d is not between 1 "+
+				"and the %d members that hold a share", tmpl.threshold, holders)
+		}
+	}
+
 	h := tmpl.hashFactory.New()
 	err := tmpl.Fingerprint(h)
 	if err != nil {
@@ -122,6 +206,41 @@ func (g Genesis) GetRoot() Digest {
 	return g.treeRoot
 }
 
+// GetRelays returns the members of the roster that do not hold any key share.
+func (g Genesis) GetRelays() []mino.Address {
+	return append([]mino.Address{}, g.relays...)
+}
+
+// IsRelay returns true if the address is a relay of the genesis block.
+func (g Genesis) IsRelay(addr mino.Address) bool {
+	for _, relay := range g.relays {
+		if relay.Equal(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetThreshold returns the number of key shares required to release a key. It
+// is zero when the chain does not set it.
+func (g Genesis) GetThreshold() int {
+	return g.threshold
+}
+
+// GetOrdering returns the name of the ordering policy of the chain. It is
+// empty when the chain uses the default policy.
+func (g Genesis) GetOrdering() string {
+	return g.ordering
+}
+
+// GetAuthority returns the public key of the authority that issues the
+// identity certificates of the chain, or nil if the chain does not require
+// them.
+func (g Genesis) GetAuthority() crypto.PublicKey {
+	return g.authority
+}
+
 // Serialize implements serde.Message. It returns the serialized data for this
 // genesis block.
 func (g Genesis) Serialize(ctx serde.Context) ([]byte, error) {
@@ -148,23 +267,76 @@ func (g Genesis) Fingerprint(w io.Writer) error {
 		return xerrors.Errorf("roster fingerprint failed: %v", err)
 	}
 
+	// The relays are written as their count followed by their index in the
+	// roster, and only when there are some so that the digest of a genesis
+	// without relays is unchanged.
+	if len(g.relays) > 0 {
+		buffer := make([]byte, 4+4*len(g.relays))
+		binary.LittleEndian.PutUint32(buffer, uint32(len(g.relays)))
+
+		for i, addr := range g.relays {
+			_, index := g.roster.GetPublicKey(addr)
+			binary.LittleEndian.PutUint32(buffer[4+4*i:], uint32(index))
+		}
+
+		err = writeField(w, relaysTag, buffer)
+		if err != nil {
+			return xerrors.Errorf("couldn't write relays: %v", err)
+		}
+	}
+
+	if g.threshold > 0 {
+		buffer := make([]byte, 4)
+		binary.LittleEndian.PutUint32(buffer, uint32(g.threshold))
+
+		err = writeField(w, thresholdTag, buffer)
+		if err != nil {
+			return xerrors.Errorf("couldn't write threshold: %v", err)
+		}
+	}
+
+	if g.ordering != "" {
+		err = writeField(w, orderingTag, []byte(g.ordering))
+		if err != nil {
+			return xerrors.Errorf("couldn't write ordering: %v", err)
+		}
+	}
+
+	if g.authority != nil {
+		data, err := g.authority.MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("couldn't marshal authority: %v", err)
+		}
+
+		err = writeField(w, authorityTag, data)
+		if err != nil {
+			return xerrors.Errorf("couldn't write authority: %v", err)
+		}
+	}
+
 	return nil
 }
 
 // RosterKey is the key of the roster factory.
 type RosterKey struct{}
 
+// PublicKeyKey is the key of the public key factory.
+type PublicKeyKey struct{}
+
 // GenesisFactory is a factory to deserialize the genesis messages.
 //
 // - implements serde.Factory
 type GenesisFactory struct {
 	rosterFac authority.Factory
+	pkFac     crypto.PublicKeyFactory
 }
 
-// NewGenesisFactory creates a new genesis factory.
-func NewGenesisFactory(rf authority.Factory) GenesisFactory {
+// NewGenesisFactory creates a new genesis factory. The public key factory
+// deserializes the key of the authority.
+func NewGenesisFactory(rf authority.Factory, pkf crypto.PublicKeyFactory) GenesisFactory {
 	return GenesisFactory{
 		rosterFac: rf,
+		pkFac:     pkf,
 	}
 }
 
@@ -174,6 +346,7 @@ func (f GenesisFactory) Deserialize(ctx serde.Context, data []byte) (serde.Messa
 	format := genesisFormats.Get(ctx.GetFormat())
 
 	ctx = serde.WithFactory(ctx, RosterKey{}, f.rosterFac)
+	ctx = serde.WithFactory(ctx, PublicKeyKey{}, f.pkFac)
 
 	msg, err := format.Decode(ctx, data)
 	if err != nil {
@@ -184,8 +357,8 @@ func (f GenesisFactory) Deserialize(ctx serde.Context, data []byte) (serde.Messa
 }
 
 // Block is a block of a chain. It holds an index which is the height of the
-// block from the genesis block, the Merkle tree root and the validation result
-// of the transactions.
+// block from the genesis block, the Merkle tree root, the time of the proposal,
+// the root of the ciphertexts and the validation result of the transactions.
 //
 // - implements serde.Message
 type Block struct {
@@ -193,6 +366,14 @@ type Block struct {
 	index    uint64
 	data     validation.Result
 	treeRoot Digest
+
+	// timestamp is the time of the proposal in nanoseconds since the epoch,
+	// or zero if it is unknown.
+	timestamp int64
+
+	// ciphertextRoot is the root of the Merkle tree of the ciphertexts of the
+	// transactions, or nil if the block has none.
+	ciphertextRoot []byte
 }
 
 type blockTemplate struct {
@@ -217,6 +398,25 @@ func WithTreeRoot(root Digest) BlockOption {
 	}
 }
 
+// WithTimestamp is an option to set the time of the proposal of the block.
+func WithTimestamp(t time.Time) BlockOption {
+	return func(tmpl *blockTemplate) {
+		tmpl.timestamp = 0
+
+		if !t.IsZero() {
+			tmpl.timestamp = t.UnixNano()
+		}
+	}
+}
+
+// WithCiphertextRoot is an option to set the root of the ciphertexts of the
+// block.
+func WithCiphertextRoot(root []byte) BlockOption {
+	return func(tmpl *blockTemplate) {
+		tmpl.ciphertextRoot = root
+	}
+}
+
 // WithHashFactory is an option to set the hash factory for the block.
 func WithHashFactory(fac crypto.HashFactory) BlockOption {
 	return func(tmpl *blockTemplate) {
@@ -282,6 +482,22 @@ func (b Block) GetTreeRoot() Digest {
 	return b.treeRoot
 }
 
+// GetTimestamp returns the time of the proposal of the block, or the zero time
+// if it is unknown.
+func (b Block) GetTimestamp() time.Time {
+	if b.timestamp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, b.timestamp)
+}
+
+// GetCiphertextRoot returns the root of the ciphertexts of the block, or nil
+// if the block has none.
+func (b Block) GetCiphertextRoot() []byte {
+	return b.ciphertextRoot
+}
+
 // Fingerprint implements serde.Fingerprinter. It deterministically writes a
 // binary representation of the block into the writer.
 func (b Block) Fingerprint(w io.Writer) error {
@@ -297,6 +513,25 @@ func (b Block) Fingerprint(w io.Writer) error {
 		return xerrors.Errorf("couldn't write root: %v", err)
 	}
 
+	// The timestamp is left out when unknown so that the blocks created before
+	// it existed keep the same hash.
+	if b.timestamp != 0 {
+		binary.LittleEndian.PutUint64(buffer, uint64(b.timestamp))
+
+		err = writeField(w, timestampTag, buffer)
+		if err != nil {
+			return xerrors.Errorf("couldn't write timestamp: %v", err)
+		}
+	}
+
+	// The root of the ciphertexts is also left out when the block has none.
+	if b.ciphertextRoot != nil {
+		err = writeField(w, ciphertextRootTag, b.ciphertextRoot)
+		if err != nil {
+			return xerrors.Errorf("couldn't write ciphertext root: %v", err)
+		}
+	}
+
 	err = b.data.Fingerprint(w)
 	if err != nil {
 		return xerrors.Errorf("data fingerprint failed: %v", err)
@@ -305,6 +540,18 @@ func (b Block) Fingerprint(w io.Writer) error {
 	return nil
 }
 
+// writeField writes an optional field of the fingerprint with its tag and its
+// length.
+func writeField(w io.Writer, tag byte, value []byte) error {
+	header := make([]byte, 9)
+	header[0] = tag
+	binary.LittleEndian.PutUint64(header[1:], uint64(len(value)))
+
+	_, err := w.Write(append(header, value...))
+
+	return err
+}
+
 // Serialize implements serde.Message. It returns the serialized data of the
 // block.
 func (b Block) Serialize(ctx serde.Context) ([]byte, error) {
@@ -349,3 +596,23 @@ func (f BlockFactory) Deserialize(ctx serde.Context, data []byte) (serde.Message
 
 	return msg, nil
 }
+
+// NewCiphertextRoot returns the root of the Merkle tree of the ciphertexts of
+// the transactions, in the order of the transactions. It returns nil if none
+// of them holds a ciphertext.
+func NewCiphertextRoot(txs []txn.Transaction) []byte {
+	var leaves [][]byte
+
+	for _, tx := range txs {
+		ct := tx.GetArg(CiphertextArg)
+		if ct != nil {
+			leaves = append(leaves, ct)
+		}
+	}
+
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	return merkle.NewTree(leaves).Root()
+}
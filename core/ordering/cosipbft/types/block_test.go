@@ -2,15 +2,21 @@ package types
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/signed"
 	"go.dedis.ch/dela/core/validation"
 	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/crypto/merkle"
 	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
 )
 
 func init() {
@@ -60,6 +66,136 @@ func TestGenesis_GetRoot(t *testing.T) {
 	require.Equal(t, Digest{5}, genesis.GetRoot())
 }
 
+func TestGenesis_Relays(t *testing.T) {
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	genesis, err := NewGenesis(ro, WithRelays(fake.NewAddress(1)))
+	require.NoError(t, err)
+	require.Equal(t, []mino.Address{fake.NewAddress(1)}, genesis.GetRelays())
+	require.True(t, genesis.IsRelay(fake.NewAddress(1)))
+	require.False(t, genesis.IsRelay(fake.NewAddress(0)))
+
+	// The relays are part of the digest.
+	other, err := NewGenesis(ro)
+	require.NoError(t, err)
+	require.Empty(t, other.GetRelays())
+	require.NotEqual(t, other.GetHash(), genesis.GetHash())
+
+	// The relays are one field with their count followed by their index.
+	genesis, err = NewGenesis(ro, WithRelays(fake.NewAddress(2), fake.NewAddress(0)))
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	err = genesis.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.Regexp(t, "\x05\x0c(\x00){7}\x02\x00{3}\x02\x00{3}\x00{4}$", buffer.String())
+
+	_, err = NewGenesis(ro, WithRelays(fake.NewAddress(3)))
+	require.EqualError(t, err, "relay fake.Address[3] is not in the roster")
+}
+
+func TestGenesis_Threshold(t *testing.T) {
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	genesis, err := NewGenesis(ro, WithThreshold(2))
+	require.NoError(t, err)
+	require.Equal(t, 2, genesis.GetThreshold())
+
+	buffer := new(bytes.Buffer)
+	err = genesis.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.Regexp(t, "\x06\x04(\x00){7}\x02\x00{3}$", buffer.String())
+
+	// The digest of a genesis without a threshold is unchanged.
+	other, err := NewGenesis(ro)
+	require.NoError(t, err)
+	require.Equal(t, 0, other.GetThreshold())
+	require.NotEqual(t, other.GetHash(), genesis.GetHash())
+
+	buffer.Reset()
+	err = other.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.NotContains(t, buffer.String(), "\x06\x04")
+
+	_, err = NewGenesis(ro, WithThreshold(3), WithRelays(fake.NewAddress(1)))
+	require.EqualError(t, err, "threshold 3 is not between 1 and the 2 members that hold a share")
+
+	_, err = NewGenesis(ro, WithThreshold(-1))
+	require.EqualError(t, err, "threshold -1 is not between 1 and the 3 members that hold a share")
+}
+
+func TestGenesis_Ordering(t *testing.T) {
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	genesis, err := NewGenesis(ro, WithOrdering("beacon"))
+	require.NoError(t, err)
+	require.Equal(t, "beacon", genesis.GetOrdering())
+
+	// The policy is part of the digest.
+	other, err := NewGenesis(ro)
+	require.NoError(t, err)
+	require.Empty(t, other.GetOrdering())
+	require.NotEqual(t, other.GetHash(), genesis.GetHash())
+
+	buffer := new(bytes.Buffer)
+	err = genesis.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.Regexp(t, "\x03\x06(\x00){7}beacon$", buffer.String())
+}
+
+func TestGenesis_Authority(t *testing.T) {
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	genesis, err := NewGenesis(ro, WithAuthority(fake.PublicKey{}))
+	require.NoError(t, err)
+	require.Equal(t, fake.PublicKey{}, genesis.GetAuthority())
+
+	// The authority is part of the digest.
+	other, err := NewGenesis(ro)
+	require.NoError(t, err)
+	require.Nil(t, other.GetAuthority())
+	require.NotEqual(t, other.GetHash(), genesis.GetHash())
+
+	buffer := new(bytes.Buffer)
+	err = genesis.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.Regexp(t, "\x04\x02(\x00){7}PK$", buffer.String())
+
+	_, err = NewGenesis(ro, WithAuthority(fake.NewBadPublicKey()))
+	require.EqualError(t, err, fake.Err("fingerprint failed: couldn't marshal authority"))
+}
+
+// The digests are pinned with the keys of seeded signers, so that a change of
+// the format is noticed. In particular, the options must not change the digest
+// of a genesis that does not use them.
+func TestGenesis_Golden_GetHash(t *testing.T) {
+	seed := []byte("genesis")
+
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSeededGenSigner(seed)))
+
+	genesis, err := NewGenesis(ro)
+	require.NoError(t, err)
+	require.Equal(t, "b18a72441389b83d8b5bec59cc69d9fcac77fdd1b9f27c7ba1bd1193342fe3d5",
+		hex.EncodeToString(genesis.GetHash().Bytes()))
+
+	genesis, err = NewGenesis(ro, WithRelays(fake.NewAddress(2)))
+	require.NoError(t, err)
+	require.Equal(t, "6a934da1f13eb40c038ce596a09d6af688fec70bfff7d56df0a657c9fcac7147",
+		hex.EncodeToString(genesis.GetHash().Bytes()))
+
+	genesis, err = NewGenesis(ro, WithOrdering("beacon"))
+	require.NoError(t, err)
+	require.Equal(t, "9acc75345bb65c894e595ceb390d9500517a979f9977ab02fbd6e1402eacc53c",
+		hex.EncodeToString(genesis.GetHash().Bytes()))
+
+	ca := fake.NewSeededBLSSigner(seed).GetPublicKey()
+
+	genesis, err = NewGenesis(ro, WithAuthority(ca))
+	require.NoError(t, err)
+	require.Equal(t, "af37e1578c1dcac78db21e2edb566b530f7c5e1b720acaf63ffe014f1997d535",
+		hex.EncodeToString(genesis.GetHash().Bytes()))
+}
+
 func TestGenesis_Serialize(t *testing.T) {
 	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
 
@@ -94,7 +230,7 @@ func TestGenesis_Fingerprint(t *testing.T) {
 }
 
 func TestGenesisFactory_Deserialize(t *testing.T) {
-	fac := NewGenesisFactory(authority.NewFactory(nil, nil))
+	fac := NewGenesisFactory(authority.NewFactory(nil, nil), fake.PublicKeyFactory{})
 
 	msg, err := fac.Deserialize(fake.NewContext(), nil)
 	require.NoError(t, err)
@@ -110,6 +246,27 @@ func TestBlock_GetHash(t *testing.T) {
 	require.NotEqual(t, Digest{}, block.GetHash())
 }
 
+func TestBlock_GetHash_Fingerprint(t *testing.T) {
+	fac := fake.NewRecordingHashFactory()
+
+	block, err := NewBlock(simple.NewResult(nil), WithIndex(3),
+		WithTimestamp(time.Unix(0, 5)), WithCiphertextRoot([]byte{6, 7}),
+		WithHashFactory(fac))
+	require.NoError(t, err)
+
+	// The digest of the block is the SHA-256 of its fingerprint.
+	fingerprint := new(bytes.Buffer)
+	require.NoError(t, block.Fingerprint(fingerprint))
+
+	written := new(bytes.Buffer)
+	for i := 0; i < fac.Call.Len(); i++ {
+		written.Write(fac.Call.Get(i, 0).([]byte))
+	}
+
+	require.Equal(t, fingerprint.Bytes(), written.Bytes())
+	require.Equal(t, Digest(sha256.Sum256(written.Bytes())), block.GetHash())
+}
+
 func TestBlock_GetIndex(t *testing.T) {
 	block, err := NewBlock(simple.NewResult(nil), WithIndex(2))
 	require.NoError(t, err)
@@ -136,6 +293,48 @@ func TestBlock_GetTreeRoot(t *testing.T) {
 	require.Equal(t, Digest{3}, block.GetTreeRoot())
 }
 
+func TestBlock_GetTimestamp(t *testing.T) {
+	block, err := NewBlock(simple.NewResult(nil))
+	require.NoError(t, err)
+	require.True(t, block.GetTimestamp().IsZero())
+
+	now := time.Unix(0, 123456789)
+
+	block, err = NewBlock(simple.NewResult(nil), WithTimestamp(now))
+	require.NoError(t, err)
+	require.True(t, now.Equal(block.GetTimestamp()))
+
+	block, err = NewBlock(simple.NewResult(nil), WithTimestamp(time.Time{}))
+	require.NoError(t, err)
+	require.True(t, block.GetTimestamp().IsZero())
+}
+
+func TestBlock_GetCiphertextRoot(t *testing.T) {
+	block, err := NewBlock(simple.NewResult(nil))
+	require.NoError(t, err)
+	require.Nil(t, block.GetCiphertextRoot())
+
+	block, err = NewBlock(simple.NewResult(nil), WithCiphertextRoot([]byte{1}))
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, block.GetCiphertextRoot())
+}
+
+func TestNewCiphertextRoot(t *testing.T) {
+	require.Nil(t, NewCiphertextRoot(nil))
+
+	plain := makeTx(t, 0)
+	require.Nil(t, NewCiphertextRoot([]txn.Transaction{plain}))
+
+	first := makeTx(t, 1, signed.WithArg(CiphertextArg, []byte("A")))
+	second := makeTx(t, 2, signed.WithArg(CiphertextArg, []byte("B")))
+
+	root := NewCiphertextRoot([]txn.Transaction{first, plain, second})
+	require.Equal(t, merkle.NewTree([][]byte{[]byte("A"), []byte("B")}).Root(), root)
+
+	// The order of the transactions changes the root.
+	require.NotEqual(t, root, NewCiphertextRoot([]txn.Transaction{second, first}))
+}
+
 func TestBlock_Fingerprint(t *testing.T) {
 	block := Block{
 		index:    3,
@@ -149,12 +348,43 @@ func TestBlock_Fingerprint(t *testing.T) {
 	require.NoError(t, err)
 	require.Regexp(t, "^\x03(\x00){7}\x04(\x00){31}$", buffer.String())
 
+	block.timestamp = 5
+	buffer.Reset()
+
+	err = block.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.Regexp(t, "^\x03(\x00){7}\x04(\x00){31}\x02\x08(\x00){7}\x05(\x00){7}$",
+		buffer.String())
+
+	block.timestamp = 0
+	block.ciphertextRoot = []byte{6, 7}
+	buffer.Reset()
+
+	err = block.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.Regexp(t, "^\x03(\x00){7}\x04(\x00){31}\x01\x02(\x00){7}\x06\x07$",
+		buffer.String())
+
+	block.timestamp = 5
+	buffer.Reset()
+
+	err = block.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.Regexp(t, "^\x03(\x00){7}\x04(\x00){31}\x02\x08(\x00){7}\x05(\x00){7}"+
+		"\x01\x02(\x00){7}\x06\x07$", buffer.String())
+
 	err = block.Fingerprint(fake.NewBadHash())
 	require.EqualError(t, err, fake.Err("couldn't write index"))
 
 	err = block.Fingerprint(fake.NewBadHashWithDelay(1))
 	require.EqualError(t, err, fake.Err("couldn't write root"))
 
+	err = block.Fingerprint(fake.NewBadHashWithDelay(2))
+	require.EqualError(t, err, fake.Err("couldn't write timestamp"))
+
+	err = block.Fingerprint(fake.NewBadHashWithDelay(3))
+	require.EqualError(t, err, fake.Err("couldn't write ciphertext root"))
+
 	block.data = badData{}
 	err = block.Fingerprint(io.Discard)
 	require.EqualError(t, err, fake.Err("data fingerprint failed"))
@@ -205,3 +435,10 @@ type badData struct {
 func (d badData) Fingerprint(io.Writer) error {
 	return fake.GetError()
 }
+
+func makeTx(t *testing.T, nonce uint64, opts ...signed.TransactionOption) txn.Transaction {
+	tx, err := signed.NewTransaction(nonce, fake.PublicKey{}, opts...)
+	require.NoError(t, err)
+
+	return tx
+}
@@ -7,6 +7,8 @@ package types
 
 import (
 	"io"
+	"runtime"
+	"sync"
 
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/crypto"
@@ -20,6 +22,9 @@ var (
 	linkFormats  = registry.NewSimpleRegistry()
 )
 
+// maxVerifyWorkers is the maximum number of links verified in parallel.
+var maxVerifyWorkers = runtime.NumCPU()
+
 // RegisterLinkFormat registers the engine for the provided format.
 func RegisterLinkFormat(f serde.Format, e serde.FormatEngine) {
 	linkFormats.Register(f, e)
@@ -40,6 +45,7 @@ type forwardLink struct {
 	from       Digest
 	to         Digest
 	changeset  authority.ChangeSet
+	roster     Digest
 	prepareSig crypto.Signature
 	commitSig  crypto.Signature
 }
@@ -70,6 +76,14 @@ func WithChangeSet(cs authority.ChangeSet) LinkOption {
 	}
 }
 
+// WithRosterDigest is the option to set the digest of the roster that takes
+// effect after the link, when the change set is not empty.
+func WithRosterDigest(digest Digest) LinkOption {
+	return func(tmpl *linkTemplate) {
+		tmpl.roster = digest
+	}
+}
+
 // WithLinkHashFactory is the option to set the hash factory for the link.
 func WithLinkHashFactory(fac crypto.HashFactory) LinkOption {
 	return func(tmpl *linkTemplate) {
@@ -136,8 +150,16 @@ func (link forwardLink) GetChangeSet() authority.ChangeSet {
 	return link.changeset
 }
 
+// GetRosterDigest implements types.Link. It returns the digest of the roster
+// that takes effect after the link, or an empty digest if the roster does not
+// change.
+func (link forwardLink) GetRosterDigest() Digest {
+	return link.roster
+}
+
 // Fingerprint implements serde.Fingerprinter. It deterministically writes a
-// binary representation of the block link.
+// binary representation of the block link. The digest of the new roster is
+// only written when it is set, so that it is signed with the link.
 func (link forwardLink) Fingerprint(w io.Writer) error {
 	_, err := w.Write(link.from[:])
 	if err != nil {
@@ -151,9 +173,32 @@ func (link forwardLink) Fingerprint(w io.Writer) error {
 		return xerrors.Errorf("couldn't write to: %v", err)
 	}
 
+	if link.roster != (Digest{}) {
+		_, err = w.Write(link.roster[:])
+		if err != nil {
+			return xerrors.Errorf("couldn't write roster: %v", err)
+		}
+	}
+
 	return nil
 }
 
+// RosterDigestOf returns the digest of the roster that a link embeds when the
+// roster changes.
+func RosterDigestOf(roster authority.Authority) (Digest, error) {
+	h := crypto.NewSha256Factory().New()
+
+	err := roster.Fingerprint(h)
+	if err != nil {
+		return Digest{}, xerrors.Errorf("failed to fingerprint roster: %v", err)
+	}
+
+	digest := Digest{}
+	copy(digest[:], h.Sum(nil))
+
+	return digest, nil
+}
+
 // Serialize implements serde.Message. It returns the data of the serialized
 // forward link.
 func (link forwardLink) Serialize(ctx serde.Context) ([]byte, error) {
@@ -331,6 +376,8 @@ func (c chain) Verify(genesis Genesis, from Digest, fac crypto.VerifierFactory)
 
 	toProcess := false
 
+	var jobs []linkJob
+
 	for _, link := range c.GetLinks() {
 		// Skip the verification until we reach the provided Digest. We still
 		// have to update the roster though.
@@ -362,32 +409,116 @@ func (c chain) Verify(genesis Genesis, from Digest, fac crypto.VerifierFactory)
 			return xerrors.New("unexpected nil commit signature in link")
 		}
 
-		// 1. Verify the prepare signature that signs the integrity of the
-		// forward link.
-		err = verifier.Verify(link.GetHash().Bytes(), link.GetPrepareSignature())
-		if err != nil {
-			return xerrors.Errorf("invalid prepare signature: %v", err)
-		}
+		jobs = append(jobs, linkJob{link: link, verifier: verifier})
+
+		prev = link.GetTo()
 
-		// 2. Verify the commit signature that signs the binary representation
-		// of the prepare signature.
-		msg, err := link.GetPrepareSignature().MarshalBinary()
+		authority = authority.Apply(link.GetChangeSet())
+
+		// The next links are verified with the new roster only if it is the
+		// one signed by the current roster.
+		err = verifyRoster(link, authority)
 		if err != nil {
-			return xerrors.Errorf("failed to marshal signature: %v", err)
+			return xerrors.Errorf("invalid roster after '%v': %v", link.GetTo(), err)
 		}
+	}
 
-		err = verifier.Verify(msg, link.GetCommitSignature())
-		if err != nil {
-			return xerrors.Errorf("invalid commit signature: %v", err)
+	if !toProcess {
+		return xerrors.Errorf("no verification made (from Digest %v)", from)
+	}
+
+	return verifyLinks(jobs)
+}
+
+// verifyRoster returns nil if the link embeds the digest of the roster when it
+// changes it, otherwise an error.
+func verifyRoster(link Link, roster authority.Authority) error {
+	if link.GetChangeSet() == nil || link.GetChangeSet().NumChanges() == 0 {
+		if link.GetRosterDigest() != (Digest{}) {
+			return xerrors.New("unexpected roster digest")
 		}
 
-		prev = link.GetTo()
+		return nil
+	}
 
-		authority = authority.Apply(link.GetChangeSet())
+	digest, err := RosterDigestOf(roster)
+	if err != nil {
+		return err
 	}
 
-	if !toProcess {
-		return xerrors.Errorf("no verification made (from Digest %v)", from)
+	if digest != link.GetRosterDigest() {
+		return xerrors.Errorf("mismatch digest '%v' != '%v'", link.GetRosterDigest(), digest)
+	}
+
+	return nil
+}
+
+// linkJob is the verification of the signatures of a link with the verifier
+// of the roster at that point of the chain.
+type linkJob struct {
+	link     Link
+	verifier crypto.Verifier
+}
+
+func (job linkJob) verify() error {
+	// 1. Verify the prepare signature that signs the integrity of the forward
+	// link.
+	err := job.verifier.Verify(job.link.GetHash().Bytes(), job.link.GetPrepareSignature())
+	if err != nil {
+		return xerrors.Errorf("invalid prepare signature: %v", err)
+	}
+
+	// 2. Verify the commit signature that signs the binary representation of
+	// the prepare signature.
+	msg, err := job.link.GetPrepareSignature().MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal signature: %v", err)
+	}
+
+	err = job.verifier.Verify(msg, job.link.GetCommitSignature())
+	if err != nil {
+		return xerrors.Errorf("invalid commit signature: %v", err)
+	}
+
+	return nil
+}
+
+// verifyLinks verifies the signatures of the links with a pool of workers.
+// Once the roster of each link is known, the verifications are independent
+// of each other. It returns the error of the first invalid link of the chain.
+func verifyLinks(jobs []linkJob) error {
+	workers := maxVerifyWorkers
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	errs := make([]error, len(jobs))
+	indices := make(chan int)
+
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				errs[i] = jobs[i].verify()
+			}
+		}()
+	}
+
+	for i := range jobs {
+		indices <- i
+	}
+
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -2,12 +2,16 @@ package types
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 )
 
@@ -83,6 +87,19 @@ func TestForwardLink_GetChangeSet(t *testing.T) {
 	require.Equal(t, authority.NewChangeSet(), link.GetChangeSet())
 }
 
+func TestForwardLink_GetRosterDigest(t *testing.T) {
+	link, err := NewForwardLink(Digest{1}, Digest{2}, WithRosterDigest(Digest{3}))
+	require.NoError(t, err)
+
+	require.Equal(t, Digest{3}, link.GetRosterDigest())
+
+	other, err := NewForwardLink(Digest{1}, Digest{2})
+	require.NoError(t, err)
+
+	// The digest of the roster is part of the signed digest of the link.
+	require.NotEqual(t, other.GetHash(), link.GetHash())
+}
+
 func TestForwardLink_Serialize(t *testing.T) {
 	link := forwardLink{}
 
@@ -109,6 +126,34 @@ func TestForwardLink_Fingerprint(t *testing.T) {
 
 	err = link.Fingerprint(fake.NewBadHashWithDelay(1))
 	require.EqualError(t, err, fake.Err("couldn't write to"))
+
+	link, err = NewForwardLink(Digest{1}, Digest{2}, WithRosterDigest(Digest{3}))
+	require.NoError(t, err)
+
+	buffer.Reset()
+
+	err = link.Fingerprint(buffer)
+	require.NoError(t, err)
+	require.Regexp(t, "^\x01\x00{31}\x02\x00{31}\x03\x00{31}$", buffer.String())
+
+	err = link.Fingerprint(fake.NewBadHashWithDelay(2))
+	require.EqualError(t, err, fake.Err("couldn't write roster"))
+}
+
+func TestRosterDigestOf(t *testing.T) {
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	digest, err := RosterDigestOf(ro)
+	require.NoError(t, err)
+
+	other, err := RosterDigestOf(ro.Take(mino.RangeFilter(0, 2)).(authority.Authority))
+	require.NoError(t, err)
+	require.NotEqual(t, digest, other)
+
+	bad := authority.New([]mino.Address{fake.NewBadAddress()}, []crypto.PublicKey{fake.PublicKey{}})
+
+	_, err = RosterDigestOf(bad)
+	require.EqualError(t, err, fake.Err("failed to fingerprint roster: couldn't marshal address"))
 }
 
 func TestBlockLink_New(t *testing.T) {
@@ -246,6 +291,145 @@ func TestChain_Verify(t *testing.T) {
 	require.EqualError(t, err, fake.Err("invalid commit signature"))
 }
 
+func TestChain_Verify_Parallel(t *testing.T) {
+	signer := bls.NewSigner()
+	ro := authority.New([]mino.Address{fake.NewAddress(0)}, []crypto.PublicKey{signer.GetPublicKey()})
+
+	genesis, err := NewGenesis(ro)
+	require.NoError(t, err)
+
+	c := makeSignedChain(t, signer, genesis, 20)
+
+	err = c.Verify(genesis, genesis.GetHash(), signer.GetVerifierFactory())
+	require.NoError(t, err)
+
+	// The error of the first invalid link is returned, whatever the order the
+	// workers check the links in.
+	links := c.GetLinks()
+	prevs := make([]Link, len(links)-1)
+	copy(prevs, links)
+
+	bad := prevs[4].(forwardLink)
+	bad.commitSig = prevs[3].GetCommitSignature()
+	prevs[4] = bad
+
+	bad = prevs[12].(forwardLink)
+	bad.prepareSig = prevs[11].GetPrepareSignature()
+	prevs[12] = bad
+
+	c = NewChain(links[len(links)-1].(BlockLink), prevs)
+
+	err = c.Verify(genesis, genesis.GetHash(), signer.GetVerifierFactory())
+	require.Error(t, err)
+	require.Regexp(t, "^invalid commit signature: ", err.Error())
+}
+
+func BenchmarkChain_Verify(b *testing.B) {
+	signer := bls.NewSigner()
+	ro := authority.New([]mino.Address{fake.NewAddress(0)}, []crypto.PublicKey{signer.GetPublicKey()})
+
+	genesis, err := NewGenesis(ro)
+	require.NoError(b, err)
+
+	// 10k links to measure the catch-up of a node that has been away for long.
+	c := makeSignedChain(b, signer, genesis, 10000)
+
+	defer func(workers int) {
+		maxVerifyWorkers = workers
+	}(maxVerifyWorkers)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			maxVerifyWorkers = workers
+
+			for i := 0; i < b.N; i++ {
+				err := c.Verify(genesis, genesis.GetHash(), signer.GetVerifierFactory())
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestChain_Verify_Churn(t *testing.T) {
+	signers := []crypto.Signer{bls.NewSigner(), bls.NewSigner(), bls.NewSigner()}
+
+	ro := authority.New([]mino.Address{fake.NewAddress(0)},
+		[]crypto.PublicKey{signers[0].GetPublicKey()})
+
+	genesis, err := NewGenesis(ro)
+	require.NoError(t, err)
+
+	// The first member is replaced by the second one, which is replaced by
+	// the third one two blocks later.
+	cs1 := authority.NewChangeSet()
+	cs1.Remove(0)
+	cs1.Add(fake.NewAddress(1), signers[1].GetPublicKey())
+
+	cs3 := authority.NewChangeSet()
+	cs3.Remove(0)
+	cs3.Add(fake.NewAddress(2), signers[2].GetPublicKey())
+
+	roster1 := ro.Apply(cs1)
+	roster3 := roster1.Apply(cs3)
+
+	link1 := makeRosterLink(t, signers[0], genesis.GetHash(), digest(1), cs1, roster1)
+	link2 := makeRosterLink(t, signers[1], digest(1), digest(2), authority.NewChangeSet(), nil)
+	link3 := makeRosterLink(t, signers[1], digest(2), digest(3), cs3, roster3)
+	link4 := makeRosterLink(t, signers[2], digest(3), digest(4), authority.NewChangeSet(), nil)
+
+	fac := bls.NewSigner().GetVerifierFactory()
+
+	c := NewChain(blockLink{forwardLink: link4}, []Link{link1, link2, link3})
+
+	err = c.Verify(genesis, genesis.GetHash(), fac)
+	require.NoError(t, err)
+
+	// A node catching up from the second block still switches to the roster
+	// of the skipped link.
+	err = c.Verify(genesis, digest(2), fac)
+	require.NoError(t, err)
+
+	// The change set is not signed, but the roster it leads to is, so that a
+	// forged member is detected.
+	forged := authority.NewChangeSet()
+	forged.Remove(0)
+	forged.Add(fake.NewAddress(1), signers[2].GetPublicKey())
+
+	bad := link1
+	bad.changeset = forged
+
+	c = NewChain(blockLink{forwardLink: link4}, []Link{bad, link2, link3})
+
+	err = c.Verify(genesis, genesis.GetHash(), fac)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid roster after '01000000': mismatch digest ", err.Error())
+
+	// A link changing the roster must embed the digest of the new one.
+	bad = makeRosterLink(t, signers[0], genesis.GetHash(), digest(1), cs1, nil)
+
+	c = NewChain(blockLink{forwardLink: link4}, []Link{bad, link2, link3})
+
+	err = c.Verify(genesis, genesis.GetHash(), fac)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid roster after '01000000': mismatch digest '00000000' != ", err.Error())
+
+	bad = makeRosterLink(t, signers[1], digest(1), digest(2), authority.NewChangeSet(), roster1)
+
+	c = NewChain(blockLink{forwardLink: link4}, []Link{link1, bad, link3})
+
+	err = c.Verify(genesis, genesis.GetHash(), fac)
+	require.EqualError(t, err, "invalid roster after '02000000': unexpected roster digest")
+
+	// Without the roster change, the third block is not signed by the roster.
+	c = NewChain(blockLink{forwardLink: link2}, []Link{
+		makeRosterLink(t, signers[0], genesis.GetHash(), digest(1), authority.NewChangeSet(), nil),
+	})
+
+	err = c.Verify(genesis, genesis.GetHash(), fac)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid prepare signature: ", err.Error())
+}
+
 func TestChain_Verify_Skip(t *testing.T) {
 	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
 
@@ -340,6 +524,70 @@ func makeLink(t *testing.T, from, to Digest) BlockLink {
 	return blockLink{forwardLink: link.(forwardLink)}
 }
 
+// makeRosterLink returns a link signed by the signer, that embeds the digest
+// of the roster if it is not nil.
+func makeRosterLink(t *testing.T, signer crypto.Signer, from, to Digest,
+	cs authority.ChangeSet, roster authority.Authority) forwardLink {
+
+	opts := []LinkOption{WithChangeSet(cs)}
+
+	if roster != nil {
+		rosterDigest, err := RosterDigestOf(roster)
+		require.NoError(t, err)
+
+		opts = append(opts, WithRosterDigest(rosterDigest))
+	}
+
+	link, err := NewForwardLink(from, to, opts...)
+	require.NoError(t, err)
+
+	fl := link.(forwardLink)
+
+	fl.prepareSig, err = signer.Sign(fl.GetHash().Bytes())
+	require.NoError(t, err)
+
+	msg, err := fl.prepareSig.MarshalBinary()
+	require.NoError(t, err)
+
+	fl.commitSig, err = signer.Sign(msg)
+	require.NoError(t, err)
+
+	return fl
+}
+
+func makeSignedChain(t require.TestingT, signer crypto.Signer, genesis Genesis, n int) Chain {
+	prevs := make([]Link, n-1)
+	from := genesis.GetHash()
+
+	for i := 0; i < n; i++ {
+		var to Digest
+		binary.LittleEndian.PutUint64(to[:], uint64(i+1))
+
+		link, err := NewForwardLink(from, to)
+		require.NoError(t, err)
+
+		fl := link.(forwardLink)
+
+		fl.prepareSig, err = signer.Sign(fl.GetHash().Bytes())
+		require.NoError(t, err)
+
+		msg, err := fl.prepareSig.MarshalBinary()
+		require.NoError(t, err)
+
+		fl.commitSig, err = signer.Sign(msg)
+		require.NoError(t, err)
+
+		if i == n-1 {
+			return NewChain(blockLink{forwardLink: fl}, prevs)
+		}
+
+		prevs[i] = fl
+		from = to
+	}
+
+	return nil
+}
+
 func digest(b byte) Digest {
 	var d Digest
 	d[0] = b
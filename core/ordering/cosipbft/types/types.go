@@ -37,6 +37,10 @@ type Link interface {
 
 	// GetChangeSet returns the roster change set for this link.
 	GetChangeSet() authority.ChangeSet
+
+	// GetRosterDigest returns the digest of the roster that takes effect after
+	// the link when it changes the roster, otherwise an empty digest.
+	GetRosterDigest() Digest
 }
 
 // BlockLink is an extension of the Link interface to include the block the link
@@ -0,0 +1,204 @@
+// Package blob implements a content-addressed store for the payloads that are
+// too big to be stored on chain.
+//
+// A payload above the threshold is stored in the blob store of the node and
+// only its digest is stored on chain, alongside the key that encrypts it when
+// the payload is sealed in an IBE envelope. The digest commits to the payload,
+// so that a node that misses a blob can fetch it from any peer and verify it.
+//
+// A transaction references a blob with the hexadecimal digest in its DigestArg
+// argument. The nodes that replicate the chain fetch the blobs of the accepted
+// transactions, so that they are available on every member.
+//
+// The blobs are not deleted with the blocks that reference them. The
+// application prunes the store with the digests it still needs.
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"go.dedis.ch/dela/core/store/kv"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DefaultThreshold is the size in bytes above which a payload should be
+	// stored off chain.
+	DefaultThreshold = 64 * 1024
+
+	// DigestArg is the argument of a transaction that references a blob with
+	// the hexadecimal encoding of its digest.
+	DigestArg = "blob:digest"
+)
+
+// ErrNotFound is the error returned when a blob is unknown.
+var ErrNotFound = errors.New("blob not found")
+
+var bucketName = []byte("blobs")
+
+// Digest is the identifier of a blob, which is the SHA-256 hash of its
+// content.
+type Digest [32]byte
+
+// NewDigest returns the digest of the content.
+func NewDigest(data []byte) Digest {
+	return sha256.Sum256(data)
+}
+
+// ParseDigest returns the digest of its hexadecimal encoding.
+func ParseDigest(str string) (Digest, error) {
+	var digest Digest
+
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		return digest, xerrors.Errorf("invalid hex: %v", err)
+	}
+
+	if len(data) != len(digest) {
+		return digest, xerrors.Errorf("invalid digest length %d", len(data))
+	}
+
+	copy(digest[:], data)
+
+	return digest, nil
+}
+
+// String implements fmt.Stringer. It returns a short hexadecimal
+// representation of the digest.
+func (d Digest) String() string {
+	return hex.EncodeToString(d[:4])
+}
+
+// Store is a content-addressed store of blobs persisted in a database.
+type Store struct {
+	db kv.DB
+}
+
+// NewStore creates a new store of blobs in the database.
+func NewStore(db kv.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Put stores the blob and returns its digest. Storing the same blob twice is a
+// no-op.
+func (s *Store) Put(data []byte) (Digest, error) {
+	digest := NewDigest(data)
+
+	err := s.db.Update(func(tx kv.WritableTx) error {
+		bucket, err := tx.GetBucketOrCreate(bucketName)
+		if err != nil {
+			return xerrors.Errorf("while getting bucket: %v", err)
+		}
+
+		err = bucket.Set(digest[:], data)
+		if err != nil {
+			return xerrors.Errorf("while writing: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return digest, xerrors.Errorf("while updating db: %v", err)
+	}
+
+	return digest, nil
+}
+
+// Get returns the blob of the digest, or ErrNotFound if it is not stored.
+func (s *Store) Get(digest Digest) ([]byte, error) {
+	var data []byte
+
+	err := s.db.View(func(tx kv.ReadableTx) error {
+		bucket := tx.GetBucket(bucketName)
+		if bucket == nil {
+			return nil
+		}
+
+		value := bucket.Get(digest[:])
+		if value != nil {
+			data = make([]byte, len(value))
+			copy(data, value)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("while reading db: %v", err)
+	}
+
+	if data == nil {
+		return nil, xerrors.Errorf("%v: %w", digest, ErrNotFound)
+	}
+
+	return data, nil
+}
+
+// Delete removes the blob of the digest, if it exists.
+func (s *Store) Delete(digest Digest) error {
+	err := s.db.Update(func(tx kv.WritableTx) error {
+		bucket := tx.GetBucket(bucketName)
+		if bucket == nil {
+			return nil
+		}
+
+		err := bucket.Delete(digest[:])
+		if err != nil {
+			return xerrors.Errorf("while deleting: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("while updating db: %v", err)
+	}
+
+	return nil
+}
+
+// Prune removes the blobs that are not kept and returns how many were removed.
+func (s *Store) Prune(keep func(Digest) bool) (int, error) {
+	removed := 0
+
+	err := s.db.Update(func(tx kv.WritableTx) error {
+		bucket := tx.GetBucket(bucketName)
+		if bucket == nil {
+			return nil
+		}
+
+		var keys [][]byte
+
+		err := bucket.ForEach(func(k, v []byte) error {
+			var digest Digest
+			copy(digest[:], k)
+
+			if !keep(digest) {
+				keys = append(keys, append([]byte{}, k...))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return xerrors.Errorf("while iterating: %v", err)
+		}
+
+		for _, key := range keys {
+			err = bucket.Delete(key)
+			if err != nil {
+				return xerrors.Errorf("while deleting: %v", err)
+			}
+
+			removed++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("while updating db: %v", err)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,156 @@
+package blob
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestDigest_String(t *testing.T) {
+	digest := Digest{0xaa, 0xbb, 0xcc, 0xdd, 0xee}
+
+	require.Equal(t, "aabbccdd", digest.String())
+}
+
+func TestParseDigest(t *testing.T) {
+	digest := NewDigest([]byte("payload"))
+
+	parsed, err := ParseDigest(hex.EncodeToString(digest[:]))
+	require.NoError(t, err)
+	require.Equal(t, digest, parsed)
+
+	_, err = ParseDigest("zz")
+	require.EqualError(t, err,
+		"invalid hex: encoding/hex: invalid byte: U+007A 'z'")
+
+	_, err = ParseDigest("aabb")
+	require.EqualError(t, err, "invalid digest length 2")
+}
+
+func TestStore_Put(t *testing.T) {
+	store := NewStore(newDB())
+
+	digest, err := store.Put([]byte("payload"))
+	require.NoError(t, err)
+	require.Equal(t, NewDigest([]byte("payload")), digest)
+
+	store = NewStore(fake.NewBadDB())
+
+	_, err = store.Put([]byte("payload"))
+	require.EqualError(t, err, fake.Err("while updating db: while getting bucket"))
+
+	db := fake.NewInMemoryDB()
+	db.SetBucket(bucketName, fake.NewBadWriteBucket())
+
+	_, err = NewStore(db).Put([]byte("payload"))
+	require.EqualError(t, err, fake.Err("while updating db: while writing"))
+}
+
+func TestStore_Get(t *testing.T) {
+	store := NewStore(newDB())
+
+	_, err := store.Get(NewDigest([]byte("payload")))
+	require.ErrorIs(t, err, ErrNotFound)
+
+	digest, err := store.Put([]byte("payload"))
+	require.NoError(t, err)
+
+	data, err := store.Get(digest)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), data)
+
+	_, err = store.Get(Digest{})
+	require.EqualError(t, err, "00000000: blob not found")
+
+	store = NewStore(fake.NewBadViewDB())
+
+	_, err = store.Get(digest)
+	require.EqualError(t, err, fake.Err("while reading db"))
+}
+
+func TestStore_Delete(t *testing.T) {
+	store := NewStore(fake.NewInMemoryDB())
+
+	err := store.Delete(Digest{})
+	require.NoError(t, err)
+
+	store = NewStore(newDB())
+
+	digest, err := store.Put([]byte("payload"))
+	require.NoError(t, err)
+
+	err = store.Delete(digest)
+	require.NoError(t, err)
+
+	_, err = store.Get(digest)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	store = NewStore(fake.NewBadUpdateDB())
+
+	err = store.Delete(digest)
+	require.EqualError(t, err, fake.Err("while updating db"))
+
+	db := fake.NewInMemoryDB()
+	db.SetBucket(bucketName, fake.NewBadDeleteBucket())
+
+	err = NewStore(db).Delete(digest)
+	require.EqualError(t, err, fake.Err("while updating db: while deleting"))
+}
+
+func TestStore_Prune(t *testing.T) {
+	store := NewStore(fake.NewInMemoryDB())
+
+	removed, err := store.Prune(nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, removed)
+
+	store = NewStore(newDB())
+
+	a, err := store.Put([]byte("A"))
+	require.NoError(t, err)
+
+	b, err := store.Put([]byte("B"))
+	require.NoError(t, err)
+
+	removed, err = store.Prune(func(digest Digest) bool { return digest == a })
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	_, err = store.Get(a)
+	require.NoError(t, err)
+
+	_, err = store.Get(b)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	store = NewStore(fake.NewBadUpdateDB())
+
+	_, err = store.Prune(nil)
+	require.EqualError(t, err, fake.Err("while updating db"))
+
+	db := fake.NewInMemoryDB()
+	db.SetBucket(bucketName, fake.NewBadForeachBucket())
+
+	_, err = NewStore(db).Prune(nil)
+	require.EqualError(t, err, fake.Err("while updating db: while iterating"))
+
+	bucket := fake.NewBadDeleteBucket()
+	bucket.Set([]byte("A"), []byte("A"))
+
+	db = fake.NewInMemoryDB()
+	db.SetBucket(bucketName, bucket)
+
+	_, err = NewStore(db).Prune(func(Digest) bool { return false })
+	require.EqualError(t, err, fake.Err("while updating db: while deleting"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func newDB() *fake.InMemoryDB {
+	db := fake.NewInMemoryDB()
+	db.SetBucket(bucketName, fake.NewBucket())
+
+	return db
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/store/blob"
+	"golang.org/x/xerrors"
+)
+
+// fetchTimeout is the maximum time to fetch a missing blob from the roster.
+const fetchTimeout = 20 * time.Second
+
+// putAction is an action to store a file in the blob store.
+//
+// - implements node.ActionTemplate
+type putAction struct{}
+
+// Execute implements node.ActionTemplate. It stores the file and prints the
+// hex-encoded digest of the blob.
+func (putAction) Execute(ctx node.Context) error {
+	var store *blob.Store
+	err := ctx.Injector.Resolve(&store)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	data, err := os.ReadFile(ctx.Flags.String("file"))
+	if err != nil {
+		return xerrors.Errorf("failed to read file: %v", err)
+	}
+
+	digest, err := store.Put(data)
+	if err != nil {
+		return xerrors.Errorf("failed to store: %v", err)
+	}
+
+	fmt.Fprintln(ctx.Out, hex.EncodeToString(digest[:]))
+
+	return nil
+}
+
+// getAction is an action to write a blob to a file.
+//
+// - implements node.ActionTemplate
+type getAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the blob from the store, or
+// fetches it from the roster of the chain, and writes it to the file.
+func (getAction) Execute(ctx node.Context) error {
+	var srvc *blob.Service
+	err := ctx.Injector.Resolve(&srvc)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var chain blob.Chain
+	err = ctx.Injector.Resolve(&chain)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	digest, err := blob.ParseDigest(ctx.Flags.String("digest"))
+	if err != nil {
+		return xerrors.Errorf("invalid digest: %v", err)
+	}
+
+	roster, err := chain.GetRoster()
+	if err != nil {
+		return xerrors.Errorf("failed to read roster: %v", err)
+	}
+
+	fetchCtx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	data, err := srvc.Fetch(fetchCtx, digest, roster)
+	if err != nil {
+		return xerrors.Errorf("failed to fetch: %v", err)
+	}
+
+	err = os.WriteFile(ctx.Flags.String("out"), data, 0600)
+	if err != nil {
+		return xerrors.Errorf("failed to write file: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "wrote blob %v to %s\n", digest, ctx.Flags.String("out"))
+
+	return nil
+}
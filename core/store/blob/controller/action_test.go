@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/store/blob"
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino/minoch"
+)
+
+func TestPutAction_Execute(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "payload")
+
+	require.NoError(t, os.WriteFile(path, []byte("payload"), 0600))
+
+	store := blob.NewStore(newDB(t))
+
+	out := new(bytes.Buffer)
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    node.FlagSet{"file": path},
+		Out:      out,
+	}
+
+	action := putAction{}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for '*blob.Store'")
+
+	ctx.Injector.Inject(store)
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+
+	digest := blob.NewDigest([]byte("payload"))
+	require.Equal(t, hex.EncodeToString(digest[:])+"\n", out.String())
+
+	data, err := store.Get(digest)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), data)
+
+	ctx.Flags = node.FlagSet{"file": filepath.Join(dir, "unknown")}
+
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read file: ", err.Error())
+
+	ctx.Injector.Inject(blob.NewStore(fake.NewBadDB()))
+	ctx.Flags = node.FlagSet{"file": path}
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to store: while updating db: "+
+		"while getting bucket"))
+}
+
+func TestGetAction_Execute(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "payload")
+
+	store := blob.NewStore(newDB(t))
+
+	digest, err := store.Put([]byte("payload"))
+	require.NoError(t, err)
+
+	m := minoch.MustCreate(minoch.NewManager(), "A")
+
+	out := new(bytes.Buffer)
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags: node.FlagSet{
+			"digest": hex.EncodeToString(digest[:]),
+			"out":    path,
+		},
+		Out: out,
+	}
+
+	action := getAction{}
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for '*blob.Service'")
+
+	ctx.Injector.Inject(blob.NewService(m, store))
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'blob.Chain'")
+
+	chain := newFakeChain()
+	ctx.Injector.Inject(chain)
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "wrote blob "+digest.String()+" to "+path+"\n", out.String())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), data)
+
+	ctx.Flags.(node.FlagSet)["out"] = dir
+
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to write file: ", err.Error())
+
+	ctx.Flags.(node.FlagSet)["digest"] = "aa"
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "invalid digest: invalid digest length 1")
+
+	ctx.Flags.(node.FlagSet)["digest"] = hex.EncodeToString(make([]byte, 32))
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to fetch: no peer has 00000000: blob not found")
+
+	chain.err = fake.GetError()
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to read roster"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func newDB(t *testing.T) kv.DB {
+	db, err := kv.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
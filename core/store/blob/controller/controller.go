@@ -0,0 +1,103 @@
+// Package controller implements a CLI controller for the blob store.
+package controller
+
+import (
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/store/blob"
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/mino"
+	"golang.org/x/xerrors"
+)
+
+// miniController is a CLI initializer to inject the blob store and its
+// service, which replicates the blobs referenced by the chain.
+//
+// - implements node.Initializer
+type miniController struct{}
+
+// NewController creates a new minimal controller for the blob store.
+func NewController() node.Initializer {
+	return miniController{}
+}
+
+// SetCommands implements node.Initializer. It sets the commands to store and
+// fetch the blobs.
+func (miniController) SetCommands(builder node.Builder) {
+	cmd := builder.SetCommand("blob")
+	cmd.SetDescription("Blob store administration")
+
+	sub := cmd.SetSubCommand("put")
+	sub.SetDescription("Store a file and print its digest, to reference it " +
+		"with the " + blob.DigestArg + " argument of a transaction")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "file",
+			Required: true,
+			Usage:    "path of the file to store",
+		},
+	)
+	sub.SetAction(builder.MakeAction(putAction{}))
+
+	sub = cmd.SetSubCommand("get")
+	sub.SetDescription("Write a blob to a file, fetched from the roster " +
+		"if it is missing")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "digest",
+			Required: true,
+			Usage:    "hex-encoded digest of the blob",
+		},
+		cli.StringFlag{
+			Name:     "out",
+			Required: true,
+			Usage:    "path of the file to write",
+		},
+	)
+	sub.SetAction(builder.MakeAction(getAction{}))
+}
+
+// OnStart implements node.Initializer. It creates the blob store in the
+// database of the node and starts to replicate the blobs of the chain.
+func (miniController) OnStart(flags cli.Flags, inj node.Injector) error {
+	var m mino.Mino
+	err := inj.Resolve(&m)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var db kv.DB
+	err = inj.Resolve(&db)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var chain blob.Chain
+	err = inj.Resolve(&chain)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	store := blob.NewStore(db)
+
+	srvc := blob.NewService(m, store)
+	srvc.Replicate(chain)
+
+	inj.Inject(store)
+	inj.Inject(srvc)
+
+	return nil
+}
+
+// OnStop implements node.Initializer. It stops the replication.
+func (miniController) OnStop(inj node.Injector) error {
+	var srvc *blob.Service
+	err := inj.Resolve(&srvc)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	srvc.Close()
+
+	return nil
+}
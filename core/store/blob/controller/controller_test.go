@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/store/blob"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestMiniController_SetCommands(t *testing.T) {
+	ctrl := NewController()
+
+	call := &fake.Call{}
+	ctrl.SetCommands(fakeBuilder{call: call})
+
+	require.Equal(t, 12, call.Len())
+	require.Equal(t, "blob", call.Get(0, 0))
+	require.Equal(t, "put", call.Get(2, 0))
+	require.Len(t, call.Get(4, 0), 1)
+	require.IsType(t, putAction{}, call.Get(5, 0))
+	require.Equal(t, "get", call.Get(7, 0))
+	require.Len(t, call.Get(9, 0), 2)
+	require.IsType(t, getAction{}, call.Get(10, 0))
+}
+
+func TestMiniController_OnStart(t *testing.T) {
+	ctrl := NewController()
+
+	inj := node.NewInjector()
+
+	err := ctrl.OnStart(node.FlagSet{}, inj)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'mino.Mino'")
+
+	inj.Inject(fake.Mino{})
+
+	err = ctrl.OnStart(node.FlagSet{}, inj)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'kv.DB'")
+
+	inj.Inject(fake.NewInMemoryDB())
+
+	err = ctrl.OnStart(node.FlagSet{}, inj)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'blob.Chain'")
+
+	inj.Inject(newFakeChain())
+
+	err = ctrl.OnStart(node.FlagSet{}, inj)
+	require.NoError(t, err)
+
+	var store *blob.Store
+	require.NoError(t, inj.Resolve(&store))
+
+	err = ctrl.OnStop(inj)
+	require.NoError(t, err)
+}
+
+func TestMiniController_OnStop(t *testing.T) {
+	err := NewController().OnStop(node.NewInjector())
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for '*blob.Service'")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type fakeChain struct {
+	roster authority.Authority
+	err    error
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{roster: authority.New(nil, nil)}
+}
+
+func (c *fakeChain) Watch(ctx context.Context) <-chan ordering.Event {
+	ch := make(chan ordering.Event)
+
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (c *fakeChain) GetRoster() (authority.Authority, error) {
+	return c.roster, c.err
+}
+
+type fakeCommandBuilder struct {
+	call *fake.Call
+}
+
+func (b fakeCommandBuilder) SetSubCommand(name string) cli.CommandBuilder {
+	b.call.Add(name)
+	return b
+}
+
+func (b fakeCommandBuilder) SetDescription(value string) {
+	b.call.Add(value)
+}
+
+func (b fakeCommandBuilder) SetFlags(flags ...cli.Flag) {
+	b.call.Add(flags)
+}
+
+func (b fakeCommandBuilder) SetAction(a cli.Action) {
+	b.call.Add(a)
+}
+
+type fakeBuilder struct {
+	call *fake.Call
+}
+
+func (b fakeBuilder) SetCommand(name string) cli.CommandBuilder {
+	b.call.Add(name)
+	return fakeCommandBuilder(b)
+}
+
+func (b fakeBuilder) SetStartFlags(flags ...cli.Flag) {
+	b.call.Add(flags)
+}
+
+func (b fakeBuilder) MakeAction(tmpl node.ActionTemplate) cli.Action {
+	b.call.Add(tmpl)
+	return nil
+}
@@ -0,0 +1,74 @@
+package json
+
+import (
+	"go.dedis.ch/dela/core/store/blob/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+func init() {
+	types.RegisterMessageFormat(serde.FormatJSON, msgFormat{})
+}
+
+// FetchRequestJSON is the JSON representation of a fetch request.
+type FetchRequestJSON struct {
+	Digest []byte
+}
+
+// FetchReplyJSON is the JSON representation of a fetch reply.
+type FetchReplyJSON struct {
+	Data []byte
+}
+
+// MessageJSON is the JSON representation of a blob message.
+type MessageJSON struct {
+	Request *FetchRequestJSON `json:",omitempty"`
+	Reply   *FetchReplyJSON   `json:",omitempty"`
+}
+
+// MsgFormat is the format engine to encode and decode blob messages.
+//
+// - implements serde.FormatEngine
+type msgFormat struct{}
+
+// Encode implements serde.FormatEngine. It returns the JSON data of the message
+// if appropriate, otherwise an error.
+func (msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	var m MessageJSON
+
+	switch in := msg.(type) {
+	case types.FetchRequest:
+		m.Request = &FetchRequestJSON{Digest: in.GetDigest()}
+	case types.FetchReply:
+		m.Reply = &FetchReplyJSON{Data: in.GetData()}
+	default:
+		return nil, xerrors.Errorf("unsupported message '%T'", msg)
+	}
+
+	data, err := ctx.Marshal(m)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal failed: %v", err)
+	}
+
+	return data, nil
+}
+
+// Decode implements serde.FormatEngine. It returns the message associated to
+// the data if appropriate, otherwise an error.
+func (msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	m := MessageJSON{}
+	err := ctx.Unmarshal(data, &m)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal failed: %v", err)
+	}
+
+	if m.Request != nil {
+		return types.NewFetchRequest(m.Request.Digest), nil
+	}
+
+	if m.Reply != nil {
+		return types.NewFetchReply(m.Reply.Data), nil
+	}
+
+	return nil, xerrors.New("message is empty")
+}
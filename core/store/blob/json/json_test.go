@@ -0,0 +1,49 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/store/blob/types"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestMsgFormat_Encode(t *testing.T) {
+	format := msgFormat{}
+
+	ctx := fake.NewContext()
+
+	data, err := format.Encode(ctx, types.NewFetchRequest([]byte{1, 2}))
+	require.NoError(t, err)
+	require.Equal(t, `{"Request":{"Digest":"AQI="}}`, string(data))
+
+	data, err = format.Encode(ctx, types.NewFetchReply([]byte{3}))
+	require.NoError(t, err)
+	require.Equal(t, `{"Reply":{"Data":"Aw=="}}`, string(data))
+
+	_, err = format.Encode(ctx, fake.Message{})
+	require.EqualError(t, err, "unsupported message 'fake.Message'")
+
+	_, err = format.Encode(fake.NewBadContext(), types.NewFetchReply(nil))
+	require.EqualError(t, err, fake.Err("marshal failed"))
+}
+
+func TestMsgFormat_Decode(t *testing.T) {
+	format := msgFormat{}
+
+	ctx := fake.NewContext()
+
+	msg, err := format.Decode(ctx, []byte(`{"Request":{"Digest":"AQI="}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewFetchRequest([]byte{1, 2}), msg)
+
+	msg, err = format.Decode(ctx, []byte(`{"Reply":{"Data":"Aw=="}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewFetchReply([]byte{3}), msg)
+
+	_, err = format.Decode(ctx, []byte(`{}`))
+	require.EqualError(t, err, "message is empty")
+
+	_, err = format.Decode(fake.NewBadContext(), []byte(`{}`))
+	require.EqualError(t, err, fake.Err("unmarshal failed"))
+}
@@ -0,0 +1,191 @@
+package blob
+
+import (
+	"context"
+
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/store/blob/types"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+const rpcName = "blob"
+
+// Chain is the interface of the ordering service whose blocks reference the
+// blobs to replicate.
+type Chain interface {
+	Watch(ctx context.Context) <-chan ordering.Event
+
+	GetRoster() (authority.Authority, error)
+}
+
+// Service serves the blobs of the store to the peers, and fetches from them
+// the blobs that are missing.
+type Service struct {
+	store *Store
+	rpc   mino.RPC
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewService creates a new service for the store.
+func NewService(m mino.Mino, store *Store) *Service {
+	h := handler{store: store}
+
+	return &Service{
+		store: store,
+		rpc:   mino.MustCreateRPC(m, rpcName, h, types.NewMessageFactory()),
+	}
+}
+
+// Fetch returns the blob of the digest. If the store does not have it, it asks
+// the players and stores the first reply that matches the digest.
+func (s *Service) Fetch(ctx context.Context, digest Digest, players mino.Players) ([]byte, error) {
+	data, err := s.store.Get(digest)
+	if err == nil {
+		return data, nil
+	}
+
+	if !xerrors.Is(err, ErrNotFound) {
+		return nil, xerrors.Errorf("failed to read store: %v", err)
+	}
+
+	resps, err := s.rpc.Call(ctx, types.NewFetchRequest(digest[:]), players)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to call: %v", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, xerrors.Errorf("context done: %v", ctx.Err())
+		case resp, more := <-resps:
+			if !more {
+				return nil, xerrors.Errorf("no peer has %v: %w", digest, ErrNotFound)
+			}
+
+			msg, err := resp.GetMessageOrError()
+			if err != nil {
+				dela.Logger.Debug().Err(err).Stringer("from", resp.GetFrom()).
+					Msg("failed to fetch blob")
+				continue
+			}
+
+			reply, ok := msg.(types.FetchReply)
+			if !ok {
+				continue
+			}
+
+			data := reply.GetData()
+
+			if NewDigest(data) != digest {
+				dela.Logger.Warn().Stringer("from", resp.GetFrom()).
+					Msg("blob does not match the digest")
+				continue
+			}
+
+			_, err = s.store.Put(data)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to store: %v", err)
+			}
+
+			return data, nil
+		}
+	}
+}
+
+// handler processes the requests of the peers for the blobs of the store.
+//
+// - implements mino.Handler
+type handler struct {
+	mino.UnsupportedHandler
+
+	store *Store
+}
+
+// Process implements mino.Handler. It returns the blob of the requested digest.
+func (h handler) Process(req mino.Request) (serde.Message, error) {
+	msg, ok := req.Message.(types.FetchRequest)
+	if !ok {
+		return nil, xerrors.Errorf("unsupported message '%T'", req.Message)
+	}
+
+	var digest Digest
+
+	if len(msg.GetDigest()) != len(digest) {
+		return nil, xerrors.Errorf("invalid digest length %d", len(msg.GetDigest()))
+	}
+
+	copy(digest[:], msg.GetDigest())
+
+	data, err := h.store.Get(digest)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read store: %v", err)
+	}
+
+	return types.NewFetchReply(data), nil
+}
+
+// Replicate starts to fetch the blobs referenced by the transactions accepted
+// in the blocks of the chain, from the members of its roster, until the
+// service is closed.
+func (s *Service) Replicate(chain Chain) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	events := chain.Watch(ctx)
+
+	go func() {
+		defer close(s.done)
+
+		for evt := range events {
+			s.replicate(ctx, chain, evt)
+		}
+	}()
+}
+
+// Close stops the replication, if it is started.
+func (s *Service) Close() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	<-s.done
+}
+
+func (s *Service) replicate(ctx context.Context, chain Chain, evt ordering.Event) {
+	for _, res := range evt.Transactions {
+		accepted, _ := res.GetStatus()
+		value := res.GetTransaction().GetArg(DigestArg)
+
+		if !accepted || value == nil {
+			continue
+		}
+
+		digest, err := ParseDigest(string(value))
+		if err != nil {
+			dela.Logger.Warn().Err(err).Uint64("block", evt.Index).
+				Msg("invalid blob digest")
+			continue
+		}
+
+		roster, err := chain.GetRoster()
+		if err != nil {
+			dela.Logger.Warn().Err(err).Msg("failed to read roster")
+			return
+		}
+
+		_, err = s.Fetch(ctx, digest, roster)
+		if err != nil {
+			dela.Logger.Warn().Err(err).Stringer("blob", digest).
+				Msg("failed to replicate blob")
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package blob
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/store/blob/types"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/minoch"
+)
+
+func TestService_Scenario_Fetch(t *testing.T) {
+	manager := minoch.NewManager()
+
+	m1 := minoch.MustCreate(manager, "A")
+	m2 := minoch.MustCreate(manager, "B")
+
+	store1 := NewStore(newDB())
+	store2 := NewStore(newDB())
+
+	NewService(m1, store1)
+	srvc := NewService(m2, store2)
+
+	digest, err := store1.Put([]byte("payload"))
+	require.NoError(t, err)
+
+	players := mino.NewAddresses(m1.GetAddress())
+
+	data, err := srvc.Fetch(context.Background(), digest, players)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), data)
+
+	// The blob is now in the local store.
+	data, err = store2.Get(digest)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), data)
+
+	_, err = srvc.Fetch(context.Background(), Digest{}, players)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_Scenario_Replicate(t *testing.T) {
+	manager := minoch.NewManager()
+
+	m1 := minoch.MustCreate(manager, "A")
+	m2 := minoch.MustCreate(manager, "B")
+
+	store1 := NewStore(newDB())
+	store2 := NewStore(newDB())
+
+	NewService(m1, store1)
+	srvc := NewService(m2, store2)
+
+	digest, err := store1.Put([]byte("payload"))
+	require.NoError(t, err)
+
+	other, err := store1.Put([]byte("refused"))
+	require.NoError(t, err)
+
+	chain := fakeChain{
+		events: make(chan ordering.Event, 1),
+		roster: authority.New([]mino.Address{m1.GetAddress()}, nil),
+	}
+
+	srvc.Replicate(chain)
+
+	// Only the blobs of the accepted transactions are replicated.
+	chain.events <- ordering.Event{
+		Index: 1,
+		Transactions: []validation.TransactionResult{
+			simple.NewTransactionResult(makeTx(t, "zz"), true, ""),
+			simple.NewTransactionResult(makeTx(t, hex.EncodeToString(other[:])), false, "refused"),
+			simple.NewTransactionResult(makeTx(t, hex.EncodeToString(digest[:])), true, ""),
+		},
+	}
+
+	require.Eventually(t, func() bool {
+		_, err := store2.Get(digest)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	close(chain.events)
+	srvc.Close()
+
+	_, err = store2.Get(other)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Closing a service that does not replicate is a no-op.
+	NewService(minoch.MustCreate(manager, "C"), NewStore(newDB())).Close()
+}
+
+func TestService_FailReadRoster_Replicate(t *testing.T) {
+	store := NewStore(newDB())
+
+	srvc := &Service{store: store, rpc: fake.NewBadRPC()}
+
+	digest := NewDigest([]byte("payload"))
+
+	chain := fakeChain{
+		events: make(chan ordering.Event, 1),
+		err:    fake.GetError(),
+	}
+
+	srvc.Replicate(chain)
+
+	chain.events <- ordering.Event{
+		Transactions: []validation.TransactionResult{
+			simple.NewTransactionResult(makeTx(t, hex.EncodeToString(digest[:])), true, ""),
+		},
+	}
+
+	close(chain.events)
+	srvc.Close()
+
+	_, err := store.Get(digest)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_Fetch(t *testing.T) {
+	digest := NewDigest([]byte("payload"))
+
+	rpc := fake.NewRPC()
+	rpc.SendResponseWithError(fake.NewAddress(0), fake.GetError())
+	rpc.SendResponse(fake.NewAddress(1), fake.Message{})
+	rpc.SendResponse(fake.NewAddress(2), types.NewFetchReply([]byte("forged")))
+	rpc.SendResponse(fake.NewAddress(3), types.NewFetchReply([]byte("payload")))
+	rpc.Done()
+
+	srvc := &Service{
+		store: NewStore(newDB()),
+		rpc:   rpc,
+	}
+
+	data, err := srvc.Fetch(context.Background(), digest, mino.NewAddresses())
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), data)
+}
+
+func TestService_FailReadStore_Fetch(t *testing.T) {
+	srvc := &Service{
+		store: NewStore(fake.NewBadViewDB()),
+	}
+
+	_, err := srvc.Fetch(context.Background(), Digest{}, mino.NewAddresses())
+	require.EqualError(t, err, fake.Err("failed to read store: while reading db"))
+}
+
+func TestService_FailCall_Fetch(t *testing.T) {
+	srvc := &Service{
+		store: NewStore(newDB()),
+		rpc:   fake.NewBadRPC(),
+	}
+
+	_, err := srvc.Fetch(context.Background(), Digest{}, mino.NewAddresses())
+	require.EqualError(t, err, fake.Err("failed to call"))
+}
+
+func TestService_FailStore_Fetch(t *testing.T) {
+	rpc := fake.NewRPC()
+	rpc.SendResponse(fake.NewAddress(0), types.NewFetchReply([]byte("payload")))
+
+	srvc := &Service{
+		store: NewStore(fake.NewBadUpdateDB()),
+		rpc:   rpc,
+	}
+
+	_, err := srvc.Fetch(context.Background(), NewDigest([]byte("payload")),
+		mino.NewAddresses())
+	require.EqualError(t, err, fake.Err("failed to store: while updating db"))
+}
+
+func TestService_ContextDone_Fetch(t *testing.T) {
+	srvc := &Service{
+		store: NewStore(newDB()),
+		rpc:   fake.NewRPC(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := srvc.Fetch(ctx, Digest{}, mino.NewAddresses())
+	require.EqualError(t, err, "context done: context canceled")
+}
+
+func TestHandler_Process(t *testing.T) {
+	store := NewStore(newDB())
+
+	digest, err := store.Put([]byte("payload"))
+	require.NoError(t, err)
+
+	h := handler{store: store}
+
+	resp, err := h.Process(mino.Request{Message: types.NewFetchRequest(digest[:])})
+	require.NoError(t, err)
+	require.Equal(t, types.NewFetchReply([]byte("payload")), resp)
+
+	_, err = h.Process(mino.Request{Message: fake.Message{}})
+	require.EqualError(t, err, "unsupported message 'fake.Message'")
+
+	_, err = h.Process(mino.Request{Message: types.NewFetchRequest([]byte{1})})
+	require.EqualError(t, err, "invalid digest length 1")
+
+	_, err = h.Process(mino.Request{Message: types.NewFetchRequest(make([]byte, 32))})
+	require.EqualError(t, err, "failed to read store: 00000000: blob not found")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeTx(t *testing.T, digest string) txn.Transaction {
+	tx, err := signed.NewTransaction(0, fake.PublicKey{},
+		signed.WithArg(DigestArg, []byte(digest)))
+	require.NoError(t, err)
+
+	return tx
+}
+
+type fakeChain struct {
+	events chan ordering.Event
+	roster authority.Authority
+	err    error
+}
+
+func (c fakeChain) Watch(context.Context) <-chan ordering.Event {
+	return c.events
+}
+
+func (c fakeChain) GetRoster() (authority.Authority, error) {
+	return c.roster, c.err
+}
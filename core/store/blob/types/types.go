@@ -0,0 +1,106 @@
+// Package types implements the messages of the protocol to fetch a blob from
+// a peer.
+//
+// The messages have been implemented in this isolated package so that it does
+// not create cycle imports when importing the serde formats.
+package types
+
+import (
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/registry"
+	"golang.org/x/xerrors"
+)
+
+var msgFormats = registry.NewSimpleRegistry()
+
+// RegisterMessageFormat registers the engine for the provided format.
+func RegisterMessageFormat(c serde.Format, f serde.FormatEngine) {
+	msgFormats.Register(c, f)
+}
+
+// FetchRequest is the message sent to ask a peer for a blob.
+//
+// - implements serde.Message
+type FetchRequest struct {
+	digest []byte
+}
+
+// NewFetchRequest creates a new request for the blob of the digest.
+func NewFetchRequest(digest []byte) FetchRequest {
+	return FetchRequest{
+		digest: digest,
+	}
+}
+
+// GetDigest returns the digest of the blob.
+func (req FetchRequest) GetDigest() []byte {
+	return append([]byte{}, req.digest...)
+}
+
+// Serialize implements serde.Message. It returns the serialized data of the
+// request.
+func (req FetchRequest) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, req)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return data, nil
+}
+
+// FetchReply is the message returned with the blob.
+//
+// - implements serde.Message
+type FetchReply struct {
+	data []byte
+}
+
+// NewFetchReply creates a new reply with the blob.
+func NewFetchReply(data []byte) FetchReply {
+	return FetchReply{
+		data: data,
+	}
+}
+
+// GetData returns the blob.
+func (reply FetchReply) GetData() []byte {
+	return append([]byte{}, reply.data...)
+}
+
+// Serialize implements serde.Message. It returns the serialized data of the
+// reply.
+func (reply FetchReply) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, reply)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return data, nil
+}
+
+// MessageFactory is the factory to deserialize the messages of the protocol.
+//
+// - implements serde.Factory
+type MessageFactory struct{}
+
+// NewMessageFactory creates a new message factory.
+func NewMessageFactory() MessageFactory {
+	return MessageFactory{}
+}
+
+// Deserialize implements serde.Factory. It returns the message of the data if
+// appropriate, otherwise an error.
+func (MessageFactory) Deserialize(ctx serde.Context, data []byte) (serde.Message, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	msg, err := format.Decode(ctx, data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func init() {
+	RegisterMessageFormat(fake.GoodFormat, fake.Format{Msg: FetchRequest{}})
+	RegisterMessageFormat(fake.BadFormat, fake.NewBadFormat())
+}
+
+func TestFetchRequest_GetDigest(t *testing.T) {
+	req := NewFetchRequest([]byte{1, 2})
+
+	require.Equal(t, []byte{1, 2}, req.GetDigest())
+}
+
+func TestFetchRequest_Serialize(t *testing.T) {
+	req := NewFetchRequest([]byte{1})
+
+	data, err := req.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = req.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("failed to encode"))
+}
+
+func TestFetchReply_GetData(t *testing.T) {
+	reply := NewFetchReply([]byte{3})
+
+	require.Equal(t, []byte{3}, reply.GetData())
+}
+
+func TestFetchReply_Serialize(t *testing.T) {
+	reply := NewFetchReply([]byte{3})
+
+	data, err := reply.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = reply.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("failed to encode"))
+}
+
+func TestMessageFactory_Deserialize(t *testing.T) {
+	fac := NewMessageFactory()
+
+	msg, err := fac.Deserialize(fake.NewContext(), nil)
+	require.NoError(t, err)
+	require.Equal(t, FetchRequest{}, msg)
+
+	_, err = fac.Deserialize(fake.NewBadContext(), nil)
+	require.EqualError(t, err, fake.Err("failed to decode"))
+}
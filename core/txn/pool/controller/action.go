@@ -6,10 +6,12 @@
 package controller
 
 import (
+	"fmt"
 	"sync"
 
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/crypto/keystore"
 	"go.dedis.ch/dela/crypto/loader"
 
 	"go.dedis.ch/dela/cli/node"
@@ -80,6 +82,31 @@ func (a *addAction) Execute(ctx node.Context) error {
 	return nil
 }
 
+// limitAction describes an action to change the limit of the pool at runtime.
+//
+// - implements node.ActionTemplate
+type limitAction struct{}
+
+// Execute implements node.ActionTemplate
+func (limitAction) Execute(ctx node.Context) error {
+	size := ctx.Flags.Int("size")
+	if size <= 0 {
+		return xerrors.Errorf("invalid size %d", size)
+	}
+
+	var p pool.Pool
+	err := ctx.Injector.Resolve(&p)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	p.SetLimit(size)
+
+	fmt.Fprintf(ctx.Out, "pool limit set to %d transactions per identity\n", size)
+
+	return nil
+}
+
 // getArgs extracts and parses arguments from the context.
 func getArgs(ctx node.Context) ([]txn.Arg, error) {
 	inArgs := ctx.Flags.StringSlice("args")
@@ -98,7 +125,10 @@ func getArgs(ctx node.Context) ([]txn.Arg, error) {
 	return args, nil
 }
 
-// getSigner creates a signer from the signerFlag flag in context.
+// getSigner creates a signer from the signerFlag flag in context. A keystore
+// file is decrypted with the passphrase of the passphrase file, or of the
+// environment of the node as the action does not run in the terminal of the
+// user.
 func getSigner(ctx node.Context) (crypto.Signer, error) {
 	l := loader.NewFileLoader(ctx.Flags.Path(signerFlag))
 
@@ -107,6 +137,25 @@ func getSigner(ctx node.Context) (crypto.Signer, error) {
 		return nil, xerrors.Errorf("failed to load signer: %v", err)
 	}
 
+	if ctx.Flags.Bool(keystoreFlag) {
+		entry, err := keystore.ReadEntry(signerdata)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read keystore file: %v", err)
+		}
+
+		path := ctx.Flags.Path(passphraseFileFlag)
+
+		passphrase, err := keystore.ReadPassphrase(path, nil, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read passphrase: %v", err)
+		}
+
+		signerdata, err = keystore.Decrypt(entry, passphrase)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decrypt signer: %v", err)
+		}
+	}
+
 	signer, err := bls.NewSignerFromBytes(signerdata)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to unmarshal signer: %v", err)
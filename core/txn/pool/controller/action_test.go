@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"go.dedis.ch/dela/core/txn/signed"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/crypto/keystore"
 	"go.dedis.ch/dela/internal/testing/fake"
 )
 
@@ -84,6 +86,87 @@ func TestExecute(t *testing.T) {
 	require.EqualError(t, err, "injector: couldn't find dependency for 'pool.Pool'")
 }
 
+func TestLimitAction_Execute(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    make(node.FlagSet),
+		Out:      out,
+	}
+
+	action := limitAction{}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err, "invalid size 0")
+
+	ctx.Flags.(node.FlagSet)["size"] = 5
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'pool.Pool'")
+
+	ctx.Injector.Inject(mem.NewPool())
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "pool limit set to 5 transactions per identity\n", out.String())
+}
+
+func TestGetSigner_Keystore(t *testing.T) {
+	dir, err := os.MkdirTemp("", "dela-keystore-")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	signer := bls.NewSigner()
+
+	privkey, err := signer.MarshalBinary()
+	require.NoError(t, err)
+
+	ks := keystore.NewKeystore(dir, keystore.WithScrypt(1<<4, 8, 1))
+	pubkey, err := signer.GetPublicKey().MarshalBinary()
+	require.NoError(t, err)
+
+	require.NoError(t, ks.Store("alice", privkey, pubkey, "secret"))
+
+	passFile := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(passFile, []byte("secret\n"), 0600))
+
+	ctx := node.Context{
+		Flags: node.FlagSet{
+			signerFlag:         filepath.Join(dir, "alice.json"),
+			keystoreFlag:       true,
+			passphraseFileFlag: passFile,
+		},
+	}
+
+	res, err := getSigner(ctx)
+	require.NoError(t, err)
+	require.True(t, signer.GetPublicKey().Equal(res.GetPublicKey()))
+
+	t.Setenv(keystore.EnvPassphrase, "wrong")
+	ctx.Flags.(node.FlagSet)[passphraseFileFlag] = ""
+
+	_, err = getSigner(ctx)
+	require.EqualError(t, err,
+		"failed to decrypt signer: wrong passphrase or corrupted key")
+
+	t.Setenv(keystore.EnvPassphrase, "")
+
+	_, err = getSigner(ctx)
+	require.EqualError(t, err, "failed to read passphrase: "+
+		"no passphrase file and DELA_PASSPHRASE is not set")
+
+	keyFile := filepath.Join(dir, "key.buf")
+	require.NoError(t, os.WriteFile(keyFile, privkey, os.ModePerm))
+
+	ctx.Flags.(node.FlagSet)[signerFlag] = keyFile
+
+	_, err = getSigner(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read keystore file: failed to decode: ", err.Error())
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
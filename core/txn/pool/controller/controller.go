@@ -7,6 +7,7 @@ import (
 	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/core/access"
+	"go.dedis.ch/dela/crypto/keystore"
 )
 
 const (
@@ -15,6 +16,14 @@ const (
 
 	// nonceFlag is the flag name containing the nonce.
 	nonceFlag = "nonce"
+
+	// keystoreFlag is the flag name telling that the keyfile comes from a
+	// keystore.
+	keystoreFlag = "keystore"
+
+	// passphraseFileFlag is the flag name containing the path to the
+	// passphrase of a keystore file.
+	passphraseFileFlag = "passphraseFile"
 )
 
 type miniController struct {
@@ -47,10 +56,29 @@ func (miniController) SetCommands(builder node.Builder) {
 		Name:     signerFlag,
 		Usage:    "path to the private keyfile",
 		Required: true,
+	}, cli.BoolFlag{
+		Name:     keystoreFlag,
+		Usage:    "if set, the keyfile is an encrypted keystore file",
+		Required: false,
+	}, cli.StringFlag{
+		Name: passphraseFileFlag,
+		Usage: "path to a file with the passphrase of the keystore file. " +
+			"Without it, the passphrase is read from the " +
+			keystore.EnvPassphrase + " variable of the node",
+		Required: false,
 	})
 	sub.SetAction(builder.MakeAction(&addAction{
 		client: &client{},
 	}))
+
+	sub = cmd.SetSubCommand("limit")
+	sub.SetDescription("set the number of pending transactions per identity")
+	sub.SetFlags(cli.IntFlag{
+		Name:     "size",
+		Usage:    "number of pending transactions per identity",
+		Required: true,
+	})
+	sub.SetAction(builder.MakeAction(limitAction{}))
 }
 
 // OnStart implements node.Initializer
@@ -15,14 +15,16 @@ func TestMiniController_Build(t *testing.T) {
 	call := &fake.Call{}
 	ctrl.SetCommands(fakeBuilder{call: call})
 
-	require.Equal(t, 7, call.Len())
+	require.Equal(t, 12, call.Len())
 	require.Equal(t, "pool", call.Get(0, 0))
 	require.Equal(t, "interact with the pool", call.Get(1, 0))
 	require.Equal(t, "add", call.Get(2, 0))
 	require.Equal(t, "add a transaction to the pool", call.Get(3, 0))
-	require.Len(t, call.Get(4, 0), 3)
+	require.Len(t, call.Get(4, 0), 5)
 	require.IsType(t, &addAction{}, call.Get(5, 0))
 	require.Nil(t, call.Get(6, 0)) // our fake MakeAction() returns nil
+	require.Equal(t, "limit", call.Get(7, 0))
+	require.IsType(t, limitAction{}, call.Get(10, 0))
 }
 
 func TestMiniController_OnStart(t *testing.T) {
@@ -1,13 +1,17 @@
 package pool
 
 import (
+	"bytes"
 	"context"
 	"sync"
-	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/internal/clock"
 	"golang.org/x/xerrors"
 )
 
@@ -15,6 +19,20 @@ import (
 // transactions.
 const DefaultIdentitySize = 100
 
+// eventBufferSize is the size of the channel of an observer of the events.
+const eventBufferSize = 100
+
+// defines prometheus metrics
+var promInclusion = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Name:    "dela_pool_inclusion_seconds",
+	Help:    "time spent by a transaction in the pool until it is included",
+	Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60, 120},
+})
+
+func init() {
+	dela.PromCollectors = append(dela.PromCollectors, promInclusion)
+}
+
 // Gatherer is a common tool to the pool implementations that helps to implement
 // the gathering process.
 type Gatherer interface {
@@ -32,6 +50,9 @@ type Gatherer interface {
 	// array, or nil if the context ends.
 	Wait(ctx context.Context, cfg Config) []txn.Transaction
 
+	// SetLimit sets the number of pending transactions allowed per identity.
+	SetLimit(size int)
+
 	// Close closes current operations and cleans the resources.
 	Close()
 
@@ -40,6 +61,10 @@ type Gatherer interface {
 
 	// ResetStats resets the transaction statistics.
 	ResetStats()
+
+	// Watch returns a channel populated with the events of the gatherer until
+	// the context is done.
+	Watch(ctx context.Context) <-chan Event
 }
 
 type item struct {
@@ -58,6 +83,9 @@ type simpleGatherer struct {
 	limit      int
 	queue      []item
 	validators []Filter
+	clock      clock.Clock
+	watcher    core.Observable
+	arrivals   uint64
 
 	// A string key is generated for each unique identity, which will have its
 	// own list of transactions, so that a limited size can be enforced
@@ -68,8 +96,10 @@ type simpleGatherer struct {
 // NewSimpleGatherer creates a new gatherer.
 func NewSimpleGatherer() Gatherer {
 	return &simpleGatherer{
-		limit: DefaultIdentitySize,
-		txs:   make(map[string]transactions),
+		limit:   DefaultIdentitySize,
+		clock:   clock.NewReal(),
+		watcher: core.NewWatcher(),
+		txs:     make(map[string]transactions),
 	}
 }
 
@@ -86,11 +116,14 @@ func (g *simpleGatherer) AddFilter(filter Filter) {
 // Add implements pool.Gatherer. It adds the transaction to the set of available
 // transactions and notify the queue of the new length.
 func (g *simpleGatherer) Add(tx txn.Transaction) error {
+	g.Lock()
+	limit := g.limit
+	g.Unlock()
 
 	for _, val := range g.validators {
 		// Make sure the transaction is not already known, or that is not in a
 		// distant future to limit the pool storage size.
-		err := val.Accept(tx, validation.Leeway{MaxSequenceDifference: g.limit})
+		err := val.Accept(tx, validation.Leeway{MaxSequenceDifference: limit})
 		if err != nil {
 			return xerrors.Errorf("invalid transaction: %v", err)
 		}
@@ -103,11 +136,22 @@ func (g *simpleGatherer) Add(tx txn.Transaction) error {
 
 	g.Lock()
 
+	size := len(g.txs[key])
+
+	now := g.clock.Now()
+
 	g.txs[key] = g.txs[key].Add(transactionStats{
-		tx,
-		time.Now(),
+		Transaction:   tx,
+		insertionTime: now,
+		arrival:       g.arrivals,
+		arrivalTime:   now,
 	})
 
+	if len(g.txs[key]) > size {
+		g.arrivals++
+		g.watcher.Notify(Event{Type: TxAdded, Tx: tx})
+	}
+
 	g.notify(g.calculateLength())
 
 	g.Unlock()
@@ -125,8 +169,21 @@ func (g *simpleGatherer) Remove(tx txn.Transaction) error {
 
 	g.Lock()
 
+	for _, stats := range g.txs[key] {
+		if bytes.Equal(stats.GetID(), tx.GetID()) {
+			elapsed := g.clock.Now().Sub(stats.arrivalTime)
+			promInclusion.Observe(elapsed.Seconds())
+		}
+	}
+
+	size := len(g.txs[key])
+
 	g.txs[key] = g.txs[key].Remove(tx)
 
+	if len(g.txs[key]) < size {
+		g.watcher.Notify(Event{Type: TxIncluded, Tx: tx})
+	}
+
 	g.Unlock()
 
 	return nil
@@ -170,7 +227,7 @@ func (g *simpleGatherer) Stats() Stats {
 	txs := g.makeStatsArray()
 	stats := Stats{
 		TxCount:  len(txs),
-		OldestTx: time.Now(),
+		OldestTx: g.clock.Now(),
 	}
 
 	for _, tx := range txs {
@@ -182,22 +239,54 @@ func (g *simpleGatherer) Stats() Stats {
 	return stats
 }
 
+// SetLimit implements pool.Gatherer. It sets the number of pending
+// transactions allowed per identity for the next ones to be added. The
+// transactions already in the pool are kept.
+func (g *simpleGatherer) SetLimit(size int) {
+	g.Lock()
+	g.limit = size
+	g.Unlock()
+}
+
 // ResetStats implements pool.Gatherer. It resets the transactions statistics.
 func (g *simpleGatherer) ResetStats() {
 	g.Lock()
 	defer g.Unlock()
 
+	now := g.clock.Now()
+
 	txs := g.makeStatsArray()
 	for _, tx := range txs {
-		tx.ResetStats()
+		tx.ResetStats(now)
 	}
 }
 
+// Watch implements pool.Gatherer. It returns a channel populated with the
+// events of the gatherer until the context is done. The channel must be
+// listened continuously as events are dropped when it is full.
+func (g *simpleGatherer) Watch(ctx context.Context) <-chan Event {
+	obs := observer{ch: make(chan Event, eventBufferSize)}
+
+	g.watcher.Add(obs)
+
+	go func() {
+		<-ctx.Done()
+		g.watcher.Remove(obs)
+		close(obs.ch)
+	}()
+
+	return obs.ch
+}
+
 // Close implements pool.Gatherer. It closes the operations and cleans the
-// resources.
+// resources. The pending transactions are announced as evicted.
 func (g *simpleGatherer) Close() {
 	g.Lock()
 
+	for _, tx := range g.makeArray() {
+		g.watcher.Notify(Event{Type: TxEvicted, Tx: tx})
+	}
+
 	g.txs = make(map[string]transactions)
 
 	for _, item := range g.queue {
@@ -242,17 +331,56 @@ func (g *simpleGatherer) makeStatsArray() []transactionStats {
 	return txs
 }
 
+// makeArray returns the transactions in their order of arrival, except that
+// the transactions of an identity stay sorted by nonce.
 func (g *simpleGatherer) makeArray() []txn.Transaction {
-	stxs := g.makeStatsArray()
-	txs := make([]txn.Transaction, 0, len(stxs))
+	lists := make([]transactions, 0, len(g.txs))
+	for _, list := range g.txs {
+		if len(list) > 0 {
+			lists = append(lists, list)
+		}
+	}
+
+	txs := make([]txn.Transaction, 0, g.calculateLength())
 
-	for _, t := range stxs {
-		txs = append(txs, t.Transaction)
+	for len(lists) > 0 {
+		next := 0
+		for i, list := range lists {
+			if list[0].arrival < lists[next][0].arrival {
+				next = i
+			}
+		}
+
+		txs = append(txs, lists[next][0].Transaction)
+
+		lists[next] = lists[next][1:]
+		if len(lists[next]) == 0 {
+			lists = append(lists[:next], lists[next+1:]...)
+		}
 	}
 
 	return txs
 }
 
+// observer forwards the events of the gatherer to a channel.
+//
+// - implements core.Observer
+type observer struct {
+	ch chan Event
+}
+
+// NotifyCallback implements core.Observer. It pushes the event to the channel,
+// or drops it if the channel is full so that the gatherer is never blocked.
+func (obs observer) NotifyCallback(event interface{}) {
+	select {
+	case obs.ch <- event.(Event):
+	default:
+		dela.Logger.Warn().
+			Stringer("type", event.(Event).Type).
+			Msg("pool event dropped")
+	}
+}
+
 func makeKey(id access.Identity) (string, error) {
 	data, err := id.MarshalText()
 	if err != nil {
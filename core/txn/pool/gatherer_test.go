@@ -2,9 +2,12 @@ package pool
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
+	"time"
 
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/txn"
@@ -23,6 +26,25 @@ func TestSimpleGatherer_Len(t *testing.T) {
 	require.Equal(t, 3, gatherer.Stats().TxCount)
 }
 
+func TestSimpleGatherer_Stats(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := fake.NewClock(now)
+
+	gatherer := NewSimpleGatherer().(*simpleGatherer)
+	gatherer.clock = clock
+
+	require.Equal(t, now, gatherer.Stats().OldestTx)
+
+	require.NoError(t, gatherer.Add(newTx(0, "Alice")))
+	clock.Advance(time.Minute)
+	require.NoError(t, gatherer.Add(newTx(1, "Alice")))
+	clock.Advance(time.Minute)
+
+	stats := gatherer.Stats()
+	require.Equal(t, 2, stats.TxCount)
+	require.Equal(t, now, stats.OldestTx)
+}
+
 func TestSimpleGatherer_Add(t *testing.T) {
 	gatherer := NewSimpleGatherer().(*simpleGatherer)
 	gatherer.AddFilter(nil)
@@ -54,6 +76,20 @@ func TestSimpleGatherer_Add(t *testing.T) {
 	require.EqualError(t, err, fake.Err("identity key failed"))
 }
 
+func TestSimpleGatherer_SetLimit(t *testing.T) {
+	gatherer := NewSimpleGatherer().(*simpleGatherer)
+	gatherer.AddFilter(fakeFilter{})
+
+	gatherer.SetLimit(2)
+	require.Equal(t, 2, gatherer.limit)
+
+	err := gatherer.Add(newTx(1, "Alice"))
+	require.NoError(t, err)
+
+	err = gatherer.Add(newTx(2, "Alice"))
+	require.EqualError(t, err, fake.Err("invalid transaction"))
+}
+
 func TestSimpleGatherer_Remove(t *testing.T) {
 	gatherer := NewSimpleGatherer().(*simpleGatherer)
 	gatherer.txs["Alice"] = transactions{newTx(0, "Alice"), newTx(1, "Alice")}
@@ -74,6 +110,30 @@ func TestSimpleGatherer_Remove(t *testing.T) {
 	require.EqualError(t, err, fake.Err("identity key failed"))
 }
 
+func TestSimpleGatherer_InclusionMetric(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := fake.NewClock(now)
+
+	gatherer := NewSimpleGatherer().(*simpleGatherer)
+	gatherer.clock = clock
+
+	before := readHistogram(t)
+
+	require.NoError(t, gatherer.Add(newTx(0, "Alice")))
+	clock.Advance(time.Minute)
+
+	// The reset of the statistics must not shorten the measure.
+	gatherer.ResetStats()
+	clock.Advance(time.Minute)
+
+	require.NoError(t, gatherer.Remove(newTx(0, "Alice")))
+	require.NoError(t, gatherer.Remove(newTx(0, "Alice")))
+
+	after := readHistogram(t)
+	require.Equal(t, before.GetSampleCount()+1, after.GetSampleCount())
+	require.Equal(t, before.GetSampleSum()+120, after.GetSampleSum())
+}
+
 func TestSimpleGatherer_Wait(t *testing.T) {
 	gatherer := NewSimpleGatherer().(*simpleGatherer)
 
@@ -100,6 +160,28 @@ func TestSimpleGatherer_Wait(t *testing.T) {
 	require.Nil(t, txs)
 }
 
+func TestSimpleGatherer_Wait_ArrivalOrder(t *testing.T) {
+	gatherer := NewSimpleGatherer().(*simpleGatherer)
+
+	require.NoError(t, gatherer.Add(newTx(3, "Alice")))
+	require.NoError(t, gatherer.Add(newTx(1, "Bob")))
+	require.NoError(t, gatherer.Add(newTx(1, "Alice")))
+	require.NoError(t, gatherer.Add(newTx(2, "Bob")))
+
+	txs := gatherer.Wait(context.Background(), Config{Min: 4})
+	require.Len(t, txs, 4)
+
+	// Alice's first transaction waits for the one with the lower nonce, which
+	// arrived after Bob's.
+	expected := []string{"Bob:1", "Alice:1", "Alice:3", "Bob:2"}
+
+	for i, tx := range txs {
+		text, err := tx.GetIdentity().MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, expected[i], fmt.Sprintf("%s:%d", text, tx.GetNonce()))
+	}
+}
+
 func TestSimpleGatherer_Close(t *testing.T) {
 	gatherer := NewSimpleGatherer().(*simpleGatherer)
 
@@ -131,6 +213,48 @@ func TestSimpleGatherer_Close(t *testing.T) {
 	wg.Wait()
 }
 
+func TestSimpleGatherer_Watch(t *testing.T) {
+	gatherer := NewSimpleGatherer().(*simpleGatherer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events := gatherer.Watch(ctx)
+
+	require.NoError(t, gatherer.Add(newTx(0, "Alice")))
+	require.NoError(t, gatherer.Add(newTx(0, "Alice")))
+	require.NoError(t, gatherer.Add(newTx(1, "Alice")))
+	require.NoError(t, gatherer.Remove(newTx(0, "Alice")))
+	require.NoError(t, gatherer.Remove(newTx(0, "Alice")))
+
+	gatherer.Close()
+
+	expected := []EventType{TxAdded, TxAdded, TxIncluded, TxEvicted}
+	for i, typ := range expected {
+		evt := <-events
+		require.Equal(t, typ, evt.Type, "event %d", i)
+	}
+
+	require.Empty(t, events)
+
+	// Events are dropped when the channel is full.
+	for i := 0; i < eventBufferSize+1; i++ {
+		require.NoError(t, gatherer.Add(newTx(uint64(i), "Bob")))
+	}
+
+	require.Len(t, events, eventBufferSize)
+
+	cancel()
+
+	// The channel is closed once the context is done.
+	for range events {
+	}
+
+	require.Equal(t, "added", TxAdded.String())
+	require.Equal(t, "included", TxIncluded.String())
+	require.Equal(t, "evicted", TxEvicted.String())
+	require.Equal(t, "unknown", EventType(-1).String())
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
@@ -146,6 +270,13 @@ func emptyTx() transactionStats {
 	}
 }
 
+func readHistogram(t *testing.T) *dto.Histogram {
+	metric := &dto.Metric{}
+	require.NoError(t, promInclusion.Write(metric))
+
+	return metric.GetHistogram()
+}
+
 func newTx(nonce uint64, identity string) transactionStats {
 	return transactionStats{
 		Transaction: fakeTx{
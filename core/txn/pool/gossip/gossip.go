@@ -22,6 +22,7 @@ type Pool struct {
 	logger   zerolog.Logger
 	actor    gossip.Actor
 	gatherer pool.Gatherer
+	reporter gossip.Reporter
 	closing  chan struct{}
 }
 
@@ -39,6 +40,10 @@ func NewPool(gossiper gossip.Gossiper) (*Pool, error) {
 		closing:  make(chan struct{}),
 	}
 
+	// The gossiper is told about the validity of the rumors when it scores the
+	// peers.
+	p.reporter, _ = gossiper.(gossip.Reporter)
+
 	go p.listenRumors(gossiper.Rumors())
 
 	return p, nil
@@ -98,6 +103,18 @@ func (p *Pool) ResetStats() {
 	p.gatherer.ResetStats()
 }
 
+// SetLimit implements pool.Pool. It sets the number of pending transactions
+// allowed per identity.
+func (p *Pool) SetLimit(size int) {
+	p.gatherer.SetLimit(size)
+}
+
+// Watch implements pool.Pool. It returns a channel populated with the events of
+// the pool until the context is done.
+func (p *Pool) Watch(ctx context.Context) <-chan pool.Event {
+	return p.gatherer.Watch(ctx)
+}
+
 // Close stops the gossiper and terminate the routine that listens for rumors.
 func (p *Pool) Close() error {
 	p.gatherer.Close()
@@ -122,6 +139,10 @@ func (p *Pool) listenRumors(ch <-chan gossip.Rumor) {
 				if err != nil {
 					p.logger.Debug().Err(err).Msg("failed to add transaction")
 				}
+
+				if p.reporter != nil {
+					p.reporter.Report(rumor, err == nil)
+				}
 			}
 		case <-p.closing:
 			return
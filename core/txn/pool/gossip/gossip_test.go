@@ -86,14 +86,25 @@ func TestPool_AddFilter(t *testing.T) {
 	p.AddFilter(nil)
 }
 
+func TestPool_SetLimit(t *testing.T) {
+	p := &Pool{
+		gatherer: pool.NewSimpleGatherer(),
+	}
+
+	p.SetLimit(5)
+}
+
 func TestPool_Add(t *testing.T) {
+	call := &fake.Call{}
+
 	p := &Pool{
-		actor:    fakeActor{},
+		actor:    fakeActor{call: call},
 		gatherer: pool.NewSimpleGatherer(),
 	}
 
 	err := p.Add(makeFakeTx(0))
 	require.NoError(t, err)
+	call.Assert(t, 0, makeFakeTx(0))
 
 	p.gatherer = badGatherer{}
 	err = p.Add(makeFakeTx(0))
@@ -149,6 +160,26 @@ func TestPool_Gather(t *testing.T) {
 	require.Len(t, txs, 0)
 }
 
+func TestPool_Watch(t *testing.T) {
+	p := &Pool{
+		actor:    fakeActor{},
+		gatherer: pool.NewSimpleGatherer(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := p.Watch(ctx)
+
+	tx := makeFakeTx(0)
+
+	require.NoError(t, p.Add(tx))
+	require.NoError(t, p.Remove(tx))
+
+	require.Equal(t, pool.TxAdded, (<-events).Type)
+	require.Equal(t, pool.TxIncluded, (<-events).Type)
+}
+
 func TestPool_Close(t *testing.T) {
 	p := &Pool{
 		gatherer: pool.NewSimpleGatherer(),
@@ -194,6 +225,38 @@ func TestPool_ListenRumors(t *testing.T) {
 
 	p.listenRumors(ch)
 	require.NotEmpty(t, buffer.String())
+
+	reporter := fakeReporter{call: &fake.Call{}}
+
+	p.reporter = reporter
+	p.closing = make(chan struct{})
+
+	ch = make(chan gossip.Rumor)
+	go func() {
+		ch <- makeFakeTx(0)
+		close(p.closing)
+	}()
+
+	p.listenRumors(ch)
+	reporter.call.Assert(t, 0, makeFakeTx(0), false)
+
+	// The rumors are reported while the pool keeps listening.
+	reporter = fakeReporter{call: &fake.Call{}}
+
+	p.reporter = reporter
+	p.gatherer = pool.NewSimpleGatherer()
+	p.closing = make(chan struct{})
+
+	ch = make(chan gossip.Rumor, 2)
+	ch <- makeFakeTx(1)
+	ch <- makeFakeTx(1)
+
+	go p.listenRumors(ch)
+	defer close(p.closing)
+
+	require.NoError(t, reporter.call.WaitFor(2, time.Second))
+	reporter.call.Assert(t, 0, makeFakeTx(1), true)
+	reporter.call.Assert(t, 1, makeFakeTx(1), true)
 }
 
 // -----------------------------------------------------------------------------
@@ -275,6 +338,14 @@ func (a fakeActor) Close() error {
 	return a.err
 }
 
+type fakeReporter struct {
+	call *fake.Call
+}
+
+func (r fakeReporter) Report(rumor gossip.Rumor, valid bool) {
+	r.call.Add(rumor, valid)
+}
+
 type fakeGossiper struct {
 	err error
 }
@@ -79,3 +79,15 @@ func (p *Pool) Stats() pool.Stats {
 func (p *Pool) ResetStats() {
 	p.gatherer.ResetStats()
 }
+
+// SetLimit implements pool.Pool. It sets the number of pending transactions
+// allowed per identity.
+func (p *Pool) SetLimit(size int) {
+	p.gatherer.SetLimit(size)
+}
+
+// Watch implements pool.Pool. It returns a channel populated with the events of
+// the pool until the context is done.
+func (p *Pool) Watch(ctx context.Context) <-chan pool.Event {
+	return p.gatherer.Watch(ctx)
+}
@@ -25,6 +25,12 @@ func TestPool_AddFilter(t *testing.T) {
 	p.AddFilter(nil)
 }
 
+func TestPool_SetLimit(t *testing.T) {
+	p := NewPool()
+
+	p.SetLimit(5)
+}
+
 func TestPool_Add(t *testing.T) {
 	p := NewPool()
 
@@ -92,6 +98,21 @@ func TestPool_Close(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPool_Watch(t *testing.T) {
+	p := NewPool()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := p.Watch(ctx)
+
+	require.NoError(t, p.Add(fakeTx{id: []byte{1}}))
+
+	evt := <-events
+	require.Equal(t, pool.TxAdded, evt.Type)
+	require.Equal(t, []byte{1}, evt.Tx.GetID())
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
@@ -44,7 +44,8 @@ type Pool interface {
 	// Add adds the transaction to the pool.
 	Add(txn.Transaction) error
 
-	// Remove removes the transaction from the pool.
+	// Remove removes the transaction from the pool. It is called when the
+	// transaction has been included in a block.
 	Remove(txn.Transaction) error
 
 	// Gather is a blocking function to gather transactions from the pool. The
@@ -57,6 +58,13 @@ type Pool interface {
 	// ResetStats resets the transaction statistics.
 	ResetStats()
 
+	// SetLimit sets the number of pending transactions allowed per identity.
+	SetLimit(size int)
+
+	// Watch returns a channel populated with the events of the pool until the
+	// context is done.
+	Watch(context.Context) <-chan Event
+
 	// Close closes the pool and cleans the resources.
 	Close() error
 }
@@ -69,3 +77,40 @@ type Stats struct {
 	// TxCount is the number of transactions available in the pool.
 	TxCount int
 }
+
+// EventType is the type of an event of the pool.
+type EventType int
+
+const (
+	// TxAdded is the type of the event when a transaction is accepted in the
+	// pool.
+	TxAdded EventType = iota
+
+	// TxIncluded is the type of the event when a transaction is removed from
+	// the pool because it has been included in a block.
+	TxIncluded
+
+	// TxEvicted is the type of the event when a transaction is dropped from the
+	// pool without being included.
+	TxEvicted
+)
+
+// String returns a human-readable name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case TxAdded:
+		return "added"
+	case TxIncluded:
+		return "included"
+	case TxEvicted:
+		return "evicted"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a change of a transaction in the pool.
+type Event struct {
+	Type EventType
+	Tx   txn.Transaction
+}
@@ -11,10 +11,18 @@ import (
 type transactionStats struct {
 	txn.Transaction
 	insertionTime time.Time
+
+	// arrival is the rank of the transaction in the order of arrival in the
+	// gatherer. Unlike the insertion time, it is never reset.
+	arrival uint64
+
+	// arrivalTime is when the transaction has been added to the gatherer.
+	// Unlike the insertion time, it is never reset.
+	arrivalTime time.Time
 }
 
-// ResetStats resets the insertion time to now.
+// ResetStats resets the insertion time to the given time.
 // It is used when a leader view change is initiated.
-func (t *transactionStats) ResetStats() {
-	t.insertionTime = time.Now()
+func (t *transactionStats) ResetStats(now time.Time) {
+	t.insertionTime = now
 }
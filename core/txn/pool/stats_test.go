@@ -14,7 +14,7 @@ func TestStats_Reset(t *testing.T) {
 
 	isRotten := time.Since(stats.insertionTime) > time.Minute
 	require.True(t, isRotten)
-	stats.ResetStats()
+	stats.ResetStats(time.Now())
 	isRotten = time.Since(stats.insertionTime) > time.Minute
 	require.False(t, isRotten)
 }
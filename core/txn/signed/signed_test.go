@@ -107,6 +107,34 @@ func TestTransaction_Sign(t *testing.T) {
 	require.EqualError(t, err, fake.Err("signer"))
 }
 
+func TestTransaction_Sign_Deterministic(t *testing.T) {
+	alice := fake.NewDeterministicSigner("alice")
+
+	tx, err := NewTransaction(2, alice.GetPublicKey(), WithArg("A", []byte{123}))
+	require.NoError(t, err)
+	require.NoError(t, tx.Sign(alice))
+
+	_, err = NewTransaction(2, alice.GetPublicKey(), WithArg("A", []byte{123}),
+		WithSignature(tx.GetSignature()))
+	require.NoError(t, err)
+
+	// The signature does not cover a different argument.
+	_, err = NewTransaction(2, alice.GetPublicKey(), WithArg("A", []byte{124}),
+		WithSignature(tx.GetSignature()))
+	require.EqualError(t, err, fake.Err("invalid signature"))
+
+	// The signature of another signer is refused.
+	bob := fake.NewDeterministicSigner("bob")
+
+	sig, err := bob.Sign(tx.GetID())
+	require.NoError(t, err)
+	require.False(t, sig.Equal(tx.GetSignature()))
+
+	_, err = NewTransaction(2, alice.GetPublicKey(), WithArg("A", []byte{123}),
+		WithSignature(sig))
+	require.EqualError(t, err, fake.Err("invalid signature"))
+}
+
 func TestTransaction_Fingerprint(t *testing.T) {
 	tx, err := NewTransaction(2, fake.PublicKey{}, WithArg("A", []byte{1, 2, 3}))
 	require.NoError(t, err)
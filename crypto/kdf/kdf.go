@@ -0,0 +1,69 @@
+// Package kdf derives keys, seeds and nonces from secrets, such as the group
+// elements recovered by the IBE scheme or the signatures of the chain.
+//
+// The derivation is HKDF with SHA-512. Every use has its own domain, which is
+// given as the info of HKDF, so that two uses of the same secret never get
+// related outputs.
+package kdf
+
+import (
+	"crypto/sha512"
+	"io"
+
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/xerrors"
+)
+
+// Domain is the separation string of a use of the derivation.
+type Domain string
+
+const (
+	// IBEStream is the domain of the key of the stream cipher of the IBE
+	// ciphertexts. It is empty because the scheme predates this package, and
+	// the ciphertexts already on chain must still decrypt.
+	IBEStream Domain = ""
+
+	// BeaconRandomness is the domain of the randomness of a block, derived
+	// from its commit signature.
+	BeaconRandomness Domain = "dela/v1/beacon-randomness"
+
+	// OrderingSeed is the domain of the seed of the shuffle of the
+	// transactions in a block, derived from the randomness of the beacon.
+	OrderingSeed Domain = "dela/v1/ordering-seed"
+
+	// CommitteeSeed is the domain of the seed of the sampling of the
+	// secret-management committee of an epoch, derived from the randomness of
+	// the beacon.
+	CommitteeSeed Domain = "dela/v1/committee-seed"
+)
+
+// Derive returns size bytes derived from the secret for the domain.
+func Derive(secret []byte, domain Domain, size int) ([]byte, error) {
+	out := make([]byte, size)
+
+	reader := hkdf.New(sha512.New, secret, nil, []byte(domain))
+
+	_, err := io.ReadFull(reader, out)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive %d bytes: %v", size, err)
+	}
+
+	return out, nil
+}
+
+// DerivePoint returns size bytes derived from the binary representation of the
+// point for the domain.
+func DerivePoint(point kyber.Point, domain Domain, size int) ([]byte, error) {
+	secret, err := point.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal point: %v", err)
+	}
+
+	out, err := Derive(secret, domain, size)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive: %v", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,80 @@
+package kdf
+
+import (
+	"crypto/sha512"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"golang.org/x/crypto/hkdf"
+)
+
+func TestDerive(t *testing.T) {
+	secret := []byte("secret")
+
+	a, err := Derive(secret, BeaconRandomness, 32)
+	require.NoError(t, err)
+	require.Len(t, a, 32)
+
+	again, err := Derive(secret, BeaconRandomness, 32)
+	require.NoError(t, err)
+	require.Equal(t, a, again)
+
+	b, err := Derive(secret, OrderingSeed, 32)
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+
+	_, err = Derive(secret, OrderingSeed, 255*sha512.Size+1)
+	require.EqualError(t, err,
+		"failed to derive 16321 bytes: hkdf: entropy limit reached")
+}
+
+func TestDerive_IBEStream(t *testing.T) {
+	secret := []byte("secret")
+
+	// The domain of the IBE stream keeps the derivation of the ciphertexts
+	// made before the package existed.
+	expected := make([]byte, 32)
+	_, err := io.ReadFull(hkdf.New(sha512.New, secret, nil, nil), expected)
+	require.NoError(t, err)
+
+	key, err := Derive(secret, IBEStream, 32)
+	require.NoError(t, err)
+	require.Equal(t, expected, key)
+}
+
+func TestDerivePoint(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	point := suite.G2().Point().Pick(suite.RandomStream())
+
+	data, err := point.MarshalBinary()
+	require.NoError(t, err)
+
+	expected, err := Derive(data, OrderingSeed, 8)
+	require.NoError(t, err)
+
+	out, err := DerivePoint(point, OrderingSeed, 8)
+	require.NoError(t, err)
+	require.Equal(t, expected, out)
+
+	_, err = DerivePoint(badPoint{}, OrderingSeed, 8)
+	require.EqualError(t, err, fake.Err("failed to marshal point"))
+
+	_, err = DerivePoint(point, OrderingSeed, 255*sha512.Size+1)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to derive: ", err.Error())
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type badPoint struct {
+	kyber.Point
+}
+
+func (badPoint) MarshalBinary() ([]byte, error) {
+	return nil, fake.GetError()
+}
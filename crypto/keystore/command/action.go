@@ -0,0 +1,155 @@
+package command
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/crypto/keystore"
+	"golang.org/x/xerrors"
+)
+
+// action defines the different cli actions of the keystore commands. Defining
+// functions and printer helps in testing the commands.
+type action struct {
+	printer io.Writer
+
+	// stdin and prompt are used to ask for the passphrase when it is neither
+	// in a file nor in the environment.
+	stdin  io.Reader
+	prompt io.Writer
+
+	newKeystore func(dir string) keystore.Keystore
+	readFile    func(filename string) ([]byte, error)
+	saveFile    func(path string, force bool, data []byte) error
+}
+
+func (a action) newAction(flags cli.Flags) error {
+	signer := bls.NewSigner()
+
+	data, err := signer.MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal signer: %v", err)
+	}
+
+	err = a.store(flags, data, signer.GetPublicKey())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a action) listAction(flags cli.Flags) error {
+	entries, err := a.newKeystore(flags.Path("dir")).List()
+	if err != nil {
+		return xerrors.Errorf("failed to list keys: %v", err)
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(a.printer, "%s\t%s\n", entry.Name,
+			base64.StdEncoding.EncodeToString(entry.PublicKey))
+	}
+
+	return nil
+}
+
+func (a action) exportAction(flags cli.Flags) error {
+	passphrase, err := a.readPassphrase(flags)
+	if err != nil {
+		return err
+	}
+
+	ks := a.newKeystore(flags.Path("dir"))
+
+	data, err := ks.Load(flags.String("name"), passphrase)
+	if err != nil {
+		return xerrors.Errorf("failed to load key: %v", err)
+	}
+
+	err = a.saveFile(flags.Path("out"), flags.Bool("force"), data)
+	if err != nil {
+		return xerrors.Errorf("failed to save file: %v", err)
+	}
+
+	return nil
+}
+
+func (a action) importAction(flags cli.Flags) error {
+	data, err := a.readFile(flags.Path("in"))
+	if err != nil {
+		return xerrors.Errorf("failed to read file: %v", err)
+	}
+
+	signer, err := bls.NewSignerFromBytes(data)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal signer: %v", err)
+	}
+
+	err = a.store(flags, data, signer.GetPublicKey())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// store encrypts the private key in the keystore and prints the public key.
+func (a action) store(flags cli.Flags, privkey []byte, pub crypto.PublicKey) error {
+	pubkey, err := pub.MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal public key: %v", err)
+	}
+
+	passphrase, err := a.readPassphrase(flags)
+	if err != nil {
+		return err
+	}
+
+	ks := a.newKeystore(flags.Path("dir"))
+
+	err = ks.Store(flags.String("name"), privkey, pubkey, passphrase)
+	if err != nil {
+		return xerrors.Errorf("failed to store key: %v", err)
+	}
+
+	fmt.Fprintln(a.printer, base64.StdEncoding.EncodeToString(pubkey))
+
+	return nil
+}
+
+// readPassphrase returns the passphrase of the passphrase file, of the
+// environment, or typed on the standard input.
+func (a action) readPassphrase(flags cli.Flags) (string, error) {
+	passphrase, err := keystore.ReadPassphrase(flags.Path("passphraseFile"), a.stdin, a.prompt)
+	if err != nil {
+		return "", xerrors.Errorf("failed to read passphrase: %v", err)
+	}
+
+	return passphrase, nil
+}
+
+func saveToFile(path string, force bool, data []byte) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	file, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		return xerrors.Errorf("failed to open file: %v", err)
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(data)
+	if err != nil {
+		return xerrors.Errorf("failed to write file: %v", err)
+	}
+
+	return nil
+}
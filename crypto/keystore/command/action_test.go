@@ -0,0 +1,171 @@
+package command
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/crypto/keystore"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestAction_NewAndList(t *testing.T) {
+	dir := makeDir(t)
+	defer os.RemoveAll(dir)
+
+	out := new(bytes.Buffer)
+
+	action := makeAction(out)
+
+	action.stdin = strings.NewReader("secret\n")
+
+	flags := node.FlagSet{"dir": dir, "name": "alice"}
+
+	err := action.newAction(flags)
+	require.NoError(t, err)
+
+	pubkey := strings.TrimSpace(out.String())
+	out.Reset()
+
+	_, err = action.newKeystore(dir).Load("alice", "secret")
+	require.NoError(t, err)
+
+	action.stdin = strings.NewReader("secret\n")
+
+	err = action.newAction(flags)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to store key: failed to create file: ", err.Error())
+
+	err = action.listAction(flags)
+	require.NoError(t, err)
+	require.Equal(t, "alice\t"+pubkey+"\n", out.String())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), nil, 0600))
+
+	err = action.listAction(flags)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to list keys: ", err.Error())
+}
+
+func TestAction_ImportExport(t *testing.T) {
+	dir := makeDir(t)
+	defer os.RemoveAll(dir)
+
+	signer := bls.NewSigner()
+
+	data, err := signer.MarshalBinary()
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, "signer")
+	require.NoError(t, os.WriteFile(file, data, 0600))
+
+	passFile := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(passFile, []byte("secret\n"), 0600))
+
+	out := new(bytes.Buffer)
+
+	action := makeAction(out)
+
+	flags := node.FlagSet{
+		"dir":            filepath.Join(dir, "keys"),
+		"name":           "alice",
+		"passphraseFile": passFile,
+		"in":             file,
+		"out":            filepath.Join(dir, "exported"),
+	}
+
+	err = action.importAction(flags)
+	require.NoError(t, err)
+
+	pubkey, err := signer.GetPublicKey().MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, base64.StdEncoding.EncodeToString(pubkey)+"\n", out.String())
+
+	err = action.exportAction(flags)
+	require.NoError(t, err)
+
+	exported, err := os.ReadFile(filepath.Join(dir, "exported"))
+	require.NoError(t, err)
+	require.Equal(t, data, exported)
+
+	err = action.exportAction(flags)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to save file: failed to open file: ", err.Error())
+
+	flags["force"] = true
+
+	err = action.exportAction(flags)
+	require.NoError(t, err)
+
+	t.Setenv(keystore.EnvPassphrase, "wrong")
+	flags["passphraseFile"] = ""
+
+	err = action.exportAction(flags)
+	require.EqualError(t, err,
+		"failed to load key: failed to decrypt: wrong passphrase or corrupted key")
+
+	flags["passphraseFile"] = filepath.Join(dir, "unknown")
+
+	err = action.exportAction(flags)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read passphrase: failed to read file: ", err.Error())
+
+	err = action.importAction(flags)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read passphrase: failed to read file: ", err.Error())
+
+	flags["passphraseFile"] = passFile
+
+	require.NoError(t, os.WriteFile(file, []byte("bad signer"), 0600))
+
+	err = action.importAction(flags)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to unmarshal signer: ", err.Error())
+
+	action.readFile = badReadFile
+
+	err = action.importAction(flags)
+	require.EqualError(t, err, fake.Err("failed to read file"))
+}
+
+func TestAction_Store(t *testing.T) {
+	action := makeAction(io.Discard)
+
+	err := action.store(node.FlagSet{}, nil, fake.NewBadPublicKey())
+	require.EqualError(t, err, fake.Err("failed to marshal public key"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeDir(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "dela-keystore-")
+	require.NoError(t, err)
+
+	return dir
+}
+
+// makeAction returns an action with a keystore that uses cheap scrypt
+// parameters for the tests.
+func makeAction(out io.Writer) action {
+	return action{
+		printer: out,
+		prompt:  io.Discard,
+		newKeystore: func(dir string) keystore.Keystore {
+			return keystore.NewKeystore(dir, keystore.WithScrypt(1<<4, 8, 1))
+		},
+		readFile: os.ReadFile,
+		saveFile: saveToFile,
+	}
+}
+
+func badReadFile(path string) ([]byte, error) {
+	return nil, fake.GetError()
+}
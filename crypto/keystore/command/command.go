@@ -0,0 +1,84 @@
+// Package command defines cli commands for the keystore package.
+package command
+
+import (
+	"os"
+
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/crypto/keystore"
+)
+
+// Initializer implements the keystore initializer for the crypto CLI.
+//
+// - implements cli.Initializer
+type Initializer struct {
+}
+
+// SetCommands implements cli.Initializer.
+func (i Initializer) SetCommands(provider cli.Provider) {
+	action := action{
+		printer: os.Stdout,
+		stdin:   os.Stdin,
+		prompt:  os.Stderr,
+		newKeystore: func(dir string) keystore.Keystore {
+			return keystore.NewKeystore(dir)
+		},
+		readFile: os.ReadFile,
+		saveFile: saveToFile,
+	}
+
+	dirFlag := cli.StringFlag{
+		Name:     "dir",
+		Usage:    "path to the keystore directory",
+		Required: true,
+	}
+
+	nameFlag := cli.StringFlag{
+		Name:     "name",
+		Usage:    "name of the key in the keystore",
+		Required: true,
+	}
+
+	passFlag := cli.StringFlag{
+		Name: "passphraseFile",
+		Usage: "path to a file with the passphrase that protects the key. " +
+			"Without it, the passphrase is read from the " + keystore.EnvPassphrase +
+			" variable, or from the standard input",
+		Required: false,
+	}
+
+	cmd := provider.SetCommand("key")
+	cmd.SetDescription("manage the encrypted signing keys of a keystore")
+
+	new := cmd.SetSubCommand("new")
+	new.SetDescription("create a new bls signer in the keystore")
+	new.SetFlags(dirFlag, nameFlag, passFlag)
+	new.SetAction(action.newAction)
+
+	list := cmd.SetSubCommand("list")
+	list.SetDescription("list the keys of the keystore with their public key")
+	list.SetFlags(dirFlag)
+	list.SetAction(action.listAction)
+
+	export := cmd.SetSubCommand("export")
+	export.SetDescription("decrypt a key and save it to a signer file")
+	export.SetFlags(dirFlag, nameFlag, passFlag, cli.StringFlag{
+		Name:     "out",
+		Usage:    "path to the signer file",
+		Required: true,
+	}, cli.BoolFlag{
+		Name:     "force",
+		Usage:    "overwrite the signer file if it exists",
+		Required: false,
+	})
+	export.SetAction(action.exportAction)
+
+	imp := cmd.SetSubCommand("import")
+	imp.SetDescription("encrypt a signer file into the keystore")
+	imp.SetFlags(dirFlag, nameFlag, passFlag, cli.StringFlag{
+		Name:     "in",
+		Usage:    "path to the signer file",
+		Required: true,
+	})
+	imp.SetAction(action.importAction)
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestSetCommands(t *testing.T) {
+	init := Initializer{}
+
+	call := &fake.Call{}
+	provider := fakeBuilder{call: call}
+	init.SetCommands(provider)
+
+	require.Equal(t, 18, call.Len())
+	require.Equal(t, "key", call.Get(0, 0))
+	require.Equal(t, "new", call.Get(2, 0))
+	require.Equal(t, "list", call.Get(6, 0))
+	require.Equal(t, "export", call.Get(10, 0))
+	require.Equal(t, "import", call.Get(14, 0))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type fakeCommandBuilder struct {
+	call *fake.Call
+}
+
+func (b fakeCommandBuilder) SetSubCommand(name string) cli.CommandBuilder {
+	b.call.Add(name)
+	return b
+}
+
+func (b fakeCommandBuilder) SetDescription(value string) {
+	b.call.Add(value)
+}
+
+func (b fakeCommandBuilder) SetFlags(flags ...cli.Flag) {
+	b.call.Add(flags)
+}
+
+func (b fakeCommandBuilder) SetAction(a cli.Action) {
+	b.call.Add(a)
+}
+
+type fakeBuilder struct {
+	call *fake.Call
+}
+
+func (b fakeBuilder) SetCommand(name string) cli.CommandBuilder {
+	b.call.Add(name)
+	return fakeCommandBuilder(b)
+}
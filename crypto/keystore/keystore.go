@@ -0,0 +1,376 @@
+// Package keystore implements a directory of private keys encrypted with a
+// passphrase. Each key is stored in its own JSON file, with the public key in
+// clear so that the keys can be listed without the passphrase.
+//
+// The encryption key is derived from the passphrase with scrypt, and the
+// private key is encrypted with AES-256-GCM so that a wrong passphrase or a
+// modified file is detected. The public key is authenticated as associated
+// data, so that it can't be replaced by another one in the file.
+package keystore
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.dedis.ch/dela/crypto"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// Version is the version of the format of the key files. The public key
+	// is authenticated since the version 2.
+	Version = 2
+
+	// EnvPassphrase is the name of the environment variable that holds the
+	// passphrase when it is not read from a file.
+	EnvPassphrase = "DELA_PASSPHRASE"
+
+	// StandardScryptN is the default CPU/memory cost of scrypt.
+	StandardScryptN = 1 << 18
+
+	// StandardScryptR is the default block size of scrypt.
+	StandardScryptR = 8
+
+	// StandardScryptP is the default parallelization of scrypt.
+	StandardScryptP = 1
+
+	keyLen   = 32
+	saltLen  = 32
+	fileExt  = ".json"
+	fileMode = 0600
+)
+
+// namePattern restricts the names of the keys so that they are safe to use as
+// file names.
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Entry is the content of a key file.
+type Entry struct {
+	Version   int
+	Name      string
+	PublicKey []byte
+	Crypto    Params
+}
+
+// Params are the parameters of the encryption of a key.
+type Params struct {
+	KDF        string
+	N          int
+	R          int
+	P          int
+	Salt       []byte
+	Cipher     string
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// Keystore is a directory of encrypted keys.
+type Keystore struct {
+	dir    string
+	n      int
+	r      int
+	p      int
+	randFn crypto.RandGenerator
+}
+
+// Option is the type of option to change the default keystore.
+type Option func(*Keystore)
+
+// WithScrypt is an option to set the scrypt parameters used to encrypt new
+// keys. The default parameters are deliberately slow, which can be too slow
+// for the tests.
+func WithScrypt(n, r, p int) Option {
+	return func(ks *Keystore) {
+		ks.n = n
+		ks.r = r
+		ks.p = p
+	}
+}
+
+// WithRandom is an option to set the source of randomness of the salts and the
+// nonces.
+func WithRandom(rand crypto.RandGenerator) Option {
+	return func(ks *Keystore) {
+		ks.randFn = rand
+	}
+}
+
+// NewKeystore creates a keystore that stores the keys in the directory.
+func NewKeystore(dir string, opts ...Option) Keystore {
+	ks := Keystore{
+		dir:    dir,
+		n:      StandardScryptN,
+		r:      StandardScryptR,
+		p:      StandardScryptP,
+		randFn: crypto.CryptographicRandomGenerator{},
+	}
+
+	for _, opt := range opts {
+		opt(&ks)
+	}
+
+	return ks
+}
+
+// Store encrypts the private key with the passphrase and stores it under the
+// name. It returns an error if a key with the same name exists.
+func (ks Keystore) Store(name string, privkey, pubkey []byte, passphrase string) error {
+	if !namePattern.MatchString(name) {
+		return xerrors.Errorf("invalid name '%s'", name)
+	}
+
+	params, err := ks.encrypt(privkey, pubkey, passphrase)
+	if err != nil {
+		return xerrors.Errorf("failed to encrypt: %v", err)
+	}
+
+	entry := Entry{
+		Version:   Version,
+		Name:      name,
+		PublicKey: pubkey,
+		Crypto:    params,
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	err = os.MkdirAll(ks.dir, 0700)
+	if err != nil {
+		return xerrors.Errorf("failed to create directory: %v", err)
+	}
+
+	file, err := os.OpenFile(ks.path(name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileMode)
+	if err != nil {
+		return xerrors.Errorf("failed to create file: %v", err)
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(data)
+	if err != nil {
+		return xerrors.Errorf("failed to write file: %v", err)
+	}
+
+	return nil
+}
+
+// Load returns the private key stored under the name, decrypted with the
+// passphrase.
+func (ks Keystore) Load(name, passphrase string) ([]byte, error) {
+	entry, err := ks.read(name)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read key: %v", err)
+	}
+
+	privkey, err := Decrypt(entry, passphrase)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decrypt: %v", err)
+	}
+
+	return privkey, nil
+}
+
+// List returns the entries of the keystore sorted by name. An empty list is
+// returned if the directory does not exist.
+func (ks Keystore) List() ([]Entry, error) {
+	files, err := os.ReadDir(ks.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read directory: %v", err)
+	}
+
+	entries := []Entry{}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), fileExt)
+
+		if file.IsDir() || name == file.Name() || !namePattern.MatchString(name) {
+			continue
+		}
+
+		entry, err := ks.read(name)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read key: %v", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+// Decrypt returns the private key of the entry, decrypted with the passphrase.
+func Decrypt(entry Entry, passphrase string) ([]byte, error) {
+	params := entry.Crypto
+
+	if params.KDF != "scrypt" {
+		return nil, xerrors.Errorf("unknown kdf '%s'", params.KDF)
+	}
+
+	if params.Cipher != "aes-256-gcm" {
+		return nil, xerrors.Errorf("unknown cipher '%s'", params.Cipher)
+	}
+
+	aead, err := makeAEAD(passphrase, params)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive key: %v", err)
+	}
+
+	if len(params.Nonce) != aead.NonceSize() {
+		return nil, xerrors.Errorf("invalid nonce size %d", len(params.Nonce))
+	}
+
+	var ad []byte
+	if entry.Version >= 2 {
+		ad = entry.PublicKey
+	}
+
+	privkey, err := aead.Open(nil, params.Nonce, params.Ciphertext, ad)
+	if err != nil {
+		return nil, xerrors.New("wrong passphrase or corrupted key")
+	}
+
+	return privkey, nil
+}
+
+// ReadEntry decodes the content of a key file.
+func ReadEntry(data []byte) (Entry, error) {
+	var entry Entry
+
+	err := json.Unmarshal(data, &entry)
+	if err != nil {
+		return entry, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	if entry.Version < 1 || entry.Version > Version {
+		return entry, xerrors.Errorf("unsupported version %d", entry.Version)
+	}
+
+	return entry, nil
+}
+
+// ReadPassphrase returns the passphrase of a key. It is read from the file at
+// the path if it is set, or from the EnvPassphrase variable if it is defined.
+// Otherwise, the prompt is written to the output and the passphrase is read
+// from the first line of the input, if any.
+func ReadPassphrase(path string, in io.Reader, out io.Writer) (string, error) {
+	var passphrase string
+
+	switch {
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", xerrors.Errorf("failed to read file: %v", err)
+		}
+
+		passphrase = trimLine(string(data))
+	case os.Getenv(EnvPassphrase) != "":
+		passphrase = os.Getenv(EnvPassphrase)
+	case in != nil:
+		fmt.Fprint(out, "Passphrase: ")
+
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", xerrors.Errorf("failed to read input: %v", err)
+		}
+
+		passphrase = trimLine(line)
+	default:
+		return "", xerrors.Errorf("no passphrase file and %s is not set", EnvPassphrase)
+	}
+
+	if passphrase == "" {
+		return "", xerrors.New("empty passphrase")
+	}
+
+	return passphrase, nil
+}
+
+// trimLine removes the end of line of the string, so that the other spaces are
+// kept in the passphrase.
+func trimLine(str string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(str, "\n"), "\r")
+}
+
+func (ks Keystore) encrypt(privkey, pubkey []byte, passphrase string) (Params, error) {
+	params := Params{
+		KDF:    "scrypt",
+		N:      ks.n,
+		R:      ks.r,
+		P:      ks.p,
+		Salt:   make([]byte, saltLen),
+		Cipher: "aes-256-gcm",
+	}
+
+	_, err := ks.randFn.Read(params.Salt)
+	if err != nil {
+		return params, xerrors.Errorf("failed to generate salt: %v", err)
+	}
+
+	aead, err := makeAEAD(passphrase, params)
+	if err != nil {
+		return params, xerrors.Errorf("failed to derive key: %v", err)
+	}
+
+	params.Nonce = make([]byte, aead.NonceSize())
+
+	_, err = ks.randFn.Read(params.Nonce)
+	if err != nil {
+		return params, xerrors.Errorf("failed to generate nonce: %v", err)
+	}
+
+	params.Ciphertext = aead.Seal(nil, params.Nonce, privkey, pubkey)
+
+	return params, nil
+}
+
+func (ks Keystore) read(name string) (Entry, error) {
+	if !namePattern.MatchString(name) {
+		return Entry{}, xerrors.Errorf("invalid name '%s'", name)
+	}
+
+	data, err := os.ReadFile(ks.path(name))
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return ReadEntry(data)
+}
+
+func (ks Keystore) path(name string) string {
+	return filepath.Join(ks.dir, name+fileExt)
+}
+
+func makeAEAD(passphrase string, params Params) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), params.Salt, params.N, params.R,
+		params.P, keyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
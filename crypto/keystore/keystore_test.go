@@ -0,0 +1,231 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestKeystore_StoreLoad(t *testing.T) {
+	dir := makeDir(t)
+	defer os.RemoveAll(dir)
+
+	ks := makeKeystore(filepath.Join(dir, "keys"))
+
+	err := ks.Store("alice", []byte("private"), []byte("public"), "secret")
+	require.NoError(t, err)
+
+	info, err := os.Stat(filepath.Join(dir, "keys", "alice.json"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(fileMode), info.Mode().Perm())
+
+	privkey, err := ks.Load("alice", "secret")
+	require.NoError(t, err)
+	require.Equal(t, []byte("private"), privkey)
+
+	_, err = ks.Load("alice", "wrong")
+	require.EqualError(t, err, "failed to decrypt: wrong passphrase or corrupted key")
+
+	_, err = ks.Load("bob", "secret")
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read key: open ", err.Error())
+
+	_, err = ks.Load("../alice", "secret")
+	require.EqualError(t, err, "failed to read key: invalid name '../alice'")
+
+	err = ks.Store("alice", []byte("other"), nil, "secret")
+	require.Error(t, err)
+	require.Regexp(t, "^failed to create file: ", err.Error())
+
+	err = ks.Store("../alice", nil, nil, "secret")
+	require.EqualError(t, err, "invalid name '../alice'")
+
+	ks.randFn = badRandom{}
+	err = ks.Store("bob", nil, nil, "secret")
+	require.EqualError(t, err, fake.Err("failed to encrypt: failed to generate salt"))
+
+	ks.randFn = badRandom{counter: fake.NewCounter(1)}
+	err = ks.Store("bob", nil, nil, "secret")
+	require.EqualError(t, err, fake.Err("failed to encrypt: failed to generate nonce"))
+
+	ks = NewKeystore(dir, WithScrypt(3, 8, 1))
+	err = ks.Store("bob", nil, nil, "secret")
+	require.EqualError(t, err,
+		"failed to encrypt: failed to derive key: scrypt: N must be > 1 and a power of 2")
+}
+
+func TestKeystore_List(t *testing.T) {
+	dir := makeDir(t)
+	defer os.RemoveAll(dir)
+
+	ks := makeKeystore(dir)
+
+	entries, err := makeKeystore(filepath.Join(dir, "unknown")).List()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	require.NoError(t, ks.Store("bob", nil, []byte{2}, "secret"))
+	require.NoError(t, ks.Store("alice", nil, []byte{1}, "secret"))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "dir.json"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.b.json"), nil, 0600))
+
+	entries, err = ks.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "alice", entries[0].Name)
+	require.Equal(t, []byte{1}, entries[0].PublicKey)
+	require.Equal(t, "bob", entries[1].Name)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{}"), 0600))
+
+	_, err = ks.List()
+	require.EqualError(t, err, "failed to read key: unsupported version 0")
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0600))
+
+	_, err = makeKeystore(file).List()
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read directory: ", err.Error())
+}
+
+func TestDecrypt(t *testing.T) {
+	ks := makeKeystore("")
+
+	params, err := ks.encrypt([]byte("private"), []byte("public"), "secret")
+	require.NoError(t, err)
+
+	entry := Entry{Version: Version, PublicKey: []byte("public"), Crypto: params}
+
+	privkey, err := Decrypt(entry, "secret")
+	require.NoError(t, err)
+	require.Equal(t, []byte("private"), privkey)
+
+	// The public key is authenticated with the private key.
+	other := entry
+	other.PublicKey = []byte("other")
+	_, err = Decrypt(other, "secret")
+	require.EqualError(t, err, "wrong passphrase or corrupted key")
+
+	// The first version of the format does not authenticate the public key.
+	params, err = ks.encrypt([]byte("private"), nil, "secret")
+	require.NoError(t, err)
+
+	privkey, err = Decrypt(Entry{Version: 1, PublicKey: []byte("public"), Crypto: params}, "secret")
+	require.NoError(t, err)
+	require.Equal(t, []byte("private"), privkey)
+
+	entry.Crypto.Ciphertext[0] ^= 1
+	_, err = Decrypt(entry, "secret")
+	require.EqualError(t, err, "wrong passphrase or corrupted key")
+
+	_, err = Decrypt(Entry{Crypto: Params{KDF: "pbkdf2"}}, "secret")
+	require.EqualError(t, err, "unknown kdf 'pbkdf2'")
+
+	_, err = Decrypt(Entry{Crypto: Params{KDF: "scrypt", Cipher: "aes-128-ctr"}}, "secret")
+	require.EqualError(t, err, "unknown cipher 'aes-128-ctr'")
+
+	_, err = Decrypt(Entry{Crypto: Params{KDF: "scrypt", Cipher: "aes-256-gcm"}}, "secret")
+	require.EqualError(t, err,
+		"failed to derive key: scrypt: N must be > 1 and a power of 2")
+
+	entry.Crypto.Nonce = entry.Crypto.Nonce[1:]
+	_, err = Decrypt(entry, "secret")
+	require.EqualError(t, err, "invalid nonce size 11")
+}
+
+func TestReadPassphrase(t *testing.T) {
+	dir := makeDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(path, []byte("my secret\r\n"), 0600))
+
+	t.Setenv(EnvPassphrase, "env secret")
+
+	passphrase, err := ReadPassphrase(path, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "my secret", passphrase)
+
+	_, err = ReadPassphrase(filepath.Join(dir, "unknown"), nil, nil)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read file: ", err.Error())
+
+	passphrase, err = ReadPassphrase("", strings.NewReader("input secret\n"), nil)
+	require.NoError(t, err)
+	require.Equal(t, "env secret", passphrase)
+
+	t.Setenv(EnvPassphrase, "")
+
+	out := new(bytes.Buffer)
+
+	passphrase, err = ReadPassphrase("", strings.NewReader("input secret\nnext"), out)
+	require.NoError(t, err)
+	require.Equal(t, "input secret", passphrase)
+	require.Equal(t, "Passphrase: ", out.String())
+
+	_, err = ReadPassphrase("", strings.NewReader("\n"), out)
+	require.EqualError(t, err, "empty passphrase")
+
+	_, err = ReadPassphrase("", badRandom{}, out)
+	require.EqualError(t, err, fake.Err("failed to read input"))
+
+	_, err = ReadPassphrase("", nil, nil)
+	require.EqualError(t, err, "no passphrase file and DELA_PASSPHRASE is not set")
+}
+
+func TestReadEntry(t *testing.T) {
+	data, err := json.Marshal(Entry{Version: Version, Name: "alice"})
+	require.NoError(t, err)
+
+	entry, err := ReadEntry(data)
+	require.NoError(t, err)
+	require.Equal(t, "alice", entry.Name)
+
+	_, err = ReadEntry([]byte("{}"))
+	require.EqualError(t, err, "unsupported version 0")
+
+	_, err = ReadEntry([]byte(`{"Version":3}`))
+	require.EqualError(t, err, "unsupported version 3")
+
+	_, err = ReadEntry(nil)
+	require.EqualError(t, err,
+		"failed to decode: unexpected end of JSON input")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeDir(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "dela-keystore-")
+	require.NoError(t, err)
+
+	return dir
+}
+
+// makeKeystore returns a keystore with cheap scrypt parameters for the tests.
+func makeKeystore(dir string) Keystore {
+	return NewKeystore(dir, WithScrypt(1<<4, 8, 1))
+}
+
+type badRandom struct {
+	counter *fake.Counter
+}
+
+func (r badRandom) Read(buffer []byte) (int, error) {
+	if r.counter.Done() {
+		return 0, fake.GetError()
+	}
+
+	r.counter.Decrease()
+
+	return len(buffer), nil
+}
@@ -0,0 +1,178 @@
+// Package merkle implements a binary Merkle tree over an ordered list of
+// leaves, with inclusion proofs for a single leaf.
+//
+// Leaves and inner nodes are hashed with a different prefix so that an inner
+// node can't be presented as a leaf. When a level has an odd number of nodes,
+// the last one is promoted to the next level as is. The root commits to the
+// number of leaves, so that a proof can't claim another size of tree.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+	rootPrefix = 0x02
+)
+
+// Tree is a Merkle tree built from a list of leaves.
+type Tree struct {
+	// levels contains the hashes of each level, from the leaves to the root.
+	levels [][][]byte
+}
+
+// NewTree returns the tree of the given leaves.
+func NewTree(leaves [][]byte) *Tree {
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = hashLeaf(leaf)
+	}
+
+	levels := [][][]byte{level}
+
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+
+			next = append(next, hashNode(level[i], level[i+1]))
+		}
+
+		levels = append(levels, next)
+		level = next
+	}
+
+	return &Tree{levels: levels}
+}
+
+// Len returns the number of leaves.
+func (t *Tree) Len() int {
+	return len(t.levels[0])
+}
+
+// Root returns the root of the tree, which is the hash of the number of leaves
+// and the top node. The root of an empty tree only commits to its size.
+func (t *Tree) Root() []byte {
+	var top []byte
+
+	last := t.levels[len(t.levels)-1]
+	if len(last) > 0 {
+		top = last[0]
+	}
+
+	return hashRoot(t.Len(), top)
+}
+
+// Proof returns the inclusion proof of the leaf at the given index.
+func (t *Tree) Proof(index int) (Proof, error) {
+	if index < 0 || index >= t.Len() {
+		return Proof{}, xerrors.Errorf("index %d out of range [0, %d)", index, t.Len())
+	}
+
+	proof := Proof{Index: index}
+
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+
+		// A node without sibling is promoted and doesn't need any hash.
+		if sibling < len(level) {
+			proof.Path = append(proof.Path, level[sibling])
+		}
+
+		index /= 2
+	}
+
+	proof.Leaves = t.Len()
+
+	return proof, nil
+}
+
+// Proof is the list of hashes needed to recompute the root from a leaf.
+type Proof struct {
+	Index  int
+	Leaves int
+	Path   [][]byte
+}
+
+// Verify returns nil if the proof shows that the leaf is part of the tree with
+// the given root. The number of leaves of the proof is checked by the root.
+func (p Proof) Verify(root, leaf []byte) error {
+	if p.Index < 0 || p.Index >= p.Leaves {
+		return xerrors.Errorf("index %d out of range [0, %d)", p.Index, p.Leaves)
+	}
+
+	curr := hashLeaf(leaf)
+	path := p.Path
+	index := p.Index
+
+	for size := p.Leaves; size > 1; size = (size + 1) / 2 {
+		sibling := index ^ 1
+
+		if sibling < size {
+			if len(path) == 0 {
+				return xerrors.New("proof is too short")
+			}
+
+			if index%2 == 0 {
+				curr = hashNode(curr, path[0])
+			} else {
+				curr = hashNode(path[0], curr)
+			}
+
+			path = path[1:]
+		}
+
+		index /= 2
+	}
+
+	if len(path) > 0 {
+		return xerrors.New("proof is too long")
+	}
+
+	curr = hashRoot(p.Leaves, curr)
+
+	if !bytes.Equal(curr, root) {
+		return xerrors.Errorf("mismatch root: %#x != %#x", curr, root)
+	}
+
+	return nil
+}
+
+func hashLeaf(leaf []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(leaf)
+
+	return h.Sum(nil)
+}
+
+func hashNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+
+	return h.Sum(nil)
+}
+
+func hashRoot(size int, top []byte) []byte {
+	buffer := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buffer, uint64(size))
+
+	h := sha256.New()
+	h.Write([]byte{rootPrefix})
+	h.Write(buffer)
+	h.Write(top)
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,101 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_Proof(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		leaves := makeLeaves(n)
+		tree := NewTree(leaves)
+
+		require.Equal(t, n, tree.Len())
+
+		for i, leaf := range leaves {
+			proof, err := tree.Proof(i)
+			require.NoError(t, err)
+
+			require.NoError(t, proof.Verify(tree.Root(), leaf), "leaf %d of %d", i, n)
+		}
+	}
+}
+
+func TestTree_Root(t *testing.T) {
+	empty := NewTree(nil)
+	require.Equal(t, 0, empty.Len())
+	require.Len(t, empty.Root(), 32)
+
+	a := NewTree(makeLeaves(3))
+	b := NewTree(makeLeaves(3))
+	require.Equal(t, a.Root(), b.Root())
+
+	// The order of the leaves is committed.
+	leaves := makeLeaves(3)
+	leaves[0], leaves[1] = leaves[1], leaves[0]
+	require.NotEqual(t, a.Root(), NewTree(leaves).Root())
+
+	// A single leaf is not its own root.
+	require.NotEqual(t, []byte("leaf0"), NewTree(makeLeaves(1)).Root())
+	require.NotEqual(t, hashLeaf([]byte("leaf0")), NewTree(makeLeaves(1)).Root())
+
+	_, err := a.Proof(3)
+	require.EqualError(t, err, "index 3 out of range [0, 3)")
+}
+
+func TestProof_Verify(t *testing.T) {
+	leaves := makeLeaves(5)
+	tree := NewTree(leaves)
+
+	proof, err := tree.Proof(2)
+	require.NoError(t, err)
+
+	err = proof.Verify(tree.Root(), []byte("leaf3"))
+	require.Error(t, err)
+	require.Regexp(t, "^mismatch root: ", err.Error())
+
+	bad := proof
+	bad.Path = bad.Path[:1]
+	require.EqualError(t, bad.Verify(tree.Root(), leaves[2]), "proof is too short")
+
+	bad = proof
+	bad.Path = append(append([][]byte{}, bad.Path...), []byte{1})
+	require.EqualError(t, bad.Verify(tree.Root(), leaves[2]), "proof is too long")
+
+	bad = proof
+	bad.Index = 5
+	require.EqualError(t, bad.Verify(tree.Root(), leaves[2]), "index 5 out of range [0, 5)")
+
+	// A tree of 6 or 8 leaves has the same shape along the path of the first
+	// leaf, but the root commits to the number of leaves.
+	proof, err = tree.Proof(0)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify(tree.Root(), leaves[0]))
+
+	for _, size := range []int{6, 8} {
+		bad = proof
+		bad.Leaves = size
+
+		err = bad.Verify(tree.Root(), leaves[0])
+		require.Error(t, err)
+		require.Regexp(t, "^mismatch root: ", err.Error())
+	}
+
+	// An inner node can't be presented as a leaf of a smaller tree.
+	inner := hashNode(hashLeaf(leaves[0]), hashLeaf(leaves[1]))
+	require.NotEqual(t, inner, hashLeaf(inner))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprintf("leaf%d", i))
+	}
+
+	return leaves
+}
@@ -1,10 +1,21 @@
 package dkg
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
 	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
 )
 
+// infoDomain separates the digest of the information from other messages
+// signed by the same key.
+const infoDomain = "dela-dkg-info"
+
 // DKG defines the primitive to start a DKG protocol
 type DKG interface {
 	// Listen starts the RPC. This function should be called on each node that
@@ -26,5 +37,171 @@ type Actor interface {
 	Sign(msg []byte) ([]byte, error)
 	Verify(msg, sig []byte) error
 
+	// GetReleasedKey returns the key previously released for the label by
+	// Sign. Returns an error if the key has not been released.
+	GetReleasedKey(label []byte) ([]byte, error)
+
+	// GetKeys returns the keys of the labels, in the same order. The keys that
+	// are not yet released are requested in batches rather than one by one.
+	GetKeys(labels [][]byte) ([][]byte, error)
+
+	// ReleaseBlock returns the keys of the labels of the transactions of the
+	// block at the height, recovered in a single extraction so that no
+	// transaction of the block is decrypted before the others.
+	ReleaseBlock(height uint64, labels [][]byte) (BlockRelease, error)
+
+	// Prune deletes the released keys that expired according to the retention
+	// policy of the node, when the chain is at the height. It returns the
+	// number of deleted keys.
+	Prune(height uint64) (int, error)
+
 	Reshare(co crypto.CollectiveAuthority, newThreshold int) error
+
+	// Resume restores the outcome of the last setup that was persisted by the
+	// node, so that it does not need a new setup after a restart. Returns an
+	// error if there is nothing to resume.
+	Resume() (pubKey kyber.Point, err error)
+
+	// GetInfo returns the description of the committee, signed by the node.
+	// Returns an error if the setup has not been done.
+	GetInfo() (Info, error)
+
+	// GetReleases returns the history of the keys released by the node that
+	// match the filter, from the oldest to the newest.
+	GetReleases(filter ReleaseFilter) ([]Release, error)
+}
+
+// Release is an entry of the audit log of the keys released by a node.
+type Release struct {
+	// Label is the label of the released key.
+	Label []byte
+
+	// Requester is the address of the node that recovered the key.
+	Requester string
+
+	// Contributors are the addresses of the participants whose share was used
+	// to recover the key.
+	Contributors []string
+
+	// Time is when the key was recovered.
+	Time time.Time
+}
+
+// BlockRelease is the release of the keys of the transactions of a block.
+type BlockRelease struct {
+	// Height is the height of the block.
+	Height uint64
+
+	// Labels and Keys are the labels of the transactions and their key, in
+	// the same order.
+	Labels [][]byte
+	Keys   [][]byte
+
+	// Aggregate is the aggregated signature of the keys, which proves the
+	// release of the whole block at once.
+	Aggregate []byte
+}
+
+// ReleaseFilter selects the entries of the audit log. A zero field matches
+// every entry.
+type ReleaseFilter struct {
+	// Label only keeps the releases of the label.
+	Label []byte
+
+	// Since and Until only keep the releases within the time range, bounds
+	// included.
+	Since time.Time
+	Until time.Time
+}
+
+// Match returns true if the release is selected by the filter.
+func (f ReleaseFilter) Match(release Release) bool {
+	if f.Label != nil && !bytes.Equal(f.Label, release.Label) {
+		return false
+	}
+
+	if !f.Since.IsZero() && release.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && release.Time.After(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Info describes the committee of a DKG and the parameters of the scheme, so
+// that a client can check who holds the shares before encrypting.
+type Info struct {
+	// Participants and PublicKeys are the members of the committee and their
+	// long-term key, in the same order.
+	Participants []mino.Address
+	PublicKeys   []kyber.Point
+
+	// Threshold is the number of members required to release a key.
+	Threshold int
+
+	// PublicKey is the collective public key.
+	PublicKey kyber.Point
+
+	// Scheme is the name of the scheme and of its parameters.
+	Scheme string
+
+	// Node is the long-term key of the node that signed the information.
+	Node kyber.Point
+
+	// Signature is the signature of the digest by the node.
+	Signature []byte
+}
+
+// Digest returns the digest of the information that is signed by the node.
+func (i Info) Digest() ([]byte, error) {
+	h := sha256.New()
+	h.Write([]byte(infoDomain))
+
+	write := func(data []byte) {
+		size := make([]byte, 8)
+		binary.BigEndian.PutUint64(size, uint64(len(data)))
+		h.Write(size)
+		h.Write(data)
+	}
+
+	if len(i.Participants) != len(i.PublicKeys) {
+		return nil, xerrors.Errorf("mismatch participants and keys: %d != %d",
+			len(i.Participants), len(i.PublicKeys))
+	}
+
+	for k, addr := range i.Participants {
+		data, err := addr.MarshalText()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal address: %v", err)
+		}
+
+		write(data)
+
+		data, err = i.PublicKeys[k].MarshalBinary()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal public key: %v", err)
+		}
+
+		write(data)
+	}
+
+	threshold := make([]byte, 8)
+	binary.BigEndian.PutUint64(threshold, uint64(i.Threshold))
+	write(threshold)
+
+	for _, point := range []kyber.Point{i.PublicKey, i.Node} {
+		data, err := point.MarshalBinary()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal point: %v", err)
+		}
+
+		write(data)
+	}
+
+	write([]byte(i.Scheme))
+
+	return h.Sum(nil), nil
 }
@@ -0,0 +1,439 @@
+// Package attack simulates an observer that tries to front-run the pending
+// transactions, in order to measure the protection of F3B empirically.
+//
+// The transactions are ordered by a cosipbft ledger and the observer watches
+// the pool of a node, so that it sees every transaction before it is
+// finalized. In the baseline mode, the payloads are in clear. In the F3B mode,
+// the payloads are encrypted to their label and the observer asks a DKG
+// committee for the key as soon as it sees the transaction. The honest members
+// refuse to give their share before the block of the transaction is
+// finalized, while the colluding ones give it. A front-run succeeds only if
+// the payload is recovered before the transaction is finalized.
+//
+// The report is meant to be stored in JSON to compare several configurations.
+package attack
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/txn/pool"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/dkg"
+	pedersen "go.dedis.ch/dela/dkg/pedersen_bn256"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/bench"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/minoch"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"golang.org/x/xerrors"
+)
+
+// errRefused is the error of a key that the committee refused to release to
+// the observer.
+var errRefused = xerrors.New("key refused")
+
+// Config is the configuration of a simulation.
+type Config struct {
+	Mode bench.Mode
+
+	// Nodes is the number of members of the committee.
+	Nodes int
+
+	// Threshold is the number of shares needed to release a key. It defaults to
+	// Nodes when it is zero.
+	Threshold int
+
+	// Colluding is the number of members that give their shares to the
+	// observer before the finalization.
+	Colluding int
+
+	// Transactions is the number of pending transactions that the observer
+	// tries to front-run.
+	Transactions int
+
+	// PayloadSize is the size in bytes of the payload of a transaction.
+	PayloadSize int
+}
+
+// Report is the result of a simulation.
+type Report struct {
+	Mode         bench.Mode `json:"mode"`
+	Nodes        int        `json:"nodes"`
+	Threshold    int        `json:"threshold"`
+	Colluding    int        `json:"colluding"`
+	Transactions int        `json:"transactions"`
+
+	// FrontRuns is the number of transactions whose payload was recovered by
+	// the observer before the finalization.
+	FrontRuns   int     `json:"frontRuns"`
+	SuccessRate float64 `json:"successRate"`
+
+	// Refused is the number of transactions whose key the committee refused
+	// to release to the observer. The others were either front-run, or asked
+	// for after the finalization.
+	Refused int `json:"refused"`
+}
+
+// WriteJSON writes the report in JSON to the writer.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(r)
+	if err != nil {
+		return xerrors.Errorf("failed to encode report: %v", err)
+	}
+
+	return nil
+}
+
+// Run submits the transactions to a ledger whose pool is watched by the
+// observer, and counts the ones that it front-runs.
+func Run(cfg Config) (Report, error) {
+	if cfg.Nodes <= 0 || cfg.Transactions <= 0 {
+		return Report{}, xerrors.Errorf("invalid configuration: %d nodes, %d transactions",
+			cfg.Nodes, cfg.Transactions)
+	}
+
+	if cfg.Threshold == 0 {
+		cfg.Threshold = cfg.Nodes
+	}
+
+	if cfg.Colluding < 0 || cfg.Colluding > cfg.Nodes {
+		return Report{}, xerrors.Errorf("invalid number of colluding nodes: %d",
+			cfg.Colluding)
+	}
+
+	if cfg.Mode != bench.Baseline && cfg.Mode != bench.F3B {
+		return Report{}, xerrors.Errorf("unknown mode '%s'", cfg.Mode)
+	}
+
+	ledger, err := bench.NewLedger(cfg.Nodes)
+	if err != nil {
+		return Report{}, xerrors.Errorf("failed to create ledger: %v", err)
+	}
+
+	defer ledger.Close()
+
+	var seal func(height uint64, index int, payload []byte) (txn.Transaction, error)
+	var open func(tx txn.Transaction) ([]byte, error)
+
+	switch cfg.Mode {
+	case bench.Baseline:
+		seal = func(_ uint64, _ int, payload []byte) (txn.Transaction, error) {
+			return bench.NewPayloadTx(payload)
+		}
+
+		// The payload is read from the pending transaction.
+		open = func(tx txn.Transaction) ([]byte, error) {
+			return tx.GetArg(bench.PayloadArg), nil
+		}
+	case bench.F3B:
+		c, err := newCommittee(cfg.Nodes, cfg.Threshold, cfg.Colluding, ledger.Events())
+		if err != nil {
+			return Report{}, xerrors.Errorf("failed to create committee: %v", err)
+		}
+
+		seal = c.seal
+		open = func(tx txn.Transaction) ([]byte, error) {
+			return c.open(tx.GetArg(types.CiphertextArg))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The pool is watched before the first submission, so that the observer
+	// does not miss any transaction.
+	pending := ledger.WatchPool(ctx, 0)
+
+	obs := newObserver(open)
+	done := make(chan struct{})
+
+	go func() {
+		obs.Listen(pending)
+		close(done)
+	}()
+
+	frontRuns, err := submit(ledger, obs, cfg, seal)
+
+	cancel()
+	<-done
+
+	if err != nil {
+		return Report{}, err
+	}
+
+	err = obs.Err()
+	if err != nil {
+		return Report{}, xerrors.Errorf("observer failed: %v", err)
+	}
+
+	report := Report{
+		Mode:         cfg.Mode,
+		Nodes:        cfg.Nodes,
+		Threshold:    cfg.Threshold,
+		Colluding:    cfg.Colluding,
+		Transactions: cfg.Transactions,
+		FrontRuns:    frontRuns,
+		SuccessRate:  float64(frontRuns) / float64(cfg.Transactions),
+		Refused:      obs.Refused(),
+	}
+
+	return report, nil
+}
+
+// submit submits the transactions one after the other and returns the number
+// of them whose payload the observer recovered before the finalization.
+func submit(ledger *bench.Ledger, obs *observer, cfg Config,
+	seal func(uint64, int, []byte) (txn.Transaction, error)) (int, error) {
+
+	frontRuns := 0
+
+	for i := 0; i < cfg.Transactions; i++ {
+		payload := make([]byte, cfg.PayloadSize)
+
+		_, err := rand.Read(payload)
+		if err != nil {
+			return 0, xerrors.Errorf("failed to generate payload: %v", err)
+		}
+
+		// The transaction is included at the earliest in the next block.
+		tx, err := seal(ledger.Height(), i, payload)
+		if err != nil {
+			return 0, xerrors.Errorf("transaction %d: %v", i, err)
+		}
+
+		err = ledger.Submit(0, tx)
+		if err != nil {
+			return 0, xerrors.Errorf("transaction %d: %v", i, err)
+		}
+
+		// The transaction is finalized once Submit returns, so that a payload
+		// recovered later does not count.
+		recovered, found := obs.Recovered(tx.GetID())
+		if found && bytes.Equal(recovered, payload) {
+			frontRuns++
+		}
+	}
+
+	return frontRuns, nil
+}
+
+// observer watches the pool of a node and tries to recover the payload of
+// every transaction that enters it.
+type observer struct {
+	sync.Mutex
+
+	open      func(txn.Transaction) ([]byte, error)
+	recovered map[string][]byte
+	refused   int
+	err       error
+}
+
+func newObserver(open func(txn.Transaction) ([]byte, error)) *observer {
+	return &observer{
+		open:      open,
+		recovered: make(map[string][]byte),
+	}
+}
+
+// Listen tries to recover the payload of the transactions added to the pool
+// until the channel is closed.
+func (o *observer) Listen(pending <-chan pool.Event) {
+	for evt := range pending {
+		if evt.Type != pool.TxAdded {
+			continue
+		}
+
+		payload, err := o.open(evt.Tx)
+
+		o.Lock()
+
+		switch {
+		case xerrors.Is(err, errRefused):
+			o.refused++
+		case err != nil && o.err == nil:
+			o.err = err
+		case err == nil:
+			o.recovered[string(evt.Tx.GetID())] = payload
+		}
+
+		o.Unlock()
+	}
+}
+
+// Recovered returns the payload of the transaction if the observer has
+// recovered it.
+func (o *observer) Recovered(id []byte) ([]byte, bool) {
+	o.Lock()
+	defer o.Unlock()
+
+	payload, found := o.recovered[string(id)]
+
+	return payload, found
+}
+
+// Refused returns the number of transactions whose key was refused.
+func (o *observer) Refused() int {
+	o.Lock()
+	defer o.Unlock()
+
+	return o.refused
+}
+
+// Err returns the first error of the observer, if any.
+func (o *observer) Err() error {
+	o.Lock()
+	defer o.Unlock()
+
+	return o.err
+}
+
+// committee is a DKG of in-process nodes. The honest members learn the
+// finalized blocks from the ledger, so that they refuse to give their share of
+// the label of a pending transaction. The colluding members are told that
+// every block is finalized, so that they give their share of any label.
+type committee struct {
+	suite    *bn256.Suite
+	pubKey   kyber.Point
+	actors   []dkg.Actor
+	observer dkg.Actor
+}
+
+// newCommittee runs a DKG of n nodes where the first colluding ones give their
+// share of any label. The observer asks for the keys from the first node.
+func newCommittee(n, threshold, colluding int, chain events.Service) (committee, error) {
+	manager := minoch.NewManager()
+
+	forged := events.NewBus()
+
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]crypto.PublicKey, n)
+	actors := make([]dkg.Actor, n)
+
+	for i := range actors {
+		m, err := minoch.NewMinoch(manager, fmt.Sprintf("member%d", i))
+		if err != nil {
+			return committee{}, xerrors.Errorf("failed to create mino: %v", err)
+		}
+
+		bus := chain
+		if i < colluding {
+			bus = forged
+		}
+
+		d, pubkey := pedersen.NewPedersen(m, pedersen.WithEvents(bus))
+
+		actors[i], err = d.Listen()
+		if err != nil {
+			return committee{}, xerrors.Errorf("failed to listen: %v", err)
+		}
+
+		addrs[i] = m.GetAddress()
+		pubkeys[i] = bls.NewPublicKeyFromPoint(pubkey)
+	}
+
+	pubKey, err := actors[0].Setup(authority.New(addrs, pubkeys), threshold)
+	if err != nil {
+		return committee{}, xerrors.Errorf("failed to run the DKG: %v", err)
+	}
+
+	forged.Publish(events.BlockFinalized{Index: math.MaxUint64})
+
+	c := committee{
+		suite:    bn256.NewSuiteG2(),
+		pubKey:   pubKey,
+		actors:   actors,
+		observer: actors[0],
+	}
+
+	return c, nil
+}
+
+// seal encrypts the payload to a new label of the block at the height like a
+// client does, and returns a transaction with the ciphertext. The label is
+// public, so it is stored in front of the ciphertext.
+func (c committee) seal(height uint64, index int, payload []byte) (txn.Transaction, error) {
+	nonce := make([]byte, 32)
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to generate nonce: %v", err)
+	}
+
+	label := ibe.NewTxLabel(height, uint64(index), nonce)
+
+	ek, err := ibe.DeriveEncryptionKeyOnG2(c.suite, c.pubKey, label)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive encryption key: %v", err)
+	}
+
+	ct, err := ibe.EncryptCPAonG2(c.suite, ek, payload)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encrypt: %v", err)
+	}
+
+	data, err := ct.Serialize(c.suite)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize ciphertext: %v", err)
+	}
+
+	value := make([]byte, 2, 2+len(label)+len(data))
+	binary.LittleEndian.PutUint16(value, uint16(len(label)))
+	value = append(value, label...)
+	value = append(value, data...)
+
+	return bench.NewCiphertextTx(value)
+}
+
+// open asks the committee for the key of the label of a transaction, through
+// the node of the observer, and decrypts the ciphertext. It returns
+// errRefused when the committee does not release the key.
+func (c committee) open(value []byte) ([]byte, error) {
+	if len(value) < 2 || len(value) < 2+int(binary.LittleEndian.Uint16(value)) {
+		return nil, xerrors.Errorf("malformed ciphertext: %d bytes", len(value))
+	}
+
+	size := 2 + int(binary.LittleEndian.Uint16(value))
+	label := value[2:size]
+
+	ct := new(ibe.CiphertextCPA)
+
+	err := ct.Deserialize(c.suite, value[size:])
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize ciphertext: %v", err)
+	}
+
+	key, err := c.observer.Sign(label)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get key: %v: %w", err, errRefused)
+	}
+
+	dk := c.suite.G1().Point()
+
+	err = dk.UnmarshalBinary(key)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal key: %v", err)
+	}
+
+	msg, err := ibe.DecryptCPAonG2(c.suite, dk, ct)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decrypt: %v", err)
+	}
+
+	return msg, nil
+}
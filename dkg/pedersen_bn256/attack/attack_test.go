@@ -0,0 +1,125 @@
+package attack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/bench"
+	"golang.org/x/xerrors"
+)
+
+func TestRun_Baseline(t *testing.T) {
+	report, err := Run(Config{
+		Mode:         bench.Baseline,
+		Nodes:        3,
+		Transactions: 10,
+		PayloadSize:  32,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, report.Threshold)
+	require.Equal(t, 10, report.FrontRuns)
+	require.Equal(t, 1.0, report.SuccessRate)
+}
+
+func TestRun_F3B(t *testing.T) {
+	cfg := Config{
+		Mode:         bench.F3B,
+		Nodes:        4,
+		Threshold:    3,
+		Transactions: 5,
+		PayloadSize:  32,
+	}
+
+	// The honest members refuse the requests of the observer, unless it asks
+	// after the finalization.
+	for colluding := 0; colluding < cfg.Threshold; colluding++ {
+		cfg.Colluding = colluding
+
+		report, err := Run(cfg)
+		require.NoError(t, err)
+		require.Equal(t, 0, report.FrontRuns, "colluding: %d", colluding)
+		require.Greater(t, report.Refused, 0, "colluding: %d", colluding)
+	}
+
+	cfg.Colluding = cfg.Threshold
+
+	report, err := Run(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 5, report.FrontRuns)
+	require.Equal(t, 1.0, report.SuccessRate)
+	require.Equal(t, 0, report.Refused)
+
+	buf := new(bytes.Buffer)
+	err = report.WriteJSON(buf)
+	require.NoError(t, err)
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, report, decoded)
+}
+
+func TestRun_BadConfig(t *testing.T) {
+	_, err := Run(Config{Mode: bench.F3B})
+	require.EqualError(t, err, "invalid configuration: 0 nodes, 0 transactions")
+
+	_, err = Run(Config{Mode: bench.F3B, Nodes: 1, Transactions: 1, Colluding: 2})
+	require.EqualError(t, err, "invalid number of colluding nodes: 2")
+
+	_, err = Run(Config{Mode: "unknown", Nodes: 1, Transactions: 1})
+	require.EqualError(t, err, "unknown mode 'unknown'")
+}
+
+func TestCommittee_Open(t *testing.T) {
+	chain := events.NewBus()
+
+	c, err := newCommittee(3, 2, 1, chain)
+	require.NoError(t, err)
+
+	payload := []byte("payload")
+
+	tx, err := c.seal(5, 0, payload)
+	require.NoError(t, err)
+
+	value := tx.GetArg(types.CiphertextArg)
+	label := value[2 : 2+binary.LittleEndian.Uint16(value)]
+
+	_, err = c.open(value)
+	require.True(t, xerrors.Is(err, errRefused), err)
+
+	// Only the colluding member gives its share, whoever asks for the key.
+	for _, actor := range c.actors {
+		_, err = actor.Sign(label)
+		require.EqualError(t, err, "not enough valid shares: 1 < 2")
+	}
+
+	// The honest members release the key once the block is finalized.
+	require.Eventually(t, func() bool {
+		chain.Publish(events.BlockFinalized{Index: 5})
+
+		msg, err := c.open(value)
+		return err == nil && bytes.Equal(payload, msg)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	_, err = c.open([]byte{0xff, 0})
+	require.EqualError(t, err, "malformed ciphertext: 2 bytes")
+}
+
+func TestCommittee_Open_Colluding(t *testing.T) {
+	c, err := newCommittee(3, 2, 2, events.NewBus())
+	require.NoError(t, err)
+
+	payload := []byte("payload")
+
+	tx, err := c.seal(5, 0, payload)
+	require.NoError(t, err)
+
+	msg, err := c.open(tx.GetArg(types.CiphertextArg))
+	require.NoError(t, err)
+	require.Equal(t, payload, msg)
+}
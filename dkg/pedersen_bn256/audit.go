@@ -0,0 +1,134 @@
+package pedersen
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/dkg"
+	"golang.org/x/xerrors"
+)
+
+// auditBucket is the name of the bucket where the audit log is persisted.
+var auditBucket = []byte("dkg-audit")
+
+// auditLog is the append-only history of the keys released by the node. The
+// entries are persisted in order when a database is provided, otherwise they
+// are only kept in memory.
+type auditLog struct {
+	sync.Mutex
+
+	db       kv.DB
+	next     uint64
+	loaded   bool
+	releases []dkg.Release
+}
+
+// newAuditLog returns a new audit log. The database is optional.
+func newAuditLog(db kv.DB) *auditLog {
+	return &auditLog{
+		db: db,
+	}
+}
+
+// Append adds the release at the end of the log.
+func (l *auditLog) Append(release dkg.Release) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.db == nil {
+		l.releases = append(l.releases, release)
+		return nil
+	}
+
+	data, err := json.Marshal(release)
+	if err != nil {
+		return xerrors.Errorf("failed to encode release: %v", err)
+	}
+
+	next := l.next
+
+	err = l.db.Update(func(tx kv.WritableTx) error {
+		bucket, err := tx.GetBucketOrCreate(auditBucket)
+		if err != nil {
+			return xerrors.Errorf("while getting bucket: %v", err)
+		}
+
+		// The entries of a previous run are counted once so that the new ones
+		// are appended after them.
+		if !l.loaded {
+			err = bucket.ForEach(func(key, value []byte) error {
+				next++
+				return nil
+			})
+			if err != nil {
+				return xerrors.Errorf("while counting: %v", err)
+			}
+		}
+
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, next)
+
+		err = bucket.Set(key, data)
+		if err != nil {
+			return xerrors.Errorf("while writing: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("while updating db: %v", err)
+	}
+
+	l.loaded = true
+	l.next = next + 1
+
+	return nil
+}
+
+// Query returns the releases that match the filter, in the order they were
+// appended.
+func (l *auditLog) Query(filter dkg.ReleaseFilter) ([]dkg.Release, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	releases := []dkg.Release{}
+
+	if l.db == nil {
+		for _, release := range l.releases {
+			if filter.Match(release) {
+				releases = append(releases, release)
+			}
+		}
+
+		return releases, nil
+	}
+
+	err := l.db.View(func(tx kv.ReadableTx) error {
+		bucket := tx.GetBucket(auditBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(key, value []byte) error {
+			var release dkg.Release
+
+			err := json.Unmarshal(value, &release)
+			if err != nil {
+				return xerrors.Errorf("failed to decode release: %v", err)
+			}
+
+			if filter.Match(release) {
+				releases = append(releases, release)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("while reading db: %v", err)
+	}
+
+	return releases, nil
+}
@@ -0,0 +1,98 @@
+package pedersen
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestAuditLog_Query(t *testing.T) {
+	now := time.Unix(1000, 0).UTC()
+
+	releases := []dkg.Release{
+		{Label: []byte("A"), Requester: "0", Contributors: []string{"1"}, Time: now},
+		{Label: []byte("B"), Requester: "0", Contributors: []string{}, Time: now.Add(time.Second)},
+		{Label: []byte("A"), Requester: "1", Contributors: []string{"2"}, Time: now.Add(2 * time.Second)},
+	}
+
+	db, err := kv.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	for _, log := range []*auditLog{newAuditLog(nil), newAuditLog(db)} {
+		found, err := log.Query(dkg.ReleaseFilter{})
+		require.NoError(t, err)
+		require.Empty(t, found)
+
+		for _, release := range releases {
+			require.NoError(t, log.Append(release))
+		}
+
+		found, err = log.Query(dkg.ReleaseFilter{})
+		require.NoError(t, err)
+		require.Equal(t, releases, found)
+
+		found, err = log.Query(dkg.ReleaseFilter{Label: []byte("A")})
+		require.NoError(t, err)
+		require.Equal(t, []dkg.Release{releases[0], releases[2]}, found)
+
+		found, err = log.Query(dkg.ReleaseFilter{
+			Since: now.Add(time.Second),
+			Until: now.Add(time.Second),
+		})
+		require.NoError(t, err)
+		require.Equal(t, releases[1:2], found)
+	}
+
+	// A new log appends after the entries of the previous one.
+	log := newAuditLog(db)
+	require.NoError(t, log.Append(releases[0]))
+
+	found, err := log.Query(dkg.ReleaseFilter{})
+	require.NoError(t, err)
+	require.Equal(t, append(releases, releases[0]), found)
+}
+
+func TestAuditLog_BadDB_Append(t *testing.T) {
+	log := newAuditLog(fake.NewBadDB())
+
+	err := log.Append(dkg.Release{})
+	require.EqualError(t, err, fake.Err("while updating db: while getting bucket"))
+
+	db := fake.NewInMemoryDB()
+	db.SetBucket(auditBucket, fake.NewBadWriteBucket())
+
+	log = newAuditLog(db)
+
+	err = log.Append(dkg.Release{})
+	require.EqualError(t, err, fake.Err("while updating db: while writing"))
+
+	// The log is counted again after a failure.
+	db.SetBucket(auditBucket, fake.NewBucket())
+
+	require.NoError(t, log.Append(dkg.Release{}))
+	require.Equal(t, uint64(1), log.next)
+}
+
+func TestAuditLog_BadDB_Query(t *testing.T) {
+	log := newAuditLog(fake.NewBadViewDB())
+
+	_, err := log.Query(dkg.ReleaseFilter{})
+	require.EqualError(t, err, fake.Err("while reading db"))
+
+	bucket := fake.NewBucket()
+	require.NoError(t, bucket.Set([]byte{0}, []byte("{")))
+
+	db := fake.NewInMemoryDB()
+	db.SetBucket(auditBucket, bucket)
+
+	_, err = newAuditLog(db).Query(dkg.ReleaseFilter{})
+	require.Error(t, err)
+	require.Regexp(t, "^while reading db: failed to decode release: ", err.Error())
+}
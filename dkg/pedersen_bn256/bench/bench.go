@@ -0,0 +1,413 @@
+// Package bench implements a reproducible harness to measure the overhead of
+// F3B. It runs a ledger of n in-process nodes ordered by cosipbft, and submits
+// transactions at a given rate. Each transaction waits to be accepted in a
+// block. The baseline mode submits the payloads in clear. In the F3B mode,
+// every payload is encrypted to its own transaction label of the next block
+// before the submission, then the key of the label is released by a DKG of n
+// nodes once the block is finalized, and the payload is decrypted.
+//
+// The report is meant to be stored in JSON to compare several runs.
+package bench
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/dkg"
+	pedersen "go.dedis.ch/dela/dkg/pedersen_bn256"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/minoch"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"golang.org/x/xerrors"
+)
+
+// Mode is the kind of transactions submitted by the harness.
+type Mode string
+
+const (
+	// Baseline submits the payloads in clear.
+	Baseline Mode = "baseline"
+
+	// F3B encrypts the payloads and releases their key with the DKG.
+	F3B Mode = "f3b"
+)
+
+// releaseInterval is the time to wait before asking again for a key that the
+// DKG refused, as it learns the finalized blocks asynchronously.
+const releaseInterval = 10 * time.Millisecond
+
+// Config is the configuration of a run.
+type Config struct {
+	Mode Mode
+
+	// Nodes is the number of nodes of the DKG.
+	Nodes int
+
+	// Threshold is the number of shares needed to release a key. It defaults to
+	// Nodes when it is zero.
+	Threshold int
+
+	// Transactions is the number of transactions to submit.
+	Transactions int
+
+	// Rate is the number of transactions submitted per second. The
+	// transactions are submitted one after each other when it is zero.
+	Rate float64
+
+	// PayloadSize is the size in bytes of the payload of a transaction.
+	PayloadSize int
+}
+
+// Latency summarizes the latencies of the transactions, in milliseconds.
+type Latency struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// Report is the result of a run.
+type Report struct {
+	Mode         Mode    `json:"mode"`
+	Nodes        int     `json:"nodes"`
+	Threshold    int     `json:"threshold"`
+	Transactions int     `json:"transactions"`
+	PayloadSize  int     `json:"payloadSize"`
+	Rate         float64 `json:"rate"`
+	Duration     float64 `json:"durationSeconds"`
+	Throughput   float64 `json:"throughput"`
+	Latency      Latency `json:"latencyMs"`
+
+	// Extraction summarizes the time taken to release the keys. It is only
+	// set in the F3B mode.
+	Extraction *Latency `json:"extractionMs,omitempty"`
+}
+
+// WriteJSON writes the report in JSON to the writer.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(r)
+	if err != nil {
+		return xerrors.Errorf("failed to encode report: %v", err)
+	}
+
+	return nil
+}
+
+// Run sets up the nodes and submits the transactions according to the
+// configuration.
+func Run(cfg Config) (Report, error) {
+	if cfg.Nodes <= 0 || cfg.Transactions <= 0 {
+		return Report{}, xerrors.Errorf("invalid configuration: %d nodes, %d transactions",
+			cfg.Nodes, cfg.Transactions)
+	}
+
+	if cfg.Threshold == 0 {
+		cfg.Threshold = cfg.Nodes
+	}
+
+	if cfg.Mode != Baseline && cfg.Mode != F3B {
+		return Report{}, xerrors.Errorf("unknown mode '%s'", cfg.Mode)
+	}
+
+	ledger, err := NewLedger(cfg.Nodes)
+	if err != nil {
+		return Report{}, xerrors.Errorf("failed to create ledger: %v", err)
+	}
+
+	defer ledger.Close()
+
+	// submit returns the time taken to release the key of the transaction, or
+	// zero in the baseline mode.
+	var submit func(index int, payload []byte) (time.Duration, error)
+
+	switch cfg.Mode {
+	case Baseline:
+		submit = func(index int, payload []byte) (time.Duration, error) {
+			tx, err := NewPayloadTx(payload)
+			if err != nil {
+				return 0, err
+			}
+
+			return 0, ledger.Submit(index, tx)
+		}
+	case F3B:
+		actors, err := setup(cfg.Nodes, cfg.Threshold, new(traffic), ledger.Events())
+		if err != nil {
+			return Report{}, xerrors.Errorf("failed to setup: %v", err)
+		}
+
+		submit = func(index int, payload []byte) (time.Duration, error) {
+			actor := actors[index%len(actors)]
+
+			// The transaction is included at the earliest in the next block.
+			height := ledger.Height()
+
+			label, ct, err := encrypt(actor, height, index, payload)
+			if err != nil {
+				return 0, err
+			}
+
+			tx, err := NewCiphertextTx(ct)
+			if err != nil {
+				return 0, err
+			}
+
+			err = ledger.Submit(index, tx)
+			if err != nil {
+				return 0, err
+			}
+
+			return decrypt(actor, height, label, ct, payload)
+		}
+	}
+
+	latencies := make([]time.Duration, cfg.Transactions)
+	extractions := make([]time.Duration, cfg.Transactions)
+	errs := make(chan error, cfg.Transactions)
+
+	var interval time.Duration
+	if cfg.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / cfg.Rate)
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(cfg.Transactions)
+
+	start := time.Now()
+
+	for i := 0; i < cfg.Transactions; i++ {
+		payload := make([]byte, cfg.PayloadSize)
+
+		_, err = rand.Read(payload)
+		if err != nil {
+			return Report{}, xerrors.Errorf("failed to generate payload: %v", err)
+		}
+
+		// The latency is measured from the time the transaction is scheduled,
+		// so that a saturated system shows in the latencies.
+		scheduled := start.Add(time.Duration(i) * interval)
+		time.Sleep(time.Until(scheduled))
+
+		do := func(index int) {
+			defer wg.Done()
+
+			extraction, err := submit(index, payload)
+			if err != nil {
+				errs <- xerrors.Errorf("transaction %d: %v", index, err)
+			}
+
+			latencies[index] = time.Since(scheduled)
+			extractions[index] = extraction
+		}
+
+		if interval == 0 {
+			do(i)
+		} else {
+			go do(i)
+		}
+	}
+
+	wg.Wait()
+
+	duration := time.Since(start)
+
+	close(errs)
+
+	err = <-errs
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Mode:         cfg.Mode,
+		Nodes:        cfg.Nodes,
+		Threshold:    cfg.Threshold,
+		Transactions: cfg.Transactions,
+		PayloadSize:  cfg.PayloadSize,
+		Rate:         cfg.Rate,
+		Duration:     duration.Seconds(),
+		Throughput:   float64(cfg.Transactions) / duration.Seconds(),
+		Latency:      summarize(latencies),
+	}
+
+	if cfg.Mode == F3B {
+		extraction := summarize(extractions)
+		report.Extraction = &extraction
+	}
+
+	return report, nil
+}
+
+// setup creates the in-process nodes and runs the DKG. The bytes delivered to
+// the nodes are counted by the traffic, and the nodes learn the finalized
+// blocks from the bus.
+func setup(n, threshold int, traffic *traffic, bus events.Service) ([]dkg.Actor, error) {
+	manager := minoch.NewManager()
+
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]crypto.PublicKey, n)
+	actors := make([]dkg.Actor, n)
+
+	for i := range actors {
+		m, err := minoch.NewMinoch(manager, fmt.Sprintf("node%d", i))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to create mino: %v", err)
+		}
+
+		d, pubkey := pedersen.NewPedersen(countingMino{Mino: m, traffic: traffic},
+			pedersen.WithEvents(bus))
+
+		actors[i], err = d.Listen()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to listen: %v", err)
+		}
+
+		addrs[i] = m.GetAddress()
+		pubkeys[i] = bls.NewPublicKeyFromPoint(pubkey)
+	}
+
+	_, err := actors[0].Setup(authority.New(addrs, pubkeys), threshold)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to run the DKG: %v", err)
+	}
+
+	return actors, nil
+}
+
+// decryptRoundTrip encrypts the payload to the label of the transaction of
+// the block at the height, releases the key of the label and decrypts the
+// payload. It returns the time taken to release the key.
+func decryptRoundTrip(actor dkg.Actor, height uint64, index int,
+	payload []byte) (time.Duration, error) {
+
+	label, ct, err := encrypt(actor, height, index, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return decrypt(actor, height, label, ct, payload)
+}
+
+// encrypt returns the label of the transaction of the block at the height and
+// the payload encrypted to it.
+func encrypt(actor dkg.Actor, height uint64, index int,
+	payload []byte) ([]byte, []byte, error) {
+
+	suite := bn256.NewSuiteG2()
+	label := ibe.NewTxLabel(height, uint64(index), payload)
+
+	pubkey, err := actor.GetPublicKey()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to get public key: %v", err)
+	}
+
+	ek, err := ibe.DeriveEncryptionKeyOnG2(suite, pubkey, label)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to derive encryption key: %v", err)
+	}
+
+	ct, err := ibe.EncryptCPAonG2(suite, ek, payload)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to encrypt: %v", err)
+	}
+
+	data, err := ct.Serialize(suite)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to serialize ciphertext: %v", err)
+	}
+
+	return label, data, nil
+}
+
+// decrypt releases the key of the label of the block at the height and checks
+// that it decrypts the ciphertext to the payload. It returns the time taken to
+// release the key.
+func decrypt(actor dkg.Actor, height uint64, label, data,
+	payload []byte) (time.Duration, error) {
+
+	suite := bn256.NewSuiteG2()
+
+	ct := new(ibe.CiphertextCPA)
+
+	err := ct.Deserialize(suite, data)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to deserialize ciphertext: %v", err)
+	}
+
+	start := time.Now()
+
+	dkBuf, err := release(actor, height, label)
+	if err != nil {
+		return 0, err
+	}
+
+	extraction := time.Since(start)
+
+	dk := suite.G1().Point()
+
+	err = dk.UnmarshalBinary(dkBuf)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to unmarshal key: %v", err)
+	}
+
+	msg, err := ibe.DecryptCPAonG2(suite, dk, ct)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to decrypt: %v", err)
+	}
+
+	if !bytes.Equal(msg, payload) {
+		return 0, xerrors.New("decrypted payload mismatch")
+	}
+
+	return extraction, nil
+}
+
+// release returns the key of the label of the block at the height. The DKG is
+// asked again until the nodes know that the block is finalized.
+func release(actor dkg.Actor, height uint64, label []byte) ([]byte, error) {
+	deadline := time.Now().Add(inclusionTimeout)
+
+	for {
+		block, err := actor.ReleaseBlock(height, [][]byte{label})
+		if err == nil {
+			return block.Keys[0], nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, xerrors.Errorf("failed to release key: %v", err)
+		}
+
+		time.Sleep(releaseInterval)
+	}
+}
+
+func summarize(latencies []time.Duration) Latency {
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p float64) float64 {
+		index := int(p * float64(len(sorted)-1))
+		return float64(sorted[index]) / float64(time.Millisecond)
+	}
+
+	return Latency{
+		P50: percentile(0.5),
+		P90: percentile(0.9),
+		P99: percentile(0.99),
+		Max: percentile(1),
+	}
+}
@@ -0,0 +1,70 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Baseline(t *testing.T) {
+	report, err := Run(Config{
+		Mode:         Baseline,
+		Nodes:        1,
+		Transactions: 10,
+		Rate:         1000,
+		PayloadSize:  32,
+	})
+	require.NoError(t, err)
+	require.Equal(t, Baseline, report.Mode)
+	require.Equal(t, 1, report.Threshold)
+	require.Equal(t, 10, report.Transactions)
+	require.Greater(t, report.Throughput, 0.0)
+	require.Nil(t, report.Extraction)
+}
+
+func TestRun_F3B(t *testing.T) {
+	report, err := Run(Config{
+		Mode:         F3B,
+		Nodes:        3,
+		Threshold:    2,
+		Transactions: 6,
+		Rate:         100,
+		PayloadSize:  32,
+	})
+	require.NoError(t, err)
+	require.Equal(t, F3B, report.Mode)
+	require.Equal(t, 2, report.Threshold)
+	require.Greater(t, report.Latency.Max, 0.0)
+	require.LessOrEqual(t, report.Latency.P50, report.Latency.Max)
+	require.NotNil(t, report.Extraction)
+	require.Greater(t, report.Extraction.Max, 0.0)
+	require.Less(t, report.Extraction.Max, report.Latency.Max)
+
+	buf := new(bytes.Buffer)
+	err = report.WriteJSON(buf)
+	require.NoError(t, err)
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, report, decoded)
+}
+
+func TestRun_BadConfig(t *testing.T) {
+	_, err := Run(Config{Mode: F3B})
+	require.EqualError(t, err, "invalid configuration: 0 nodes, 0 transactions")
+
+	_, err = Run(Config{Mode: "unknown", Nodes: 1, Transactions: 1})
+	require.EqualError(t, err, "unknown mode 'unknown'")
+}
+
+func TestSummarize(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+
+	require.Equal(t, Latency{P50: 50, P90: 90, P99: 99, Max: 100}, summarize(latencies))
+}
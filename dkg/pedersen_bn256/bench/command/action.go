@@ -0,0 +1,100 @@
+package command
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/bench"
+	"golang.org/x/xerrors"
+)
+
+// action defines the cli actions of the benchmark commands. Defining functions
+// and printer helps in testing the commands.
+type action struct {
+	printer io.Writer
+
+	sweep      func(bench.SweepConfig) ([]bench.SweepResult, error)
+	createFile func(path string) (io.WriteCloser, error)
+}
+
+func (a action) sweepAction(flags cli.Flags) error {
+	nodes, err := parseNodes(flags.String("n"))
+	if err != nil {
+		return xerrors.Errorf("failed to parse nodes: %v", err)
+	}
+
+	threshold, err := parseThreshold(flags.String("t"))
+	if err != nil {
+		return xerrors.Errorf("failed to parse threshold: %v", err)
+	}
+
+	results, err := a.sweep(bench.SweepConfig{
+		Nodes:       nodes,
+		Threshold:   threshold,
+		Releases:    flags.Int("releases"),
+		PayloadSize: flags.Int("payloadSize"),
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to sweep: %v", err)
+	}
+
+	out := a.printer
+
+	output := flags.String("output")
+	if output != "" {
+		file, err := a.createFile(output)
+		if err != nil {
+			return xerrors.Errorf("failed to create output: %v", err)
+		}
+
+		defer file.Close()
+
+		out = file
+	}
+
+	err = bench.WriteCSV(out, results)
+	if err != nil {
+		return xerrors.Errorf("failed to write results: %v", err)
+	}
+
+	return nil
+}
+
+// parseNodes parses a comma-separated list of numbers of nodes.
+func parseNodes(value string) ([]int, error) {
+	tokens := strings.Split(value, ",")
+	nodes := make([]int, len(tokens))
+
+	for i, token := range tokens {
+		n, err := strconv.Atoi(strings.TrimSpace(token))
+		if err != nil {
+			return nil, xerrors.Errorf("invalid number of nodes '%s'", token)
+		}
+
+		nodes[i] = n
+	}
+
+	return nodes, nil
+}
+
+// parseThreshold returns the threshold of the value, which is either 'auto'
+// or the same threshold for every number of nodes.
+func parseThreshold(value string) (func(int) int, error) {
+	if value == "auto" {
+		return bench.AutoThreshold, nil
+	}
+
+	t, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid threshold '%s'", value)
+	}
+
+	return func(int) int { return t }, nil
+}
+
+func createFile(path string) (io.WriteCloser, error) {
+	return os.Create(path)
+}
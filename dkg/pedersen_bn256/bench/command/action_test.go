@@ -0,0 +1,100 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/bench"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+const header = "nodes,threshold,setup_ms,extraction_p50_ms,extraction_p90_ms," +
+	"extraction_p99_ms,extraction_max_ms,setup_bytes,release_bytes\n"
+
+func TestSweepAction(t *testing.T) {
+	var cfg bench.SweepConfig
+
+	buf := new(bytes.Buffer)
+
+	action := action{
+		printer: buf,
+		sweep: func(c bench.SweepConfig) ([]bench.SweepResult, error) {
+			cfg = c
+			return []bench.SweepResult{{Nodes: 8, Threshold: 6}}, nil
+		},
+		createFile: createFile,
+	}
+
+	flags := node.FlagSet{
+		"n":           "8, 16",
+		"t":           "auto",
+		"releases":    5,
+		"payloadSize": 64,
+	}
+
+	err := action.sweepAction(flags)
+	require.NoError(t, err)
+	require.Equal(t, []int{8, 16}, cfg.Nodes)
+	require.Equal(t, 11, cfg.Threshold(16))
+	require.Equal(t, 5, cfg.Releases)
+	require.Equal(t, 64, cfg.PayloadSize)
+	require.Equal(t, header+"8,6,0.000,0.000,0.000,0.000,0.000,0,0\n", buf.String())
+
+	flags["t"] = "4"
+	flags["output"] = filepath.Join(t.TempDir(), "sweep.csv")
+
+	err = action.sweepAction(flags)
+	require.NoError(t, err)
+	require.Equal(t, 4, cfg.Threshold(16))
+
+	data, err := os.ReadFile(flags.String("output"))
+	require.NoError(t, err)
+	require.Equal(t, header+"8,6,0.000,0.000,0.000,0.000,0.000,0,0\n", string(data))
+}
+
+func TestSweepAction_Failures(t *testing.T) {
+	action := action{
+		printer: io.Discard,
+		sweep: func(bench.SweepConfig) ([]bench.SweepResult, error) {
+			return nil, fake.GetError()
+		},
+		createFile: func(string) (io.WriteCloser, error) {
+			return nil, fake.GetError()
+		},
+	}
+
+	flags := node.FlagSet{"n": "8,a", "t": "auto"}
+
+	err := action.sweepAction(flags)
+	require.EqualError(t, err, "failed to parse nodes: invalid number of nodes 'a'")
+
+	flags["n"] = "8"
+	flags["t"] = "half"
+
+	err = action.sweepAction(flags)
+	require.EqualError(t, err, "failed to parse threshold: invalid threshold 'half'")
+
+	flags["t"] = "auto"
+
+	err = action.sweepAction(flags)
+	require.EqualError(t, err, fake.Err("failed to sweep"))
+
+	action.sweep = func(bench.SweepConfig) ([]bench.SweepResult, error) {
+		return nil, nil
+	}
+	flags["output"] = "sweep.csv"
+
+	err = action.sweepAction(flags)
+	require.EqualError(t, err, fake.Err("failed to create output"))
+
+	action.printer = fake.NewBadHash()
+	delete(flags, "output")
+
+	err = action.sweepAction(flags)
+	require.EqualError(t, err, fake.Err("failed to write results: failed to flush"))
+}
@@ -0,0 +1,54 @@
+// Package command defines cli commands for the benchmark harness of F3B.
+package command
+
+import (
+	"os"
+
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/bench"
+)
+
+// Initializer implements the benchmark initializer for the F3B CLI.
+//
+// - implements cli.Initializer
+type Initializer struct {
+}
+
+// SetCommands implements cli.Initializer.
+func (i Initializer) SetCommands(provider cli.Provider) {
+	action := action{
+		printer: os.Stdout,
+
+		sweep:      bench.Sweep,
+		createFile: createFile,
+	}
+
+	cmd := provider.SetCommand("bench")
+
+	sweep := cmd.SetSubCommand("sweep")
+	sweep.SetDescription("run the DKG, the encryption and the release of " +
+		"keys for each number of nodes, and output the setup time, the " +
+		"extraction latency and the bandwidth in CSV")
+	sweep.SetFlags(cli.StringFlag{
+		Name:  "n",
+		Usage: "comma-separated list of the numbers of nodes",
+		Value: "8,16,32,64",
+	}, cli.StringFlag{
+		Name: "t",
+		Usage: "threshold of every configuration, or 'auto' for the " +
+			"Byzantine threshold of the number of nodes",
+		Value: "auto",
+	}, cli.IntFlag{
+		Name:  "releases",
+		Usage: "number of keys released per configuration",
+		Value: 10,
+	}, cli.IntFlag{
+		Name:  "payloadSize",
+		Usage: "size in bytes of the encrypted payloads",
+		Value: 32,
+	}, cli.StringFlag{
+		Name:  "output",
+		Usage: "if provided, write the CSV to that file instead of stdout",
+	})
+	sweep.SetAction(action.sweepAction)
+}
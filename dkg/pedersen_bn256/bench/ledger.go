@@ -0,0 +1,317 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.dedis.ch/dela/core/access/darc"
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/execution"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering/cosipbft"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/store/hashtree/binprefix"
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/txn/pool"
+	poolgossip "go.dedis.ch/dela/core/txn/pool/gossip"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/cosi/threshold"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/gossip"
+	"go.dedis.ch/dela/mino/minoch"
+	"go.dedis.ch/dela/serde/json"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// PayloadContract is the name of the contract that stores the payload of
+	// the transactions of the ledger.
+	PayloadContract = "bench"
+
+	// PayloadArg is the argument of a transaction that holds its payload.
+	PayloadArg = "bench:payload"
+
+	// inclusionTimeout is the maximum time to wait for a transaction to be
+	// part of a block.
+	inclusionTimeout = 30 * time.Second
+)
+
+// Ledger is an in-process chain of nodes that order the transactions with
+// cosipbft. The transactions carry a payload that a contract stores in the
+// state of the ledger. The blocks finalized by the first node are published on
+// the bus of the ledger.
+type Ledger struct {
+	nodes []ledgerNode
+	bus   *events.Bus
+}
+
+type ledgerNode struct {
+	service *cosipbft.Service
+	pool    pool.Pool
+	blocks  blockstore.BlockStore
+	db      kv.DB
+	dir     string
+}
+
+// NewLedger creates the nodes of the ledger and creates the genesis block.
+func NewLedger(n int) (*Ledger, error) {
+	if n <= 0 {
+		return nil, xerrors.Errorf("invalid number of nodes: %d", n)
+	}
+
+	manager := minoch.NewManager()
+
+	ledger := &Ledger{
+		bus: events.NewBus(),
+	}
+
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]crypto.PublicKey, n)
+
+	for i := 0; i < n; i++ {
+		m, err := minoch.NewMinoch(manager, fmt.Sprintf("ledger%d", i))
+		if err != nil {
+			ledger.Close()
+			return nil, xerrors.Errorf("failed to create mino: %v", err)
+		}
+
+		var opts []cosipbft.ServiceOption
+		if i == 0 {
+			opts = append(opts, cosipbft.WithEvents(ledger.bus))
+		}
+
+		node, pubkey, err := newLedgerNode(m, opts...)
+		if err != nil {
+			ledger.Close()
+			return nil, xerrors.Errorf("failed to create node %d: %v", i, err)
+		}
+
+		ledger.nodes = append(ledger.nodes, node)
+
+		addrs[i] = m.GetAddress()
+		pubkeys[i] = pubkey
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), inclusionTimeout)
+	defer cancel()
+
+	err := ledger.nodes[0].service.Setup(ctx, authority.New(addrs, pubkeys))
+	if err != nil {
+		ledger.Close()
+		return nil, xerrors.Errorf("failed to create genesis: %v", err)
+	}
+
+	return ledger, nil
+}
+
+func newLedgerNode(m mino.Mino,
+	opts ...cosipbft.ServiceOption) (ledgerNode, crypto.PublicKey, error) {
+
+	signer := bls.NewSigner()
+
+	c := threshold.NewThreshold(m, signer)
+	c.SetThreshold(threshold.ByzantineThreshold)
+
+	dir, err := os.MkdirTemp("", "dela-bench")
+	if err != nil {
+		return ledgerNode{}, nil, xerrors.Errorf("failed to create dir: %v", err)
+	}
+
+	db, err := kv.New(filepath.Join(dir, "ledger.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return ledgerNode{}, nil, xerrors.Errorf("failed to open db: %v", err)
+	}
+
+	txFac := signed.NewTransactionFactory()
+
+	p, err := poolgossip.NewPool(gossip.NewFlat(m, txFac))
+	if err != nil {
+		db.Close()
+		os.RemoveAll(dir)
+		return ledgerNode{}, nil, xerrors.Errorf("failed to create pool: %v", err)
+	}
+
+	exec := native.NewExecution()
+	exec.Set(PayloadContract, payloadContract{})
+
+	accessSrvc := darc.NewService(json.NewContext())
+
+	rosterFac := authority.NewFactory(m.GetAddressFactory(), c.GetPublicKeyFactory())
+	cosipbft.RegisterRosterContract(exec, rosterFac, accessSrvc)
+
+	param := cosipbft.ServiceParam{
+		Mino:       m,
+		Cosi:       c,
+		Validation: simple.NewService(exec, txFac),
+		Access:     accessSrvc,
+		Pool:       p,
+		Tree:       binprefix.NewMerkleTree(db, binprefix.Nonce{}),
+		DB:         db,
+	}
+
+	blocks := blockstore.NewInMemory()
+	opts = append(opts, cosipbft.WithBlockStore(blocks))
+
+	srvc, err := cosipbft.NewService(param, opts...)
+	if err != nil {
+		db.Close()
+		os.RemoveAll(dir)
+		return ledgerNode{}, nil, xerrors.Errorf("failed to create service: %v", err)
+	}
+
+	node := ledgerNode{
+		service: srvc,
+		pool:    p,
+		blocks:  blocks,
+		db:      db,
+		dir:     dir,
+	}
+
+	return node, signer.GetPublicKey(), nil
+}
+
+// NewPayloadTx returns a transaction that stores the payload in clear. Each
+// transaction is signed by its own identity, so that they do not depend on
+// each other's nonce.
+func NewPayloadTx(payload []byte) (txn.Transaction, error) {
+	return newTx(PayloadArg, payload)
+}
+
+// NewCiphertextTx returns a transaction that stores the ciphertext. The
+// ciphertext is committed in the header of the block that includes the
+// transaction.
+func NewCiphertextTx(ct []byte) (txn.Transaction, error) {
+	return newTx(types.CiphertextArg, ct)
+}
+
+func newTx(arg string, value []byte) (txn.Transaction, error) {
+	signer := bls.NewSigner()
+
+	tx, err := signed.NewTransaction(0, signer.GetPublicKey(),
+		signed.WithArg(native.ContractArg, []byte(PayloadContract)),
+		signed.WithArg(arg, value))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create transaction: %v", err)
+	}
+
+	err = tx.Sign(signer)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sign transaction: %v", err)
+	}
+
+	return tx, nil
+}
+
+// Submit adds the transaction to the pool of a node and waits until it is
+// accepted in a block.
+func (l *Ledger) Submit(index int, tx txn.Transaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), inclusionTimeout)
+	defer cancel()
+
+	node := l.nodes[index%len(l.nodes)]
+
+	events := node.service.Watch(ctx)
+
+	err := node.pool.Add(tx)
+	if err != nil {
+		return xerrors.Errorf("failed to add to the pool: %v", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("transaction not included: %v", ctx.Err())
+		case evt := <-events:
+			for _, res := range evt.Transactions {
+				if !bytes.Equal(res.GetTransaction().GetID(), tx.GetID()) {
+					continue
+				}
+
+				accepted, reason := res.GetStatus()
+				if !accepted {
+					return xerrors.Errorf("transaction refused: %s", reason)
+				}
+
+				return nil
+			}
+		}
+	}
+}
+
+// Events returns the bus where the blocks finalized by the ledger are
+// published.
+func (l *Ledger) Events() events.Service {
+	return l.bus
+}
+
+// Height returns the index of the next block of the ledger.
+func (l *Ledger) Height() uint64 {
+	return l.nodes[0].blocks.Len()
+}
+
+// WatchPool returns the events of the pool of a node until the context is
+// done.
+func (l *Ledger) WatchPool(ctx context.Context, index int) <-chan pool.Event {
+	return l.nodes[index%len(l.nodes)].pool.Watch(ctx)
+}
+
+// Close stops the nodes and deletes their storage.
+func (l *Ledger) Close() error {
+	var errs []error
+
+	for _, node := range l.nodes {
+		err := node.service.Close()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		err = node.db.Close()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		err = os.RemoveAll(node.dir)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return xerrors.Errorf("failed to close: %v", errs)
+	}
+
+	return nil
+}
+
+// payloadContract is a contract that stores the payload or the ciphertext of
+// a transaction at its identifier. It does not check any access right.
+//
+// - implements native.Contract
+type payloadContract struct{}
+
+// Execute implements native.Contract.
+func (payloadContract) Execute(snap store.Snapshot, step execution.Step) error {
+	value := step.Current.GetArg(PayloadArg)
+	if value == nil {
+		value = step.Current.GetArg(types.CiphertextArg)
+	}
+
+	err := snap.Set(step.Current.GetID(), value)
+	if err != nil {
+		return xerrors.Errorf("failed to store payload: %v", err)
+	}
+
+	return nil
+}
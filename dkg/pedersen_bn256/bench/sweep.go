@@ -0,0 +1,234 @@
+package bench
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/cosi/threshold"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// SweepConfig is the configuration of a sweep over the number of nodes.
+type SweepConfig struct {
+	// Nodes is the number of nodes of each configuration.
+	Nodes []int
+
+	// Threshold returns the threshold for a number of nodes. It defaults to
+	// AutoThreshold when it is nil.
+	Threshold func(nodes int) int
+
+	// Releases is the number of keys released by each configuration.
+	Releases int
+
+	// PayloadSize is the size in bytes of the encrypted payloads.
+	PayloadSize int
+}
+
+// SweepResult is the measurements of a configuration of the sweep.
+type SweepResult struct {
+	Nodes     int
+	Threshold int
+
+	// Setup is the duration of the DKG.
+	Setup time.Duration
+
+	// Extraction summarizes the time taken to release a key.
+	Extraction Latency
+
+	// SetupBytes is the number of bytes delivered to the nodes by the DKG.
+	SetupBytes uint64
+
+	// ReleaseBytes is the average number of bytes delivered to the nodes to
+	// release a key.
+	ReleaseBytes uint64
+}
+
+// AutoThreshold returns the number of honest nodes required by a Byzantine
+// fault tolerant system of n nodes.
+func AutoThreshold(n int) int {
+	return threshold.ByzantineThreshold(n)
+}
+
+// Sweep runs the DKG, the encryption and the release of the keys for each
+// number of nodes of the configuration, one after each other.
+func Sweep(cfg SweepConfig) ([]SweepResult, error) {
+	if cfg.Releases <= 0 {
+		return nil, xerrors.Errorf("invalid number of releases: %d", cfg.Releases)
+	}
+
+	thresholdOf := cfg.Threshold
+	if thresholdOf == nil {
+		thresholdOf = AutoThreshold
+	}
+
+	results := make([]SweepResult, len(cfg.Nodes))
+
+	for i, n := range cfg.Nodes {
+		t := thresholdOf(n)
+
+		if n <= 0 || t <= 0 || t > n {
+			return nil, xerrors.Errorf("invalid configuration: %d nodes, "+
+				"threshold %d", n, t)
+		}
+
+		result, err := sweepOne(n, t, cfg)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to run %d nodes: %v", n, err)
+		}
+
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+func sweepOne(n, t int, cfg SweepConfig) (SweepResult, error) {
+	traffic := new(traffic)
+
+	start := time.Now()
+
+	bus := events.NewBus()
+
+	actors, err := setup(n, t, traffic, bus)
+	if err != nil {
+		return SweepResult{}, xerrors.Errorf("failed to setup: %v", err)
+	}
+
+	// There is no ledger, so the blocks of the releases are announced as
+	// finalized on the bus of the nodes.
+	bus.Publish(events.BlockFinalized{Index: uint64(cfg.Releases), Time: time.Now()})
+
+	setupTime := time.Since(start)
+	setupBytes := traffic.reset()
+
+	latencies := make([]time.Duration, cfg.Releases)
+
+	for i := range latencies {
+		payload := make([]byte, cfg.PayloadSize)
+
+		_, err = rand.Read(payload)
+		if err != nil {
+			return SweepResult{}, xerrors.Errorf("failed to generate payload: %v", err)
+		}
+
+		latencies[i], err = decryptRoundTrip(actors[i%len(actors)], uint64(i), i, payload)
+		if err != nil {
+			return SweepResult{}, xerrors.Errorf("release %d: %v", i, err)
+		}
+	}
+
+	result := SweepResult{
+		Nodes:        n,
+		Threshold:    t,
+		Setup:        setupTime,
+		Extraction:   summarize(latencies),
+		SetupBytes:   setupBytes,
+		ReleaseBytes: traffic.reset() / uint64(cfg.Releases),
+	}
+
+	return result, nil
+}
+
+// WriteCSV writes the results in CSV to the writer, with a header.
+func WriteCSV(w io.Writer, results []SweepResult) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"nodes", "threshold", "setup_ms", "extraction_p50_ms",
+		"extraction_p90_ms", "extraction_p99_ms", "extraction_max_ms",
+		"setup_bytes", "release_bytes"}
+
+	err := writer.Write(header)
+	if err != nil {
+		return xerrors.Errorf("failed to write header: %v", err)
+	}
+
+	for _, result := range results {
+		ms := float64(result.Setup) / float64(time.Millisecond)
+
+		record := []string{
+			strconv.Itoa(result.Nodes),
+			strconv.Itoa(result.Threshold),
+			formatMs(ms),
+			formatMs(result.Extraction.P50),
+			formatMs(result.Extraction.P90),
+			formatMs(result.Extraction.P99),
+			formatMs(result.Extraction.Max),
+			strconv.FormatUint(result.SetupBytes, 10),
+			strconv.FormatUint(result.ReleaseBytes, 10),
+		}
+
+		err = writer.Write(record)
+		if err != nil {
+			return xerrors.Errorf("failed to write result: %v", err)
+		}
+	}
+
+	writer.Flush()
+
+	err = writer.Error()
+	if err != nil {
+		return xerrors.Errorf("failed to flush: %v", err)
+	}
+
+	return nil
+}
+
+func formatMs(ms float64) string {
+	return fmt.Sprintf("%.3f", ms)
+}
+
+// traffic counts the bytes delivered to the nodes.
+type traffic struct {
+	bytes uint64
+}
+
+func (t *traffic) add(n int) {
+	atomic.AddUint64(&t.bytes, uint64(n))
+}
+
+// reset returns the number of bytes counted so far and starts again from zero.
+func (t *traffic) reset() uint64 {
+	return atomic.SwapUint64(&t.bytes, 0)
+}
+
+// countingMino is a mino that counts the bytes of the messages delivered to
+// the RPCs it creates.
+//
+// - implements mino.Mino
+type countingMino struct {
+	mino.Mino
+
+	traffic *traffic
+}
+
+// CreateRPC implements mino.Mino. It creates the RPC with a factory that counts
+// the bytes of the messages before they are deserialized.
+func (m countingMino) CreateRPC(name string, h mino.Handler,
+	f serde.Factory) (mino.RPC, error) {
+
+	return m.Mino.CreateRPC(name, h, countingFactory{Factory: f, traffic: m.traffic})
+}
+
+// countingFactory is a factory that counts the bytes it deserializes.
+//
+// - implements serde.Factory
+type countingFactory struct {
+	serde.Factory
+
+	traffic *traffic
+}
+
+// Deserialize implements serde.Factory.
+func (f countingFactory) Deserialize(ctx serde.Context, data []byte) (serde.Message, error) {
+	f.traffic.add(len(data))
+
+	return f.Factory.Deserialize(ctx, data)
+}
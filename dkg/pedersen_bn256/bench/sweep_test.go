@@ -0,0 +1,92 @@
+package bench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestSweep(t *testing.T) {
+	results, err := Sweep(SweepConfig{
+		Nodes:       []int{2, 3},
+		Releases:    2,
+		PayloadSize: 32,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, 2, results[0].Nodes)
+	require.Equal(t, 2, results[0].Threshold)
+	require.Equal(t, 3, results[1].Nodes)
+	require.Equal(t, 3, results[1].Threshold)
+
+	for _, result := range results {
+		require.Greater(t, int64(result.Setup), int64(0))
+		require.Greater(t, result.Extraction.Max, 0.0)
+	}
+
+	// A larger committee exchanges more messages.
+	require.Greater(t, results[1].SetupBytes, results[0].SetupBytes)
+	require.Greater(t, results[1].ReleaseBytes, results[0].ReleaseBytes)
+
+	results, err = Sweep(SweepConfig{
+		Nodes:     []int{3},
+		Threshold: func(int) int { return 2 },
+		Releases:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, results[0].Threshold)
+}
+
+func TestSweep_BadConfig(t *testing.T) {
+	_, err := Sweep(SweepConfig{Nodes: []int{1}})
+	require.EqualError(t, err, "invalid number of releases: 0")
+
+	_, err = Sweep(SweepConfig{Nodes: []int{0}, Releases: 1})
+	require.EqualError(t, err, "invalid configuration: 0 nodes, threshold 0")
+
+	_, err = Sweep(SweepConfig{
+		Nodes:     []int{2},
+		Threshold: func(int) int { return 3 },
+		Releases:  1,
+	})
+	require.EqualError(t, err, "invalid configuration: 2 nodes, threshold 3")
+}
+
+func TestAutoThreshold(t *testing.T) {
+	require.Equal(t, 6, AutoThreshold(8))
+	require.Equal(t, 11, AutoThreshold(16))
+	require.Equal(t, 22, AutoThreshold(32))
+	require.Equal(t, 43, AutoThreshold(64))
+}
+
+func TestWriteCSV(t *testing.T) {
+	results := []SweepResult{
+		{
+			Nodes:        8,
+			Threshold:    6,
+			Setup:        1500000,
+			Extraction:   Latency{P50: 1, P90: 2, P99: 3, Max: 4.25},
+			SetupBytes:   1024,
+			ReleaseBytes: 64,
+		},
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := WriteCSV(buf, results)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, []string{
+		"nodes,threshold,setup_ms,extraction_p50_ms,extraction_p90_ms," +
+			"extraction_p99_ms,extraction_max_ms,setup_bytes,release_bytes",
+		"8,6,1.500,1.000,2.000,3.000,4.250,1024,64",
+	}, lines)
+
+	err = WriteCSV(fake.NewBadHash(), results)
+	require.EqualError(t, err, fake.Err("failed to flush"))
+}
@@ -0,0 +1,79 @@
+package pedersen
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"math/big"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/mod"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+	"golang.org/x/xerrors"
+)
+
+// blindingBits is the size of the random multiple of the group order added to
+// a share before it is used.
+const blindingBits = 128
+
+type hashablePoint interface {
+	Hash([]byte) kyber.Point
+}
+
+// signShare returns the signature share of the message, in the format of
+// tbls.Sign.
+//
+// The points of bn256 are multiplied by a double-and-add loop that branches on
+// every bit of the scalar, so that the time to sign depends on the share, which
+// is a long-term secret. The share is therefore blinded with a random multiple
+// of the group order before the multiplication. The signature is unchanged but
+// the bits processed differ for every request.
+func signShare(priShare *share.PriShare, msg []byte, rand cipher.Stream) ([]byte, error) {
+	hashable, ok := pairingSuite.G1().Point().(hashablePoint)
+	if !ok {
+		return nil, xerrors.New("point needs to implement hashablePoint")
+	}
+
+	blinded, err := blindScalar(priShare.V, rand)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to blind share: %v", err)
+	}
+
+	hm := hashable.Hash(msg)
+
+	sig, err := hm.Mul(blinded, hm).MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal signature: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	err = binary.Write(buf, binary.BigEndian, uint16(priShare.I))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to write index: %v", err)
+	}
+
+	buf.Write(sig)
+
+	return buf.Bytes(), nil
+}
+
+// blindScalar returns the scalar plus a random multiple of the group order.
+// The result is deliberately left unreduced, as the multiplication of a point
+// uses its value as is.
+func blindScalar(s kyber.Scalar, rand cipher.Stream) (kyber.Scalar, error) {
+	secret, ok := s.(*mod.Int)
+	if !ok {
+		return nil, xerrors.Errorf("unsupported scalar '%T'", s)
+	}
+
+	r := new(big.Int).SetBytes(random.Bits(blindingBits, false, rand))
+
+	blinded := &mod.Int{M: bn256.Order, BO: secret.BO}
+	blinded.V.Mul(r, bn256.Order)
+	blinded.V.Add(&blinded.V, &secret.V)
+
+	return blinded, nil
+}
@@ -0,0 +1,61 @@
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/mod"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestSignShare(t *testing.T) {
+	priShare := &share.PriShare{I: 3, V: suite.Scalar().Pick(suite.RandomStream())}
+	msg := []byte("label")
+
+	expected, err := tbls.Sign(pairingSuite, priShare, msg)
+	require.NoError(t, err)
+
+	sig, err := signShare(priShare, msg, suite.RandomStream())
+	require.NoError(t, err)
+	require.Equal(t, expected, sig)
+
+	_, err = signShare(&share.PriShare{V: fakeScalar{}}, msg, suite.RandomStream())
+	require.EqualError(t, err,
+		"failed to blind share: unsupported scalar 'pedersen.fakeScalar'")
+}
+
+func TestBlindScalar(t *testing.T) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	rand := random.New()
+
+	first, err := blindScalar(secret, rand)
+	require.NoError(t, err)
+
+	second, err := blindScalar(secret, rand)
+	require.NoError(t, err)
+
+	// The blinded values differ for every call, so that the multiplication
+	// processes different bits, but they are all equal to the share modulo
+	// the order of the group.
+	a := first.(*mod.Int)
+	b := second.(*mod.Int)
+
+	require.NotEqual(t, 0, a.V.Cmp(&b.V))
+	require.Greater(t, a.V.BitLen(), bn256.Order.BitLen())
+
+	require.True(t, secret.Equal(mod.NewInt(&a.V, bn256.Order)))
+	require.True(t, secret.Equal(mod.NewInt(&b.V, bn256.Order)))
+
+	p := suite.Point().Mul(secret, nil)
+	require.True(t, p.Equal(suite.Point().Mul(first, nil)))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type fakeScalar struct {
+	*mod.Int
+}
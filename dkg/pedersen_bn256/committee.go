@@ -0,0 +1,301 @@
+package pedersen
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sort"
+	"time"
+
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/kdf"
+	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/dela/mino"
+	"golang.org/x/xerrors"
+)
+
+// Randomness provides the randomness of the blocks of the chain, such as the
+// beacon.
+type Randomness interface {
+	GetRandomness(index uint64) ([]byte, error)
+}
+
+// EpochParam is the parameters of the sampling of the committees.
+type EpochParam struct {
+	// Pool is the members of the consensus that can be sampled, with their DKG
+	// public key.
+	Pool crypto.CollectiveAuthority
+
+	// Randomness provides the seed of the sampling of each epoch.
+	Randomness Randomness
+
+	// Roles tells which members of the pool are relays, which are never
+	// sampled. It is optional.
+	Roles Roles
+
+	// Length is the number of blocks of an epoch. It must be positive.
+	Length uint64
+
+	// Size is the number of members of a committee. The whole pool is sampled
+	// when it is not larger.
+	Size int
+
+	// Threshold is the threshold of the DKG of each committee.
+	Threshold int
+
+	// Timeout is how long each member of the committee waits for the one
+	// before it to hand the secret over, before it starts the handoff itself.
+	// It should be longer than a resharing.
+	Timeout time.Duration
+}
+
+// Epochs samples a secret-management committee out of the pool for each epoch
+// of the chain, so that the DKG does not grow with the consensus.
+//
+// The committee of an epoch is drawn from the randomness of the last block of
+// the previous epoch, so that it is not known before that block is final and
+// anyone can verify it. The first epoch has no previous block and its committee
+// only depends on the pool.
+type Epochs struct {
+	param EpochParam
+}
+
+// NewEpochs returns the epochs of the parameters.
+func NewEpochs(param EpochParam) Epochs {
+	return Epochs{
+		param: param,
+	}
+}
+
+// GetEpoch returns the epoch of the block at the index.
+func (e Epochs) GetEpoch(index uint64) uint64 {
+	return index / e.param.Length
+}
+
+// GetThreshold returns the threshold of the committees.
+func (e Epochs) GetThreshold() int {
+	return e.param.Threshold
+}
+
+// GetCommittee returns the committee of the epoch, in the order of the pool.
+func (e Epochs) GetCommittee(epoch uint64) (crypto.CollectiveAuthority, error) {
+	var seed []byte
+
+	if epoch > 0 {
+		randomness, err := e.param.Randomness.GetRandomness(epoch*e.param.Length - 1)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read randomness: %v", err)
+		}
+
+		seed = randomness
+	}
+
+	committee, err := e.sample(seed)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sample: %v", err)
+	}
+
+	return committee, nil
+}
+
+// sample draws the committee out of the members of the pool that are not
+// relays, with a shuffle seeded by the randomness.
+func (e Epochs) sample(randomness []byte) (crypto.CollectiveAuthority, error) {
+	addrs := make([]mino.Address, 0, e.param.Pool.Len())
+	pubkeys := make([]crypto.PublicKey, 0, e.param.Pool.Len())
+
+	addrIter := e.param.Pool.AddressIterator()
+	pkIter := e.param.Pool.PublicKeyIterator()
+
+	for addrIter.HasNext() && pkIter.HasNext() {
+		addr := addrIter.GetNext()
+		pubkey := pkIter.GetNext()
+
+		if e.param.Roles != nil && e.param.Roles.IsRelay(addr) {
+			continue
+		}
+
+		addrs = append(addrs, addr)
+		pubkeys = append(pubkeys, pubkey)
+	}
+
+	if e.param.Size > 0 && e.param.Size < len(addrs) {
+		digest, err := kdf.Derive(randomness, kdf.CommitteeSeed, 8)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to derive seed: %v", err)
+		}
+
+		source := rand.NewSource(int64(binary.LittleEndian.Uint64(digest)))
+
+		indices := rand.New(source).Perm(len(addrs))[:e.param.Size]
+		sort.Ints(indices)
+
+		sampledAddrs := make([]mino.Address, len(indices))
+		sampledKeys := make([]crypto.PublicKey, len(indices))
+
+		for i, index := range indices {
+			sampledAddrs[i] = addrs[index]
+			sampledKeys[i] = pubkeys[index]
+		}
+
+		addrs, pubkeys = sampledAddrs, sampledKeys
+	}
+
+	return authority.New(addrs, pubkeys), nil
+}
+
+// Handoff hands the secret over from the committee of an epoch to the next
+// one by resharing it when the chain reaches a new epoch.
+//
+// Any member of the current committee can start the resharing. They take
+// turns in the order of the committee, each one waiting for the timeout after
+// the previous one, so that it happens once per epoch even when some members
+// are down. The other nodes take part through the protocol.
+type Handoff struct {
+	epochs Epochs
+	actor  dkg.Actor
+	me     mino.Address
+}
+
+// NewHandoff returns a handoff of the secret of the actor across the epochs.
+func NewHandoff(epochs Epochs, actor dkg.Actor, me mino.Address) Handoff {
+	return Handoff{
+		epochs: epochs,
+		actor:  actor,
+		me:     me,
+	}
+}
+
+// Listen hands the secret over every time a block starts a new epoch, until
+// the channel is closed. A failure is logged and the committee stays in place
+// until the next epoch.
+//
+// The handoff runs aside so that the blocks keep being read while it waits or
+// reshares. An epoch that starts during a handoff is only handled after it.
+func (h Handoff) Listen(events <-chan ordering.Event) {
+	pending := make(chan uint64, 1)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for index := range pending {
+			err := h.Rotate(index)
+			if err != nil {
+				dela.Logger.Error().Err(err).
+					Uint64("index", index).
+					Msg("committee handoff failed")
+			}
+		}
+	}()
+
+	for event := range events {
+		if event.Index == 0 || event.Index%h.epochs.param.Length != 0 {
+			continue
+		}
+
+		// Only the latest epoch matters if the previous one is still pending.
+		select {
+		case <-pending:
+		default:
+		}
+
+		pending <- event.Index
+	}
+
+	close(pending)
+	<-done
+}
+
+// Rotate reshares the secret to the committee of the epoch if the block at the
+// index is the first one of an epoch and no member before this node in the
+// committee did it in time.
+func (h Handoff) Rotate(index uint64) error {
+	if index == 0 || index%h.epochs.param.Length != 0 {
+		return nil
+	}
+
+	epoch := h.epochs.GetEpoch(index)
+
+	info, err := h.actor.GetInfo()
+	if xerrors.Is(err, errNotInitialized) {
+		// The node holds no share, thus it is not a member of the committee.
+		return nil
+	}
+
+	if err != nil {
+		return xerrors.Errorf("failed to read current committee: %v", err)
+	}
+
+	rank := rankOf(info.Participants, h.me)
+	if rank < 0 {
+		return nil
+	}
+
+	committee, err := h.epochs.GetCommittee(epoch)
+	if err != nil {
+		return xerrors.Errorf("failed to get committee of epoch %d: %v", epoch, err)
+	}
+
+	if sameMembers(info.Participants, committee) {
+		return nil
+	}
+
+	if rank > 0 {
+		time.Sleep(time.Duration(rank) * h.epochs.param.Timeout)
+
+		info, err = h.actor.GetInfo()
+		if err != nil {
+			return xerrors.Errorf("failed to read current committee: %v", err)
+		}
+
+		if sameMembers(info.Participants, committee) {
+			// A member before this one handed the secret over.
+			return nil
+		}
+	}
+
+	err = h.actor.Reshare(committee, h.epochs.GetThreshold())
+	if err != nil {
+		return xerrors.Errorf("failed to reshare: %v", err)
+	}
+
+	dela.Logger.Info().
+		Uint64("epoch", epoch).
+		Int("members", committee.Len()).
+		Int("rank", rank).
+		Msg("secret handed over to the committee of the new epoch")
+
+	return nil
+}
+
+// rankOf returns the position of the address in the participants, or -1 if it
+// is not one of them.
+func rankOf(participants []mino.Address, addr mino.Address) int {
+	for i, participant := range participants {
+		if participant.Equal(addr) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// sameMembers returns true if the participants are the members of the
+// committee.
+func sameMembers(participants []mino.Address, committee crypto.CollectiveAuthority) bool {
+	if len(participants) != committee.Len() {
+		return false
+	}
+
+	for _, addr := range participants {
+		_, index := committee.GetPublicKey(addr)
+		if index < 0 {
+			return false
+		}
+	}
+
+	return true
+}
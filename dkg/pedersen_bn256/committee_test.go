@@ -0,0 +1,314 @@
+package pedersen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+)
+
+func TestEpochs_GetCommittee(t *testing.T) {
+	pool := fake.NewAuthority(10, fake.NewSigner)
+
+	epochs := NewEpochs(EpochParam{
+		Pool:       pool,
+		Randomness: fakeRandomness{},
+		Length:     5,
+		Size:       4,
+	})
+
+	require.Equal(t, uint64(0), epochs.GetEpoch(4))
+	require.Equal(t, uint64(1), epochs.GetEpoch(5))
+
+	first, err := epochs.GetCommittee(0)
+	require.NoError(t, err)
+	require.Equal(t, 4, first.Len())
+
+	// The sampling is deterministic.
+	again, err := epochs.GetCommittee(0)
+	require.NoError(t, err)
+	require.Equal(t, members(first), members(again))
+
+	// The committees are in the order of the pool.
+	indices := make([]int, 0, first.Len())
+	for _, addr := range members(first) {
+		_, index := pool.GetPublicKey(addr)
+		indices = append(indices, index)
+	}
+	require.IsIncreasing(t, indices)
+
+	// Each epoch has its own committee, drawn from the randomness of the last
+	// block of the previous epoch.
+	distinct := map[string]struct{}{}
+
+	for epoch := uint64(1); epoch <= 10; epoch++ {
+		committee, err := epochs.GetCommittee(epoch)
+		require.NoError(t, err)
+		require.Equal(t, 4, committee.Len())
+
+		key := ""
+		for _, addr := range members(committee) {
+			key += addr.String()
+		}
+
+		distinct[key] = struct{}{}
+	}
+
+	require.Greater(t, len(distinct), 1)
+
+	epochs.param.Randomness = fakeRandomness{err: fake.GetError()}
+
+	_, err = epochs.GetCommittee(1)
+	require.EqualError(t, err, fake.Err("failed to read randomness"))
+}
+
+func TestEpochs_Relays_GetCommittee(t *testing.T) {
+	epochs := NewEpochs(EpochParam{
+		Pool:   fake.NewAuthority(5, fake.NewSigner),
+		Roles:  fakeRoles{fake.NewAddress(1), fake.NewAddress(3)},
+		Length: 1,
+		Size:   3,
+	})
+
+	committee, err := epochs.GetCommittee(0)
+	require.NoError(t, err)
+	require.Equal(t, []mino.Address{fake.NewAddress(0), fake.NewAddress(2),
+		fake.NewAddress(4)}, members(committee))
+
+	// A committee larger than the pool is the whole pool.
+	epochs.param.Size = 10
+
+	committee, err = epochs.GetCommittee(0)
+	require.NoError(t, err)
+	require.Equal(t, 3, committee.Len())
+}
+
+func TestHandoff_Rotate(t *testing.T) {
+	pool := fake.NewAuthority(6, fake.NewSigner)
+
+	epochs := NewEpochs(EpochParam{
+		Pool:       pool,
+		Randomness: fakeRandomness{},
+		Length:     10,
+		Size:       3,
+		Threshold:  2,
+	})
+
+	actor := &fakeEpochActor{}
+	actor.info.Participants = []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+
+	handoff := NewHandoff(epochs, actor, fake.NewAddress(0))
+
+	// Only the first block of an epoch starts a handoff.
+	require.NoError(t, handoff.Rotate(0))
+	require.NoError(t, handoff.Rotate(15))
+	require.Empty(t, actor.reshared)
+
+	require.NoError(t, handoff.Rotate(20))
+	require.Len(t, actor.reshared, 1)
+	require.Equal(t, 2, actor.threshold)
+
+	expected, err := epochs.GetCommittee(2)
+	require.NoError(t, err)
+	require.Equal(t, members(expected), members(actor.reshared[0]))
+
+	// A node outside of the committee does not take part.
+	handoff = NewHandoff(epochs, actor, fake.NewAddress(5))
+
+	require.NoError(t, handoff.Rotate(20))
+	require.Len(t, actor.reshared, 1)
+
+	actor.infoErr = errNotInitialized
+
+	require.NoError(t, handoff.Rotate(20))
+	require.Len(t, actor.reshared, 1)
+
+	actor.infoErr = nil
+
+	// The next member waits for the first one, and does nothing if it handed
+	// the secret over in the meantime.
+	epochs.param.Timeout = time.Millisecond
+	actor.later = &dkg.Info{Participants: members(expected)}
+	handoff = NewHandoff(epochs, actor, fake.NewAddress(1))
+
+	require.NoError(t, handoff.Rotate(20))
+	require.Len(t, actor.reshared, 1)
+
+	// It hands the secret over itself after the timeout otherwise.
+	actor.info.Participants = []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+
+	require.NoError(t, handoff.Rotate(20))
+	require.Len(t, actor.reshared, 2)
+	require.Equal(t, members(expected), members(actor.reshared[1]))
+
+	// Nothing happens when the committee does not change.
+	actor.info.Participants = members(expected)
+	handoff = NewHandoff(epochs, actor, members(expected)[0])
+
+	require.NoError(t, handoff.Rotate(20))
+	require.Len(t, actor.reshared, 2)
+
+	actor.info.Participants = []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+	actor.err = fake.GetError()
+	handoff = NewHandoff(epochs, actor, fake.NewAddress(0))
+
+	err = handoff.Rotate(30)
+	require.EqualError(t, err, fake.Err("failed to reshare"))
+
+	actor.infoErr = fake.GetError()
+
+	err = handoff.Rotate(30)
+	require.EqualError(t, err, fake.Err("failed to read current committee"))
+
+	actor.infoErr = nil
+	actor.laterErr = fake.GetError()
+	handoff = NewHandoff(epochs, actor, fake.NewAddress(1))
+
+	err = handoff.Rotate(30)
+	require.EqualError(t, err, fake.Err("failed to read current committee"))
+
+	actor.infoErr = nil
+	handoff = NewHandoff(epochs, actor, fake.NewAddress(0))
+	handoff.epochs.param.Randomness = fakeRandomness{err: fake.GetError()}
+
+	err = handoff.Rotate(30)
+	require.EqualError(t, err, fake.Err("failed to get committee of epoch 3: "+
+		"failed to read randomness"))
+}
+
+func TestHandoff_Listen(t *testing.T) {
+	oldLog := dela.Logger
+	defer func() {
+		dela.Logger = oldLog
+	}()
+
+	logger := fake.NewLogger()
+	dela.Logger = logger.GetLogger()
+
+	actor := &fakeEpochActor{infoErr: fake.GetError()}
+
+	epochs := NewEpochs(EpochParam{Length: 2})
+
+	events := make(chan ordering.Event, 3)
+	events <- ordering.Event{Index: 1}
+	events <- ordering.Event{Index: 2}
+	events <- ordering.Event{Index: 3}
+	close(events)
+
+	NewHandoff(epochs, actor, fake.NewAddress(0)).Listen(events)
+
+	entries := logger.Find(zerolog.ErrorLevel, "committee handoff failed")
+	require.Len(t, entries, 1)
+	require.Equal(t, float64(2), entries[0].Fields["index"])
+}
+
+func TestHandoff_Blocking_Listen(t *testing.T) {
+	pool := fake.NewAuthority(4, fake.NewSigner)
+
+	epochs := NewEpochs(EpochParam{
+		Pool:       pool,
+		Randomness: fakeRandomness{},
+		Length:     2,
+		Size:       2,
+		Threshold:  2,
+		Timeout:    50 * time.Millisecond,
+	})
+
+	actor := &fakeEpochActor{}
+	actor.info.Participants = members(pool)
+
+	events := make(chan ordering.Event)
+	done := make(chan struct{})
+
+	go func() {
+		NewHandoff(epochs, actor, fake.NewAddress(1)).Listen(events)
+		close(done)
+	}()
+
+	// The blocks are still read while the member waits for its turn.
+	for i := uint64(1); i <= 6; i++ {
+		select {
+		case events <- ordering.Event{Index: i}:
+		case <-time.After(time.Second):
+			t.Fatal("handoff is blocking the events")
+		}
+	}
+
+	close(events)
+	<-done
+
+	// The epochs that start during a handoff are skipped for the latest one.
+	expected, err := epochs.GetCommittee(3)
+	require.NoError(t, err)
+	require.NotEmpty(t, actor.reshared)
+	require.LessOrEqual(t, len(actor.reshared), 2)
+	require.Equal(t, members(expected), members(actor.reshared[len(actor.reshared)-1]))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func members(co crypto.CollectiveAuthority) []mino.Address {
+	addrs := make([]mino.Address, 0, co.Len())
+
+	iter := co.AddressIterator()
+	for iter.HasNext() {
+		addrs = append(addrs, iter.GetNext())
+	}
+
+	return addrs
+}
+
+type fakeRandomness struct {
+	err error
+}
+
+func (r fakeRandomness) GetRandomness(index uint64) ([]byte, error) {
+	return []byte{byte(index)}, r.err
+}
+
+type fakeEpochActor struct {
+	dkg.Actor
+
+	info      dkg.Info
+	infoErr   error
+	later     *dkg.Info
+	laterErr  error
+	reshared  []crypto.CollectiveAuthority
+	threshold int
+	err       error
+}
+
+// GetInfo returns the information, which is replaced by the later one, if
+// any, after the call.
+func (a *fakeEpochActor) GetInfo() (dkg.Info, error) {
+	info, err := a.info, a.infoErr
+
+	if a.later != nil {
+		a.info, a.later = *a.later, nil
+	}
+
+	if a.laterErr != nil {
+		a.infoErr, a.laterErr = a.laterErr, nil
+	}
+
+	return info, err
+}
+
+func (a *fakeEpochActor) Reshare(co crypto.CollectiveAuthority, threshold int) error {
+	if a.err != nil {
+		return a.err
+	}
+
+	a.reshared = append(a.reshared, co)
+	a.threshold = threshold
+
+	return nil
+}
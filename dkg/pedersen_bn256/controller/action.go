@@ -1,23 +1,34 @@
 package controller
 
 import (
+	"context"
 	"encoding/base64"
+	"encoding/csv"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/dkg"
+	pedersen "go.dedis.ch/dela/dkg/pedersen_bn256"
 	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/proxy"
 	"go.dedis.ch/kyber/v3"
-	"go.dedis.ch/kyber/v3/suites"
 	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
 
@@ -56,6 +67,28 @@ func (a setupAction) Execute(ctx node.Context) error {
 	return nil
 }
 
+type resumeAction struct{}
+
+// Execute implements node.ActionTemplate. It restores the outcome of the last
+// setup persisted by the node, instead of running a new setup.
+func (a resumeAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf(resolveActorFailed, err)
+	}
+
+	pubkey, err := actor.Resume()
+	if err != nil {
+		return xerrors.Errorf("failed to resume: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "✅ DKG resumed.\n🔑 Pubkey: %s", pubkey.String())
+
+	return nil
+}
+
 func getCollectiveAuth(ctx node.Context) (crypto.CollectiveAuthority, error) {
 	authorities := ctx.Flags.StringSlice("authority")
 
@@ -262,9 +295,9 @@ func (a encryptAction) Execute(ctx node.Context) error {
 		return xerrors.Errorf(resolveActorFailed, err)
 	}
 
-	label, err := hex.DecodeString(ctx.Flags.String("label"))
+	label, err := getLabel(ctx.Flags)
 	if err != nil {
-		return xerrors.Errorf("failed to decode label: %v", err)
+		return xerrors.Errorf("failed to get label: %v", err)
 	}
 
 	message, err := hex.DecodeString(ctx.Flags.String("message"))
@@ -309,9 +342,9 @@ func (a decryptAction) Execute(ctx node.Context) error {
 		return xerrors.Errorf(resolveActorFailed, err)
 	}
 
-	label, err := hex.DecodeString(ctx.Flags.String("label"))
+	label, err := getLabel(ctx.Flags)
 	if err != nil {
-		return xerrors.Errorf("failed to decode label: %v", err)
+		return xerrors.Errorf("failed to get label: %v", err)
 	}
 
 	ctBytes, err := hex.DecodeString(ctx.Flags.String("ciphertext"))
@@ -349,6 +382,428 @@ func (a decryptAction) Execute(ctx node.Context) error {
 
 // reshare
 
+type getReleasedKeyAction struct{}
+
+func (a getReleasedKeyAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf(resolveActorFailed, err)
+	}
+
+	label, err := hex.DecodeString(ctx.Flags.String("label"))
+	if err != nil {
+		return xerrors.Errorf("failed to decode label: %v", err)
+	}
+
+	key, err := actor.GetReleasedKey(label)
+	if err != nil {
+		return xerrors.Errorf("failed to get released key: %v", err)
+	}
+
+	fmt.Fprint(ctx.Out, hex.EncodeToString(key))
+
+	return nil
+}
+
+type pruneAction struct{}
+
+// Execute implements node.ActionTemplate. It deletes the expired released
+// keys.
+func (a pruneAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf(resolveActorFailed, err)
+	}
+
+	count, err := actor.Prune(uint64(ctx.Flags.Int("height")))
+	if err != nil {
+		return xerrors.Errorf("failed to prune: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "✅ %d released keys deleted.\n", count)
+
+	return nil
+}
+
+type auditAction struct{}
+
+// Execute implements node.ActionTemplate. It exports the history of the keys
+// released by the node in CSV, to the file if one is given.
+func (a auditAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf(resolveActorFailed, err)
+	}
+
+	filter := dkg.ReleaseFilter{}
+
+	if ctx.Flags.String("label") != "" {
+		filter.Label, err = hex.DecodeString(ctx.Flags.String("label"))
+		if err != nil {
+			return xerrors.Errorf("failed to decode label: %v", err)
+		}
+	}
+
+	filter.Since, err = parseTime(ctx.Flags.String("since"))
+	if err != nil {
+		return xerrors.Errorf("failed to parse since: %v", err)
+	}
+
+	filter.Until, err = parseTime(ctx.Flags.String("until"))
+	if err != nil {
+		return xerrors.Errorf("failed to parse until: %v", err)
+	}
+
+	releases, err := actor.GetReleases(filter)
+	if err != nil {
+		return xerrors.Errorf("failed to get releases: %v", err)
+	}
+
+	path := ctx.Flags.String("out")
+	if path == "" {
+		return writeReleases(ctx.Out, releases)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return xerrors.Errorf("failed to create file: %v", err)
+	}
+
+	err = writeReleases(file, releases)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return xerrors.Errorf("failed to close file: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "✅ %d releases exported to %s\n", len(releases), path)
+
+	return nil
+}
+
+// parseTime returns the time in RFC3339, or the zero time if it is empty.
+func parseTime(str string) (time.Time, error) {
+	if str == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, str)
+}
+
+// writeReleases writes the releases in CSV, with one row per release and the
+// contributors separated by a semicolon.
+func writeReleases(out io.Writer, releases []dkg.Release) error {
+	w := csv.NewWriter(out)
+
+	err := w.Write([]string{"time", "label", "requester", "contributors"})
+	if err != nil {
+		return xerrors.Errorf("failed to write header: %v", err)
+	}
+
+	for _, release := range releases {
+		err = w.Write([]string{
+			release.Time.Format(time.RFC3339Nano),
+			hex.EncodeToString(release.Label),
+			release.Requester,
+			strings.Join(release.Contributors, ";"),
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to write release: %v", err)
+		}
+	}
+
+	w.Flush()
+
+	err = w.Error()
+	if err != nil {
+		return xerrors.Errorf("failed to flush: %v", err)
+	}
+
+	return nil
+}
+
+type healthAction struct{}
+
+// Execute implements node.ActionTemplate. It registers a liveness handler that
+// checks the database and the network, and a readiness handler that also
+// expects the DKG to be set up.
+func (a healthAction) Execute(ctx node.Context) error {
+	var proxyhttp proxy.Proxy
+
+	err := ctx.Injector.Resolve(&proxyhttp)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve the proxy: %v", err)
+	}
+
+	live := []check{
+		{name: "db", fn: func() error { return checkDB(ctx.Injector) }},
+		{name: "mino", fn: func() error { return checkMino(ctx.Injector) }},
+	}
+
+	ready := append(live, check{name: "dkg", fn: func() error {
+		return checkDKG(ctx.Injector)
+	}})
+
+	proxyhttp.RegisterHandler(ctx.Flags.String("healthPath"), serveChecks(live))
+	proxyhttp.RegisterHandler(ctx.Flags.String("readyPath"), serveChecks(ready))
+
+	fmt.Fprintf(ctx.Out, "registered health handlers on %q and %q",
+		ctx.Flags.String("healthPath"), ctx.Flags.String("readyPath"))
+
+	return nil
+}
+
+// check is a named condition verified by a health handler.
+type check struct {
+	name string
+	fn   func() error
+}
+
+// serveChecks returns a handler that replies 200 when every check passes, or
+// 503 with the first failing check.
+func serveChecks(checks []check) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, c := range checks {
+			err := c.fn()
+			if err != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprintf(w, "%s: %v", c.name, err)
+				return
+			}
+		}
+
+		fmt.Fprint(w, "ok")
+	}
+}
+
+func checkDB(inj node.Injector) error {
+	var db kv.DB
+
+	err := inj.Resolve(&db)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve db: %v", err)
+	}
+
+	err = db.View(func(kv.ReadableTx) error { return nil })
+	if err != nil {
+		return xerrors.Errorf("failed to read: %v", err)
+	}
+
+	return nil
+}
+
+func checkMino(inj node.Injector) error {
+	var m mino.Mino
+
+	err := inj.Resolve(&m)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve mino: %v", err)
+	}
+
+	return nil
+}
+
+func checkDKG(inj node.Injector) error {
+	var actor dkg.Actor
+
+	err := inj.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve actor: %v", err)
+	}
+
+	_, err = actor.GetPublicKey()
+	if err != nil {
+		return xerrors.Errorf("not set up: %v", err)
+	}
+
+	return nil
+}
+
+type keysAction struct{}
+
+// Execute implements node.ActionTemplate. It registers a handler that returns
+// the key released for the hex-encoded label that follows the path.
+func (a keysAction) Execute(ctx node.Context) error {
+	var proxyhttp proxy.Proxy
+
+	err := ctx.Injector.Resolve(&proxyhttp)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve the proxy: %v", err)
+	}
+
+	path := ctx.Flags.String("keysPath")
+
+	proxyhttp.RegisterHandler(path, serveReleasedKeys(path, ctx.Injector))
+
+	fmt.Fprintf(ctx.Out, "registered keys handler on %q", path)
+
+	return nil
+}
+
+// releasedKey is the reply of the keys handler.
+type releasedKey struct {
+	Label string
+	Key   string
+}
+
+// serveReleasedKeys returns a handler that replies the released key of the
+// label, or 404 if the key has not been released.
+func serveReleasedKeys(prefix string, inj node.Injector) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		label, err := hex.DecodeString(strings.TrimPrefix(r.URL.Path, prefix))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "failed to decode label: %v", err)
+			return
+		}
+
+		var actor dkg.Actor
+
+		err = inj.Resolve(&actor)
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "failed to resolve actor: %v", err)
+			return
+		}
+
+		key, err := actor.GetReleasedKey(label)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(releasedKey{
+			Label: hex.EncodeToString(label),
+			Key:   hex.EncodeToString(key),
+		})
+	}
+}
+
+type infoAction struct{}
+
+// Execute implements node.ActionTemplate. It registers a handler that returns
+// the committee of the DKG, signed by the node.
+func (a infoAction) Execute(ctx node.Context) error {
+	var proxyhttp proxy.Proxy
+
+	err := ctx.Injector.Resolve(&proxyhttp)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve the proxy: %v", err)
+	}
+
+	path := ctx.Flags.String("infoPath")
+
+	proxyhttp.RegisterHandler(path, serveInfo(ctx.Injector))
+
+	fmt.Fprintf(ctx.Out, "registered info handler on %q", path)
+
+	return nil
+}
+
+// infoMember is a member of the committee in the reply of the info handler.
+// The address is the text representation used in the signed digest, encoded
+// in base64 like in the member commands.
+type infoMember struct {
+	Address   string
+	PublicKey string
+}
+
+// infoReply is the reply of the info handler. The keys and the signature are
+// encoded in hex.
+type infoReply struct {
+	Participants []infoMember
+	Threshold    int
+	PublicKey    string
+	Scheme       string
+	Node         string
+	Signature    string
+}
+
+// serveInfo returns a handler that replies the committee, the threshold and
+// the collective public key, signed by the node.
+func serveInfo(inj node.Injector) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var actor dkg.Actor
+
+		err := inj.Resolve(&actor)
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "failed to resolve actor: %v", err)
+			return
+		}
+
+		info, err := actor.GetInfo()
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "failed to get info: %v", err)
+			return
+		}
+
+		reply, err := makeInfoReply(info)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reply)
+	}
+}
+
+func makeInfoReply(info dkg.Info) (infoReply, error) {
+	reply := infoReply{
+		Participants: make([]infoMember, len(info.Participants)),
+		Threshold:    info.Threshold,
+		Scheme:       info.Scheme,
+		Signature:    hex.EncodeToString(info.Signature),
+	}
+
+	for i, addr := range info.Participants {
+		text, err := addr.MarshalText()
+		if err != nil {
+			return reply, xerrors.Errorf("failed to marshal address: %v", err)
+		}
+
+		pubkey, err := info.PublicKeys[i].MarshalBinary()
+		if err != nil {
+			return reply, xerrors.Errorf("failed to marshal public key: %v", err)
+		}
+
+		reply.Participants[i] = infoMember{
+			Address:   base64.StdEncoding.EncodeToString(text),
+			PublicKey: hex.EncodeToString(pubkey),
+		}
+	}
+
+	pubkey, err := info.PublicKey.MarshalBinary()
+	if err != nil {
+		return reply, xerrors.Errorf("failed to marshal public key: %v", err)
+	}
+
+	node, err := info.Node.MarshalBinary()
+	if err != nil {
+		return reply, xerrors.Errorf("failed to marshal node key: %v", err)
+	}
+
+	reply.PublicKey = hex.EncodeToString(pubkey)
+	reply.Node = hex.EncodeToString(node)
+
+	return reply, nil
+}
+
 type reshareAction struct{}
 
 func (a reshareAction) Execute(ctx node.Context) error {
@@ -375,3 +830,96 @@ func (a reshareAction) Execute(ctx node.Context) error {
 
 	return nil
 }
+
+type epochsAction struct{}
+
+// Execute implements node.Action. It samples a new committee out of the pool
+// at every epoch of the chain and hands the secret over to it.
+func (a epochsAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf(resolveActorFailed, err)
+	}
+
+	var srvc ordering.Service
+
+	err = ctx.Injector.Resolve(&srvc)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve ordering service: %v", err)
+	}
+
+	var randomness pedersen.Randomness
+
+	err = ctx.Injector.Resolve(&randomness)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve randomness: %v", err)
+	}
+
+	var no mino.Mino
+
+	err = ctx.Injector.Resolve(&no)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve mino: %v", err)
+	}
+
+	pool, err := getCollectiveAuth(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to get collective authority: %v", err)
+	}
+
+	length := ctx.Flags.Int("epochLength")
+	if length <= 0 {
+		return xerrors.Errorf("invalid epoch length: %d", length)
+	}
+
+	size := ctx.Flags.Int("committeeSize")
+
+	param := pedersen.EpochParam{
+		Pool:       pool,
+		Randomness: randomness,
+		Length:     uint64(length),
+		Size:       size,
+		Threshold:  ctx.Flags.Int("threshold"),
+		Timeout:    ctx.Flags.Duration("timeout"),
+	}
+
+	// The relays are never sampled when the chain has some.
+	var roles pedersen.Roles
+	err = ctx.Injector.Resolve(&roles)
+	if err == nil {
+		param.Roles = roles
+	}
+
+	handoff := pedersen.NewHandoff(pedersen.NewEpochs(param), actor,
+		no.GetAddress())
+
+	go handoff.Listen(srvc.Watch(context.Background()))
+
+	fmt.Fprintf(ctx.Out, "✅ Committees of %d members sampled every %d blocks.\n",
+		size, length)
+
+	return nil
+}
+
+// getLabel returns the IBE label from the flags. A deadline given with
+// --release-at takes precedence over the hex-encoded --label.
+func getLabel(flags cli.Flags) ([]byte, error) {
+	releaseAt := flags.String("release-at")
+	if releaseAt == "" {
+		label, err := hex.DecodeString(flags.String("label"))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode label: %v", err)
+		}
+
+		return label, nil
+	}
+
+	deadline, err := time.Parse(time.RFC3339, releaseAt)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse deadline: %v", err)
+	}
+
+	return ibe.NewTimeLabel(deadline), nil
+}
@@ -2,15 +2,24 @@ package controller
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/dkg"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/proxy"
 	"go.dedis.ch/kyber/v3"
 )
 
@@ -89,6 +98,61 @@ func TestSetupAction_OK(t *testing.T) {
 	require.Regexp(t, "^✅ Setup done", out.String())
 }
 
+func TestResumeAction_Execute(t *testing.T) {
+	a := resumeAction{}
+
+	inj := node.NewInjector()
+
+	out := &bytes.Buffer{}
+	ctx := node.Context{
+		Injector: inj,
+		Out:      out,
+	}
+
+	err := a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve actor, did you call listen?: "+
+		"couldn't find dependency for 'dkg.Actor'")
+
+	inj.Inject(fakeActor{resumeErr: fake.GetError()})
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to resume"))
+
+	inj.Inject(fakeActor{})
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Regexp(t, "DKG resumed", out.String())
+}
+
+func TestPruneAction_Execute(t *testing.T) {
+	a := pruneAction{}
+
+	inj := node.NewInjector()
+
+	out := &bytes.Buffer{}
+	ctx := node.Context{
+		Injector: inj,
+		Flags:    node.FlagSet{"height": 10},
+		Out:      out,
+	}
+
+	err := a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve actor, did you call listen?: "+
+		"couldn't find dependency for 'dkg.Actor'")
+
+	inj.Inject(fakeActor{pruneErr: fake.GetError()})
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to prune"))
+
+	inj.Inject(fakeActor{pruned: 3})
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "✅ 3 released keys deleted.\n", out.String())
+}
+
 func TestListenAction_NoDKG(t *testing.T) {
 	a := listenAction{}
 
@@ -318,6 +382,41 @@ func TestEncryptAction_badMessage(t *testing.T) {
 	require.Regexp(t, "^failed to decode message:", err.Error())
 }
 
+func TestEncryptAction_badReleaseAt(t *testing.T) {
+	a := encryptAction{}
+
+	inj := node.NewInjector()
+	inj.Inject(fakeActor{})
+
+	flags := node.FlagSet{
+		"release-at": "tomorrow",
+	}
+
+	ctx := node.Context{
+		Injector: inj,
+		Flags:    flags,
+	}
+
+	err := a.Execute(ctx)
+	require.Regexp(t, "^failed to get label: failed to parse deadline:", err.Error())
+}
+
+func TestGetLabel(t *testing.T) {
+	label, err := getLabel(node.FlagSet{"label": "aabb"})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xaa, 0xbb}, label)
+
+	label, err = getLabel(node.FlagSet{
+		"label":      "aabb",
+		"release-at": "2024-06-01T12:00:00Z",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "f3b:time:2024-06-01T12:00:00Z", string(label))
+
+	_, err = getLabel(node.FlagSet{"label": "not hex"})
+	require.Regexp(t, "^failed to decode label:", err.Error())
+}
+
 func TestDecryptAction_noActor(t *testing.T) {
 	a := decryptAction{}
 
@@ -332,6 +431,308 @@ func TestDecryptAction_noActor(t *testing.T) {
 		" couldn't find dependency for 'dkg.Actor'")
 }
 
+func TestGetReleasedKeyAction_Execute(t *testing.T) {
+	a := getReleasedKeyAction{}
+
+	inj := node.NewInjector()
+	out := &bytes.Buffer{}
+
+	ctx := node.Context{
+		Injector: inj,
+		Flags:    node.FlagSet{"label": "aabb"},
+		Out:      out,
+	}
+
+	err := a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve actor, did you call listen?: "+
+		"couldn't find dependency for 'dkg.Actor'")
+
+	inj.Inject(fakeActor{releasedKey: []byte{0xcc}})
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "cc", out.String())
+
+	inj.Inject(fakeActor{releasedKeyErr: fake.GetError()})
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to get released key"))
+
+	ctx.Flags = node.FlagSet{"label": "not hex"}
+
+	err = a.Execute(ctx)
+	require.Regexp(t, "^failed to decode label:", err.Error())
+}
+
+func TestAuditAction_Execute(t *testing.T) {
+	a := auditAction{}
+
+	inj := node.NewInjector()
+	out := &bytes.Buffer{}
+
+	ctx := node.Context{
+		Injector: inj,
+		Flags: node.FlagSet{
+			"label": "aabb",
+			"since": "2023-01-01T00:00:00Z",
+		},
+		Out: out,
+	}
+
+	err := a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve actor, did you call listen?: "+
+		"couldn't find dependency for 'dkg.Actor'")
+
+	filter := dkg.ReleaseFilter{}
+
+	inj.Inject(fakeActor{
+		releases: []dkg.Release{
+			{
+				Label:        []byte{0xaa, 0xbb},
+				Requester:    "A",
+				Contributors: []string{"B", "C"},
+				Time:         time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		filter: &filter,
+	})
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "time,label,requester,contributors\n"+
+		"2023-01-02T00:00:00Z,aabb,A,B;C\n", out.String())
+
+	require.Equal(t, []byte{0xaa, 0xbb}, filter.Label)
+	require.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), filter.Since)
+	require.True(t, filter.Until.IsZero())
+
+	path := filepath.Join(t.TempDir(), "audit.csv")
+	ctx.Flags = node.FlagSet{"out": path}
+	out.Reset()
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "✅ 1 releases exported to "+path+"\n", out.String())
+	require.Nil(t, filter.Label)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "time,label,requester,contributors\n"+
+		"2023-01-02T00:00:00Z,aabb,A,B;C\n", string(data))
+
+	ctx.Flags = node.FlagSet{"out": filepath.Join(path, "invalid")}
+
+	err = a.Execute(ctx)
+	require.Regexp(t, "^failed to create file: ", err.Error())
+
+	inj.Inject(fakeActor{releasesErr: fake.GetError()})
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to get releases"))
+
+	ctx.Flags = node.FlagSet{"until": "yesterday"}
+
+	err = a.Execute(ctx)
+	require.Regexp(t, "^failed to parse until: ", err.Error())
+
+	ctx.Flags = node.FlagSet{"since": "yesterday"}
+
+	err = a.Execute(ctx)
+	require.Regexp(t, "^failed to parse since: ", err.Error())
+
+	ctx.Flags = node.FlagSet{"label": "not hex"}
+
+	err = a.Execute(ctx)
+	require.Regexp(t, "^failed to decode label: ", err.Error())
+}
+
+func TestHealthAction_Execute(t *testing.T) {
+	a := healthAction{}
+
+	inj := node.NewInjector()
+	prox := &fakeProxy{handlers: make(map[string]func(http.ResponseWriter, *http.Request))}
+
+	ctx := node.Context{
+		Injector: inj,
+		Flags:    node.FlagSet{"healthPath": "/healthz", "readyPath": "/readyz"},
+		Out:      io.Discard,
+	}
+
+	err := a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve the proxy: "+
+		"couldn't find dependency for 'proxy.Proxy'")
+
+	inj.Inject(prox)
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, prox.handlers, 2)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		prox.handlers[path](rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get("/healthz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "db: failed to resolve db: couldn't find dependency for 'kv.DB'", body)
+
+	inj.Inject(fake.NewBadViewDB())
+
+	_, body = get("/healthz")
+	require.Equal(t, fake.Err("db: failed to read"), body)
+
+	inj.Inject(fake.NewInMemoryDB())
+
+	_, body = get("/healthz")
+	require.Equal(t, "mino: failed to resolve mino: couldn't find dependency for 'mino.Mino'", body)
+
+	inj.Inject(fake.Mino{})
+
+	code, body = get("/healthz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ok", body)
+
+	_, body = get("/readyz")
+	require.Equal(t, "dkg: failed to resolve actor: couldn't find dependency for 'dkg.Actor'", body)
+
+	inj.Inject(fakeActor{pubkeyErr: fake.GetError()})
+
+	_, body = get("/readyz")
+	require.Equal(t, fake.Err("dkg: not set up"), body)
+
+	inj.Inject(fakeActor{})
+
+	code, body = get("/readyz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ok", body)
+}
+
+func TestKeysAction_Execute(t *testing.T) {
+	a := keysAction{}
+
+	inj := node.NewInjector()
+	prox := &fakeProxy{handlers: make(map[string]func(http.ResponseWriter, *http.Request))}
+
+	ctx := node.Context{
+		Injector: inj,
+		Flags:    node.FlagSet{"keysPath": "/keys/"},
+		Out:      io.Discard,
+	}
+
+	err := a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve the proxy: "+
+		"couldn't find dependency for 'proxy.Proxy'")
+
+	inj.Inject(prox)
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, prox.handlers, 1)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		prox.handlers["/keys/"](rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get("/keys/zz")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "failed to decode label: encoding/hex: invalid byte: U+007A 'z'", body)
+
+	code, body = get("/keys/aabb")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "failed to resolve actor: couldn't find dependency for 'dkg.Actor'", body)
+
+	inj.Inject(fakeActor{releasedKeyErr: fake.GetError()})
+
+	code, body = get("/keys/aabb")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, fake.GetError().Error(), body)
+
+	inj.Inject(fakeActor{releasedKey: []byte{0xcc}})
+
+	code, body = get("/keys/aabb")
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `{"Label":"aabb","Key":"cc"}`, body)
+}
+
+func TestInfoAction_Execute(t *testing.T) {
+	a := infoAction{}
+
+	inj := node.NewInjector()
+	prox := &fakeProxy{handlers: make(map[string]func(http.ResponseWriter, *http.Request))}
+
+	ctx := node.Context{
+		Injector: inj,
+		Flags:    node.FlagSet{"infoPath": "/info"},
+		Out:      io.Discard,
+	}
+
+	err := a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve the proxy: "+
+		"couldn't find dependency for 'proxy.Proxy'")
+
+	inj.Inject(prox)
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, prox.handlers, 1)
+
+	get := func() (int, string) {
+		rec := httptest.NewRecorder()
+		prox.handlers["/info"](rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get()
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "failed to resolve actor: couldn't find dependency for 'dkg.Actor'", body)
+
+	inj.Inject(fakeActor{infoErr: fake.GetError()})
+
+	code, body = get()
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, fake.Err("failed to get info"), body)
+
+	info := dkg.Info{
+		Participants: []mino.Address{fake.NewAddress(0)},
+		PublicKeys:   []kyber.Point{badPoint{err: fake.GetError()}},
+		PublicKey:    suite.Point(),
+		Node:         suite.Point(),
+	}
+
+	inj.Inject(fakeActor{info: info})
+
+	code, body = get()
+	require.Equal(t, http.StatusInternalServerError, code)
+	require.Equal(t, fake.Err("failed to marshal public key"), body)
+
+	info.PublicKeys = []kyber.Point{badPoint{data: "\xaa"}}
+	info.Threshold = 1
+	info.Scheme = "scheme"
+	info.Signature = []byte{0xbb}
+
+	inj.Inject(fakeActor{info: info})
+
+	code, body = get()
+	require.Equal(t, http.StatusOK, code)
+
+	var reply infoReply
+	require.NoError(t, json.Unmarshal([]byte(body), &reply))
+	require.Equal(t, []infoMember{{Address: "AAAAAA==", PublicKey: "aa"}},
+		reply.Participants)
+	require.Equal(t, 1, reply.Threshold)
+	require.Equal(t, "scheme", reply.Scheme)
+	require.Equal(t, "bb", reply.Signature)
+	require.NotEmpty(t, reply.PublicKey)
+	require.NotEmpty(t, reply.Node)
+}
+
 func TestReshareAction_noActor(t *testing.T) {
 	a := reshareAction{}
 
@@ -406,6 +807,73 @@ func TestReshareAction_OK(t *testing.T) {
 	require.Equal(t, "✅ Reshare done.\n", out.String())
 }
 
+func TestEpochsAction_Execute(t *testing.T) {
+	a := epochsAction{}
+
+	inj := node.NewInjector()
+
+	flags := node.FlagSet{
+		"epochLength":   10,
+		"committeeSize": 3,
+		"threshold":     2,
+	}
+
+	out := &bytes.Buffer{}
+
+	ctx := node.Context{
+		Injector: inj,
+		Flags:    flags,
+		Out:      out,
+	}
+
+	err := a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve actor, did you call listen?: "+
+		"couldn't find dependency for 'dkg.Actor'")
+
+	inj.Inject(&fakeActor{})
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve ordering service: "+
+		"couldn't find dependency for 'ordering.Service'")
+
+	events := make(chan ordering.Event)
+	inj.Inject(fakeOrdering{events: events})
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve randomness: "+
+		"couldn't find dependency for 'pedersen.Randomness'")
+
+	inj.Inject(fakeRandomness{})
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve mino: "+
+		"couldn't find dependency for 'mino.Mino'")
+
+	inj.Inject(fake.Mino{})
+
+	flags["authority"] = []interface{}{"fake"}
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, "failed to get collective authority: "+
+		"failed to decode authority: invalid identity base64 string")
+
+	delete(flags, "authority")
+	flags["epochLength"] = 0
+
+	err = a.Execute(ctx)
+	require.EqualError(t, err, "invalid epoch length: 0")
+
+	flags["epochLength"] = 10
+
+	err = a.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "✅ Committees of 3 members sampled every 10 blocks.\n",
+		out.String())
+
+	// The handoff stops with the events.
+	close(events)
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
@@ -424,6 +892,27 @@ type fakeActor struct {
 
 	decryptData  []byte
 	vdecryptData [][]byte
+
+	releasedKey    []byte
+	releasedKeyErr error
+
+	pubkeyErr error
+
+	info    dkg.Info
+	infoErr error
+
+	resumeErr error
+
+	pruned   int
+	pruneErr error
+
+	releases    []dkg.Release
+	releasesErr error
+	filter      *dkg.ReleaseFilter
+}
+
+func (f fakeActor) GetPublicKey() (kyber.Point, error) {
+	return suite.Point(), f.pubkeyErr
 }
 
 func (f fakeActor) Setup(co crypto.CollectiveAuthority, threshold int) (pubKey kyber.Point, err error) {
@@ -438,6 +927,38 @@ func (f fakeActor) Decrypt(K, C kyber.Point) ([]byte, error) {
 	return f.decryptData, f.decryptErr
 }
 
+func (f fakeActor) GetReleasedKey(label []byte) ([]byte, error) {
+	return f.releasedKey, f.releasedKeyErr
+}
+
+func (f fakeActor) GetKeys(labels [][]byte) ([][]byte, error) {
+	return nil, nil
+}
+
+func (f fakeActor) ReleaseBlock(height uint64, labels [][]byte) (dkg.BlockRelease, error) {
+	return dkg.BlockRelease{}, nil
+}
+
+func (f fakeActor) Resume() (kyber.Point, error) {
+	return suite.Point(), f.resumeErr
+}
+
+func (f fakeActor) Prune(height uint64) (int, error) {
+	return f.pruned, f.pruneErr
+}
+
+func (f fakeActor) GetReleases(filter dkg.ReleaseFilter) ([]dkg.Release, error) {
+	if f.filter != nil {
+		*f.filter = filter
+	}
+
+	return f.releases, f.releasesErr
+}
+
+func (f fakeActor) GetInfo() (dkg.Info, error) {
+	return f.info, f.infoErr
+}
+
 func (f fakeActor) Reshare(co crypto.CollectiveAuthority, newThreshold int) error {
 	return f.reshareErr
 }
@@ -484,3 +1005,29 @@ type fakeMino struct {
 func (f fakeMino) GetAddress() mino.Address {
 	return f.addr
 }
+
+type fakeProxy struct {
+	proxy.Proxy
+
+	handlers map[string]func(http.ResponseWriter, *http.Request)
+}
+
+func (p *fakeProxy) RegisterHandler(path string, handler func(http.ResponseWriter, *http.Request)) {
+	p.handlers[path] = handler
+}
+
+type fakeOrdering struct {
+	ordering.Service
+
+	events chan ordering.Event
+}
+
+func (o fakeOrdering) Watch(ctx context.Context) <-chan ordering.Event {
+	return o.events
+}
+
+type fakeRandomness struct{}
+
+func (fakeRandomness) GetRandomness(index uint64) ([]byte, error) {
+	return []byte{byte(index)}, nil
+}
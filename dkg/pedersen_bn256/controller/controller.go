@@ -1,14 +1,26 @@
 package controller
 
 import (
+	"path/filepath"
+	"time"
+
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/crypto/loader"
 	"go.dedis.ch/dela/dkg/pedersen_bn256"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/kyber/v3"
 	"golang.org/x/xerrors"
 )
 
+// privateKeyFile is the name of the file, in the configuration folder, that
+// holds the long-term key of the node in the DKG.
+const privateKeyFile = "dkg.key"
+
 // NewMinimal returns a new minimal initializer
 func NewMinimal() node.Initializer {
 	return minimal{
@@ -26,6 +38,31 @@ type minimal struct {
 
 // Build implements node.Initializer. In this case we don't need any command.
 func (m minimal) SetCommands(builder node.Builder) {
+	builder.SetStartFlags(
+		cli.DurationFlag{
+			Name: "dkgTimeout",
+			Usage: "bound each phase of the DKG setup so that it completes " +
+				"without the unreachable participants. By default, the setup " +
+				"waits for every participant",
+		},
+		cli.StringSliceFlag{
+			Name: "dkgNamespaces",
+			Usage: "only release the keys of the labels in the namespaces, " +
+				"like f3b:block, f3b:tx, f3b:time or app:<id>, or 'raw' for the " +
+				"labels without a namespace. By default, any label is released",
+		},
+		cli.IntFlag{
+			Name: "dkgRetainBlocks",
+			Usage: "number of blocks after which the released key of a block " +
+				"label is deleted by prune. By default, they are kept",
+		},
+		cli.DurationFlag{
+			Name: "dkgRetainAge",
+			Usage: "duration after its deadline when the released key of a " +
+				"time label is deleted by prune. By default, they are kept",
+		},
+	)
+
 	cmd := builder.SetCommand("dkg")
 	cmd.SetDescription("DKG service administration")
 
@@ -41,12 +78,18 @@ func (m minimal) SetCommands(builder node.Builder) {
 			Usage: "<ADDR>:<PK> string, where each token is encoded in base64",
 		},
 		cli.IntFlag{
-			Name:  "threshold",
-			Usage: "the threshold of the committee",
+			Name: "threshold",
+			Usage: "the threshold of the committee, by default the one of " +
+				"the chain",
 		},
 	)
 	sub.SetAction(builder.MakeAction(setupAction{}))
 
+	sub = cmd.SetSubCommand("resume")
+	sub.SetDescription("restore the result of the last setup after a restart, " +
+		"instead of running a new setup")
+	sub.SetAction(builder.MakeAction(resumeAction{}))
+
 	sub = cmd.SetSubCommand("get-public-key")
 	sub.SetDescription("Query the collective public key. Outputs in hex")
 	sub.SetAction(builder.MakeAction(getPublicKeyAction{}))
@@ -82,6 +125,11 @@ func (m minimal) SetCommands(builder node.Builder) {
 			Name:  "label",
 			Usage: "the IBE label to encrypt to, encoded in hex",
 		},
+		cli.StringFlag{
+			Name: "release-at",
+			Usage: "a deadline in RFC3339 after which the key is released, " +
+				"used as the label instead of --label",
+		},
 		cli.StringFlag{
 			Name:  "message",
 			Usage: "the message to encrypt, encoded in hex",
@@ -96,6 +144,11 @@ func (m minimal) SetCommands(builder node.Builder) {
 			Name:  "label",
 			Usage: "the IBE label to encrypt to, encoded in hex",
 		},
+		cli.StringFlag{
+			Name: "release-at",
+			Usage: "a deadline in RFC3339 after which the key is released, " +
+				"used as the label instead of --label",
+		},
 		cli.StringFlag{
 			Name:  "ciphertext",
 			Usage: "the ciphertext to decrypt, encoded in hex",
@@ -103,6 +156,93 @@ func (m minimal) SetCommands(builder node.Builder) {
 	)
 	sub.SetAction(builder.MakeAction(decryptAction{}))
 
+	sub = cmd.SetSubCommand("get-released-key")
+	sub.SetDescription("get a key already released by sign. Outputs the key in hex")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "label",
+			Usage: "the IBE label of the key, encoded in hex",
+		},
+	)
+	sub.SetAction(builder.MakeAction(getReleasedKeyAction{}))
+
+	sub = cmd.SetSubCommand("prune")
+	sub.SetDescription("delete the released keys that expired according to " +
+		"the retention flags of the node")
+	sub.SetFlags(
+		cli.IntFlag{
+			Name:     "height",
+			Usage:    "the current height of the chain",
+			Required: true,
+		},
+	)
+	sub.SetAction(builder.MakeAction(pruneAction{}))
+
+	sub = cmd.SetSubCommand("audit")
+	sub.SetDescription("export the history of the keys released by the node " +
+		"in CSV")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "label",
+			Usage: "only export the releases of the IBE label, encoded in hex",
+		},
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "only export the releases from the time, in RFC3339",
+		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "only export the releases up to the time, in RFC3339",
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "the path of the CSV file, or the standard output if empty",
+		},
+	)
+	sub.SetAction(builder.MakeAction(auditAction{}))
+
+	sub = cmd.SetSubCommand("health")
+	sub.SetDescription("registers the health and readiness handlers on the " +
+		"proxy. The proxy must be started first.")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "healthPath",
+			Usage: "the path of the liveness handler",
+			Value: "/healthz",
+		},
+		cli.StringFlag{
+			Name:  "readyPath",
+			Usage: "the path of the readiness handler",
+			Value: "/readyz",
+		},
+	)
+	sub.SetAction(builder.MakeAction(healthAction{}))
+
+	sub = cmd.SetSubCommand("keys")
+	sub.SetDescription("registers the handler of the released keys on the " +
+		"proxy. The proxy must be started first.")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "keysPath",
+			Usage: "the path prefix of the keys handler, followed by the label in hex",
+			Value: "/keys/",
+		},
+	)
+	sub.SetAction(builder.MakeAction(keysAction{}))
+
+	sub = cmd.SetSubCommand("info")
+	sub.SetDescription("registers the handler that returns the committee, the " +
+		"threshold and the public key, signed by the node. The proxy must be " +
+		"started first.")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "infoPath",
+			Usage: "the path of the info handler",
+			Value: "/info",
+		},
+	)
+	sub.SetAction(builder.MakeAction(infoAction{}))
+
 	sub = cmd.SetSubCommand("reshare")
 	sub.SetDescription("reshare the DKG secret")
 	sub.SetFlags(
@@ -117,6 +257,37 @@ func (m minimal) SetCommands(builder node.Builder) {
 		},
 	)
 	sub.SetAction(builder.MakeAction(reshareAction{}))
+
+	sub = cmd.SetSubCommand("epochs")
+	sub.SetDescription("hand the DKG secret over to a committee sampled out " +
+		"of the pool at every epoch")
+	sub.SetFlags(
+		cli.StringSliceFlag{
+			Name:  "authority",
+			Usage: "<ADDR>:<PK> string of a member of the pool",
+		},
+		cli.IntFlag{
+			Name:     "epochLength",
+			Usage:    "the number of blocks of an epoch",
+			Required: true,
+		},
+		cli.IntFlag{
+			Name:  "committeeSize",
+			Usage: "the number of members of a committee, the whole pool if 0",
+		},
+		cli.IntFlag{
+			Name:     "threshold",
+			Usage:    "the threshold of the committees",
+			Required: true,
+		},
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "how long each member of the committee waits for the " +
+				"previous one to hand the secret over before doing it",
+			Value: 5 * time.Minute,
+		},
+	)
+	sub.SetAction(builder.MakeAction(epochsAction{}))
 }
 
 // OnStart implements node.Initializer. It creates and registers a pedersen DKG.
@@ -127,7 +298,52 @@ func (m minimal) OnStart(ctx cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to resolve mino: %v", err)
 	}
 
-	dkg, pubkey := pedersen.NewPedersen(no)
+	privkey, err := loadPrivateKey(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to load private key: %v", err)
+	}
+
+	opts := []pedersen.Option{pedersen.WithPrivateKey(privkey)}
+
+	// Released keys are persisted when the node has a database.
+	var db kv.DB
+	err = inj.Resolve(&db)
+	if err == nil {
+		opts = append(opts, pedersen.WithKeyStore(db))
+	}
+
+	// The relays of the chain are refused as members when the node has one.
+	var roles pedersen.Roles
+	err = inj.Resolve(&roles)
+	if err == nil {
+		opts = append(opts, pedersen.WithRoles(roles))
+	}
+
+	// The released keys are published when the node has an event bus.
+	var bus events.Service
+	err = inj.Resolve(&bus)
+	if err == nil {
+		opts = append(opts, pedersen.WithEvents(bus))
+	}
+
+	timeout := ctx.Duration("dkgTimeout")
+	if timeout > 0 {
+		opts = append(opts, pedersen.WithTimeout(timeout))
+	}
+
+	namespaces := ctx.StringSlice("dkgNamespaces")
+	if len(namespaces) > 0 {
+		opts = append(opts, pedersen.WithLabelRegistry(makeRegistry(namespaces)))
+	}
+
+	retention := pedersen.RetentionPolicy{
+		Blocks: uint64(ctx.Int("dkgRetainBlocks")),
+		Age:    ctx.Duration("dkgRetainAge"),
+	}
+
+	opts = append(opts, pedersen.WithRetention(retention))
+
+	dkg, pubkey := pedersen.NewPedersen(no, opts...)
 
 	inj.Inject(dkg)
 
@@ -150,3 +366,55 @@ func (m minimal) OnStart(ctx cli.Flags, inj node.Injector) error {
 func (minimal) OnStop(node.Injector) error {
 	return nil
 }
+
+// loadPrivateKey returns the long-term key of the node, which is created the
+// first time, so that the node keeps its identity in the DKG and the key that
+// signs its information after a restart.
+func loadPrivateKey(flags cli.Flags) (kyber.Scalar, error) {
+	l := loader.NewFileLoader(filepath.Join(flags.Path("config"), privateKeyFile))
+
+	data, err := l.LoadOrCreate(keyGenerator{})
+	if err != nil {
+		return nil, xerrors.Errorf("while loading: %v", err)
+	}
+
+	privkey := suite.Scalar()
+
+	err = privkey.UnmarshalBinary(data)
+	if err != nil {
+		return nil, xerrors.Errorf("while unmarshaling: %v", err)
+	}
+
+	return privkey, nil
+}
+
+// keyGenerator is an implementation to generate a long-term key.
+//
+// - implements loader.Generator
+type keyGenerator struct{}
+
+// Generate implements loader.Generator. It returns the marshaled data of a
+// random scalar.
+func (keyGenerator) Generate() ([]byte, error) {
+	data, err := suite.Scalar().Pick(suite.RandomStream()).MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal key: %v", err)
+	}
+
+	return data, nil
+}
+
+// makeRegistry returns the registry of the namespaces, where 'raw' stands for
+// the labels without a namespace.
+func makeRegistry(names []string) ibe.Registry {
+	namespaces := make([]ibe.Namespace, len(names))
+	for i, name := range names {
+		if name == "raw" {
+			namespaces[i] = ibe.RawNamespace
+		} else {
+			namespaces[i] = ibe.Namespace(name)
+		}
+	}
+
+	return ibe.NewRegistry(namespaces...)
+}
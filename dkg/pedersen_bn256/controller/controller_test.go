@@ -1,11 +1,16 @@
 package controller
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	urfave "github.com/urfave/cli/v2"
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/dkg/pedersen_bn256"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
 	"golang.org/x/xerrors"
@@ -18,18 +23,66 @@ func TestMinimal_SetCommands(t *testing.T) {
 	minimal.SetCommands(b)
 }
 
+func TestMinimal_ReleaseAtFlag(t *testing.T) {
+	app := node.NewBuilder(NewMinimal()).Build().(*urfave.App)
+
+	// The commands reading the label of a deadline are the only ones to
+	// declare the flag.
+	require.Contains(t, flagNames(t, app, "encrypt"), "release-at")
+	require.Contains(t, flagNames(t, app, "decrypt"), "release-at")
+	require.NotContains(t, flagNames(t, app, "sign"), "release-at")
+}
+
 func TestMinimal_OnStart(t *testing.T) {
-	minimal := NewMinimal()
+	dir := t.TempDir()
+
+	ctrl := NewMinimal()
+
+	flags := node.FlagSet{
+		"config":        dir,
+		"dkgTimeout":    float64(time.Second),
+		"dkgNamespaces": []interface{}{"f3b:block"},
+	}
 
 	inj := newInjector(fake.Mino{})
-	err := minimal.OnStart(nil, inj)
+	err := ctrl.OnStart(flags, inj)
 	require.NoError(t, err)
 
 	require.Len(t, inj.(*fakeInjector).history, 1)
 	require.IsType(t, &pedersen.Pedersen{}, inj.(*fakeInjector).history[0])
+	require.FileExists(t, filepath.Join(dir, privateKeyFile))
+
+	pubkey := ctrl.(minimal).la.pubkey
 
-	err = minimal.OnStart(nil, newBadInjector())
+	// The long-term key is loaded after a restart.
+	restarted := NewMinimal()
+
+	err = restarted.OnStart(flags, newInjector(fake.Mino{}))
+	require.NoError(t, err)
+	require.True(t, pubkey.Equal(restarted.(minimal).la.pubkey))
+
+	err = ctrl.OnStart(node.FlagSet{}, newBadInjector())
 	require.EqualError(t, err, fake.Err("failed to resolve mino"))
+
+	err = ctrl.OnStart(node.FlagSet{"config": filepath.Join(dir, "unknown")},
+		newInjector(fake.Mino{}))
+	require.Error(t, err)
+	require.Regexp(t, "^failed to load private key: while loading: ", err.Error())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, privateKeyFile),
+		[]byte("invalid"), 0600))
+
+	err = ctrl.OnStart(flags, newInjector(fake.Mino{}))
+	require.Error(t, err)
+	require.Regexp(t, "^failed to load private key: while unmarshaling: ", err.Error())
+}
+
+func TestMakeRegistry(t *testing.T) {
+	registry := makeRegistry([]string{"raw", "f3b:block"})
+
+	require.True(t, registry.Allows(ibe.RawNamespace))
+	require.True(t, registry.Allows(ibe.BlockNamespace))
+	require.False(t, registry.Allows(ibe.TimeNamespace))
 }
 
 func TestMinimal_OnStop(t *testing.T) {
@@ -42,6 +95,33 @@ func TestMinimal_OnStop(t *testing.T) {
 // -----------------------------------------------------------------------------
 // Utility functions
 
+// flagNames returns the names of the flags of a subcommand of dkg.
+func flagNames(t *testing.T, app *urfave.App, name string) []string {
+	var dkgCmd *urfave.Command
+	for _, cmd := range app.Commands {
+		if cmd.Name == "dkg" {
+			dkgCmd = cmd
+		}
+	}
+
+	require.NotNil(t, dkgCmd)
+
+	for _, cmd := range dkgCmd.Subcommands {
+		if cmd.Name == name {
+			var names []string
+			for _, flag := range cmd.Flags {
+				names = append(names, flag.Names()...)
+			}
+
+			return names
+		}
+	}
+
+	t.Fatalf("command %q not found", name)
+
+	return nil
+}
+
 func newInjector(mino mino.Mino) node.Injector {
 	return &fakeInjector{
 		mino: mino,
@@ -4,14 +4,17 @@ import (
 	"context"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/dedis/debugtools/channel"
 	"github.com/rs/zerolog"
 	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
 	"go.dedis.ch/dela/dkg/pedersen_bn256/types"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
 	"go.dedis.ch/kyber/v3/share"
 	pedersen "go.dedis.ch/kyber/v3/share/dkg/pedersen"
 	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
@@ -24,6 +27,10 @@ const newState = "new state"
 const badState = "bad state: %v"
 const failedState = "failed to switch state: %v"
 
+// maxBatchSize is the maximum number of labels of a batch of signature
+// requests. The clients split larger requests into several batches.
+const maxBatchSize = 64
+
 type nodeType byte
 
 // enumeration of the node type
@@ -39,6 +46,8 @@ type dkgService interface {
 	Deals() (map[int]*pedersen.Deal, error)
 	ProcessResponse(resp *pedersen.Response) (*pedersen.Justification, error)
 	Certified() bool
+	ThresholdCertified() bool
+	SetTimeout()
 	DistKeyShare() (*pedersen.DistKeyShare, error)
 	ProcessDeal(dd *pedersen.Deal) (*pedersen.Response, error)
 }
@@ -49,10 +58,21 @@ type dkgInstance interface {
 	isRunning() bool
 	handleMessage(ctx context.Context, msg serde.Message, from mino.Address, out mino.Sender) error
 	getState() *state
+	resume() (kyber.Point, error)
+}
+
+// chainHeight tells the index and the timestamp of the latest finalized block
+// of the chain, or false if they are unknown.
+type chainHeight interface {
+	Height() (uint64, bool)
+	Time() (time.Time, bool)
 }
 
 // newInstance returns a new initialized dkg handler
-func newInstance(log zerolog.Logger, me mino.Address, privKey kyber.Scalar) *instance {
+func newInstance(log zerolog.Logger, me mino.Address, privKey kyber.Scalar,
+	transcripts *transcriptStore, timeout time.Duration, labels *ibe.Registry,
+	chain chainHeight) *instance {
+
 	return &instance{
 		running:   true,
 		deals:     channel.WithExpiration[types.Deal](200),
@@ -66,6 +86,11 @@ func newInstance(log zerolog.Logger, me mino.Address, privKey kyber.Scalar) *ins
 		startRes: &state{
 			dkgState: initial,
 		},
+
+		transcripts: transcripts,
+		timeout:     timeout,
+		labels:      labels,
+		chain:       chain,
 	}
 }
 
@@ -89,6 +114,25 @@ type instance struct {
 	privKey   kyber.Scalar
 
 	startRes *state
+
+	// transcripts persists the outcome of the DKG, if any.
+	transcripts *transcriptStore
+
+	// timeout is the maximum duration of a phase of the DKG. When it expires,
+	// the missing deals and responses are considered as complaints and the
+	// DKG completes if enough participants are qualified. Zero means that the
+	// node waits for every participant.
+	timeout time.Duration
+
+	// labels restricts the namespaces of the labels that are signed, or nil to
+	// sign any label.
+	labels *ibe.Registry
+
+	// chain tells the height and the time of the chain so that the key of a
+	// block label is only released once the block is finalized, and the key of
+	// a time label once a finalized block is past the deadline. The block and
+	// time labels are refused when it is nil.
+	chain chainHeight
 }
 
 // isRunning implements dkgInstance. It tells if an instance of DKG is already
@@ -105,6 +149,32 @@ func (s *instance) getState() *state {
 	return s.startRes
 }
 
+// resume implements dkgInstance. It restores the outcome of the last DKG from
+// the transcript and returns the collective public key. The transcript is
+// encrypted with the long-term key, so that the node resumes with the identity
+// it had in the DKG and can take part in a resharing.
+func (s *instance) resume() (kyber.Point, error) {
+	if s.transcripts == nil {
+		return nil, xerrors.New("no transcript store")
+	}
+
+	transcript, err := s.transcripts.Load()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load transcript: %v", err)
+	}
+
+	err = s.startRes.restore(transcript)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to restore state: %v", err)
+	}
+
+	s.Lock()
+	s.privShare = transcript.GetShare()
+	s.Unlock()
+
+	return transcript.GetDistKey(), nil
+}
+
 // handleMessage implements dkgInstance. It handles the DKG messages.
 func (s *instance) handleMessage(ctx context.Context, msg serde.Message, from mino.Address, out mino.Sender) error {
 	// We expect a Start message or a decrypt request at first, but we might
@@ -167,6 +237,14 @@ func (s *instance) handleMessage(ctx context.Context, msg serde.Message, from mi
 
 		return s.handleSign(out, msg, from)
 
+	case types.SignBatchRequest:
+		err := s.startRes.checkState(certified)
+		if err != nil {
+			return xerrors.Errorf(badState, err)
+		}
+
+		return s.handleSignBatch(out, msg, from)
+
 	default:
 		return xerrors.Errorf("expected Start message, decrypt request or "+
 			"Deal as first message, got: %T", msg)
@@ -298,11 +376,21 @@ func (s *instance) deal(ctx context.Context, out mino.Sender) error {
 }
 
 func (s *instance) respond(ctx context.Context, deals channel.Timed[types.Deal], out mino.Sender) error {
+	phaseCtx, cancel := s.withPhaseTimeout(ctx)
+	defer cancel()
+
 	numReceivedDeals := 0
+	expected := len(s.startRes.getParticipants()) - 1
 
-	for numReceivedDeals < len(s.startRes.getParticipants())-1 {
-		deal, err := deals.NonBlockingReceiveWithContext(ctx)
+	for numReceivedDeals < expected {
+		deal, err := deals.NonBlockingReceiveWithContext(phaseCtx)
 		if err != nil {
+			if phaseExpired(ctx, phaseCtx) {
+				s.log.Warn().Int("missing", expected-numReceivedDeals).
+					Msg("deals missing after timeout")
+				break
+			}
+
 			return xerrors.Errorf("context done: %v", err)
 		}
 
@@ -329,11 +417,18 @@ func (s *instance) respond(ctx context.Context, deals channel.Timed[types.Deal],
 //   - Resharing with staying node: (n_common + n_new) * n_old
 func (s *instance) certify(ctx context.Context, resps channel.Timed[types.Response], expected int) error {
 
+	phaseCtx, cancel := s.withPhaseTimeout(ctx)
+	defer cancel()
+
 	responsesReceived := 0
 
 	for responsesReceived < expected {
-		msg, err := resps.NonBlockingReceiveWithContext(ctx)
+		msg, err := resps.NonBlockingReceiveWithContext(phaseCtx)
 		if err != nil {
+			if phaseExpired(ctx, phaseCtx) {
+				return s.certifyAfterTimeout(expected - responsesReceived)
+			}
+
 			return xerrors.Errorf("context done: %v", err)
 		}
 
@@ -364,6 +459,37 @@ func (s *instance) certify(ctx context.Context, resps channel.Timed[types.Respon
 	return nil
 }
 
+// certifyAfterTimeout turns the missing responses into complaints and checks
+// that enough deals are certified to complete the DKG without the missing
+// participants.
+func (s *instance) certifyAfterTimeout(missing int) error {
+	s.log.Warn().Int("missing", missing).Msg("responses missing after timeout")
+
+	s.dkg.SetTimeout()
+
+	if !s.dkg.ThresholdCertified() {
+		return xerrors.New("node is not certified after timeout")
+	}
+
+	return nil
+}
+
+// withPhaseTimeout returns a context that expires at the end of the phase, if
+// the instance has a timeout.
+func (s *instance) withPhaseTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// phaseExpired returns true if the phase context is done while the parent is
+// still alive, which means that only the phase timed out.
+func phaseExpired(parent, phase context.Context) bool {
+	return parent.Err() == nil && phase.Err() != nil
+}
+
 // finalize saves the result and announces it to the orchestrator.
 func (s *instance) finalize(ctx context.Context, from mino.Address, out mino.Sender) error {
 	// Send back the public DKG key
@@ -381,6 +507,8 @@ func (s *instance) finalize(ctx context.Context, from mino.Address, out mino.Sen
 	s.privShare = distKey.PriShare()
 	s.Unlock()
 
+	s.saveTranscript(distKey)
+
 	done := types.NewStartDone(distKey.Public())
 
 	select {
@@ -395,6 +523,28 @@ func (s *instance) finalize(ctx context.Context, from mino.Address, out mino.Sen
 	return nil
 }
 
+// saveTranscript persists the outcome of the DKG. A failure is only logged
+// because the node can still serve requests until it restarts.
+func (s *instance) saveTranscript(distKey *pedersen.DistKeyShare) {
+	if s.transcripts == nil {
+		return
+	}
+
+	transcript := types.NewTranscript(
+		s.startRes.getThreshold(),
+		s.startRes.getParticipants(),
+		s.startRes.getPublicKeys(),
+		distKey.Public(),
+		distKey.Commits,
+		distKey.PriShare(),
+	)
+
+	err := s.transcripts.Store(transcript)
+	if err != nil {
+		s.log.Err(err).Msg("failed to persist the transcript")
+	}
+}
+
 // handleDeal process the Deal and send the responses to the other nodes.
 func (s *instance) handleDeal(ctx context.Context, msg types.Deal,
 	out mino.Sender, to []mino.Address) error {
@@ -437,7 +587,12 @@ func (s *instance) handleDeal(ctx context.Context, msg types.Deal,
 
 		select {
 		case err = <-errs:
-			if err != nil {
+			// With a timeout, an unreachable participant is excluded later
+			// instead of failing the whole DKG.
+			if err != nil && s.timeout > 0 {
+				s.log.Warn().Err(err).Str("to", addr.String()).
+					Msg("failed to send response")
+			} else if err != nil {
 				return xerrors.Errorf("failed to send response to '%s': %v", addr, err)
 			}
 		case <-ctx.Done():
@@ -464,9 +619,12 @@ func (s *instance) finalizeReshare(ctx context.Context, nt nodeType, out mino.Se
 		// Update the state before sending to acknowledgement to the
 		// orchestrator, so that it can process decrypt requests right away.
 		s.startRes.setDistKey(distrKey.Public())
+		s.startRes.Commits = distrKey.Commits
 		s.Lock()
 		s.privShare = distrKey.PriShare()
 		s.Unlock()
+
+		s.saveTranscript(distrKey)
 	}
 
 	// all the old, new and common nodes should announce their public key to the
@@ -537,7 +695,7 @@ func (s *instance) doReshare(ctx context.Context, start types.StartResharing,
 	switch nt {
 	case oldNode:
 		// Update local DKG for resharing
-		share, err := s.dkg.DistKeyShare()
+		share, err := s.distKeyShare()
 		if err != nil {
 			return xerrors.Errorf("old node failed to create: %v", err)
 		}
@@ -571,7 +729,7 @@ func (s *instance) doReshare(ctx context.Context, start types.StartResharing,
 
 	case commonNode:
 		// Update local DKG for resharing
-		share, err := s.dkg.DistKeyShare()
+		share, err := s.distKeyShare()
 		if err != nil {
 			return xerrors.Errorf("common node failed to create: %v", err)
 		}
@@ -647,6 +805,30 @@ func (s *instance) doReshare(ctx context.Context, start types.StartResharing,
 	return nil
 }
 
+// distKeyShare returns the share of the node in the current DKG. A node that
+// resumed from a transcript has no DKG handler, so the share is rebuilt from
+// the state that the transcript restored.
+func (s *instance) distKeyShare() (*pedersen.DistKeyShare, error) {
+	if s.dkg != nil {
+		return s.dkg.DistKeyShare()
+	}
+
+	s.Lock()
+	privShare := s.privShare
+	s.Unlock()
+
+	if privShare == nil {
+		return nil, xerrors.New("no share")
+	}
+
+	share := &pedersen.DistKeyShare{
+		Commits: s.startRes.Commits,
+		Share:   privShare,
+	}
+
+	return share, nil
+}
+
 // sendDealsResharing is similar to sendDeals except that it creates
 // dealResharing which has more data than Deal. Only the old nodes call this
 // function.
@@ -769,9 +951,11 @@ func (s *instance) handleSign(out mino.Sender, req types.SignRequest,
 		return xerrors.Errorf("you must first initialize DKG. Did you call setup() first?")
 	}
 
-	sig, err := tbls.Sign(pairingSuite, s.privShare, req.GetMsg())
+	// A refused message gets an empty share so that the requester does not
+	// wait for the reply until its timeout.
+	sig, err := s.makeShare(req.GetMsg(), req.GetPublicKey())
 	if err != nil {
-		return xerrors.Errorf("tbls.Sign: %v", err)
+		s.log.Warn().Err(err).Msgf("refused label %#x", req.GetMsg())
 	}
 
 	signReply := types.NewSignReply(sig)
@@ -785,6 +969,170 @@ func (s *instance) handleSign(out mino.Sender, req types.SignRequest,
 	return nil
 }
 
+// handleSignBatch replies the signature shares of the messages of the batch in
+// a single reply. A message that is refused gets an empty share so that the
+// other messages of the batch are still answered.
+func (s *instance) handleSignBatch(out mino.Sender, req types.SignBatchRequest,
+	from mino.Address) error {
+
+	if !s.startRes.Done() {
+		return xerrors.Errorf("you must first initialize DKG. Did you call setup() first?")
+	}
+
+	msgs := req.GetMsgs()
+	if len(msgs) > maxBatchSize {
+		return xerrors.Errorf("batch is too large: %d > %d", len(msgs), maxBatchSize)
+	}
+
+	shares := make([]tbls.SigShare, len(msgs))
+
+	for i, msg := range msgs {
+		sig, err := s.makeShare(msg, req.GetPublicKey())
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("refused label %#x", msg)
+			continue
+		}
+
+		shares[i] = sig
+	}
+
+	errs := out.Send(types.NewSignBatchReply(shares), from)
+	err := <-errs
+	if err != nil {
+		return xerrors.Errorf("got an error while sending the batch reply: %v", err)
+	}
+
+	return nil
+}
+
+// makeShare returns the signature share of the message, encrypted under the
+// public key if any, after checking that the label can be released.
+func (s *instance) makeShare(msg, pubKey []byte) ([]byte, error) {
+	if s.labels != nil {
+		err := s.labels.Validate(msg)
+		if err != nil {
+			return nil, xerrors.Errorf("label refused: %v", err)
+		}
+	}
+
+	if ibe.IsBlockLabel(msg) || ibe.IsTxLabel(msg) {
+		err := s.checkBlockLabel(msg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ibe.IsTimeLabel(msg) {
+		err := s.checkTimeLabel(msg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sig, err := signShare(s.privShare, msg, suite.RandomStream())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	// The share is only encrypted when the requester provides a key, so that
+	// the requests of older nodes are still answered.
+	if len(pubKey) > 0 {
+		sig, err = encryptShare(pubKey, sig)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to encrypt share: %v", err)
+		}
+	}
+
+	return sig, nil
+}
+
+// checkBlockLabel returns nil if the block of the label is finalized,
+// otherwise an error. The labels of the transactions are checked against the
+// block that includes them.
+func (s *instance) checkBlockLabel(label []byte) error {
+	index, err := labelHeight(label)
+	if err != nil {
+		return xerrors.Errorf("invalid label: %v", err)
+	}
+
+	if s.chain == nil {
+		return xerrors.New("block labels are refused without a chain")
+	}
+
+	height, synced := s.chain.Height()
+	if !synced || height < index {
+		return xerrors.Errorf("label is locked until block %d", index)
+	}
+
+	return nil
+}
+
+// labelHeight returns the height of the block at which the key of a block or
+// transaction label is released.
+func labelHeight(label []byte) (uint64, error) {
+	if ibe.IsTxLabel(label) {
+		height, _, err := ibe.ParseTxLabel(label)
+		return height, err
+	}
+
+	return ibe.ParseBlockLabel(label)
+}
+
+// checkTimeLabel returns nil if the timestamp of the latest finalized block is
+// past the deadline of the label, otherwise an error. The clock of the node is
+// not used so that the nodes agree on the time, whatever their clock says.
+func (s *instance) checkTimeLabel(label []byte) error {
+	deadline, err := ibe.ParseTimeLabel(label)
+	if err != nil {
+		return xerrors.Errorf("invalid label: %v", err)
+	}
+
+	if s.chain == nil {
+		return xerrors.New("time labels are refused without a chain")
+	}
+
+	now, synced := s.chain.Time()
+	if !synced || now.Before(deadline) {
+		return xerrors.Errorf("label is locked until %s", deadline.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// encryptShare encrypts the signature share under the public key of the
+// requester.
+func encryptShare(pubKey, sigShare []byte) ([]byte, error) {
+	point := suite.Point()
+
+	err := point.UnmarshalBinary(pubKey)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid public key: %v", err)
+	}
+
+	ciphertext, err := ecies.Encrypt(suite, point, sigShare, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("ecies: %v", err)
+	}
+
+	return ciphertext, nil
+}
+
+// decryptShare decrypts a signature share encrypted under the public key of
+// the secret.
+func decryptShare(secret kyber.Scalar, ciphertext []byte) ([]byte, error) {
+	// The decryption expects at least the ephemeral point and panics otherwise.
+	if len(ciphertext) < suite.PointLen() {
+		return nil, xerrors.Errorf("ciphertext is too short: %d", len(ciphertext))
+	}
+
+	sigShare, err := ecies.Decrypt(suite, secret, ciphertext, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("ecies: %v", err)
+	}
+
+	return sigShare, nil
+}
+
 // isInSlice gets an address and a slice of addresses and returns true if that
 // address is in the slice. This function is called for checking whether an old
 // committee member is in the new committee as well or not
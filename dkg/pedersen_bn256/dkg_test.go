@@ -3,6 +3,7 @@ package pedersen
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,11 +11,13 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
 	"go.dedis.ch/dela/dkg/pedersen_bn256/types"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
 	pedersen "go.dedis.ch/kyber/v3/share/dkg/pedersen"
 	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
 )
@@ -105,6 +108,205 @@ func TestDKGInstance_HandleUnknown(t *testing.T) {
 		"Deal as first message, got: fake.Message")
 }
 
+func TestDKGInstance_HandleSignTimeLabel(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	s := instance{
+		startRes: &state{dkgState: certified},
+		privShare: &share.PriShare{
+			I: 0,
+			V: pairingSuite.G1().Scalar().Pick(pairingSuite.RandomStream()),
+		},
+	}
+
+	label := ibe.NewTimeLabel(now.Add(time.Minute))
+
+	_, err := s.makeShare(label, nil)
+	require.EqualError(t, err, "time labels are refused without a chain")
+
+	s.chain = fakeChain{}
+
+	_, err = s.makeShare(label, nil)
+	require.EqualError(t, err, "label is locked until 2024-06-01T12:01:00Z")
+
+	// The local time is long past the deadline, but the chain is not yet.
+	require.True(t, time.Now().After(now.Add(time.Minute)))
+
+	s.chain = fakeChain{height: 3, synced: true, time: now}
+
+	_, err = s.makeShare(label, nil)
+	require.EqualError(t, err, "label is locked until 2024-06-01T12:01:00Z")
+
+	s.chain = fakeChain{height: 4, synced: true, time: now.Add(time.Minute)}
+
+	_, err = s.makeShare(label, nil)
+	require.NoError(t, err)
+
+	_, err = s.makeShare([]byte("f3b:time:"), nil)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid label: failed to parse deadline: ", err.Error())
+}
+
+func TestDKGInstance_HandleSignLabelRegistry(t *testing.T) {
+	registry := ibe.NewRegistry(ibe.BlockNamespace)
+
+	s := instance{
+		startRes: &state{dkgState: certified},
+		privShare: &share.PriShare{
+			I: 0,
+			V: pairingSuite.G1().Scalar().Pick(pairingSuite.RandomStream()),
+		},
+		labels: &registry,
+		chain:  fakeChain{height: 1, synced: true},
+	}
+
+	_, err := s.makeShare(ibe.NewBlockLabel(1), nil)
+	require.NoError(t, err)
+
+	_, err = s.makeShare([]byte("app:auction:bid"), nil)
+	require.EqualError(t, err, `label refused: namespace "app:auction" is not allowed`)
+}
+
+func TestDKGInstance_HandleSignBlockLabel(t *testing.T) {
+	s := instance{
+		startRes: &state{dkgState: certified},
+		privShare: &share.PriShare{
+			I: 0,
+			V: pairingSuite.G1().Scalar().Pick(pairingSuite.RandomStream()),
+		},
+	}
+
+	sign := func(label []byte) error {
+		_, err := s.makeShare(label, nil)
+		return err
+	}
+
+	err := sign(ibe.NewBlockLabel(5))
+	require.EqualError(t, err, "block labels are refused without a chain")
+
+	s.chain = fakeChain{}
+
+	err = sign(ibe.NewBlockLabel(0))
+	require.EqualError(t, err, "label is locked until block 0")
+
+	s.chain = fakeChain{height: 4, synced: true}
+
+	err = sign(ibe.NewBlockLabel(5))
+	require.EqualError(t, err, "label is locked until block 5")
+
+	require.NoError(t, sign(ibe.NewBlockLabel(4)))
+
+	err = sign([]byte("f3b:block:abc"))
+	require.Error(t, err)
+	require.Regexp(t, "^invalid label: failed to parse index: ", err.Error())
+}
+
+func TestDKGInstance_HandleSignTxLabel(t *testing.T) {
+	s := instance{
+		startRes: &state{dkgState: certified},
+		privShare: &share.PriShare{
+			I: 0,
+			V: pairingSuite.G1().Scalar().Pick(pairingSuite.RandomStream()),
+		},
+	}
+
+	sign := func(label []byte) error {
+		_, err := s.makeShare(label, nil)
+		return err
+	}
+
+	label := ibe.NewTxLabel(5, 2, []byte("tx"))
+
+	err := sign(label)
+	require.EqualError(t, err, "block labels are refused without a chain")
+
+	// The transaction is not finalized, even if it is already ordered.
+	s.chain = fakeChain{height: 4, synced: true}
+
+	err = sign(label)
+	require.EqualError(t, err, "label is locked until block 5")
+
+	// The refusal is replied to the requester so that it does not wait.
+	logger := fake.NewLogger()
+	s.log = logger.GetLogger()
+
+	err = s.handleSign(fake.Sender{}, types.NewSignRequest(label), fake.NewAddress(0))
+	require.NoError(t, err)
+	require.True(t, logger.Has(zerolog.WarnLevel, fmt.Sprintf("refused label %#x", label)))
+
+	err = s.handleSign(fake.NewBadSender(), types.NewSignRequest(label), fake.NewAddress(0))
+	require.EqualError(t, err, fake.Err("got an error while sending the decrypt reply"))
+
+	s.chain = fakeChain{height: 5, synced: true}
+
+	require.NoError(t, sign(label))
+
+	err = sign([]byte("f3b:tx:5:2"))
+	require.EqualError(t, err, `invalid label: malformed transaction label: "f3b:tx:5:2"`)
+}
+
+func TestDKGInstance_HandleSignBatch(t *testing.T) {
+	registry := ibe.NewRegistry(ibe.BlockNamespace)
+
+	s := instance{
+		startRes: &state{dkgState: certified},
+		privShare: &share.PriShare{
+			I: 0,
+			V: pairingSuite.G1().Scalar().Pick(pairingSuite.RandomStream()),
+		},
+		labels: &registry,
+		chain:  fakeChain{height: 1, synced: true},
+	}
+
+	// A refused label does not prevent the reply of the others.
+	labels := [][]byte{ibe.NewBlockLabel(1), []byte("app:auction:bid")}
+
+	err := s.handleSignBatch(fake.Sender{}, types.NewSignBatchRequest(labels, nil),
+		fake.NewAddress(0))
+	require.NoError(t, err)
+
+	err = s.handleSignBatch(fake.NewBadSender(), types.NewSignBatchRequest(labels, nil),
+		fake.NewAddress(0))
+	require.EqualError(t, err, fake.Err("got an error while sending the batch reply"))
+
+	labels = make([][]byte, maxBatchSize+1)
+
+	err = s.handleSignBatch(fake.Sender{}, types.NewSignBatchRequest(labels, nil),
+		fake.NewAddress(0))
+	require.EqualError(t, err, "batch is too large: 65 > 64")
+
+	s.startRes = &state{}
+
+	err = s.handleMessage(context.TODO(), types.NewSignBatchRequest(nil, nil),
+		fake.NewAddress(0), fake.Sender{})
+	require.EqualError(t, err, "bad state: unexpected state: Initial != one of [Certified]")
+}
+
+func TestEncryptDecryptShare(t *testing.T) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+
+	pubKey, err := suite.Point().Mul(secret, nil).MarshalBinary()
+	require.NoError(t, err)
+
+	ciphertext, err := encryptShare(pubKey, []byte("share"))
+	require.NoError(t, err)
+
+	sigShare, err := decryptShare(secret, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, []byte("share"), sigShare)
+
+	_, err = decryptShare(suite.Scalar().One(), ciphertext)
+	require.Error(t, err)
+	require.Regexp(t, "^ecies: ", err.Error())
+
+	_, err = decryptShare(secret, ciphertext[:10])
+	require.EqualError(t, err, "ciphertext is too short: 10")
+
+	_, err = encryptShare([]byte{1}, []byte("share"))
+	require.Error(t, err)
+	require.Regexp(t, "^invalid public key: ", err.Error())
+}
+
 func TestDKGInstance_StartFailNewDKG(t *testing.T) {
 	s := instance{
 		startRes: &state{},
@@ -193,6 +395,48 @@ func TestDKGInstance_doDKG_CertifyFail(t *testing.T) {
 		"receive data from channel.")
 }
 
+func TestDKGInstance_respond_timeout(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	s := instance{
+		startRes: &state{
+			participants: []mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		},
+		timeout: time.Millisecond,
+		log:     zerolog.New(out),
+	}
+
+	err := s.respond(context.Background(), channel.WithExpiration[types.Deal](1), nil)
+	require.NoError(t, err)
+	require.Regexp(t, "deals missing after timeout", out.String())
+}
+
+func TestDKGInstance_certify_timeout(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	s := instance{
+		dkg:     fakeDKGService{},
+		timeout: time.Millisecond,
+		log:     zerolog.New(out),
+	}
+
+	err := s.certify(context.Background(), channel.WithExpiration[types.Response](1), 1)
+	require.EqualError(t, err, "node is not certified after timeout")
+	require.Regexp(t, "responses missing after timeout", out.String())
+
+	s.dkg = fakeDKGService{thresholdCertified: true}
+
+	err = s.certify(context.Background(), channel.WithExpiration[types.Response](1), 1)
+	require.NoError(t, err)
+
+	// The parent context is done, which is not a timeout of the phase.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = s.certify(ctx, channel.WithExpiration[types.Response](1), 1)
+	require.EqualError(t, err, "context done: Could not receive data from channel.")
+}
+
 func TestDKGInstance_doDKG_SwitchFail(t *testing.T) {
 	s := instance{
 		dkg: fakeDKGService{
@@ -378,6 +622,93 @@ func TestDKGInstance_certify_ctxFail(t *testing.T) {
 	require.EqualError(t, err, "context done: Could not receive data from channel.")
 }
 
+func TestDKGInstance_Resume(t *testing.T) {
+	db := fake.NewInMemoryDB()
+	db.SetBucket(transcriptBucket, fake.NewBucket())
+
+	transcripts := newTranscriptStore(db, suite.Scalar(),
+		types.NewMessageFactory(fake.AddressFactory{}))
+
+	s := newInstance(zerolog.Nop(), fake.NewAddress(0), suite.Scalar(), nil, 0, nil, nil)
+
+	_, err := s.resume()
+	require.EqualError(t, err, "no transcript store")
+
+	s.transcripts = transcripts
+
+	_, err = s.resume()
+	require.EqualError(t, err, "failed to load transcript: no transcript")
+
+	transcript := makeTranscript()
+	require.NoError(t, transcripts.Store(transcript))
+
+	pubkey, err := s.resume()
+	require.NoError(t, err)
+	require.True(t, transcript.GetDistKey().Equal(pubkey))
+	require.True(t, transcript.GetShare().V.Equal(s.privShare.V))
+	require.True(t, s.startRes.Done())
+
+	_, err = s.resume()
+	require.EqualError(t, err,
+		"failed to restore state: state must be initial: Certified")
+}
+
+func TestDKGInstance_distKeyShare(t *testing.T) {
+	s := instance{startRes: &state{}}
+
+	_, err := s.distKeyShare()
+	require.EqualError(t, err, "no share")
+
+	// A resumed node rebuilds its share from the restored state.
+	s.privShare = &share.PriShare{I: 1, V: suite.Scalar().One()}
+	s.startRes.Commits = []kyber.Point{suite.Point()}
+
+	distKey, err := s.distKeyShare()
+	require.NoError(t, err)
+	require.Equal(t, s.privShare, distKey.Share)
+	require.Equal(t, s.startRes.Commits, distKey.Commits)
+
+	s.dkg = fakeDKGService{shareErr: fake.GetError()}
+
+	_, err = s.distKeyShare()
+	require.EqualError(t, err, fake.GetError().Error())
+}
+
+func TestDKGInstance_finalize_transcript(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	db := fake.NewInMemoryDB()
+	db.SetBucket(transcriptBucket, fake.NewBucket())
+
+	transcripts := newTranscriptStore(db, suite.Scalar(),
+		types.NewMessageFactory(fake.AddressFactory{}))
+
+	s := instance{
+		dkg: fakeDKGService{
+			distKeyShare: &pedersen.DistKeyShare{
+				Commits: []kyber.Point{suite.Point()},
+				Share:   &share.PriShare{I: 1, V: suite.Scalar().One()},
+			},
+		},
+		startRes:    &state{},
+		transcripts: transcripts,
+		log:         zerolog.New(out),
+	}
+
+	err := s.finalize(context.Background(), nil, fake.Sender{})
+	require.NoError(t, err)
+
+	transcript, err := transcripts.Load()
+	require.NoError(t, err)
+	require.Equal(t, 1, transcript.GetShare().I)
+
+	s.transcripts = newTranscriptStore(fake.NewBadUpdateDB(), suite.Scalar(), nil)
+
+	err = s.finalize(context.Background(), nil, fake.Sender{})
+	require.NoError(t, err)
+	require.Regexp(t, "failed to persist the transcript", out.String())
+}
+
 func TestDKGInstance_finalize_sendFail(t *testing.T) {
 	s := instance{
 		dkg: fakeDKGService{
@@ -1017,13 +1348,28 @@ func getCertified(t *testing.T) (*pedersen.DistKeyGenerator, *pedersen.Response)
 	return dkg1, resp2
 }
 
+type fakeChain struct {
+	height uint64
+	time   time.Time
+	synced bool
+}
+
+func (c fakeChain) Height() (uint64, bool) {
+	return c.height, c.synced
+}
+
+func (c fakeChain) Time() (time.Time, bool) {
+	return c.time, c.synced
+}
+
 type fakeDKGService struct {
 	dealsErr   error
 	respoErr   error
 	shareErr   error
 	processErr error
 
-	certified bool
+	certified          bool
+	thresholdCertified bool
 
 	deals        map[int]*pedersen.Deal
 	distKeyShare *pedersen.DistKeyShare
@@ -1041,6 +1387,12 @@ func (s fakeDKGService) Certified() bool {
 	return s.certified
 }
 
+func (s fakeDKGService) ThresholdCertified() bool {
+	return s.thresholdCertified
+}
+
+func (s fakeDKGService) SetTimeout() {}
+
 func (s fakeDKGService) DistKeyShare() (*pedersen.DistKeyShare, error) {
 	return s.distKeyShare, s.shareErr
 }
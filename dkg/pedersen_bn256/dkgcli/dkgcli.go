@@ -6,10 +6,12 @@ import (
 	"os"
 
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/cli/node/admin"
 
 	db "go.dedis.ch/dela/core/store/kv/controller"
 	dkg "go.dedis.ch/dela/dkg/pedersen_bn256/controller"
 	mino "go.dedis.ch/dela/mino/minogrpc/controller"
+	proxy "go.dedis.ch/dela/mino/proxy/http/controller"
 )
 
 func main() {
@@ -35,6 +37,8 @@ func runWithCfg(args []string, cfg config) error {
 		db.NewController(),
 		mino.NewController(),
 		dkg.NewMinimal(),
+		proxy.NewController(),
+		admin.NewController(),
 	)
 
 	app := builder.Build()
@@ -0,0 +1,37 @@
+// Package main provides a cli for the tools of F3B, like the benchmarks of the
+// threshold parameters.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/cli/ucli"
+	bench "go.dedis.ch/dela/dkg/pedersen_bn256/bench/command"
+)
+
+var builder cli.Builder = ucli.NewBuilder("f3b", nil)
+var printer io.Writer = os.Stderr
+
+func main() {
+	err := run(os.Args, bench.Initializer{})
+	if err != nil {
+		fmt.Fprintf(printer, "%+v\n", err)
+	}
+}
+
+func run(args []string, inits ...cli.Initializer) error {
+	for _, init := range inits {
+		init.SetCommands(builder)
+	}
+
+	app := builder.Build()
+	err := app.Run(args)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package pedersen
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.dedis.ch/dela/core/events"
+)
+
+// finalityWindow is the number of the last finalized blocks whose time is
+// remembered.
+const finalityWindow = 1000
+
+// finality remembers when the last blocks have been finalized, so that the
+// latency to release the key of a block label can be measured. It also tells
+// the height and the time of the chain.
+//
+// - implements chainHeight
+type finality struct {
+	sync.Mutex
+
+	times     map[uint64]time.Time
+	now       func() time.Time
+	height    uint64
+	timestamp time.Time
+	synced    bool
+}
+
+func newFinality() *finality {
+	return &finality{
+		times: make(map[uint64]time.Time),
+		now:   time.Now,
+	}
+}
+
+// Listen records the time of the blocks finalized on the bus until the context
+// is done.
+func (f *finality) Listen(ctx context.Context, bus events.Service) {
+	isBlock := func(evt events.Event) bool {
+		_, ok := evt.(events.BlockFinalized)
+		return ok
+	}
+
+	evts := bus.Subscribe(ctx, events.WithFilter(isBlock),
+		events.WithPolicy(events.DropOldest))
+
+	for evt := range evts {
+		f.finalized(evt.GetIndex(), evt.(events.BlockFinalized).Time)
+	}
+}
+
+// finalized records the block at the index, with the timestamp agreed by the
+// consensus.
+func (f *finality) finalized(index uint64, timestamp time.Time) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.times[index] = f.now()
+
+	if !f.synced || index > f.height {
+		f.height = index
+		f.timestamp = timestamp
+		f.synced = true
+	}
+
+	if index >= finalityWindow {
+		delete(f.times, index-finalityWindow)
+	}
+}
+
+// Since returns the time elapsed since the block has been finalized, or false
+// if the time of the block is unknown.
+func (f *finality) Since(index uint64) (time.Duration, bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	at, found := f.times[index]
+	if !found {
+		return 0, false
+	}
+
+	return f.now().Sub(at), true
+}
+
+// Height implements chainHeight. It returns the index of the latest finalized
+// block, or false if no block has been finalized since the start.
+func (f *finality) Height() (uint64, bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.height, f.synced
+}
+
+// Time implements chainHeight. It returns the timestamp of the latest
+// finalized block, or false if no block has been finalized since the start.
+func (f *finality) Time() (time.Time, bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.timestamp, f.synced
+}
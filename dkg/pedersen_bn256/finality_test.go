@@ -0,0 +1,113 @@
+package pedersen
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestFinality_Listen(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := fake.NewClock(now)
+
+	f := newFinality()
+	f.now = clock.Now
+
+	bus := events.NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		f.Listen(ctx, bus)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		bus.Publish(events.KeyReleased{Index: 1})
+		bus.Publish(events.BlockFinalized{Index: 2, Time: now})
+
+		_, found := f.Since(2)
+		return found
+	}, time.Second, 10*time.Millisecond)
+
+	clock.Advance(time.Minute)
+
+	elapsed, found := f.Since(2)
+	require.True(t, found)
+	require.Equal(t, time.Minute, elapsed)
+
+	_, found = f.Since(1)
+	require.False(t, found)
+
+	timestamp, _ := f.Time()
+	require.Equal(t, now, timestamp)
+
+	cancel()
+	<-done
+}
+
+func TestFinality_Window(t *testing.T) {
+	f := newFinality()
+
+	f.finalized(0, time.Time{})
+	f.finalized(1, time.Time{})
+	f.finalized(finalityWindow, time.Time{})
+
+	_, found := f.Since(0)
+	require.False(t, found)
+
+	_, found = f.Since(1)
+	require.True(t, found)
+
+	_, found = f.Since(finalityWindow)
+	require.True(t, found)
+}
+
+func TestFinality_Height(t *testing.T) {
+	f := newFinality()
+
+	_, synced := f.Height()
+	require.False(t, synced)
+
+	f.finalized(0, time.Time{})
+
+	height, synced := f.Height()
+	require.True(t, synced)
+	require.Equal(t, uint64(0), height)
+
+	// The events might be received out of order.
+	f.finalized(3, time.Time{})
+	f.finalized(2, time.Time{})
+
+	height, _ = f.Height()
+	require.Equal(t, uint64(3), height)
+}
+
+func TestFinality_Time(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	f := newFinality()
+
+	_, synced := f.Time()
+	require.False(t, synced)
+
+	f.finalized(1, now)
+
+	timestamp, synced := f.Time()
+	require.True(t, synced)
+	require.Equal(t, now, timestamp)
+
+	// The time is the one of the latest block, whatever the order of the
+	// events.
+	f.finalized(3, now.Add(time.Minute))
+	f.finalized(2, now.Add(time.Second))
+
+	timestamp, _ = f.Time()
+	require.Equal(t, now.Add(time.Minute), timestamp)
+}
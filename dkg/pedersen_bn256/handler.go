@@ -10,7 +10,9 @@ import (
 
 	"github.com/rs/zerolog"
 	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/bounded"
 	"go.dedis.ch/kyber/v3"
 	"golang.org/x/xerrors"
 )
@@ -30,14 +32,21 @@ type Handler struct {
 	dkgInstance dkgInstance
 }
 
-// NewHandler creates a new handler
-func NewHandler(privKey kyber.Scalar, me mino.Address) *Handler {
+// NewHandler creates a new handler. The transcript store is optional and
+// persists the outcome of the DKG. The timeout bounds each phase of the DKG, or
+// zero to wait for every participant. The registry, if any, restricts the
+// namespaces of the labels that are signed. The chain, if any, tells when the
+// key of a block label can be released, and the block labels are refused
+// otherwise.
+func NewHandler(privKey kyber.Scalar, me mino.Address, transcripts *transcriptStore,
+	timeout time.Duration, labels *ibe.Registry, chain chainHeight) *Handler {
+
 	log := dela.Logger.With().Str("role", "DKG handler").Str("addr", me.String()).Logger()
 
 	return &Handler{
 		log: log,
 
-		dkgInstance: newInstance(log, me, privKey),
+		dkgInstance: newInstance(log, me, privKey, transcripts, timeout, labels, chain),
 	}
 }
 
@@ -53,6 +62,11 @@ func (h *Handler) Stream(out mino.Sender, in mino.Receiver) error {
 
 	h.log.Info().Msg("stream start")
 
+	// The replies, and the deals of the participants, are queued per
+	// destination so that a slow participant cannot make the node spawn a
+	// routine for every message.
+	out = bounded.NewSender(out)
+
 	globalCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -0,0 +1,320 @@
+package ibe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	kyber "go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// An envelope is made of the IBE encryption of a random session key, followed
+// by the payload encrypted with an AEAD under that key. The payload is split in
+// chunks so that large payloads are never held in memory:
+//
+//	header:  magic (1 byte) || AEAD (1 byte) ||
+//	         U (128 bytes) || encrypted session key (32 bytes)
+//	chunk:   length (4 bytes) || AEAD(chunk)
+//
+// The nonce of a chunk is its index, and the last chunk has the final bit set
+// in its nonce and in its length, so that chunks can't be reordered, dropped or
+// truncated without being noticed. The magic and the AEAD bytes are the
+// additional data of every chunk.
+//
+// The first envelopes had neither the magic nor the AEAD bytes and always used
+// AES-GCM. They are still opened, as the magic can't be the first byte of U:
+// the coordinates of a point are smaller than the modulus, which starts with
+// 0x8f.
+
+// AEAD is the identifier of the algorithm that encrypts the payload of an
+// envelope.
+type AEAD byte
+
+const (
+	// AESGCM is AES-256 in Galois/Counter mode.
+	AESGCM AEAD = 1
+
+	// XChaCha20Poly1305 is ChaCha20-Poly1305 with an extended nonce.
+	XChaCha20Poly1305 AEAD = 2
+
+	// DefaultAEAD is the algorithm of the envelopes sealed by SealEnvelope.
+	DefaultAEAD = AESGCM
+)
+
+// String implements fmt.Stringer. It returns the name of the algorithm.
+func (a AEAD) String() string {
+	switch a {
+	case AESGCM:
+		return "AES-GCM"
+	case XChaCha20Poly1305:
+		return "XChaCha20-Poly1305"
+	default:
+		return fmt.Sprintf("AEAD(%d)", byte(a))
+	}
+}
+
+const (
+	sessionKeySize     = 32
+	envelopeHeaderSize = pointMarshalledSize + sessionKeySize
+
+	// envelopeMagic is the first byte of the envelopes that announce their
+	// AEAD.
+	envelopeMagic = 0xE1
+
+	// EnvelopeChunkSize is the size of the plaintext chunks of an envelope.
+	EnvelopeChunkSize = 64 * 1024
+
+	finalChunkFlag = uint32(1) << 31
+
+	// chunkBufferSize is the size of a buffer that can hold a chunk with its
+	// length prefix and the tag of the AEAD, which is 16 bytes for both.
+	chunkBufferSize = 4 + EnvelopeChunkSize + 16
+)
+
+// chunkPool keeps the buffers of the chunks, so that sealing or opening many
+// envelopes does not allocate new buffers for each of them.
+var chunkPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, chunkBufferSize)
+		return &buf
+	},
+}
+
+// SealEnvelope reads the payload from r and writes an envelope to w that can
+// only be opened with the decryption key of ek. The payload is encrypted with
+// the default AEAD.
+func SealEnvelope(suite pairing.Suite, ek kyber.Point, w io.Writer, r io.Reader) error {
+	return SealEnvelopeWith(suite, ek, DefaultAEAD, w, r)
+}
+
+// SealEnvelopeWith is like SealEnvelope but encrypts the payload with the given
+// AEAD.
+func SealEnvelopeWith(suite pairing.Suite, ek kyber.Point, alg AEAD, w io.Writer, r io.Reader) error {
+	return sealEnvelope(suite, ek, alg, w, r, random.New())
+}
+
+// sealEnvelope seals the envelope with the session key and the randomness of
+// its encryption taken from the stream, which lets the tests produce
+// deterministic envelopes.
+func sealEnvelope(suite pairing.Suite, ek kyber.Point, alg AEAD, w io.Writer, r io.Reader,
+	stream cipher.Stream) error {
+
+	key := make([]byte, sessionKeySize)
+	stream.XORKeyStream(key, key)
+
+	ct, err := encryptCPAonG2(suite, ek, key, stream)
+	if err != nil {
+		return fmt.Errorf("failed to wrap session key: %v", err)
+	}
+
+	aead, err := newEnvelopeAEAD(alg, key)
+	if err != nil {
+		return err
+	}
+
+	ad := []byte{envelopeMagic, byte(alg)}
+
+	header, err := ct.Serialize(suite)
+	if err != nil {
+		return fmt.Errorf("failed to serialize session key: %v", err)
+	}
+
+	_, err = w.Write(append(ad, header...))
+	if err != nil {
+		return fmt.Errorf("failed to write header: %v", err)
+	}
+
+	chunkBuf := chunkPool.Get().(*[]byte)
+	defer chunkPool.Put(chunkBuf)
+
+	nextBuf := chunkPool.Get().(*[]byte)
+	defer chunkPool.Put(nextBuf)
+
+	outBuf := chunkPool.Get().(*[]byte)
+	defer chunkPool.Put(outBuf)
+
+	chunk := (*chunkBuf)[:EnvelopeChunkSize]
+	next := (*nextBuf)[:EnvelopeChunkSize]
+
+	n, err := io.ReadFull(r, chunk)
+	for index := uint64(0); ; index++ {
+		final := true
+		m := 0
+
+		switch err {
+		case nil:
+			// The chunk is full, the next read tells if it's the last one.
+			m, err = io.ReadFull(r, next)
+			final = err == io.EOF
+		case io.EOF, io.ErrUnexpectedEOF:
+		default:
+			return fmt.Errorf("failed to read payload: %v", err)
+		}
+
+		// The chunk is sealed after the room left for its length prefix.
+		out := aead.Seal((*outBuf)[:4], chunkNonce(aead, index, final), chunk[:n], ad)
+
+		length := uint32(len(out) - 4)
+		if final {
+			length |= finalChunkFlag
+		}
+
+		binary.BigEndian.PutUint32(out, length)
+
+		_, err2 := w.Write(out)
+		if err2 != nil {
+			return fmt.Errorf("failed to write chunk: %v", err2)
+		}
+
+		if final {
+			return nil
+		}
+
+		chunk, next = next, chunk
+		n = m
+	}
+}
+
+// OpenEnvelope reads an envelope from r and writes the payload to w. The
+// decryption key is the key released for the label the envelope was sealed
+// to. The AEAD is the one announced by the envelope, or AES-GCM for the
+// envelopes that predate the announcement. Chunks are written as soon as they
+// are authenticated, therefore the content of w must be discarded if an error
+// is returned.
+func OpenEnvelope(suite pairing.Suite, dk kyber.Point, w io.Writer, r io.Reader) error {
+	header := make([]byte, envelopeHeaderSize)
+
+	_, err := io.ReadFull(r, header[:1])
+	if err != nil {
+		return fmt.Errorf("failed to read header: %v", err)
+	}
+
+	alg := AESGCM
+	var ad []byte
+
+	if header[0] == envelopeMagic {
+		_, err = io.ReadFull(r, header[:1])
+		if err != nil {
+			return fmt.Errorf("failed to read header: %v", err)
+		}
+
+		alg = AEAD(header[0])
+		ad = []byte{envelopeMagic, header[0]}
+
+		_, err = io.ReadFull(r, header)
+	} else {
+		_, err = io.ReadFull(r, header[1:])
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read header: %v", err)
+	}
+
+	var ct CiphertextCPA
+	err = ct.Deserialize(suite, header)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize session key: %v", err)
+	}
+
+	key, err := DecryptCPAonG2(suite, dk, &ct)
+	if err != nil {
+		return fmt.Errorf("failed to unwrap session key: %v", err)
+	}
+
+	aead, err := newEnvelopeAEAD(alg, key)
+	if err != nil {
+		return err
+	}
+
+	prefix := make([]byte, 4)
+
+	buf := chunkPool.Get().(*[]byte)
+	defer chunkPool.Put(buf)
+
+	for index := uint64(0); ; index++ {
+		_, err = io.ReadFull(r, prefix)
+		if err != nil {
+			return fmt.Errorf("failed to read chunk %d: %v", index, err)
+		}
+
+		length := binary.BigEndian.Uint32(prefix)
+		final := length&finalChunkFlag != 0
+		length &^= finalChunkFlag
+
+		if length > uint32(EnvelopeChunkSize+aead.Overhead()) {
+			return fmt.Errorf("chunk %d is too big: %d", index, length)
+		}
+
+		sealed := (*buf)[:length]
+
+		_, err = io.ReadFull(r, sealed)
+		if err != nil {
+			return fmt.Errorf("failed to read chunk %d: %v", index, err)
+		}
+
+		// The chunk is opened in place, as the buffer is not needed anymore.
+		chunk, err := aead.Open(sealed[:0], chunkNonce(aead, index, final), sealed, ad)
+		if err != nil {
+			return fmt.Errorf("failed to open chunk %d: %v", index, err)
+		}
+
+		_, err = w.Write(chunk)
+		if err != nil {
+			return fmt.Errorf("failed to write payload: %v", err)
+		}
+
+		if final {
+			return nil
+		}
+	}
+}
+
+func newEnvelopeAEAD(alg AEAD, key []byte) (cipher.AEAD, error) {
+	if len(key) != sessionKeySize {
+		return nil, errors.New("invalid session key size")
+	}
+
+	switch alg {
+	case AESGCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %v", err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AEAD: %v", err)
+		}
+
+		return aead, nil
+	case XChaCha20Poly1305:
+		aead, err := chacha20poly1305.NewX(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AEAD: %v", err)
+		}
+
+		return aead, nil
+	default:
+		return nil, fmt.Errorf("unknown AEAD %v", alg)
+	}
+}
+
+// chunkNonce returns the nonce of a chunk. The session key is used for a single
+// envelope, so a counter is enough to keep the nonces unique.
+func chunkNonce(aead cipher.AEAD, index uint64, final bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce, index)
+
+	if final {
+		nonce[len(nonce)-1] = 1
+	}
+
+	return nonce
+}
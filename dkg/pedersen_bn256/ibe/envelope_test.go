@@ -0,0 +1,196 @@
+package ibe
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kyber "go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestEnvelope_SealOpen(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(t, suite, []byte("label"))
+
+	sizes := []int{0, 1, EnvelopeChunkSize, EnvelopeChunkSize + 1, 3*EnvelopeChunkSize - 1}
+
+	for _, alg := range []AEAD{AESGCM, XChaCha20Poly1305} {
+		for _, size := range sizes {
+			payload := make([]byte, size)
+			for i := range payload {
+				payload[i] = byte(i)
+			}
+
+			sealed := new(bytes.Buffer)
+			err := SealEnvelopeWith(suite, ek, alg, sealed, bytes.NewReader(payload))
+			require.NoError(t, err)
+			require.Equal(t, []byte{envelopeMagic, byte(alg)}, sealed.Bytes()[:2])
+
+			opened := new(bytes.Buffer)
+			err = OpenEnvelope(suite, dk, opened, sealed)
+			require.NoError(t, err, alg)
+			require.Equal(t, size, opened.Len())
+			require.True(t, bytes.Equal(payload, opened.Bytes()))
+		}
+	}
+}
+
+func TestEnvelope_OpenLegacy(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(t, suite, []byte("label"))
+
+	payload := make([]byte, EnvelopeChunkSize+1)
+	sealed := sealLegacyEnvelope(t, suite, ek, payload, random.New())
+
+	opened := new(bytes.Buffer)
+	err := OpenEnvelope(suite, dk, opened, bytes.NewReader(sealed))
+	require.NoError(t, err)
+	require.Equal(t, payload, opened.Bytes())
+}
+
+func TestEnvelope_UnknownAEAD(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(t, suite, []byte("label"))
+
+	err := SealEnvelopeWith(suite, ek, AEAD(0xff), new(bytes.Buffer), bytes.NewReader(nil))
+	require.EqualError(t, err, "unknown AEAD AEAD(255)")
+
+	sealed := new(bytes.Buffer)
+	err = SealEnvelope(suite, ek, sealed, bytes.NewReader([]byte("hello")))
+	require.NoError(t, err)
+
+	data := sealed.Bytes()
+	data[1] = 0xff
+
+	err = OpenEnvelope(suite, dk, new(bytes.Buffer), bytes.NewReader(data))
+	require.EqualError(t, err, "unknown AEAD AEAD(255)")
+
+	// Announcing another AEAD does not authenticate.
+	data[1] = byte(XChaCha20Poly1305)
+
+	err = OpenEnvelope(suite, dk, new(bytes.Buffer), bytes.NewReader(data))
+	require.EqualError(t, err, "failed to open chunk 0: chacha20poly1305: message authentication failed")
+}
+
+func TestEnvelope_OpenWrongKey(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	ek, _ := makeKeys(t, suite, []byte("label"))
+	_, dk := makeKeys(t, suite, []byte("other"))
+
+	sealed := new(bytes.Buffer)
+	err := SealEnvelope(suite, ek, sealed, bytes.NewReader([]byte("hello")))
+	require.NoError(t, err)
+
+	err = OpenEnvelope(suite, dk, new(bytes.Buffer), sealed)
+	require.EqualError(t, err, "failed to open chunk 0: cipher: message authentication failed")
+}
+
+func TestEnvelope_OpenTruncated(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(t, suite, []byte("label"))
+
+	sealed := new(bytes.Buffer)
+	err := SealEnvelope(suite, ek, sealed, bytes.NewReader(make([]byte, 2*EnvelopeChunkSize+1)))
+	require.NoError(t, err)
+
+	data := sealed.Bytes()
+
+	err = OpenEnvelope(suite, dk, new(bytes.Buffer), bytes.NewReader(data[:10]))
+	require.EqualError(t, err, "failed to read header: unexpected EOF")
+
+	// Dropping the last chunk leaves a stream without a final chunk.
+	chunkLen := 4 + EnvelopeChunkSize + 16
+	headerLen := 2 + envelopeHeaderSize
+	truncated := data[:headerLen+2*chunkLen]
+
+	err = OpenEnvelope(suite, dk, new(bytes.Buffer), bytes.NewReader(truncated))
+	require.EqualError(t, err, "failed to read chunk 2: EOF")
+
+	// Flagging an intermediate chunk as final doesn't authenticate.
+	forged := append([]byte{}, truncated...)
+	forged[headerLen+chunkLen] |= 0x80
+
+	err = OpenEnvelope(suite, dk, new(bytes.Buffer), bytes.NewReader(forged))
+	require.EqualError(t, err, "failed to open chunk 1: cipher: message authentication failed")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func BenchmarkEnvelope_Open(b *testing.B) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(b, suite, []byte("label"))
+
+	sealed := new(bytes.Buffer)
+	err := SealEnvelope(suite, ek, sealed, bytes.NewReader(make([]byte, 4*EnvelopeChunkSize)))
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err := OpenEnvelope(suite, dk, io.Discard, bytes.NewReader(sealed.Bytes()))
+		require.NoError(b, err)
+	}
+}
+
+// sealLegacyEnvelope seals the payload in the format of the envelopes that do
+// not announce their AEAD.
+func sealLegacyEnvelope(t testing.TB, suite *bn256.Suite, ek kyber.Point, payload []byte,
+	stream cipher.Stream) []byte {
+
+	key := make([]byte, sessionKeySize)
+	stream.XORKeyStream(key, key)
+
+	ct, err := encryptCPAonG2(suite, ek, key, stream)
+	require.NoError(t, err)
+
+	out, err := ct.Serialize(suite)
+	require.NoError(t, err)
+
+	aead, err := newEnvelopeAEAD(AESGCM, key)
+	require.NoError(t, err)
+
+	for index := uint64(0); ; index++ {
+		n := len(payload)
+		if n > EnvelopeChunkSize {
+			n = EnvelopeChunkSize
+		}
+
+		final := n == len(payload)
+
+		sealed := aead.Seal(nil, chunkNonce(aead, index, final), payload[:n], nil)
+
+		length := uint32(len(sealed))
+		if final {
+			length |= finalChunkFlag
+		}
+
+		out = binary.BigEndian.AppendUint32(out, length)
+		out = append(out, sealed...)
+
+		if final {
+			return out
+		}
+
+		payload = payload[n:]
+	}
+}
+
+func makeKeys(t testing.TB, suite *bn256.Suite, label []byte) (kyber.Point, kyber.Point) {
+	secret := suite.G2().Scalar().Pick(suite.RandomStream())
+	pub := suite.G2().Point().Mul(secret, nil)
+
+	ek, err := DeriveEncryptionKeyOnG2(suite, pub, label)
+	require.NoError(t, err)
+
+	dk := suite.G1().Point().(hashablePoint).Hash(label)
+	dk = dk.Mul(secret, dk)
+
+	return ek, dk
+}
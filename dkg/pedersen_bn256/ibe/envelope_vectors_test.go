@@ -0,0 +1,170 @@
+package ibe
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+)
+
+// The golden file is generated with:
+//
+//	go test ./dkg/pedersen_bn256/ibe -run TestEnvelopeVectors -update
+//
+// Another implementation of the envelope can be checked against the file: it
+// must open every envelope with the decryption key, and seal the same bytes
+// when it draws its randomness like computeEnvelopeVector.
+var envelopeVectorsPath = filepath.Join("testdata", "envelopes.json")
+
+// envelopeVector is an envelope sealed from a seed. The master secret, the
+// session key and the randomness of its encryption are read in that order from
+// the XOF of the suite seeded with the seed. AEAD zero is an envelope that
+// predates the announcement of the AEAD. Every value is encoded in hex.
+type envelopeVector struct {
+	Seed    string
+	AEAD    AEAD
+	Label   string
+	Payload string
+
+	PublicKey     string
+	DecryptionKey string
+	Envelope      string
+}
+
+func TestEnvelopeVectors(t *testing.T) {
+	if *update {
+		vectors := []envelopeVector{
+			{Seed: "00", AEAD: 0, Label: "6c6162656c", Payload: "68656c6c6f"},
+			{Seed: "01", AEAD: AESGCM, Label: "", Payload: ""},
+			{Seed: "02", AEAD: AESGCM, Label: "6c6162656c", Payload: "68656c6c6f"},
+			{Seed: "03", AEAD: XChaCha20Poly1305, Label: "", Payload: ""},
+			{Seed: "04", AEAD: XChaCha20Poly1305, Label: "0000000000000001",
+				Payload: "00ff00ff00ff00ff00ff00ff00ff00ff00ff"},
+			// The payloads span two chunks, the second one holding one byte.
+			{Seed: "05", AEAD: AESGCM, Label: "6c6162656c",
+				Payload: makeVectorPayload(EnvelopeChunkSize + 1)},
+			{Seed: "06", AEAD: XChaCha20Poly1305, Label: "6c6162656c",
+				Payload: makeVectorPayload(EnvelopeChunkSize + 1)},
+		}
+
+		for i := range vectors {
+			computeEnvelopeVector(t, &vectors[i])
+		}
+
+		data, err := json.MarshalIndent(vectors, "", "  ")
+		require.NoError(t, err)
+
+		err = os.WriteFile(envelopeVectorsPath, append(data, '\n'), 0644)
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(envelopeVectorsPath)
+	require.NoError(t, err)
+
+	var vectors []envelopeVector
+	err = json.Unmarshal(data, &vectors)
+	require.NoError(t, err)
+	require.NotEmpty(t, vectors)
+
+	chunked := 0
+
+	for _, expected := range vectors {
+		actual := envelopeVector{
+			Seed:    expected.Seed,
+			AEAD:    expected.AEAD,
+			Label:   expected.Label,
+			Payload: expected.Payload,
+		}
+
+		computeEnvelopeVector(t, &actual)
+		require.Equal(t, expected, actual)
+
+		checkEnvelopeVector(t, expected)
+
+		if len(expected.Payload)/2 > EnvelopeChunkSize {
+			chunked++
+		}
+	}
+
+	require.Equal(t, 2, chunked)
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func computeEnvelopeVector(t *testing.T, v *envelopeVector) {
+	suite := bn256.NewSuiteG2()
+
+	stream := suite.XOF(mustDecode(t, v.Seed))
+	label := mustDecode(t, v.Label)
+	payload := mustDecode(t, v.Payload)
+
+	secret := suite.G2().Scalar().Pick(stream)
+	pub := suite.G2().Point().Mul(secret, nil)
+
+	pubBuf, err := pub.MarshalBinary()
+	require.NoError(t, err)
+
+	ek, err := DeriveEncryptionKeyOnG2(suite, pub, label)
+	require.NoError(t, err)
+
+	dk := suite.G1().Point().(hashablePoint).Hash(label)
+	dk = dk.Mul(secret, dk)
+
+	dkBuf, err := dk.MarshalBinary()
+	require.NoError(t, err)
+
+	var sealed []byte
+
+	if v.AEAD == 0 {
+		sealed = sealLegacyEnvelope(t, suite, ek, payload, stream)
+	} else {
+		out := new(bytes.Buffer)
+		err = sealEnvelope(suite, ek, v.AEAD, out, bytes.NewReader(payload), stream)
+		require.NoError(t, err)
+
+		sealed = out.Bytes()
+	}
+
+	v.PublicKey = hex.EncodeToString(pubBuf)
+	v.DecryptionKey = hex.EncodeToString(dkBuf)
+	v.Envelope = hex.EncodeToString(sealed)
+}
+
+// makeVectorPayload returns n bytes in hex that count modulo a prime, so that
+// two chunks swapped in an envelope don't open to the same payload.
+func makeVectorPayload(n int) string {
+	payload := make([]byte, n)
+	for i := range payload {
+		payload[i] = byte(i % 251)
+	}
+
+	return hex.EncodeToString(payload)
+}
+
+// checkEnvelopeVector makes sure the envelope opens with the decryption key
+// alone, and that the decryption key is valid for the public key.
+func checkEnvelopeVector(t *testing.T, v envelopeVector) {
+	suite := bn256.NewSuiteG2()
+
+	pub := suite.G2().Point()
+	err := pub.UnmarshalBinary(mustDecode(t, v.PublicKey))
+	require.NoError(t, err)
+
+	err = VerifyDecryptionKey(suite, pub, mustDecode(t, v.Label), mustDecode(t, v.DecryptionKey))
+	require.NoError(t, err)
+
+	dk := suite.G1().Point()
+	err = dk.UnmarshalBinary(mustDecode(t, v.DecryptionKey))
+	require.NoError(t, err)
+
+	opened := new(bytes.Buffer)
+	err = OpenEnvelope(suite, dk, opened, bytes.NewReader(mustDecode(t, v.Envelope)))
+	require.NoError(t, err)
+	require.Equal(t, v.Payload, hex.EncodeToString(opened.Bytes()))
+}
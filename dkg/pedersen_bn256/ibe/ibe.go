@@ -6,15 +6,13 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/sha512"
 	"errors"
 	"fmt"
 
+	"go.dedis.ch/dela/crypto/kdf"
 	kyber "go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/util/random"
-
-	"golang.org/x/crypto/hkdf"
 )
 
 // Based on https://github.com/drand/kyber/blob/master/encrypt/ibe/ibe.go
@@ -41,9 +39,15 @@ func (ct *CiphertextCPA) Serialize(suite pairing.Suite) ([]byte, error) {
 	return buf, nil
 }
 func (ct *CiphertextCPA) Deserialize(suite pairing.Suite, data []byte) error {
+	if len(data) < pointMarshalledSize {
+		return fmt.Errorf("ciphertext is too short: %v", len(data))
+	}
 	marshalledU := data[:pointMarshalledSize]
 	U := suite.G2().Point()
-	U.UnmarshalBinary(marshalledU)
+	err := U.UnmarshalBinary(marshalledU)
+	if err != nil {
+		return fmt.Errorf("invalid point: %v", err)
+	}
 	ct.U = U
 	ct.V = bytes.Clone(data[pointMarshalledSize:])
 	return nil
@@ -54,13 +58,10 @@ type hashablePoint interface {
 }
 
 func gtToStream(GidT kyber.Point) (cipher.Stream, error) {
-	seed, err := GidT.MarshalBinary()
+	key, err := kdf.DerivePoint(GidT, kdf.IBEStream, 32)
 	if err != nil {
 		return nil, err
 	}
-	key := make([]byte, 32)
-	kdf := hkdf.New(sha512.New, seed, nil, nil)
-	kdf.Read(key)
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -79,7 +80,13 @@ func DeriveEncryptionKeyOnG2(suite pairing.Suite, X kyber.Point, label []byte) (
 }
 
 func EncryptCPAonG2(suite pairing.Suite, P kyber.Point, msg []byte) (*CiphertextCPA, error) {
-	r := suite.G2().Scalar().Pick(random.New())
+	return encryptCPAonG2(suite, P, msg, random.New())
+}
+
+// encryptCPAonG2 encrypts the message with the randomness taken from the
+// stream, which lets the tests produce deterministic ciphertexts.
+func encryptCPAonG2(suite pairing.Suite, P kyber.Point, msg []byte, rand cipher.Stream) (*CiphertextCPA, error) {
+	r := suite.G2().Scalar().Pick(rand)
 	U := suite.G2().Point().Mul(r, suite.G2().Point().Base())
 	xof, err := gtToStream(suite.GT().Point().Mul(r, P))
 	if err != nil {
@@ -92,11 +99,24 @@ func EncryptCPAonG2(suite pairing.Suite, P kyber.Point, msg []byte) (*Ciphertext
 }
 
 func DecryptCPAonG2(suite pairing.Suite, decKey kyber.Point, ct *CiphertextCPA) ([]byte, error) {
+	return AppendDecryptCPAonG2(make([]byte, 0, len(ct.V)), suite, decKey, ct)
+}
+
+// AppendDecryptCPAonG2 decrypts the ciphertext and appends the message to dst.
+// A caller decrypting many ciphertexts can reuse the same buffer to avoid an
+// allocation per message.
+func AppendDecryptCPAonG2(dst []byte, suite pairing.Suite, decKey kyber.Point, ct *CiphertextCPA) ([]byte, error) {
 	xof, err := gtToStream(suite.Pair(decKey, ct.U))
 	if err != nil {
 		return nil, err
 	}
-	msg := make([]byte, len(ct.V))
-	xof.XORKeyStream(msg, ct.V)
-	return msg, nil
+	start := len(dst)
+	if cap(dst)-start < len(ct.V) {
+		grown := make([]byte, start, start+len(ct.V))
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:start+len(ct.V)]
+	xof.XORKeyStream(dst[start:], ct.V)
+	return dst, nil
 }
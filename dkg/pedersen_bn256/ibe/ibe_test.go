@@ -0,0 +1,110 @@
+package ibe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+)
+
+func TestCiphertextCPA_Deserialize(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(t, suite, []byte("label"))
+
+	ct, err := EncryptCPAonG2(suite, ek, []byte("message"))
+	require.NoError(t, err)
+
+	data, err := ct.Serialize(suite)
+	require.NoError(t, err)
+
+	var decoded CiphertextCPA
+	require.NoError(t, decoded.Deserialize(suite, data))
+
+	msg, err := DecryptCPAonG2(suite, dk, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, []byte("message"), msg)
+
+	err = decoded.Deserialize(suite, data[:pointMarshalledSize-1])
+	require.EqualError(t, err, "ciphertext is too short: 127")
+
+	bad := append([]byte{}, data...)
+	bad[0] ^= 0xff
+	err = decoded.Deserialize(suite, bad)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid point: ", err.Error())
+}
+
+func TestAppendDecryptCPAonG2(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(t, suite, []byte("label"))
+
+	ct, err := EncryptCPAonG2(suite, ek, []byte("message"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 0, 64)
+
+	msg, err := AppendDecryptCPAonG2(buf, suite, dk, ct)
+	require.NoError(t, err)
+	require.Equal(t, []byte("message"), msg)
+	require.Equal(t, &buf[:1][0], &msg[0])
+
+	msg, err = AppendDecryptCPAonG2([]byte("a "), suite, dk, ct)
+	require.NoError(t, err)
+	require.Equal(t, []byte("a message"), msg)
+}
+
+func BenchmarkDecryptCPAonG2(b *testing.B) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(b, suite, []byte("label"))
+
+	ct, err := EncryptCPAonG2(suite, ek, make([]byte, 1024))
+	require.NoError(b, err)
+
+	b.Run("alloc", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_, err := DecryptCPAonG2(suite, dk, ct)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("reuse", func(b *testing.B) {
+		b.ReportAllocs()
+
+		buf := make([]byte, 0, 1024)
+
+		for i := 0; i < b.N; i++ {
+			_, err := AppendDecryptCPAonG2(buf[:0], suite, dk, ct)
+			require.NoError(b, err)
+		}
+	})
+}
+
+func FuzzCiphertextCPA_Deserialize(f *testing.F) {
+	suite := bn256.NewSuiteG2()
+	ek, dk := makeKeys(f, suite, []byte("label"))
+
+	for _, msg := range [][]byte{nil, []byte("message"), make([]byte, 64)} {
+		ct, err := EncryptCPAonG2(suite, ek, msg)
+		require.NoError(f, err)
+
+		data, err := ct.Serialize(suite)
+		require.NoError(f, err)
+
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var ct CiphertextCPA
+
+		err := ct.Deserialize(suite, data)
+		if err != nil {
+			return
+		}
+
+		msg, err := DecryptCPAonG2(suite, dk, &ct)
+		require.NoError(t, err)
+		require.Len(t, msg, len(data)-pointMarshalledSize)
+	})
+}
@@ -0,0 +1,22 @@
+package ibe
+
+import (
+	"fmt"
+
+	kyber "go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/sign/bls"
+)
+
+// VerifyDecryptionKey checks that the decryption key of the label was released
+// by the committee of the public key. The key is the BLS signature of the label
+// recovered from the shares, so that a client verifies a single signature
+// instead of the share of each member.
+func VerifyDecryptionKey(suite pairing.Suite, pubKey kyber.Point, label, key []byte) error {
+	err := bls.Verify(suite, pubKey, label, key)
+	if err != nil {
+		return fmt.Errorf("invalid decryption key: %v", err)
+	}
+
+	return nil
+}
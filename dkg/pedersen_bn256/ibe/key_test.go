@@ -0,0 +1,38 @@
+package ibe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+func TestVerifyDecryptionKey(t *testing.T) {
+	suite := bn256.NewSuiteG2()
+	label := []byte("label")
+
+	poly := share.NewPriPoly(suite.G2(), 2, nil, suite.RandomStream())
+	pubPoly := poly.Commit(nil)
+
+	sigs := make([][]byte, 3)
+	for i, priShare := range poly.Shares(3) {
+		var err error
+		sigs[i], err = tbls.Sign(suite, priShare, label)
+		require.NoError(t, err)
+	}
+
+	key, err := tbls.Recover(suite, pubPoly, label, sigs[1:], 2, 3)
+	require.NoError(t, err)
+
+	err = VerifyDecryptionKey(suite, pubPoly.Commit(), label, key)
+	require.NoError(t, err)
+
+	err = VerifyDecryptionKey(suite, pubPoly.Commit(), []byte("other"), key)
+	require.EqualError(t, err, "invalid decryption key: bls: invalid signature")
+
+	err = VerifyDecryptionKey(suite, pubPoly.Commit(), label, key[1:])
+	require.Error(t, err)
+	require.Regexp(t, "^invalid decryption key: ", err.Error())
+}
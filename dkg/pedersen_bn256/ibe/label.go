@@ -0,0 +1,245 @@
+package ibe
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Namespace is the namespace of a label. It tells when the key of a label can
+// be released, and prevents the labels of different applications to collide.
+type Namespace string
+
+const (
+	// RawNamespace is the namespace of the labels that do not have a prefix.
+	RawNamespace Namespace = ""
+
+	// BlockNamespace is the namespace of the labels released once a given block
+	// is reached.
+	BlockNamespace Namespace = "f3b:block"
+
+	// TimeNamespace is the namespace of the labels released once a deadline
+	// has passed.
+	TimeNamespace Namespace = "f3b:time"
+
+	// TxNamespace is the namespace of the labels of the transactions, released
+	// once the block of the transaction is finalized.
+	TxNamespace Namespace = "f3b:tx"
+
+	// appPrefix is the prefix of the namespaces of the applications.
+	appPrefix = "app:"
+)
+
+// timeLabelPrefix is the prefix of the labels that are released once a
+// deadline has passed, instead of when a given block is reached.
+const timeLabelPrefix = string(TimeNamespace) + ":"
+
+// blockLabelPrefix is the prefix of the labels released at a given block.
+const blockLabelPrefix = string(BlockNamespace) + ":"
+
+// txLabelPrefix is the prefix of the labels of the transactions.
+const txLabelPrefix = string(TxNamespace) + ":"
+
+// AppNamespace returns the namespace of the application with the identifier.
+func AppNamespace(id string) Namespace {
+	return Namespace(appPrefix + id)
+}
+
+// NewBlockLabel returns the label whose identity key is released once the
+// block at the index is reached.
+func NewBlockLabel(index uint64) []byte {
+	return []byte(blockLabelPrefix + strconv.FormatUint(index, 10))
+}
+
+// IsBlockLabel returns true if the label is in the namespace of the blocks.
+func IsBlockLabel(label []byte) bool {
+	return bytes.HasPrefix(label, []byte(blockLabelPrefix))
+}
+
+// ParseBlockLabel returns the index of the block encoded in the label.
+func ParseBlockLabel(label []byte) (uint64, error) {
+	if !bytes.HasPrefix(label, []byte(blockLabelPrefix)) {
+		return 0, fmt.Errorf("not a block label: %q", label)
+	}
+
+	index, err := strconv.ParseUint(string(label[len(blockLabelPrefix):]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse index: %v", err)
+	}
+
+	return index, nil
+}
+
+// NewAppLabel returns a label in the namespace of the application. The
+// identifier must not be empty nor contain a colon.
+func NewAppLabel(id string, custom []byte) ([]byte, error) {
+	if id == "" || strings.Contains(id, ":") {
+		return nil, fmt.Errorf("invalid application identifier %q", id)
+	}
+
+	return append([]byte(appPrefix+id+":"), custom...), nil
+}
+
+// GetNamespace returns the namespace of the label. It returns an error if the
+// label has the prefix of a namespace but is malformed.
+func GetNamespace(label []byte) (Namespace, error) {
+	switch {
+	case IsTimeLabel(label):
+		_, err := ParseTimeLabel(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid time label: %v", err)
+		}
+
+		return TimeNamespace, nil
+	case bytes.HasPrefix(label, []byte(blockLabelPrefix)):
+		_, err := ParseBlockLabel(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid block label: %v", err)
+		}
+
+		return BlockNamespace, nil
+	case IsTxLabel(label):
+		_, _, err := ParseTxLabel(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid transaction label: %v", err)
+		}
+
+		return TxNamespace, nil
+	case bytes.HasPrefix(label, []byte(appPrefix)):
+		id, _, found := strings.Cut(string(label[len(appPrefix):]), ":")
+		if !found || id == "" {
+			return "", fmt.Errorf("invalid application label: %q", label)
+		}
+
+		return AppNamespace(id), nil
+	case bytes.HasPrefix(label, []byte("f3b:")):
+		return "", fmt.Errorf("unknown namespace: %q", label)
+	default:
+		return RawNamespace, nil
+	}
+}
+
+// Registry is the set of namespaces whose labels are allowed on a chain.
+type Registry struct {
+	namespaces map[Namespace]struct{}
+}
+
+// NewRegistry returns a registry that allows the namespaces.
+func NewRegistry(namespaces ...Namespace) Registry {
+	r := Registry{
+		namespaces: make(map[Namespace]struct{}),
+	}
+
+	for _, ns := range namespaces {
+		r.namespaces[ns] = struct{}{}
+	}
+
+	return r
+}
+
+// Allows returns true if the namespace is allowed.
+func (r Registry) Allows(ns Namespace) bool {
+	_, found := r.namespaces[ns]
+	return found
+}
+
+// Validate returns an error if the label is malformed or if its namespace is
+// not allowed.
+func (r Registry) Validate(label []byte) error {
+	ns, err := GetNamespace(label)
+	if err != nil {
+		return err
+	}
+
+	if !r.Allows(ns) {
+		return fmt.Errorf("namespace %q is not allowed", ns)
+	}
+
+	return nil
+}
+
+// NewTimeLabel returns the label whose identity key is released once the
+// deadline has passed. The deadline is encoded in UTC with a second precision
+// so that every party derives the same label.
+func NewTimeLabel(deadline time.Time) []byte {
+	return []byte(timeLabelPrefix + deadline.UTC().Format(time.RFC3339))
+}
+
+// IsTimeLabel returns true if the label is a deadline.
+func IsTimeLabel(label []byte) bool {
+	return bytes.HasPrefix(label, []byte(timeLabelPrefix))
+}
+
+// ParseTimeLabel returns the deadline encoded in the label.
+func ParseTimeLabel(label []byte) (time.Time, error) {
+	if !IsTimeLabel(label) {
+		return time.Time{}, fmt.Errorf("not a time label: %q", label)
+	}
+
+	value := label[len(timeLabelPrefix):]
+
+	deadline, err := time.Parse(time.RFC3339, string(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse deadline: %v", err)
+	}
+
+	return deadline, nil
+}
+
+// NewTxLabel returns the label of a transaction, whose key is released once
+// the block at the height is finalized. The index is the position of the
+// transaction in the block. The label ends with H(height || commitment) where
+// the commitment binds the content of the transaction, so that two
+// transactions get different labels which can't be guessed before the block
+// is known.
+func NewTxLabel(height, index uint64, commitment []byte) []byte {
+	h := sha256.New()
+
+	buffer := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buffer, height)
+
+	h.Write(buffer)
+	h.Write(commitment)
+
+	return []byte(fmt.Sprintf("%s%d:%d:%x", txLabelPrefix, height, index, h.Sum(nil)))
+}
+
+// IsTxLabel returns true if the label is in the namespace of the transactions.
+func IsTxLabel(label []byte) bool {
+	return bytes.HasPrefix(label, []byte(txLabelPrefix))
+}
+
+// ParseTxLabel returns the height of the block and the index of the
+// transaction encoded in the label.
+func ParseTxLabel(label []byte) (uint64, uint64, error) {
+	if !IsTxLabel(label) {
+		return 0, 0, fmt.Errorf("not a transaction label: %q", label)
+	}
+
+	parts := strings.Split(string(label[len(txLabelPrefix):]), ":")
+	if len(parts) != 3 {
+		return 0, 0, fmt.Errorf("malformed transaction label: %q", label)
+	}
+
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse height: %v", err)
+	}
+
+	index, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse index: %v", err)
+	}
+
+	digest, err := hex.DecodeString(parts[2])
+	if err != nil || len(digest) != sha256.Size {
+		return 0, 0, fmt.Errorf("invalid digest: %q", parts[2])
+	}
+
+	return height, index, nil
+}
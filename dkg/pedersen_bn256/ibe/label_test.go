@@ -0,0 +1,162 @@
+package ibe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeLabel(t *testing.T) {
+	deadline := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	label := NewTimeLabel(deadline.In(time.FixedZone("CEST", 2*60*60)))
+	require.Equal(t, "f3b:time:2024-06-01T12:00:00Z", string(label))
+	require.True(t, IsTimeLabel(label))
+
+	parsed, err := ParseTimeLabel(label)
+	require.NoError(t, err)
+	require.True(t, deadline.Equal(parsed))
+
+	// The prefix of the time labels before the namespaces is not recognized.
+	require.False(t, IsTimeLabel([]byte("release-at:2024-06-01T12:00:00Z")))
+}
+
+func TestTimeLabel_Bad(t *testing.T) {
+	require.False(t, IsTimeLabel([]byte("block:1")))
+
+	_, err := ParseTimeLabel([]byte("block:1"))
+	require.EqualError(t, err, `not a time label: "block:1"`)
+
+	_, err = ParseTimeLabel([]byte("f3b:time:tomorrow"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse deadline: ")
+}
+
+func TestTxLabel(t *testing.T) {
+	label := NewTxLabel(1, 2, []byte("tx1"))
+	require.Equal(t, label, NewTxLabel(1, 2, []byte("tx1")))
+	require.Equal(t, "f3b:tx:1:2:", string(label[:11]))
+	require.Len(t, label, 11+64)
+
+	require.NotEqual(t, label, NewTxLabel(3, 2, []byte("tx1")))
+	require.NotEqual(t, label, NewTxLabel(1, 2, []byte("tx2")))
+	require.True(t, IsTxLabel(label))
+	require.False(t, IsTxLabel(NewBlockLabel(1)))
+	require.False(t, IsTimeLabel(label))
+	require.False(t, IsBlockLabel(label))
+
+	height, index, err := ParseTxLabel(label)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), height)
+	require.Equal(t, uint64(2), index)
+
+	_, _, err = ParseTxLabel(NewBlockLabel(1))
+	require.EqualError(t, err, `not a transaction label: "f3b:block:1"`)
+
+	_, _, err = ParseTxLabel([]byte("f3b:tx:1:2"))
+	require.EqualError(t, err, `malformed transaction label: "f3b:tx:1:2"`)
+
+	_, _, err = ParseTxLabel([]byte("f3b:tx:one:2:00"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse height: ")
+
+	_, _, err = ParseTxLabel([]byte("f3b:tx:1:two:00"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse index: ")
+
+	_, _, err = ParseTxLabel([]byte("f3b:tx:1:2:00"))
+	require.EqualError(t, err, `invalid digest: "00"`)
+}
+
+func TestBlockLabel(t *testing.T) {
+	label := NewBlockLabel(42)
+	require.Equal(t, "f3b:block:42", string(label))
+
+	require.True(t, IsBlockLabel(label))
+	require.False(t, IsBlockLabel(NewTimeLabel(time.Now())))
+
+	index, err := ParseBlockLabel(label)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), index)
+
+	_, err = ParseBlockLabel([]byte("block:1"))
+	require.EqualError(t, err, `not a block label: "block:1"`)
+
+	_, err = ParseBlockLabel([]byte("f3b:block:-1"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse index: ")
+}
+
+func TestAppLabel(t *testing.T) {
+	label, err := NewAppLabel("auction", []byte("bid:1"))
+	require.NoError(t, err)
+	require.Equal(t, "app:auction:bid:1", string(label))
+
+	_, err = NewAppLabel("", nil)
+	require.EqualError(t, err, `invalid application identifier ""`)
+
+	_, err = NewAppLabel("a:b", nil)
+	require.EqualError(t, err, `invalid application identifier "a:b"`)
+}
+
+func TestGetNamespace(t *testing.T) {
+	deadline := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	ns, err := GetNamespace(NewTimeLabel(deadline))
+	require.NoError(t, err)
+	require.Equal(t, TimeNamespace, ns)
+
+	ns, err = GetNamespace(NewBlockLabel(1))
+	require.NoError(t, err)
+	require.Equal(t, BlockNamespace, ns)
+
+	ns, err = GetNamespace([]byte("app:auction:bid"))
+	require.NoError(t, err)
+	require.Equal(t, AppNamespace("auction"), ns)
+
+	ns, err = GetNamespace(NewTxLabel(1, 0, []byte("tx1")))
+	require.NoError(t, err)
+	require.Equal(t, TxNamespace, ns)
+
+	ns, err = GetNamespace([]byte("label"))
+	require.NoError(t, err)
+	require.Equal(t, RawNamespace, ns)
+
+	_, err = GetNamespace([]byte("f3b:time:tomorrow"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid time label: failed to parse deadline: ")
+
+	_, err = GetNamespace([]byte("f3b:tx:1:2"))
+	require.EqualError(t, err, `invalid transaction label: malformed `+
+		`transaction label: "f3b:tx:1:2"`)
+
+	_, err = GetNamespace([]byte("f3b:block:one"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid block label: failed to parse index: ")
+
+	_, err = GetNamespace([]byte("app:auction"))
+	require.EqualError(t, err, `invalid application label: "app:auction"`)
+
+	_, err = GetNamespace([]byte("f3b:epoch:1"))
+	require.EqualError(t, err, `unknown namespace: "f3b:epoch:1"`)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(BlockNamespace, AppNamespace("auction"))
+
+	require.True(t, registry.Allows(BlockNamespace))
+	require.False(t, registry.Allows(TimeNamespace))
+
+	require.NoError(t, registry.Validate(NewBlockLabel(1)))
+	require.NoError(t, registry.Validate([]byte("app:auction:bid")))
+
+	err := registry.Validate([]byte("app:lottery:draw"))
+	require.EqualError(t, err, `namespace "app:lottery" is not allowed`)
+
+	err = registry.Validate([]byte("label"))
+	require.EqualError(t, err, `namespace "" is not allowed`)
+
+	err = registry.Validate([]byte("f3b:epoch:1"))
+	require.EqualError(t, err, `unknown namespace: "f3b:epoch:1"`)
+}
@@ -0,0 +1,154 @@
+package ibe
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+)
+
+// The golden file is generated with:
+//
+//	go test ./dkg/pedersen_bn256/ibe -run TestSchemeVectors -update
+var update = flag.Bool("update", false, "regenerate the test vectors")
+
+var vectorsPath = filepath.Join("testdata", "vectors.json")
+
+// vector is a test case derived from a seed. Every value is encoded in hex.
+// The master secret, the polynomial of the shares and the randomness of the
+// encryption are read from the XOF of the suite seeded with the seed.
+type vector struct {
+	Seed      string
+	Label     string
+	Message   string
+	Threshold int
+	Shares    int
+
+	PublicKey     string
+	Ciphertext    string
+	SigShares     []string
+	DecryptionKey string
+}
+
+func TestSchemeVectors(t *testing.T) {
+	if *update {
+		deadline := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		vectors := []vector{
+			{Seed: "00", Label: "", Message: "", Threshold: 1, Shares: 1},
+			{Seed: "01", Label: "6c6162656c", Message: "68656c6c6f", Threshold: 2, Shares: 3},
+			{Seed: "02", Label: "0000000000000001", Message: "00ff00ff00ff", Threshold: 3, Shares: 4},
+			{Seed: "03", Label: hex.EncodeToString(NewTimeLabel(deadline)),
+				Message: "74696d65", Threshold: 4, Shares: 7},
+		}
+
+		for i := range vectors {
+			computeVector(t, &vectors[i])
+		}
+
+		data, err := json.MarshalIndent(vectors, "", "  ")
+		require.NoError(t, err)
+
+		err = os.WriteFile(vectorsPath, append(data, '\n'), 0644)
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(vectorsPath)
+	require.NoError(t, err)
+
+	var vectors []vector
+	err = json.Unmarshal(data, &vectors)
+	require.NoError(t, err)
+	require.NotEmpty(t, vectors)
+
+	for _, expected := range vectors {
+		actual := vector{
+			Seed:      expected.Seed,
+			Label:     expected.Label,
+			Message:   expected.Message,
+			Threshold: expected.Threshold,
+			Shares:    expected.Shares,
+		}
+
+		computeVector(t, &actual)
+		require.Equal(t, expected, actual)
+
+		checkVector(t, expected)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func computeVector(t *testing.T, v *vector) {
+	suite := bn256.NewSuiteG2()
+
+	stream := suite.XOF(mustDecode(t, v.Seed))
+	label := mustDecode(t, v.Label)
+
+	secret := suite.G2().Scalar().Pick(stream)
+	poly := share.NewPriPoly(suite.G2(), v.Threshold, secret, stream)
+	pub := poly.Commit(nil)
+
+	pubBuf, err := pub.Commit().MarshalBinary()
+	require.NoError(t, err)
+
+	ek, err := DeriveEncryptionKeyOnG2(suite, pub.Commit(), label)
+	require.NoError(t, err)
+
+	ct, err := encryptCPAonG2(suite, ek, mustDecode(t, v.Message), stream)
+	require.NoError(t, err)
+
+	ctBuf, err := ct.Serialize(suite)
+	require.NoError(t, err)
+
+	v.PublicKey = hex.EncodeToString(pubBuf)
+	v.Ciphertext = hex.EncodeToString(ctBuf)
+	v.SigShares = make([]string, v.Shares)
+
+	sigs := make([][]byte, v.Shares)
+	for i, priShare := range poly.Shares(v.Shares) {
+		sigs[i], err = tbls.Sign(suite, priShare, label)
+		require.NoError(t, err)
+
+		v.SigShares[i] = hex.EncodeToString(sigs[i])
+	}
+
+	dk, err := tbls.Recover(suite, pub, label, sigs, v.Threshold, v.Shares)
+	require.NoError(t, err)
+
+	v.DecryptionKey = hex.EncodeToString(dk)
+}
+
+// checkVector makes sure the golden values are consistent on their own, so
+// that a client only needs the public values to validate its implementation.
+func checkVector(t *testing.T, v vector) {
+	suite := bn256.NewSuiteG2()
+
+	dk := suite.G1().Point()
+	err := dk.UnmarshalBinary(mustDecode(t, v.DecryptionKey))
+	require.NoError(t, err)
+
+	var ct CiphertextCPA
+	err = ct.Deserialize(suite, mustDecode(t, v.Ciphertext))
+	require.NoError(t, err)
+
+	msg, err := DecryptCPAonG2(suite, dk, &ct)
+	require.NoError(t, err)
+	require.Equal(t, v.Message, hex.EncodeToString(msg))
+}
+
+func mustDecode(t *testing.T, str string) []byte {
+	buf, err := hex.DecodeString(str)
+	require.NoError(t, err)
+
+	return buf
+}
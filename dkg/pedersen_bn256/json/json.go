@@ -5,6 +5,8 @@ import (
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
 	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
@@ -67,7 +69,8 @@ type StartDone struct {
 }
 
 type SignRequest struct {
-	Msg []byte
+	Msg       []byte
+	PublicKey []byte `json:",omitempty"`
 }
 
 type Ciphertext struct {
@@ -83,6 +86,15 @@ type SignReply struct {
 	Share []byte
 }
 
+type SignBatchRequest struct {
+	Msgs      [][]byte
+	PublicKey []byte `json:",omitempty"`
+}
+
+type SignBatchReply struct {
+	Shares [][]byte
+}
+
 type ShareAndProof struct {
 	V  PublicKey
 	I  int64
@@ -93,6 +105,16 @@ type ShareAndProof struct {
 
 }
 
+type Transcript struct {
+	Threshold  int
+	Addresses  []Address
+	PublicKeys []PublicKey
+	DistKey    PublicKey
+	Commits    []PublicKey
+	ShareIndex int
+	Share      []byte
+}
+
 type Message struct {
 	Start                    *Start                    `json:",omitempty"`
 	StartResharing           *StartResharing           `json:",omitempty"`
@@ -102,6 +124,9 @@ type Message struct {
 	StartDone                *StartDone                `json:",omitempty"`
 	SignRequest           *SignRequest           `json:",omitempty"`
 	SignReply             *SignReply             `json:",omitempty"`
+	SignBatchRequest      *SignBatchRequest      `json:",omitempty"`
+	SignBatchReply        *SignBatchReply        `json:",omitempty"`
+	Transcript            *Transcript            `json:",omitempty"`
 }
 
 // MsgFormat is the engine to encode and decode dkg messages in JSON format.
@@ -161,6 +186,12 @@ func (f msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error)
 		m, err = encodeSignRequest(in)
 	case types.SignReply:
 		m, err = encodeSignReply(in)
+	case types.SignBatchRequest:
+		m, err = encodeSignBatchRequest(in)
+	case types.SignBatchReply:
+		m, err = encodeSignBatchReply(in)
+	case types.Transcript:
+		m, err = encodeTranscript(in)
 	default:
 		return nil, xerrors.Errorf("unsupported message of type '%T'", msg)
 	}
@@ -231,6 +262,15 @@ func (f msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 
 	case m.SignReply != nil:
 		return f.decodeSignReply(ctx, m.SignReply)
+
+	case m.SignBatchRequest != nil:
+		return f.decodeSignBatchRequest(ctx, m.SignBatchRequest)
+
+	case m.SignBatchReply != nil:
+		return f.decodeSignBatchReply(ctx, m.SignBatchReply)
+
+	case m.Transcript != nil:
+		return f.decodeTranscript(ctx, m.Transcript)
 	}
 
 	return nil, xerrors.New("message is empty")
@@ -481,7 +521,8 @@ func (f msgFormat) decodeStartDone(ctx serde.Context, msg *StartDone) (serde.Mes
 
 func encodeSignRequest(msg types.SignRequest) (Message, error) {
 	req := SignRequest{
-		Msg: msg.GetMsg(),
+		Msg:       msg.GetMsg(),
+		PublicKey: msg.GetPublicKey(),
 	}
 
 
@@ -489,7 +530,7 @@ func encodeSignRequest(msg types.SignRequest) (Message, error) {
 }
 
 func (f msgFormat) decodeSignRequest(ctx serde.Context, msg *SignRequest) (serde.Message, error) {
-	req := types.NewSignRequest(msg.Msg)
+	req := types.NewEncryptedSignRequest(msg.Msg, msg.PublicKey)
 
 	return req, nil
 }
@@ -505,3 +546,125 @@ func (f msgFormat) decodeSignReply(ctx serde.Context, msg *SignReply) (serde.Mes
 
 	return resp, nil
 }
+
+func encodeSignBatchRequest(msg types.SignBatchRequest) (Message, error) {
+	req := SignBatchRequest{
+		Msgs:      msg.GetMsgs(),
+		PublicKey: msg.GetPublicKey(),
+	}
+
+	return Message{SignBatchRequest: &req}, nil
+}
+
+func (f msgFormat) decodeSignBatchRequest(ctx serde.Context, msg *SignBatchRequest) (serde.Message, error) {
+	req := types.NewSignBatchRequest(msg.Msgs, msg.PublicKey)
+
+	return req, nil
+}
+
+func encodeSignBatchReply(msg types.SignBatchReply) (Message, error) {
+	shares := make([][]byte, len(msg.Shares))
+	for i, share := range msg.Shares {
+		shares[i] = share
+	}
+
+	return Message{SignBatchReply: &SignBatchReply{Shares: shares}}, nil
+}
+
+func (f msgFormat) decodeSignBatchReply(ctx serde.Context, msg *SignBatchReply) (serde.Message, error) {
+	shares := make([]tbls.SigShare, len(msg.Shares))
+	for i, share := range msg.Shares {
+		shares[i] = share
+	}
+
+	resp := types.NewSignBatchReply(shares)
+
+	return resp, nil
+}
+
+func encodeTranscript(msg types.Transcript) (Message, error) {
+	start, err := encodeStart(types.NewStart(msg.GetThreshold(),
+		msg.GetAddresses(), msg.GetPublicKeys()))
+	if err != nil {
+		return Message{}, xerrors.Errorf("couldn't encode participants: %v", err)
+	}
+
+	distKey, err := msg.GetDistKey().MarshalBinary()
+	if err != nil {
+		return Message{}, xerrors.Errorf("couldn't marshal dist key: %v", err)
+	}
+
+	commits := make([]PublicKey, len(msg.GetCommits()))
+	for i, commit := range msg.GetCommits() {
+		data, err := commit.MarshalBinary()
+		if err != nil {
+			return Message{}, xerrors.Errorf("couldn't marshal commit: %v", err)
+		}
+
+		commits[i] = data
+	}
+
+	share, err := msg.GetShare().V.MarshalBinary()
+	if err != nil {
+		return Message{}, xerrors.Errorf("couldn't marshal share: %v", err)
+	}
+
+	transcript := Transcript{
+		Threshold:  start.Start.Threshold,
+		Addresses:  start.Start.Addresses,
+		PublicKeys: start.Start.PublicKeys,
+		DistKey:    distKey,
+		Commits:    commits,
+		ShareIndex: msg.GetShare().I,
+		Share:      share,
+	}
+
+	return Message{Transcript: &transcript}, nil
+}
+
+func (f msgFormat) decodeTranscript(ctx serde.Context, msg *Transcript) (serde.Message, error) {
+	start, err := f.decodeStart(ctx, &Start{
+		Threshold:  msg.Threshold,
+		Addresses:  msg.Addresses,
+		PublicKeys: msg.PublicKeys,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't decode participants: %v", err)
+	}
+
+	distKey := f.suite.Point()
+	err = distKey.UnmarshalBinary(msg.DistKey)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't unmarshal dist key: %v", err)
+	}
+
+	commits := make([]kyber.Point, len(msg.Commits))
+	for i, commit := range msg.Commits {
+		point := f.suite.Point()
+		err := point.UnmarshalBinary(commit)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't unmarshal commit: %v", err)
+		}
+
+		commits[i] = point
+	}
+
+	scalar := f.suite.Scalar()
+	err = scalar.UnmarshalBinary(msg.Share)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't unmarshal share: %v", err)
+	}
+
+	participants := start.(types.Start)
+
+	transcript := types.NewTranscript(
+		participants.GetThreshold(),
+		participants.GetAddresses(),
+		participants.GetPublicKeys(),
+		distKey,
+		commits,
+		&share.PriShare{I: msg.ShareIndex, V: scalar},
+	)
+
+	return transcript, nil
+}
@@ -10,6 +10,8 @@ import (
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
 	"go.dedis.ch/kyber/v3/suites"
 )
 
@@ -135,6 +137,16 @@ func TestMessageFormat_SignRequest_Encode(t *testing.T) {
 	data, err := format.Encode(ctx, req)
 	require.NoError(t, err)
 	require.Regexp(t, `{(("SignRequest":{"Msg":"[^"]+"}|"\w+":null),?)+}`, string(data))
+
+	req = types.NewEncryptedSignRequest([]byte{1, 2, 3, 4}, []byte{5})
+
+	data, err = format.Encode(ctx, req)
+	require.NoError(t, err)
+	require.Regexp(t, `"SignRequest":{"Msg":"[^"]+","PublicKey":"BQ=="}`, string(data))
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, req, msg)
 }
 
 func TestMessageFormat_SignReply_Encode(t *testing.T) {
@@ -148,6 +160,118 @@ func TestMessageFormat_SignReply_Encode(t *testing.T) {
 	require.Regexp(t, `{(("SignReply":{"Share":"[^"]+"}|"\w+":null),?)+}`, string(data))
 }
 
+func TestMessageFormat_SignBatch_Encode(t *testing.T) {
+	req := types.NewSignBatchRequest([][]byte{{1, 2}, {3}}, []byte{5})
+
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, req)
+	require.NoError(t, err)
+	require.Regexp(t, `"SignBatchRequest":{"Msgs":\["AQI=","Aw=="\],"PublicKey":"BQ=="}`, string(data))
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, req, msg)
+
+	resp := types.NewSignBatchReply([]tbls.SigShare{{1, 2}, {3}})
+
+	data, err = format.Encode(ctx, resp)
+	require.NoError(t, err)
+	require.Regexp(t, `"SignBatchReply":{"Shares":\["AQI=","Aw=="\]}`, string(data))
+
+	msg, err = format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, resp, msg)
+}
+
+func TestMessageFormat_Transcript_Encode(t *testing.T) {
+	priShare := &share.PriShare{I: 1, V: suite.Scalar()}
+	addrs := []mino.Address{fake.NewAddress(0)}
+	points := []kyber.Point{suite.Point()}
+
+	transcript := types.NewTranscript(1, addrs, points, suite.Point(), points, priShare)
+
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, transcript)
+	require.NoError(t, err)
+	require.Regexp(t, `{(("Transcript":{"Threshold":1,"Addresses":\["AAAAAA=="\],`+
+		`"PublicKeys":\["[^"]+"\],"DistKey":"[^"]+","Commits":\["[^"]+"\],`+
+		`"ShareIndex":1,"Share":"[^"]+"}|"\w+":null),?)+}`, string(data))
+
+	transcript = types.NewTranscript(0, nil, []kyber.Point{badPoint{}}, nil, nil, nil)
+	_, err = format.Encode(ctx, transcript)
+	require.EqualError(t, err, fake.Err("failed to encode message: "+
+		"couldn't encode participants: couldn't marshal public key"))
+
+	transcript = types.NewTranscript(0, nil, nil, badPoint{}, nil, nil)
+	_, err = format.Encode(ctx, transcript)
+	require.EqualError(t, err, fake.Err("failed to encode message: couldn't marshal dist key"))
+
+	transcript = types.NewTranscript(0, nil, nil, suite.Point(), []kyber.Point{badPoint{}}, nil)
+	_, err = format.Encode(ctx, transcript)
+	require.EqualError(t, err, fake.Err("failed to encode message: couldn't marshal commit"))
+
+	transcript = types.NewTranscript(0, nil, nil, suite.Point(), nil,
+		&share.PriShare{V: badScallar{}})
+	_, err = format.Encode(ctx, transcript)
+	require.EqualError(t, err, fake.Err("failed to encode message: couldn't marshal share"))
+}
+
+func TestMessageFormat_Decode_Transcript(t *testing.T) {
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+	ctx = serde.WithFactory(ctx, types.AddrKey{}, fake.AddressFactory{})
+
+	point := suite.Point().Pick(suite.RandomStream())
+	priShare := &share.PriShare{I: 2, V: suite.Scalar().Pick(suite.RandomStream())}
+
+	expected := types.NewTranscript(
+		2,
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]kyber.Point{point, point},
+		point,
+		[]kyber.Point{point, point},
+		priShare,
+	)
+
+	data, err := format.Encode(ctx, expected)
+	require.NoError(t, err)
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+
+	transcript := msg.(types.Transcript)
+	require.Equal(t, 2, transcript.GetThreshold())
+	require.Len(t, transcript.GetAddresses(), 2)
+	require.Len(t, transcript.GetPublicKeys(), 2)
+	require.True(t, point.Equal(transcript.GetDistKey()))
+	require.Len(t, transcript.GetCommits(), 2)
+	require.True(t, point.Equal(transcript.GetCommits()[1]))
+	require.Equal(t, 2, transcript.GetShare().I)
+	require.True(t, priShare.V.Equal(transcript.GetShare().V))
+
+	_, err = format.Decode(ctx, []byte(`{"Transcript":{"PublicKeys":[[]]}}`))
+	require.EqualError(t, err, "couldn't decode participants: "+
+		"couldn't unmarshal public key: bn256.G2: not enough data")
+
+	_, err = format.Decode(ctx, []byte(`{"Transcript":{"DistKey":[]}}`))
+	require.EqualError(t, err,
+		"couldn't unmarshal dist key: bn256.G2: not enough data")
+
+	data = []byte(fmt.Sprintf(`{"Transcript":{"DistKey":"%s","Commits":[[]]}}`, testPoint))
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err,
+		"couldn't unmarshal commit: bn256.G2: not enough data")
+
+	data = []byte(fmt.Sprintf(`{"Transcript":{"DistKey":"%s","Share":"AA=="}}`, testPoint))
+	_, err = format.Decode(ctx, data)
+	require.Error(t, err)
+	require.Regexp(t, "^couldn't unmarshal share: ", err.Error())
+}
+
 func TestMessageFormat_Decode(t *testing.T) {
 	format := newMsgFormat()
 	ctx := serde.NewContext(fake.ContextEngine{})
@@ -276,9 +400,67 @@ func TestMessageFormat_Decode_Reshare(t *testing.T) {
 	require.EqualError(t, err, "couldn't unmarshal public coeff key: bn256.G2: not enough data")
 }
 
+func FuzzMessageFormat_Decode(f *testing.F) {
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+	ctx = serde.WithFactory(ctx, types.AddrKey{}, fake.AddressFactory{})
+
+	for _, data := range makeCorpus(f, format, ctx) {
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg, err := format.Decode(ctx, data)
+		if err != nil {
+			return
+		}
+
+		// Whatever is accepted by the decoder must be encoded back.
+		_, err = format.Encode(ctx, msg)
+		require.NoError(t, err)
+	})
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
+// makeCorpus returns the encoding of one message of each type, which is used
+// as the seed corpus of the fuzzer.
+func makeCorpus(f *testing.F, format serde.FormatEngine, ctx serde.Context) [][]byte {
+	point := suite.Point().Pick(suite.RandomStream())
+	addrs := []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+	deal := types.NewDeal(1, []byte{2}, types.NewEncryptedDeal([]byte{3}, []byte{4},
+		[]byte{5}, []byte{6}))
+
+	msgs := []serde.Message{
+		types.NewStart(2, addrs, []kyber.Point{point, point}),
+		types.NewStartResharing(2, 2, addrs, addrs, []kyber.Point{point, point},
+			[]kyber.Point{point, point}),
+		deal,
+		types.NewReshare(deal, []kyber.Point{point}),
+		types.NewResponse(1, types.NewDealerResponse(2, true, []byte{3}, []byte{4})),
+		types.NewStartDone(point),
+		types.NewSignRequest([]byte("label")),
+		types.NewEncryptedSignRequest([]byte("label"), []byte{1}),
+		types.NewSignReply([]byte{0, 1, 2, 3}),
+		types.NewSignBatchRequest([][]byte{[]byte("label")}, []byte{1}),
+		types.NewSignBatchReply([]tbls.SigShare{{0, 1, 2, 3}}),
+		types.NewTranscript(2, addrs, []kyber.Point{point, point}, point,
+			[]kyber.Point{point, point}, &share.PriShare{I: 1, V: suite.Scalar().One()}),
+	}
+
+	corpus := make([][]byte, len(msgs))
+
+	for i, msg := range msgs {
+		data, err := format.Encode(ctx, msg)
+		require.NoError(f, err)
+
+		corpus[i] = data
+	}
+
+	return corpus
+}
+
 const testPoint = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
 
 type badPoint struct {
@@ -0,0 +1,196 @@
+package pedersen
+
+import (
+	"container/list"
+	"sync"
+
+	"go.dedis.ch/dela/core/store/kv"
+	"golang.org/x/xerrors"
+)
+
+// keyBucket is the name of the bucket where the released keys are persisted.
+var keyBucket = []byte("dkg-released-keys")
+
+// defaultKeyCacheSize is the default number of released keys kept in memory.
+const defaultKeyCacheSize = 1024
+
+// keyStore keeps the keys released by the DKG, i.e. the signatures recovered
+// for a label. The most recent keys are cached in memory and, when a database
+// is provided, every key is persisted so that it survives a restart.
+type keyStore struct {
+	sync.Mutex
+
+	db    kv.DB
+	size  int
+	order *list.List
+	cache map[string]*list.Element
+}
+
+type keyEntry struct {
+	label string
+	key   []byte
+}
+
+// newKeyStore returns a new empty key store. The database is optional.
+func newKeyStore(db kv.DB, size int) *keyStore {
+	return &keyStore{
+		db:    db,
+		size:  size,
+		order: list.New(),
+		cache: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the key released for the label, or nil if it is unknown. It
+// first tries the cache, then the database.
+func (s *keyStore) Get(label []byte) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	elem, found := s.cache[string(label)]
+	if found {
+		s.order.MoveToFront(elem)
+		return elem.Value.(keyEntry).key, nil
+	}
+
+	if s.db == nil {
+		return nil, nil
+	}
+
+	var key []byte
+
+	err := s.db.View(func(tx kv.ReadableTx) error {
+		bucket := tx.GetBucket(keyBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		value := bucket.Get(label)
+		if len(value) == 0 {
+			return nil
+		}
+
+		key = make([]byte, len(value))
+		copy(key, value)
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("while reading db: %v", err)
+	}
+
+	if key != nil {
+		s.add(label, key)
+	}
+
+	return key, nil
+}
+
+// Store persists the key released for the label and keeps it in the cache.
+func (s *keyStore) Store(label, key []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.db != nil {
+		err := s.db.Update(func(tx kv.WritableTx) error {
+			bucket, err := tx.GetBucketOrCreate(keyBucket)
+			if err != nil {
+				return xerrors.Errorf("while getting bucket: %v", err)
+			}
+
+			err = bucket.Set(label, key)
+			if err != nil {
+				return xerrors.Errorf("while writing: %v", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return xerrors.Errorf("while updating db: %v", err)
+		}
+	}
+
+	s.add(label, key)
+
+	return nil
+}
+
+// add inserts the key in the cache and evicts the least recently used key when
+// the cache is full. The lock must be held by the caller.
+func (s *keyStore) add(label, key []byte) {
+	elem, found := s.cache[string(label)]
+	if found {
+		elem.Value = keyEntry{label: string(label), key: key}
+		s.order.MoveToFront(elem)
+		return
+	}
+
+	s.cache[string(label)] = s.order.PushFront(keyEntry{label: string(label), key: key})
+
+	if s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.cache, oldest.Value.(keyEntry).label)
+	}
+}
+
+// Prune deletes the keys of the expired labels from the cache and the
+// database. It returns the number of deleted keys and the number of bytes
+// reclaimed by their labels and keys.
+func (s *keyStore) Prune(expired func(label []byte) bool) (int, int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	deleted := make(map[string]int)
+
+	for label, elem := range s.cache {
+		if expired([]byte(label)) {
+			deleted[label] = len(label) + len(elem.Value.(keyEntry).key)
+
+			s.order.Remove(elem)
+			delete(s.cache, label)
+		}
+	}
+
+	if s.db != nil {
+		err := s.db.Update(func(tx kv.WritableTx) error {
+			bucket := tx.GetBucket(keyBucket)
+			if bucket == nil {
+				return nil
+			}
+
+			var labels [][]byte
+
+			err := bucket.ForEach(func(k, v []byte) error {
+				if expired(k) {
+					labels = append(labels, append([]byte{}, k...))
+					deleted[string(k)] = len(k) + len(v)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return xerrors.Errorf("while iterating: %v", err)
+			}
+
+			for _, label := range labels {
+				err = bucket.Delete(label)
+				if err != nil {
+					return xerrors.Errorf("while deleting: %v", err)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return 0, 0, xerrors.Errorf("while updating db: %v", err)
+		}
+	}
+
+	reclaimed := 0
+	for _, size := range deleted {
+		reclaimed += size
+	}
+
+	return len(deleted), reclaimed, nil
+}
@@ -0,0 +1,156 @@
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestKeyStore_GetStore(t *testing.T) {
+	store := newKeyStore(nil, 2)
+
+	key, err := store.Get([]byte("A"))
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	require.NoError(t, store.Store([]byte("A"), []byte("a")))
+	require.NoError(t, store.Store([]byte("B"), []byte("b")))
+
+	key, err = store.Get([]byte("A"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), key)
+
+	// B is the least recently used key and is evicted.
+	require.NoError(t, store.Store([]byte("C"), []byte("c")))
+	require.Len(t, store.cache, 2)
+
+	key, err = store.Get([]byte("B"))
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	require.NoError(t, store.Store([]byte("A"), []byte("aa")))
+
+	key, err = store.Get([]byte("A"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("aa"), key)
+}
+
+func TestKeyStore_Persistence(t *testing.T) {
+	bucket := fake.NewBucket()
+	bucket.Call = &fake.Call{}
+
+	db := fake.NewInMemoryDB()
+	db.SetBucket(keyBucket, bucket)
+	store := newKeyStore(db, 1)
+
+	require.NoError(t, store.Store([]byte("A"), []byte("a")))
+	require.NoError(t, store.Store([]byte("B"), []byte("b")))
+	require.Equal(t, 2, bucket.Call.Len())
+	require.Equal(t, []interface{}{[]byte("A"), []byte("a")}, bucket.Call.GetAll(0))
+
+	// A was evicted from the cache but is still in the database.
+	key, err := store.Get([]byte("A"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), key)
+
+	key, err = store.Get([]byte("C"))
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	store = newKeyStore(fake.NewBadUpdateDB(), 1)
+
+	err = store.Store([]byte("A"), []byte("a"))
+	require.EqualError(t, err, fake.Err("while updating db"))
+
+	store = newKeyStore(fake.NewBadViewDB(), 1)
+
+	_, err = store.Get([]byte("A"))
+	require.EqualError(t, err, fake.Err("while reading db"))
+
+	// The key that could not be persisted is not kept in the cache either.
+	db = fake.NewBadUpdateDBWithDelay(1)
+	db.SetBucket(keyBucket, fake.NewBucket())
+	store = newKeyStore(db, 2)
+
+	require.NoError(t, store.Store([]byte("A"), []byte("a")))
+
+	err = store.Store([]byte("B"), []byte("b"))
+	require.EqualError(t, err, fake.Err("while updating db"))
+
+	key, err = store.Get([]byte("B"))
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	// The cache is only backed by the database for the evicted keys.
+	db = fake.NewBadViewDBWithDelay(1)
+	db.SetBucket(keyBucket, fake.NewBucket())
+	store = newKeyStore(db, 1)
+
+	require.NoError(t, store.Store([]byte("A"), []byte("a")))
+	require.NoError(t, store.Store([]byte("B"), []byte("b")))
+
+	key, err = store.Get([]byte("B"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("b"), key)
+
+	key, err = store.Get([]byte("A"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), key)
+
+	_, err = store.Get([]byte("C"))
+	require.EqualError(t, err, fake.Err("while reading db"))
+}
+
+func TestKeyStore_Prune(t *testing.T) {
+	db := fake.NewInMemoryDB()
+	db.SetBucket(keyBucket, fake.NewBucket())
+	store := newKeyStore(db, 1)
+
+	require.NoError(t, store.Store([]byte("A"), []byte("a")))
+	require.NoError(t, store.Store([]byte("B"), []byte("b")))
+	require.NoError(t, store.Store([]byte("CC"), []byte("cc")))
+
+	expired := func(label []byte) bool { return len(label) == 1 }
+
+	// A is only in the database and B in both the cache and the database.
+	count, reclaimed, err := store.Prune(expired)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, 4, reclaimed)
+
+	key, err := store.Get([]byte("A"))
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	key, err = store.Get([]byte("CC"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("cc"), key)
+
+	store = newKeyStore(nil, 2)
+	require.NoError(t, store.Store([]byte("A"), []byte("a")))
+
+	count, _, err = store.Prune(expired)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Empty(t, store.cache)
+
+	count, _, err = newKeyStore(fake.NewInMemoryDB(), 1).Prune(expired)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	db = fake.NewInMemoryDB()
+	db.SetBucket(keyBucket, fake.NewBadForeachBucket())
+
+	_, _, err = newKeyStore(db, 1).Prune(expired)
+	require.EqualError(t, err, fake.Err("while updating db: while iterating"))
+
+	bucket := fake.NewBadDeleteBucket()
+	bucket.Set([]byte("A"), []byte("a"))
+
+	db = fake.NewInMemoryDB()
+	db.SetBucket(keyBucket, bucket)
+
+	_, _, err = newKeyStore(db, 1).Prune(expired)
+	require.EqualError(t, err, fake.Err("while updating db: while deleting"))
+}
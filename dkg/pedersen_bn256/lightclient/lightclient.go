@@ -0,0 +1,121 @@
+// Package lightclient verifies the chain of an F3B ledger and the decryption
+// keys of its blocks without running a node.
+//
+// The client starts from a genesis block that it trusts through its digest,
+// and follows the forward links of the chains it receives. The roster changes
+// are applied along the way so that each link is checked against the roster
+// of its time. The decryption key of a block is accepted once the block is
+// part of the verified chain, and if it is signed by the committee.
+package lightclient
+
+import (
+	"sync"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+// suite is the suite of the keys released by the DKG.
+var suite = suites.MustFind("bn256.G2").(pairing.Suite)
+
+// Client is a light client that keeps track of the latest verified block.
+type Client struct {
+	sync.Mutex
+
+	genesis     types.Genesis
+	pubKey      kyber.Point
+	verifierFac crypto.VerifierFactory
+
+	// last is the digest of the latest verified block, or the one of the
+	// genesis block.
+	last   types.Digest
+	height uint64
+	synced bool
+}
+
+// NewClient returns a light client for the chain of the genesis block. The
+// genesis block is only accepted if its digest is the trusted one. The public
+// key is the collective key of the DKG that releases the keys.
+func NewClient(trusted types.Digest, genesis types.Genesis, pubKey kyber.Point,
+	fac crypto.VerifierFactory) (*Client, error) {
+
+	if genesis.GetHash() != trusted {
+		return nil, xerrors.Errorf("untrusted genesis: %v != %v",
+			genesis.GetHash(), trusted)
+	}
+
+	c := &Client{
+		genesis:     genesis,
+		pubKey:      pubKey,
+		verifierFac: fac,
+		last:        genesis.GetHash(),
+	}
+
+	return c, nil
+}
+
+// GetHeight returns the index of the latest verified block, or false if no
+// block has been verified yet.
+func (c *Client) GetHeight() (uint64, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.height, c.synced
+}
+
+// Update verifies the links of the chain that follow the latest verified
+// block, and moves to the block of the chain. The chain must contain every
+// link from the genesis block so that the roster changes are known, but only
+// the new links have their signatures verified.
+func (c *Client) Update(chain types.Chain) error {
+	c.Lock()
+	defer c.Unlock()
+
+	index := chain.GetBlock().GetIndex()
+
+	if c.synced && index <= c.height {
+		return xerrors.Errorf("block %d is not after %d", index, c.height)
+	}
+
+	links := chain.GetLinks()
+
+	// The first block follows the genesis block, so that the index of a block
+	// is also its position in the chain.
+	if uint64(len(links)) != index+1 {
+		return xerrors.Errorf("chain of %d links for block %d", len(links), index)
+	}
+
+	err := chain.Verify(c.genesis, c.last, c.verifierFac)
+	if err != nil {
+		return xerrors.Errorf("invalid chain: %v", err)
+	}
+
+	c.last = links[len(links)-1].GetTo()
+	c.height = index
+	c.synced = true
+
+	return nil
+}
+
+// VerifyKeyFor returns nil if the key is the decryption key of the block at
+// the index, and if the block has been verified, otherwise an error.
+func (c *Client) VerifyKeyFor(index uint64, key []byte) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.synced || index > c.height {
+		return xerrors.Errorf("block %d is not verified", index)
+	}
+
+	err := ibe.VerifyDecryptionKey(suite, c.pubKey, ibe.NewBlockLabel(index), key)
+	if err != nil {
+		return xerrors.Errorf("block %d: %v", index, err)
+	}
+
+	return nil
+}
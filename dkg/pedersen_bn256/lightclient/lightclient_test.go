@@ -0,0 +1,162 @@
+package lightclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/kyber/v3"
+	kyber_bls "go.dedis.ch/kyber/v3/sign/bls"
+)
+
+func TestClient_Scenario(t *testing.T) {
+	signer := bls.NewSigner()
+	genesis := makeGenesis(t, signer)
+	blocks := makeBlocks(t, signer, genesis, 5)
+
+	secret, pubKey := kyber_bls.NewKeyPair(suite, suite.RandomStream())
+
+	client, err := NewClient(genesis.GetHash(), genesis, pubKey,
+		signer.GetVerifierFactory())
+	require.NoError(t, err)
+
+	_, synced := client.GetHeight()
+	require.False(t, synced)
+
+	key := makeKey(t, secret, 2)
+
+	err = client.VerifyKeyFor(2, key)
+	require.EqualError(t, err, "block 2 is not verified")
+
+	require.NoError(t, client.Update(getChain(t, genesis, blocks, 1)))
+
+	err = client.VerifyKeyFor(2, key)
+	require.EqualError(t, err, "block 2 is not verified")
+
+	// Only the links after block 1 are verified.
+	require.NoError(t, client.Update(getChain(t, genesis, blocks, 4)))
+
+	height, synced := client.GetHeight()
+	require.True(t, synced)
+	require.Equal(t, uint64(4), height)
+
+	require.NoError(t, client.VerifyKeyFor(2, key))
+
+	err = client.VerifyKeyFor(3, key)
+	require.Error(t, err)
+	require.Regexp(t, "^block 3: invalid decryption key: ", err.Error())
+
+	err = client.Update(getChain(t, genesis, blocks, 3))
+	require.EqualError(t, err, "block 3 is not after 4")
+}
+
+func TestNewClient_ForeignGenesis(t *testing.T) {
+	signer := bls.NewSigner()
+	genesis := makeGenesis(t, signer)
+	foreign := makeGenesis(t, bls.NewSigner())
+
+	_, err := NewClient(genesis.GetHash(), foreign, nil, signer.GetVerifierFactory())
+	require.EqualError(t, err, "untrusted genesis: "+foreign.GetHash().String()+
+		" != "+genesis.GetHash().String())
+}
+
+func TestClient_Update_Invalid(t *testing.T) {
+	signer := bls.NewSigner()
+	genesis := makeGenesis(t, signer)
+	blocks := makeBlocks(t, signer, genesis, 2)
+
+	// The chain of another roster is refused.
+	other := bls.NewSigner()
+	otherGenesis := makeGenesis(t, other)
+	otherBlocks := makeBlocks(t, other, otherGenesis, 2)
+
+	client, err := NewClient(genesis.GetHash(), genesis, nil,
+		signer.GetVerifierFactory())
+	require.NoError(t, err)
+
+	err = client.Update(getChain(t, otherGenesis, otherBlocks, 1))
+	require.Error(t, err)
+	require.Regexp(t, "^invalid chain: ", err.Error())
+
+	chain := getChain(t, genesis, blocks, 1)
+	chain = types.NewChain(chain.GetLinks()[1].(types.BlockLink), nil)
+
+	err = client.Update(chain)
+	require.EqualError(t, err, "chain of 1 links for block 1")
+
+	_, synced := client.GetHeight()
+	require.False(t, synced)
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeGenesis(t *testing.T, signer crypto.Signer) types.Genesis {
+	ro := authority.New([]mino.Address{fake.NewAddress(0)},
+		[]crypto.PublicKey{signer.GetPublicKey()})
+
+	genesis, err := types.NewGenesis(ro)
+	require.NoError(t, err)
+
+	return genesis
+}
+
+// makeBlocks returns a store with the blocks signed by the signer.
+func makeBlocks(t *testing.T, signer crypto.Signer, genesis types.Genesis,
+	n int) blockstore.BlockStore {
+
+	blocks := blockstore.NewInMemory()
+	prev := genesis.GetHash()
+
+	for i := 0; i < n; i++ {
+		block, err := types.NewBlock(simple.NewResult(nil),
+			types.WithIndex(uint64(i)))
+		require.NoError(t, err)
+
+		unsigned, err := types.NewBlockLink(prev, block)
+		require.NoError(t, err)
+
+		prepare, err := signer.Sign(unsigned.GetHash().Bytes())
+		require.NoError(t, err)
+
+		msg, err := prepare.MarshalBinary()
+		require.NoError(t, err)
+
+		commit, err := signer.Sign(msg)
+		require.NoError(t, err)
+
+		link, err := types.NewBlockLink(prev, block,
+			types.WithSignatures(prepare, commit))
+		require.NoError(t, err)
+
+		require.NoError(t, blocks.Store(link))
+
+		prev = link.GetTo()
+	}
+
+	return blocks
+}
+
+func getChain(t *testing.T, genesis types.Genesis, blocks blockstore.BlockStore,
+	index uint64) types.Chain {
+
+	proof, err := blockstore.GetProof(genesis, blocks, index)
+	require.NoError(t, err)
+
+	return proof.Chain
+}
+
+func makeKey(t *testing.T, secret kyber.Scalar, index uint64) []byte {
+	key, err := kyber_bls.Sign(suite, secret, ibe.NewBlockLabel(index))
+	require.NoError(t, err)
+
+	return key
+}
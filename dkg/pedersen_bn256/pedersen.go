@@ -1,15 +1,21 @@
 package pedersen
 
 import (
+	"crypto/cipher"
 	"runtime"
 	"time"
 
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.dedis.ch/dela"
 
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/dkg"
 
 	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
 	"go.dedis.ch/dela/dkg/pedersen_bn256/types"
 	"go.dedis.ch/dela/internal/tracing"
 	"go.dedis.ch/dela/mino"
@@ -27,12 +33,19 @@ import (
 // initDkgFirst message helping the developer to verify whether setup did occur
 const initDkgFirst = "you must first initialize DKG. Did you call setup() first?"
 
+// errNotInitialized is the error of the information of a node without a share.
+var errNotInitialized = xerrors.New(initDkgFirst)
+
 // failedStreamCreation message indicating a stream creation failure
 const failedStreamCreation = "failed to create stream: %v"
 
 // unexpectedStreamStop message indicating that a stream stopped unexpectedly
 const unexpectedStreamStop = "stream stopped unexpectedly: %v"
 
+// Scheme is the name of the scheme and of its parameters, as announced to the
+// clients.
+const Scheme = "pedersen-dkg/bn256/ibe"
+
 // suite is the Kyber suite for Pedersen.
 var suite = suites.MustFind("bn256.G2")
 var pairingSuite = suite.(pairing.Suite)
@@ -49,45 +62,261 @@ var (
 	protocolNameResharing = "dkg-resharing"
 	// number of workers used to perform the encryption/decryption
 	workerNum = runtime.NumCPU()
+	// getTracerForAddr returns the tracer of an actor. It can be replaced in
+	// the tests.
+	getTracerForAddr = tracing.GetTracerForAddr
+)
+
+// defines prometheus metrics
+var (
+	promSetupDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dela_dkg_setup_seconds",
+		Help:    "duration of the DKG setup",
+		Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60, 120, 300},
+	})
+
+	promSignDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dela_dkg_sign_seconds",
+		Help:    "duration to recover a threshold signature, i.e. a key release",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	})
+
+	promShareFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dela_dkg_share_verification_failures",
+		Help: "total number of signature shares that failed the verification",
+	})
+
+	promReshareDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dela_dkg_reshare_seconds",
+		Help:    "duration of the DKG resharing",
+		Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60, 120, 300},
+	})
+
+	promPrunedKeys = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dela_dkg_pruned_keys",
+		Help: "total number of released keys deleted by the retention policy",
+	})
+
+	promReclaimedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dela_dkg_pruned_bytes",
+		Help: "total size in bytes of the released keys and labels deleted",
+	})
+
+	promReleaseLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dela_dkg_release_after_finalization_seconds",
+		Help:    "time between the finalization of a block and the release of its key",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+	})
 )
 
+func init() {
+	dela.PromCollectors = append(dela.PromCollectors, promSetupDuration,
+		promSignDuration, promShareFailures, promReshareDuration,
+		promPrunedKeys, promReclaimedBytes, promReleaseLatency)
+}
+
+// setupPhases is the number of phases of the DKG that can each take up to the
+// timeout, including the announcement of the result.
+const setupPhases = 4
+
 const (
 	setupTimeout     = time.Minute * 50
 	decryptTimeout   = time.Minute * 5
 	resharingTimeout = time.Minute * 5
 )
 
+// Roles tells which nodes are relays, i.e. nodes that take part in the
+// consensus but must not hold any key share, and the threshold of the chain.
+type Roles interface {
+	IsRelay(addr mino.Address) bool
+
+	// GetThreshold returns the number of key shares required to release a
+	// key, or zero if the chain does not set it.
+	GetThreshold() int
+}
+
 // Pedersen allows one to initialize a new DKG protocol.
 //
 // - implements dkg.DKG
 type Pedersen struct {
 	privKey kyber.Scalar
+	pubKey  kyber.Point
 	mino    mino.Mino
 	factory serde.Factory
+	keys    *keyStore
+	audit   *auditLog
+
+	transcripts *transcriptStore
+	timeout     time.Duration
+	labels      *ibe.Registry
+	retention   RetentionPolicy
+	roles       Roles
+	bus         events.Service
+	finality    *finality
+}
+
+type pedersenTemplate struct {
+	privKey   kyber.Scalar
+	db        kv.DB
+	cacheSize int
+	timeout   time.Duration
+	labels    *ibe.Registry
+	retention RetentionPolicy
+	roles     Roles
+	bus       events.Service
+}
+
+// Option is the type of option to set some fields of a DKG.
+type Option func(*pedersenTemplate)
+
+// WithPrivateKey is an option to use the long-term key of the node, so that
+// its identity in the DKG stays the same after a restart. By default, a random
+// key is created.
+func WithPrivateKey(key kyber.Scalar) Option {
+	return func(tmpl *pedersenTemplate) {
+		tmpl.privKey = key
+	}
+}
+
+// WithKeyStore is an option to persist the released keys and the outcome of
+// the DKG in the database, so that they are still available after a restart.
+func WithKeyStore(db kv.DB) Option {
+	return func(tmpl *pedersenTemplate) {
+		tmpl.db = db
+	}
+}
+
+// WithKeyCacheSize is an option to set the number of released keys kept in
+// memory.
+func WithKeyCacheSize(size int) Option {
+	return func(tmpl *pedersenTemplate) {
+		tmpl.cacheSize = size
+	}
+}
+
+// WithTimeout is an option to bound each phase of the setup. When a phase
+// expires, the missing participants are excluded and the setup completes as
+// long as a threshold of them is qualified. By default, the setup waits for
+// every participant.
+func WithTimeout(timeout time.Duration) Option {
+	return func(tmpl *pedersenTemplate) {
+		tmpl.timeout = timeout
+	}
+}
+
+// WithLabelRegistry is an option to only sign the labels whose namespace is
+// allowed by the registry. By default, any label is signed.
+func WithLabelRegistry(registry ibe.Registry) Option {
+	return func(tmpl *pedersenTemplate) {
+		tmpl.labels = &registry
+	}
+}
+
+// WithRetention is an option to set the policy that decides which released
+// keys are deleted by Prune. By default, the keys are never deleted.
+func WithRetention(policy RetentionPolicy) Option {
+	return func(tmpl *pedersenTemplate) {
+		tmpl.retention = policy
+	}
+}
+
+// WithRoles is an option to refuse the relays as members of the DKG and to
+// ignore their shares during the extraction. The setup uses the threshold of
+// the chain. By default, there are no relays.
+func WithRoles(roles Roles) Option {
+	return func(tmpl *pedersenTemplate) {
+		tmpl.roles = roles
+	}
+}
+
+// WithEvents is an option to publish the keys released by the actor on the
+// event bus. The finalized blocks of the bus are used to measure how long the
+// key of a block label takes to be released.
+func WithEvents(bus events.Service) Option {
+	return func(tmpl *pedersenTemplate) {
+		tmpl.bus = bus
+	}
 }
 
 // NewPedersen returns a new DKG Pedersen factory
-func NewPedersen(m mino.Mino) (*Pedersen, kyber.Point) {
+func NewPedersen(m mino.Mino, opts ...Option) (*Pedersen, kyber.Point) {
+	tmpl := pedersenTemplate{
+		cacheSize: defaultKeyCacheSize,
+	}
+
+	for _, opt := range opts {
+		opt(&tmpl)
+	}
+
 	factory := types.NewMessageFactory(m.GetAddressFactory())
 
-	privkey, pubkey := kyber_bls.NewKeyPair(pairingSuite, suite.RandomStream())
+	var pubkey kyber.Point
+
+	privkey := tmpl.privKey
+	if privkey == nil {
+		privkey, pubkey = kyber_bls.NewKeyPair(pairingSuite, suite.RandomStream())
+	} else {
+		pubkey = suite.Point().Mul(privkey, nil)
+	}
+
+	var blocks *finality
+	if tmpl.bus != nil {
+		blocks = newFinality()
+		go blocks.Listen(context.Background(), tmpl.bus)
+	}
 
 	return &Pedersen{
 		privKey: privkey,
+		pubKey:  pubkey,
 		mino:    m,
 		factory: factory,
+		keys:    newKeyStore(tmpl.db, tmpl.cacheSize),
+		audit:   newAuditLog(tmpl.db),
+
+		transcripts: newTranscriptStore(tmpl.db, privkey, factory),
+		timeout:     tmpl.timeout,
+		labels:      tmpl.labels,
+		retention:   tmpl.retention,
+		roles:       tmpl.roles,
+		bus:         tmpl.bus,
+		finality:    blocks,
 	}, pubkey
 }
 
 // Listen implements dkg.DKG. It must be called on each node that participates
 // in the DKG. Creates the RPC.
 func (s *Pedersen) Listen() (dkg.Actor, error) {
-	h := NewHandler(s.privKey, s.mino.GetAddress())
+	// The height of the chain is known from the blocks finalized on the bus.
+	var chain chainHeight
+	if s.finality != nil {
+		chain = s.finality
+	}
+
+	h := NewHandler(s.privKey, s.mino.GetAddress(), s.transcripts, s.timeout,
+		s.labels, chain)
+
+	tracer, err := getTracerForAddr(s.mino.GetAddress().String())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get tracer: %v", err)
+	}
 
 	a := &Actor{
 		rpc:      mino.MustCreateRPC(s.mino, "dkg", h, s.factory),
 		factory:  s.factory,
 		startRes: h.dkgInstance.getState(),
+		instance: h.dkgInstance,
+		tracer:   tracer,
+		keys:     s.keys,
+		audit:    s.audit,
+		addr:     s.mino.GetAddress(),
+		privKey:  s.privKey,
+		pubKey:   s.pubKey,
+		timeout:  s.timeout,
+
+		retention: s.retention,
+		roles:     s.roles,
+		bus:       s.bus,
+		finality:  s.finality,
 	}
 
 	return a, nil
@@ -103,19 +332,60 @@ type Actor struct {
 	rpc      mino.RPC
 	factory  serde.Factory
 	startRes *state
+	instance dkgInstance
+	tracer   opentracing.Tracer
+	keys     *keyStore
+	audit    *auditLog
+	addr     mino.Address
+	privKey  kyber.Scalar
+	pubKey   kyber.Point
+	timeout  time.Duration
+
+	// retention decides which released keys are deleted by Prune.
+	retention RetentionPolicy
+
+	// roles tells which nodes must not hold any key share, or nil if there
+	// are no relays.
+	roles Roles
+
+	// bus is where the released keys are published, or nil.
+	bus events.Service
+
+	// finality tells when the blocks have been finalized, or nil.
+	finality *finality
+
+	// random is the source of the ephemeral keys under which the signature
+	// shares are encrypted, or nil to use a random one.
+	random cipher.Stream
 }
 
-// Setup implement dkg.Actor. It initializes the DKG.
+// Setup implement dkg.Actor. It initializes the DKG. A zero threshold is
+// replaced by the one of the chain, if any.
 func (a *Actor) Setup(co crypto.CollectiveAuthority, threshold int) (kyber.Point, error) {
 
 	if a.startRes.Done() {
 		return nil, xerrors.Errorf("startRes is already done, only one setup call is allowed")
 	}
 
+	err := a.checkRelays(co)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold, err = a.checkThreshold(threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
 	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
 	defer cancel()
 	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameSetup)
 
+	span, ctx := a.startSpan(ctx, protocolNameSetup)
+	defer span.Finish()
+
 	sender, receiver, err := a.rpc.Stream(ctx, co)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to stream: %v", err)
@@ -142,17 +412,27 @@ func (a *Actor) Setup(co crypto.CollectiveAuthority, threshold int) (kyber.Point
 	message := types.NewStart(threshold, addrs, pubkeys)
 
 	errs := sender.Send(message, addrs...)
-	err = <-errs
+
+	expected, err := a.reachable(errs, len(addrs), threshold)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to send start: %v", err)
 	}
 
-	dkgPubKeys := make([]kyber.Point, len(addrs))
+	recvCtx, cancelRecv := a.withSetupDeadline()
+	defer cancelRecv()
+
+	dkgPubKeys := make([]kyber.Point, 0, expected)
 
-	for i := 0; i < len(addrs); i++ {
+	for len(dkgPubKeys) < expected {
 
-		addr, msg, err := receiver.Recv(context.Background())
+		addr, msg, err := receiver.Recv(recvCtx)
 		if err != nil {
+			if a.timeout > 0 && recvCtx.Err() != nil && len(dkgPubKeys) >= threshold {
+				dela.Logger.Warn().Int("missing", expected-len(dkgPubKeys)).
+					Msg("setup done without every participant")
+				break
+			}
+
 			return nil, xerrors.Errorf("got an error from '%s' while "+
 				"receiving: %v", addr, err)
 		}
@@ -165,19 +445,74 @@ func (a *Actor) Setup(co crypto.CollectiveAuthority, threshold int) (kyber.Point
 
 		dela.Logger.Info().Msgf("node %q done", addr.String())
 
-		dkgPubKeys[i] = doneMsg.GetPublicKey()
+		dkgPubKeys = append(dkgPubKeys, doneMsg.GetPublicKey())
 
 		// this is a simple check that every node sends back the same DKG pub
 		// key.
 		// TODO: handle the situation where a pub key is not the same
-		if i != 0 && !dkgPubKeys[i-1].Equal(doneMsg.GetPublicKey()) {
+		if len(dkgPubKeys) > 1 && !dkgPubKeys[0].Equal(doneMsg.GetPublicKey()) {
 			return nil, xerrors.Errorf("the public keys does not match: %v", dkgPubKeys)
 		}
 	}
 
+	promSetupDuration.Observe(time.Since(start).Seconds())
+
 	return dkgPubKeys[0], nil
 }
 
+// reachable returns the number of participants that received the start
+// message. Without a timeout, every participant must be reachable, otherwise a
+// threshold of them is enough.
+func (a *Actor) reachable(errs <-chan error, n, threshold int) (int, error) {
+	if a.timeout <= 0 {
+		err := <-errs
+		if err != nil {
+			return 0, err
+		}
+
+		return n, nil
+	}
+
+	unreachable := 0
+
+	for err := range errs {
+		dela.Logger.Warn().Err(err).Msg("participant unreachable")
+		unreachable++
+	}
+
+	if n-unreachable < threshold {
+		return 0, xerrors.Errorf("only %d participants reachable for a "+
+			"threshold of %d", n-unreachable, threshold)
+	}
+
+	return n - unreachable, nil
+}
+
+// withSetupDeadline returns the context used to wait for the participants. With
+// a timeout, it expires after every phase of the DKG had the time to complete.
+func (a *Actor) withSetupDeadline() (context.Context, context.CancelFunc) {
+	if a.timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), setupPhases*a.timeout)
+}
+
+// Resume implements dkg.Actor. It restores the outcome of the last DKG that was
+// persisted by the node.
+func (a *Actor) Resume() (kyber.Point, error) {
+	if a.startRes.Done() {
+		return nil, xerrors.Errorf("startRes is already done, nothing to resume")
+	}
+
+	pubkey, err := a.instance.resume()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to resume: %v", err)
+	}
+
+	return pubkey, nil
+}
+
 // GetPublicKey implements dkg.Actor
 func (a *Actor) GetPublicKey() (kyber.Point, error) {
 	if !a.startRes.Done() {
@@ -195,39 +530,57 @@ func (a *Actor) Sign(msg []byte) ([]byte, error) {
 		return nil, xerrors.Errorf(initDkgFirst)
 	}
 
-	players := mino.NewAddresses(a.startRes.getParticipants()...)
+	released, err := a.keys.Get(msg)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read key store: %v", err)
+	}
+
+	if released != nil {
+		return released, nil
+	}
+
+	start := time.Now()
+
+	addrs := a.holders()
+	players := mino.NewAddresses(addrs...)
 
 	ctx, cancel := context.WithTimeout(context.Background(), decryptTimeout)
 	defer cancel()
 	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameDecrypt)
 
+	span, ctx := a.startSpan(ctx, protocolNameDecrypt)
+	defer span.Finish()
+
 	sender, receiver, err := a.rpc.Stream(ctx, players)
 	if err != nil {
 		return nil, xerrors.Errorf(failedStreamCreation, err)
 	}
 
-	players = mino.NewAddresses(a.startRes.getParticipants()...)
-	iterator := players.AddressIterator()
-
-	addrs := make([]mino.Address, 0, players.Len())
-	for iterator.HasNext() {
-		addrs = append(addrs, iterator.GetNext())
+	// The shares are encrypted under an ephemeral key so that only this actor
+	// can read them, even when the replies are relayed by other nodes.
+	shareKey, message, err := a.makeSignRequest(msg)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create request: %v", err)
 	}
 
-	message := types.NewSignRequest(msg)
+	var n = len(a.startRes.getParticipants())
+	var t = a.startRes.getThreshold()
 
-	err = <-sender.Send(message, addrs...)
+	reachable, err := a.reachable(sender.Send(message, addrs...), len(addrs), t)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to send decrypt request: %v", err)
 	}
 
 	pubPoly := share.NewPubPoly(suite, nil, a.startRes.Commits)
 
-	var n = len(addrs)
-	var t = a.startRes.getThreshold()
-	sigShares := make([][]byte, t)
+	sigShares := make([][]byte, 0, t)
+	contributors := make([]string, 0, t)
 
-	for i := 0; i < t; i++ {
+	extraction, _ := a.startSpan(ctx, "extraction")
+
+	// A share that fails the verification is ignored so that one faulty node
+	// cannot prevent the recovery as long as t nodes reply with valid shares.
+	for i := 0; i < reachable && len(sigShares) < t; i++ {
 		src, message, err := receiver.Recv(ctx)
 		if err != nil {
 			return []byte{}, xerrors.Errorf(unexpectedStreamStop, err)
@@ -241,17 +594,560 @@ func (a *Actor) Sign(msg []byte) ([]byte, error) {
 				"%T but got: %T", signReply, message)
 		}
 
-		sigShares[i] = signReply.Share
+		if a.isRelay(src) {
+			dela.Logger.Warn().Msgf("ignored the share of the relay %v", src)
+			continue
+		}
+
+		// A message refused by the participant has an empty share.
+		if len(signReply.Share) == 0 {
+			dela.Logger.Debug().Msgf("%v refused to sign", src)
+			continue
+		}
+
+		sigShare, ok := readShare(shareKey, pubPoly, msg, signReply.Share, src)
+		if ok {
+			sigShares = append(sigShares, sigShare)
+			contributors = append(contributors, src.String())
+		}
+	}
+
+	extraction.Finish()
+
+	if len(sigShares) < t {
+		return []byte{}, xerrors.Errorf("not enough valid shares: %d < %d",
+			len(sigShares), t)
 	}
 
+	recovery, _ := a.startSpan(ctx, "recovery")
 	signature, err := tbls.Recover(suite.(pairing.Suite), pubPoly, msg, sigShares, t, n)
+	recovery.Finish()
 	if err != nil {
 		return []byte{}, xerrors.Errorf("failed to recover signature: %v", err)
 	}
 
+	// The key is checked once against the collective public key, like a client
+	// would do, before it is stored and served.
+	err = ibe.VerifyDecryptionKey(pairingSuite, pubPoly.Commit(), msg, signature)
+	if err != nil {
+		return []byte{}, xerrors.Errorf("recovered key is invalid: %v", err)
+	}
+
+	promSignDuration.Observe(time.Since(start).Seconds())
+
+	err = a.keys.Store(msg, signature)
+	if err != nil {
+		dela.Logger.Warn().Err(err).Msg("failed to store released key")
+	}
+
+	a.record(msg, signature, contributors)
+
 	return signature, nil
 }
 
+// GetKeys implements dkg.Actor. It returns the keys of the labels, in the same
+// order. The keys already released are read from the store, and the others are
+// requested in batches so that a node catching up on many labels does not need
+// a round trip per label.
+func (a *Actor) GetKeys(labels [][]byte) ([][]byte, error) {
+	if !a.startRes.Done() {
+		return nil, xerrors.Errorf(initDkgFirst)
+	}
+
+	keys := make([][]byte, len(labels))
+	missing := make([]int, 0, len(labels))
+
+	for i, label := range labels {
+		released, err := a.keys.Get(label)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read key store: %v", err)
+		}
+
+		if released == nil {
+			missing = append(missing, i)
+		}
+
+		keys[i] = released
+	}
+
+	for start := 0; start < len(missing); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		batch := make([][]byte, end-start)
+		for i, index := range missing[start:end] {
+			batch[i] = labels[index]
+		}
+
+		released, err := a.signBatch(batch)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to sign batch: %v", err)
+		}
+
+		for i, index := range missing[start:end] {
+			keys[index] = released[i]
+		}
+	}
+
+	return keys, nil
+}
+
+// ReleaseBlock implements dkg.Actor. It recovers the keys of the labels of the
+// transactions of the block at the height in a single batch, and aggregates
+// them so that a client verifies the release of the block at once.
+func (a *Actor) ReleaseBlock(height uint64, labels [][]byte) (dkg.BlockRelease, error) {
+	if !a.startRes.Done() {
+		return dkg.BlockRelease{}, xerrors.Errorf(initDkgFirst)
+	}
+
+	err := checkBlockLabels(height, labels)
+	if err != nil {
+		return dkg.BlockRelease{}, xerrors.Errorf("invalid labels: %v", err)
+	}
+
+	keys, err := a.GetKeys(labels)
+	if err != nil {
+		return dkg.BlockRelease{}, xerrors.Errorf("failed to get keys: %v", err)
+	}
+
+	aggregate, err := kyber_bls.AggregateSignatures(pairingSuite, keys...)
+	if err != nil {
+		return dkg.BlockRelease{}, xerrors.Errorf("failed to aggregate: %v", err)
+	}
+
+	release := dkg.BlockRelease{
+		Height:    height,
+		Labels:    labels,
+		Keys:      keys,
+		Aggregate: aggregate,
+	}
+
+	return release, nil
+}
+
+// VerifyBlockRelease returns nil if the keys of the release are the keys of its
+// labels under the collective public key, and if the aggregate proves the
+// release of all of them, otherwise an error. The aggregate alone does not tell
+// which key belongs to which label, hence the keys are verified one by one.
+func VerifyBlockRelease(pubKey kyber.Point, release dkg.BlockRelease) error {
+	err := checkBlockLabels(release.Height, release.Labels)
+	if err != nil {
+		return xerrors.Errorf("invalid labels: %v", err)
+	}
+
+	if len(release.Keys) != len(release.Labels) {
+		return xerrors.Errorf("expected %d keys, got %d",
+			len(release.Labels), len(release.Keys))
+	}
+
+	for i, label := range release.Labels {
+		err = ibe.VerifyDecryptionKey(pairingSuite, pubKey, label, release.Keys[i])
+		if err != nil {
+			return xerrors.Errorf("invalid key of label %q: %v", label, err)
+		}
+	}
+
+	publics := make([]kyber.Point, len(release.Labels))
+	for i := range publics {
+		publics[i] = pubKey
+	}
+
+	err = kyber_bls.BatchVerify(pairingSuite, publics, release.Labels, release.Aggregate)
+	if err != nil {
+		return xerrors.Errorf("invalid aggregate: %v", err)
+	}
+
+	return nil
+}
+
+// checkBlockLabels returns nil if the labels are distinct transaction labels
+// of the block at the height and fit in a single batch, otherwise an error.
+func checkBlockLabels(height uint64, labels [][]byte) error {
+	if len(labels) == 0 {
+		return xerrors.New("no label")
+	}
+
+	if len(labels) > maxBatchSize {
+		return xerrors.Errorf("too many labels: %d > %d", len(labels), maxBatchSize)
+	}
+
+	seen := make(map[string]struct{}, len(labels))
+
+	for _, label := range labels {
+		block, _, err := ibe.ParseTxLabel(label)
+		if err != nil {
+			return xerrors.Errorf("not a transaction label: %v", err)
+		}
+
+		if block != height {
+			return xerrors.Errorf("label %q is for block %d", label, block)
+		}
+
+		_, found := seen[string(label)]
+		if found {
+			return xerrors.Errorf("duplicate label %q", label)
+		}
+
+		seen[string(label)] = struct{}{}
+	}
+
+	return nil
+}
+
+// signBatch requests the shares of the labels in a single request to each
+// participant, and recovers the key of every label.
+func (a *Actor) signBatch(labels [][]byte) ([][]byte, error) {
+	start := time.Now()
+
+	addrs := a.holders()
+	players := mino.NewAddresses(addrs...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), decryptTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameDecrypt)
+
+	span, ctx := a.startSpan(ctx, protocolNameDecrypt)
+	defer span.Finish()
+
+	sender, receiver, err := a.rpc.Stream(ctx, players)
+	if err != nil {
+		return nil, xerrors.Errorf(failedStreamCreation, err)
+	}
+
+	shareKey, pubKey, err := a.makeShareKey()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create request: %v", err)
+	}
+
+	message := types.NewSignBatchRequest(labels, pubKey)
+
+	var n = len(a.startRes.getParticipants())
+	var t = a.startRes.getThreshold()
+
+	reachable, err := a.reachable(sender.Send(message, addrs...), len(addrs), t)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to send decrypt request: %v", err)
+	}
+
+	pubPoly := share.NewPubPoly(suite, nil, a.startRes.Commits)
+
+	sigShares := make([][][]byte, len(labels))
+	contributors := make([][]string, len(labels))
+	complete := 0
+
+	extraction, _ := a.startSpan(ctx, "extraction")
+
+	for i := 0; i < reachable && complete < len(labels); i++ {
+		src, message, err := receiver.Recv(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf(unexpectedStreamStop, err)
+		}
+
+		reply, ok := message.(types.SignBatchReply)
+		if !ok {
+			return nil, xerrors.Errorf("got unexpected reply, expected "+
+				"%T but got: %T", reply, message)
+		}
+
+		if a.isRelay(src) {
+			dela.Logger.Warn().Msgf("ignored the shares of the relay %v", src)
+			continue
+		}
+
+		if len(reply.Shares) != len(labels) {
+			dela.Logger.Warn().Msgf("expected %d shares from %v, got %d",
+				len(labels), src, len(reply.Shares))
+			continue
+		}
+
+		for j, label := range labels {
+			// A label refused by the participant has an empty share.
+			if len(sigShares[j]) >= t || len(reply.Shares[j]) == 0 {
+				continue
+			}
+
+			sigShare, ok := readShare(shareKey, pubPoly, label, reply.Shares[j], src)
+			if !ok {
+				continue
+			}
+
+			sigShares[j] = append(sigShares[j], sigShare)
+			contributors[j] = append(contributors[j], src.String())
+			if len(sigShares[j]) == t {
+				complete++
+			}
+		}
+	}
+
+	extraction.Finish()
+
+	keys := make([][]byte, len(labels))
+
+	recovery, _ := a.startSpan(ctx, "recovery")
+	defer recovery.Finish()
+
+	for i, label := range labels {
+		if len(sigShares[i]) < t {
+			return nil, xerrors.Errorf("not enough valid shares for label %#x: "+
+				"%d < %d", label, len(sigShares[i]), t)
+		}
+
+		signature, err := tbls.Recover(pairingSuite, pubPoly, label, sigShares[i], t, n)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to recover signature: %v", err)
+		}
+
+		err = ibe.VerifyDecryptionKey(pairingSuite, pubPoly.Commit(), label, signature)
+		if err != nil {
+			return nil, xerrors.Errorf("recovered key is invalid: %v", err)
+		}
+
+		err = a.keys.Store(label, signature)
+		if err != nil {
+			dela.Logger.Warn().Err(err).Msg("failed to store released key")
+		}
+
+		a.record(label, signature, contributors[i])
+
+		keys[i] = signature
+	}
+
+	promSignDuration.Observe(time.Since(start).Seconds())
+
+	return keys, nil
+}
+
+// record appends the release of the key of the label to the audit log and
+// publishes it on the bus. A failure is only logged as the key is already
+// recovered.
+func (a *Actor) record(label, key []byte, contributors []string) {
+	// The index is zero if the label is not bound to a block.
+	index, err := labelHeight(label)
+
+	if err == nil && a.finality != nil {
+		elapsed, found := a.finality.Since(index)
+		if found {
+			promReleaseLatency.Observe(elapsed.Seconds())
+		}
+	}
+
+	if a.bus != nil {
+		a.bus.Publish(events.KeyReleased{Index: index, Label: label, Key: key})
+	}
+
+	release := dkg.Release{
+		Label:        label,
+		Requester:    a.addr.String(),
+		Contributors: contributors,
+		Time:         time.Now(),
+	}
+
+	err = a.audit.Append(release)
+	if err != nil {
+		dela.Logger.Warn().Err(err).Msg("failed to append to the audit log")
+	}
+}
+
+// isRelay returns true if the address must not hold any key share.
+func (a *Actor) isRelay(addr mino.Address) bool {
+	return a.roles != nil && a.roles.IsRelay(addr)
+}
+
+// checkRelays returns an error if a member of the authority is a relay.
+func (a *Actor) checkRelays(co crypto.CollectiveAuthority) error {
+	iter := co.AddressIterator()
+	for iter.HasNext() {
+		addr := iter.GetNext()
+
+		if a.isRelay(addr) {
+			return xerrors.Errorf("%v is a relay and cannot hold a key share", addr)
+		}
+	}
+
+	return nil
+}
+
+// checkThreshold returns the threshold of the setup. It is the one of the
+// chain when the threshold is zero, otherwise it must be the same.
+func (a *Actor) checkThreshold(threshold int) (int, error) {
+	if a.roles == nil || a.roles.GetThreshold() == 0 {
+		return threshold, nil
+	}
+
+	if threshold == 0 {
+		return a.roles.GetThreshold(), nil
+	}
+
+	if threshold != a.roles.GetThreshold() {
+		return 0, xerrors.Errorf("threshold %d differs from the threshold %d "+
+			"of the chain", threshold, a.roles.GetThreshold())
+	}
+
+	return threshold, nil
+}
+
+// holders returns the participants that are not relays, which are the ones
+// asked for their shares.
+func (a *Actor) holders() []mino.Address {
+	participants := a.startRes.getParticipants()
+
+	addrs := make([]mino.Address, 0, len(participants))
+	for _, addr := range participants {
+		if !a.isRelay(addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
+
+// makeSignRequest returns a new ephemeral key and the request of the shares of
+// the message to encrypt under it.
+func (a *Actor) makeSignRequest(msg []byte) (kyber.Scalar, types.SignRequest, error) {
+	secret, pubKey, err := a.makeShareKey()
+	if err != nil {
+		return nil, types.SignRequest{}, err
+	}
+
+	return secret, types.NewEncryptedSignRequest(msg, pubKey), nil
+}
+
+// makeShareKey returns a new ephemeral key and its marshaled public key, under
+// which the participants encrypt the shares.
+func (a *Actor) makeShareKey() (kyber.Scalar, []byte, error) {
+	random := a.random
+	if random == nil {
+		random = suite.RandomStream()
+	}
+
+	secret := suite.Scalar().Pick(random)
+
+	pubKey, err := suite.Point().Mul(secret, nil).MarshalBinary()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to marshal key: %v", err)
+	}
+
+	return secret, pubKey, nil
+}
+
+// readShare decrypts the signature share of the message and verifies it
+// against the public polynomial. A share that fails is logged and ignored so
+// that one faulty node cannot prevent the recovery.
+func readShare(secret kyber.Scalar, pubPoly *share.PubPoly, msg, ciphertext []byte,
+	src mino.Address) ([]byte, bool) {
+
+	sigShare, err := decryptShare(secret, ciphertext)
+	if err != nil {
+		promShareFailures.Inc()
+
+		dela.Logger.Warn().Err(err).Msgf("failed to decrypt share from %v", src)
+		return nil, false
+	}
+
+	err = tbls.Verify(pairingSuite, pubPoly, msg, sigShare)
+	if err != nil {
+		promShareFailures.Inc()
+
+		dela.Logger.Warn().Err(err).Msgf("invalid signature share from %v", src)
+		return nil, false
+	}
+
+	return sigShare, true
+}
+
+// GetReleases implements dkg.Actor. It queries the audit log of the keys
+// recovered by this node.
+func (a *Actor) GetReleases(filter dkg.ReleaseFilter) ([]dkg.Release, error) {
+	releases, err := a.audit.Query(filter)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to query audit log: %v", err)
+	}
+
+	return releases, nil
+}
+
+// GetReleasedKey implements dkg.Actor. It returns the key previously released
+// for the label without running the recovery again.
+func (a *Actor) GetReleasedKey(label []byte) ([]byte, error) {
+	key, err := a.keys.Get(label)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read key store: %v", err)
+	}
+
+	if key == nil {
+		return nil, xerrors.Errorf("no key released for label %#x", label)
+	}
+
+	return key, nil
+}
+
+// Prune implements dkg.Actor. It deletes the released keys that expired
+// according to the retention policy, when the chain is at the height.
+func (a *Actor) Prune(height uint64) (int, error) {
+	now := time.Now()
+
+	expired := func(label []byte) bool {
+		return a.retention.Expired(label, height, now)
+	}
+
+	count, reclaimed, err := a.keys.Prune(expired)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to prune key store: %v", err)
+	}
+
+	promPrunedKeys.Add(float64(count))
+	promReclaimedBytes.Add(float64(reclaimed))
+
+	return count, nil
+}
+
+// GetInfo implements dkg.Actor. It returns the committee, the threshold and the
+// collective public key, signed with the long-term key of the node.
+func (a *Actor) GetInfo() (dkg.Info, error) {
+	if !a.startRes.Done() {
+		return dkg.Info{}, errNotInitialized
+	}
+
+	info := dkg.Info{
+		Participants: a.startRes.getParticipants(),
+		PublicKeys:   a.startRes.getPublicKeys(),
+		Threshold:    a.startRes.getThreshold(),
+		PublicKey:    a.startRes.getDistKey(),
+		Scheme:       Scheme,
+		Node:         a.pubKey,
+	}
+
+	digest, err := info.Digest()
+	if err != nil {
+		return dkg.Info{}, xerrors.Errorf("failed to compute digest: %v", err)
+	}
+
+	info.Signature, err = kyber_bls.Sign(pairingSuite, a.privKey, digest)
+	if err != nil {
+		return dkg.Info{}, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	return info, nil
+}
+
+// VerifyInfo checks that the information is signed by the node it announces.
+// The caller must check that the key of the node is one it trusts.
+func VerifyInfo(info dkg.Info) error {
+	digest, err := info.Digest()
+	if err != nil {
+		return xerrors.Errorf("failed to compute digest: %v", err)
+	}
+
+	err = kyber_bls.Verify(pairingSuite, info.Node, digest, info.Signature)
+	if err != nil {
+		return xerrors.Errorf("invalid signature: %v", err)
+	}
+
+	return nil
+}
+
 func (a *Actor) Verify(msg, signature []byte) error {
 
 	if !a.startRes.Done() {
@@ -273,6 +1169,13 @@ func (a *Actor) Reshare(co crypto.CollectiveAuthority, thresholdNew int) error {
 		return xerrors.Errorf(initDkgFirst)
 	}
 
+	err := a.checkRelays(co)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+
 	addrsNew := make([]mino.Address, 0, co.Len())
 	pubkeysNew := make([]kyber.Point, 0, co.Len())
 
@@ -301,6 +1204,9 @@ func (a *Actor) Reshare(co crypto.CollectiveAuthority, thresholdNew int) error {
 
 	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameResharing)
 
+	span, ctx := a.startSpan(ctx, protocolNameResharing)
+	defer span.Finish()
+
 	dela.Logger.Info().Msgf("resharing with the following participants: %v", addrsAll)
 
 	sender, receiver, err := a.rpc.Stream(ctx, players)
@@ -368,11 +1274,25 @@ func (a *Actor) Reshare(co crypto.CollectiveAuthority, thresholdNew int) error {
 		}
 	}
 
+	promReshareDuration.Observe(time.Since(start).Seconds())
+
 	dela.Logger.Info().Msgf("resharing done")
 
 	return nil
 }
 
+// startSpan starts a new span from the context, as a child of the span of the
+// context if any. The context of the span is returned so that the RPC can
+// propagate it.
+func (a *Actor) startSpan(ctx context.Context, operation string) (opentracing.Span, context.Context) {
+	tracer := a.tracer
+	if tracer == nil {
+		tracer = opentracing.NoopTracer{}
+	}
+
+	return opentracing.StartSpanFromContextWithTracer(ctx, tracer, operation)
+}
+
 // difference performs "el1 difference el2", i.e. it extracts all members of el1
 // that are not present in el2.
 func difference(el1 []mino.Address, el2 []mino.Address) []mino.Address {
@@ -1,22 +1,33 @@
 package pedersen
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/events"
+	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
 	"go.dedis.ch/dela/dkg/pedersen_bn256/types"
 	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/internal/tracing"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/minoch"
 	"go.dedis.ch/dela/mino/minogrpc"
 	"go.dedis.ch/dela/mino/router/tree"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing/bn256"
 	"go.dedis.ch/kyber/v3/share"
+	kyber_bls "go.dedis.ch/kyber/v3/sign/bls"
 	"go.dedis.ch/kyber/v3/sign/tbls"
 )
 
@@ -29,117 +40,1045 @@ func TestPedersen_Listen(t *testing.T) {
 	require.NotNil(t, actor)
 }
 
+func TestNewPedersen_PrivateKey(t *testing.T) {
+	privkey := suite.Scalar().Pick(suite.RandomStream())
+
+	d, pubkey := NewPedersen(fake.Mino{}, WithPrivateKey(privkey))
+	require.True(t, d.privKey.Equal(privkey))
+	require.True(t, pubkey.Equal(suite.Point().Mul(privkey, nil)))
+
+	// The same key gives the same identity after a restart.
+	_, other := NewPedersen(fake.Mino{}, WithPrivateKey(privkey))
+	require.True(t, pubkey.Equal(other))
+
+	_, other = NewPedersen(fake.Mino{})
+	require.False(t, pubkey.Equal(other))
+}
+
+func TestPedersen_ListenBadTracer(t *testing.T) {
+	getTracerForAddr = fake.GetTracerForAddrWithError
+	defer func() {
+		getTracerForAddr = tracing.GetTracerForAddr
+	}()
+
+	pedersen, _ := NewPedersen(fake.Mino{})
+
+	_, err := pedersen.Listen()
+	require.EqualError(t, err, fake.Err("failed to get tracer"))
+}
+
 func TestPedersen_Setup(t *testing.T) {
 	actor := Actor{
 		rpc:      fake.NewBadRPC(),
 		startRes: &state{},
 	}
 
-	fakeAuthority := fake.NewAuthority(1, fake.NewSigner)
+	fakeAuthority := fake.NewAuthority(1, fake.NewSigner)
+
+	_, err := actor.Setup(fakeAuthority, 0)
+	require.EqualError(t, err, fake.Err("failed to stream"))
+
+	rpc := fake.NewStreamRPC(fake.NewReceiver(), fake.NewBadSender())
+	actor.rpc = rpc
+
+	_, err = actor.Setup(fakeAuthority, 0)
+	require.EqualError(t, err, "expected bls.PublicKey, got 'fake.PublicKey'")
+
+	fakeAuthority = fake.NewAuthority(2, bls.Generate)
+
+	_, err = actor.Setup(fakeAuthority, 1)
+	require.EqualError(t, err, fake.Err("failed to send start"))
+
+	rpc = fake.NewStreamRPC(fake.NewBadReceiver(), fake.Sender{})
+	actor.rpc = rpc
+
+	_, err = actor.Setup(fakeAuthority, 1)
+	require.EqualError(t, err, fake.Err("got an error from '%!s(<nil>)' while receiving"))
+
+	recv := fake.NewReceiver(fake.NewRecvMsg(fake.NewAddress(0), nil))
+
+	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+
+	_, err = actor.Setup(fakeAuthority, 1)
+	require.EqualError(t, err, "expected to receive a Done message, but go the following: <nil>")
+
+	rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewStartDone(suite.Point())),
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewStartDone(suite.Point().Pick(suite.RandomStream()))),
+	), fake.Sender{})
+	actor.rpc = rpc
+
+	_, err = actor.Setup(fakeAuthority, 1)
+	require.Error(t, err)
+	require.Regexp(t, "^the public keys does not match:", err)
+}
+
+func TestPedersen_GetPublicKey(t *testing.T) {
+	actor := Actor{
+		startRes: &state{},
+	}
+
+	_, err := actor.GetPublicKey()
+	require.EqualError(t, err, "DKG has not been initialized")
+
+	actor.startRes = &state{dkgState: certified}
+	_, err = actor.GetPublicKey()
+	require.NoError(t, err)
+}
+
+func TestPedersen_GetInfo(t *testing.T) {
+	privkey, pubkey := kyber_bls.NewKeyPair(pairingSuite, suite.RandomStream())
+
+	actor := Actor{
+		startRes: &state{},
+		privKey:  privkey,
+		pubKey:   pubkey,
+	}
+
+	_, err := actor.GetInfo()
+	require.EqualError(t, err, initDkgFirst)
+
+	actor.startRes = &state{
+		dkgState:     certified,
+		participants: []mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		pubkeys:      []kyber.Point{suite.Point().Pick(suite.RandomStream()), pubkey},
+		threshold:    2,
+		distrKey:     suite.Point().Pick(suite.RandomStream()),
+	}
+
+	info, err := actor.GetInfo()
+	require.NoError(t, err)
+	require.Len(t, info.Participants, 2)
+	require.Equal(t, 2, info.Threshold)
+	require.Equal(t, Scheme, info.Scheme)
+	require.True(t, info.Node.Equal(pubkey))
+	require.NoError(t, VerifyInfo(info))
+
+	info.Threshold = 1
+	err = VerifyInfo(info)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid signature: ", err.Error())
+
+	info.PublicKeys = nil
+	err = VerifyInfo(info)
+	require.EqualError(t, err,
+		"failed to compute digest: mismatch participants and keys: 2 != 0")
+
+	actor.startRes.participants = []mino.Address{fake.NewBadAddress()}
+	actor.startRes.pubkeys = []kyber.Point{pubkey}
+	_, err = actor.GetInfo()
+	require.EqualError(t, err,
+		fake.Err("failed to compute digest: failed to marshal address"))
+}
+
+func TestPedersen_Sign(t *testing.T) {
+	priShares := []*share.PriShare{
+		{I: 0, V: suite.Scalar().Pick(suite.RandomStream())},
+		{I: 1, V: suite.Scalar().Pick(suite.RandomStream())},
+	}
+	priPoly, err := share.RecoverPriPoly(bn256.NewSuite().G2(), priShares, 2, 2)
+	require.NoError(t, err)
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+
+	actor := Actor{
+		rpc: fake.NewBadRPC(),
+		startRes: &state{dkgState: certified,
+			participants: []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}, Commits: commits, threshold: 2},
+		keys:  newKeyStore(nil, defaultKeyCacheSize),
+		audit: newAuditLog(nil),
+		addr:  fake.NewAddress(2),
+	}
+
+	msg := []byte("merry christmas")
+	var tsigs [][]byte
+	for i := range priShares {
+		tsig, err := tbls.Sign(pairingSuite, priShares[i], msg)
+		require.NoError(t, err)
+		tsigs = append(tsigs, tsig)
+	}
+
+	_, err = tbls.Recover(pairingSuite, pubPoly, msg, tsigs, 2, 2)
+	require.NoError(t, err)
+
+	recv := fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), makeSignReply(t, tsigs[0])),
+		fake.NewRecvMsg(fake.NewAddress(1), makeSignReply(t, tsigs[1])),
+	)
+
+	rpc := fake.NewStreamRPC(recv, fake.Sender{})
+	actor.rpc = rpc
+	actor.random = suite.XOF(shareKeySeed)
+
+	tracer := mocktracer.New()
+	actor.tracer = tracer
+
+	sig, err := actor.Sign(msg)
+	require.NoError(t, err)
+
+	// Expect a valid signature
+	err = bls.NewPublicKeyFromPoint(pubPoly.Commit()).Verify(msg, bls.NewSignature(sig))
+	require.NoError(t, err)
+
+	// Expect the extraction and the recovery to be children of the main span.
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 3)
+	require.Equal(t, "extraction", spans[0].OperationName)
+	require.Equal(t, "recovery", spans[1].OperationName)
+	require.Equal(t, protocolNameDecrypt, spans[2].OperationName)
+	require.Equal(t, spans[2].SpanContext.SpanID, spans[0].ParentID)
+	require.Equal(t, spans[2].SpanContext.SpanID, spans[1].ParentID)
+
+	// The key is released only once.
+	actor.rpc = fake.NewBadRPC()
+
+	cached, err := actor.Sign(msg)
+	require.NoError(t, err)
+	require.Equal(t, sig, cached)
+
+	released, err := actor.GetReleasedKey(msg)
+	require.NoError(t, err)
+	require.Equal(t, sig, released)
+
+	// The release is recorded only when the key is recovered.
+	releases, err := actor.GetReleases(dkg.ReleaseFilter{})
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	require.Equal(t, msg, releases[0].Label)
+	require.Equal(t, "fake.Address[2]", releases[0].Requester)
+	require.Equal(t, []string{"fake.Address[0]", "fake.Address[1]"},
+		releases[0].Contributors)
+}
+
+func TestPedersen_Prune(t *testing.T) {
+	actor := Actor{
+		keys:      newKeyStore(nil, defaultKeyCacheSize),
+		retention: RetentionPolicy{Blocks: 2},
+	}
+
+	require.NoError(t, actor.keys.Store(ibe.NewBlockLabel(1), []byte("a")))
+	require.NoError(t, actor.keys.Store(ibe.NewBlockLabel(5), []byte("b")))
+
+	count, err := actor.Prune(5)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	_, err = actor.GetReleasedKey(ibe.NewBlockLabel(1))
+	require.Error(t, err)
+
+	_, err = actor.GetReleasedKey(ibe.NewBlockLabel(5))
+	require.NoError(t, err)
+
+	actor.keys = newKeyStore(fake.NewBadUpdateDB(), defaultKeyCacheSize)
+
+	_, err = actor.Prune(5)
+	require.EqualError(t, err, fake.Err("failed to prune key store: while updating db"))
+}
+
+func TestPedersen_GetReleasedKey(t *testing.T) {
+	db := fake.NewInMemoryDB()
+	db.SetBucket(keyBucket, fake.NewBucket())
+
+	actor := Actor{
+		keys: newKeyStore(db, defaultKeyCacheSize),
+	}
+
+	_, err := actor.GetReleasedKey([]byte("label"))
+	require.EqualError(t, err, "no key released for label 0x6c6162656c")
+
+	require.NoError(t, actor.keys.Store([]byte("label"), []byte("key")))
+
+	// A new store reads the keys persisted in the database.
+	actor.keys = newKeyStore(db, defaultKeyCacheSize)
+
+	key, err := actor.GetReleasedKey([]byte("label"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("key"), key)
+
+	actor.keys = newKeyStore(fake.NewBadViewDB(), defaultKeyCacheSize)
+
+	_, err = actor.GetReleasedKey([]byte("label"))
+	require.EqualError(t, err, fake.Err("failed to read key store: while reading db"))
+}
+
+func TestPedersen_GetKeys(t *testing.T) {
+	priShares := []*share.PriShare{
+		{I: 0, V: suite.Scalar().Pick(suite.RandomStream())},
+		{I: 1, V: suite.Scalar().Pick(suite.RandomStream())},
+	}
+	priPoly, err := share.RecoverPriPoly(bn256.NewSuite().G2(), priShares, 2, 2)
+	require.NoError(t, err)
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+
+	actor := Actor{
+		startRes: &state{},
+	}
+
+	_, err = actor.GetKeys(nil)
+	require.EqualError(t, err, initDkgFirst)
+
+	actor = Actor{
+		startRes: &state{dkgState: certified,
+			participants: []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}, Commits: commits, threshold: 2},
+		keys:   newKeyStore(nil, defaultKeyCacheSize),
+		audit:  newAuditLog(nil),
+		addr:   fake.NewAddress(2),
+		random: suite.XOF(shareKeySeed),
+	}
+
+	labels := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+
+	// The key of the first label is already released.
+	require.NoError(t, actor.keys.Store(labels[0], []byte("key")))
+
+	replies := make([]fake.ReceiverMessage, len(priShares))
+	for i, priShare := range priShares {
+		replies[i] = fake.NewRecvMsg(fake.NewAddress(i),
+			makeSignBatchReply(t, priShare, labels[1:]...))
+	}
+
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(replies...), fake.Sender{})
+
+	keys, err := actor.GetKeys(labels)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	require.Equal(t, []byte("key"), keys[0])
+
+	for i, label := range labels[1:] {
+		err = bls.NewPublicKeyFromPoint(pubPoly.Commit()).Verify(label, bls.NewSignature(keys[i+1]))
+		require.NoError(t, err)
+
+		released, err := actor.GetReleasedKey(label)
+		require.NoError(t, err)
+		require.Equal(t, keys[i+1], released)
+
+		releases, err := actor.GetReleases(dkg.ReleaseFilter{Label: label})
+		require.NoError(t, err)
+		require.Len(t, releases, 1)
+		require.Len(t, releases[0].Contributors, 2)
+	}
+
+	// A label refused by a participant cannot be recovered.
+	refused := makeSignBatchReply(t, priShares[1], []byte("d"))
+	refused.Shares[0] = nil
+
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), makeSignBatchReply(t, priShares[0], []byte("d"))),
+		fake.NewRecvMsg(fake.NewAddress(1), refused),
+	), fake.Sender{})
+	actor.random = suite.XOF(shareKeySeed)
+
+	_, err = actor.GetKeys([][]byte{[]byte("d")})
+	require.EqualError(t, err, "failed to sign batch: not enough valid shares "+
+		"for label 0x64: 1 < 2")
+
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), makeSignReply(t, []byte{})),
+	), fake.Sender{})
+
+	_, err = actor.GetKeys([][]byte{[]byte("d")})
+	require.EqualError(t, err, "failed to sign batch: got unexpected reply, "+
+		"expected types.SignBatchReply but got: types.SignReply")
+
+	actor.rpc = fake.NewBadRPC()
+
+	_, err = actor.GetKeys([][]byte{[]byte("d")})
+	require.EqualError(t, err, fake.Err("failed to sign batch: failed to create stream"))
+
+	actor.keys = newKeyStore(fake.NewBadViewDB(), defaultKeyCacheSize)
+
+	_, err = actor.GetKeys([][]byte{[]byte("d")})
+	require.EqualError(t, err, fake.Err("failed to read key store: while reading db"))
+}
+
+func TestPedersen_GetKeys_Streams(t *testing.T) {
+	priShares := []*share.PriShare{
+		{I: 0, V: suite.Scalar().Pick(suite.RandomStream())},
+		{I: 1, V: suite.Scalar().Pick(suite.RandomStream())},
+	}
+	priPoly, err := share.RecoverPriPoly(bn256.NewSuite().G2(), priShares, 2, 2)
+	require.NoError(t, err)
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+
+	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+
+	actor := Actor{
+		startRes: &state{dkgState: certified, participants: participants,
+			Commits: commits, threshold: 2},
+		keys:  newKeyStore(nil, defaultKeyCacheSize),
+		audit: newAuditLog(nil),
+		addr:  fake.NewAddress(2),
+	}
+
+	labels := make([][]byte, maxBatchSize+2)
+	for i := range labels {
+		labels[i] = ibe.NewBlockLabel(uint64(i))
+	}
+
+	// Each batch is requested on its own stream, which replies the shares of
+	// the labels of the batch only.
+	batches := [][][]byte{labels[:maxBatchSize], labels[maxBatchSize:]}
+	streams := 0
+
+	rpc := fake.NewRPC()
+	rpc.OnStream(func(players mino.Players) (mino.Sender, mino.Receiver) {
+		require.Equal(t, len(participants), players.Len())
+
+		batch := batches[streams]
+		streams++
+
+		replies := make([]fake.ReceiverMessage, len(priShares))
+		for i, priShare := range priShares {
+			replies[i] = fake.NewRecvMsg(participants[i],
+				makeSignBatchReply(t, priShare, batch...))
+		}
+
+		actor.random = suite.XOF(shareKeySeed)
+
+		return fake.Sender{}, fake.NewReceiver(replies...)
+	})
+
+	actor.rpc = rpc
+
+	keys, err := actor.GetKeys(labels)
+	require.NoError(t, err)
+	require.Len(t, keys, len(labels))
+	require.Equal(t, 2, streams)
+	require.Equal(t, 2, rpc.Calls.Len())
+
+	for i, label := range labels {
+		err = ibe.VerifyDecryptionKey(pairingSuite, pubPoly.Commit(), label, keys[i])
+		require.NoError(t, err)
+	}
+}
+
+func TestPedersen_GetKeys_Flaky(t *testing.T) {
+	oldLog := dela.Logger
+	defer func() {
+		dela.Logger = oldLog
+	}()
+
+	dela.Logger = dela.Logger.Level(zerolog.FatalLevel)
+
+	priShares := []*share.PriShare{
+		{I: 0, V: suite.Scalar().Pick(suite.RandomStream())},
+		{I: 1, V: suite.Scalar().Pick(suite.RandomStream())},
+	}
+	priPoly, err := share.RecoverPriPoly(bn256.NewSuite().G2(), priShares, 2, 2)
+	require.NoError(t, err)
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+
+	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+
+	db, err := kv.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	// The database, the sender and the receivers fail randomly, but always in
+	// the same order thanks to the seeds. The cache holds a single key so that
+	// the database is read.
+	actor := Actor{
+		startRes: &state{dkgState: certified, participants: participants,
+			Commits: commits, threshold: 2},
+		keys:  newKeyStore(fake.NewFlakyDB(db, 0.2, 2), 1),
+		audit: newAuditLog(nil),
+		addr:  fake.NewAddress(2),
+	}
+
+	var current []byte
+	streams := 0
+
+	rpc := fake.NewRPC()
+	rpc.OnStream(func(mino.Players) (mino.Sender, mino.Receiver) {
+		streams++
+
+		replies := make([]fake.ReceiverMessage, len(priShares))
+		for i, priShare := range priShares {
+			replies[i] = fake.NewRecvMsg(participants[i],
+				makeSignBatchReply(t, priShare, current))
+		}
+
+		actor.random = suite.XOF(shareKeySeed)
+
+		sender := fake.Flaky(fake.Sender{}, 0.2, int64(streams))
+		receiver := fake.NewFlakyReceiver(fake.NewReceiver(replies...), 0.2, int64(streams))
+
+		return sender, receiver
+	})
+
+	actor.rpc = rpc
+
+	labels := make([][]byte, 10)
+	for i := range labels {
+		labels[i] = ibe.NewBlockLabel(uint64(i))
+	}
+
+	// getKey asks for the key of the label until it succeeds, and returns the
+	// number of failures.
+	getKey := func(label []byte) ([]byte, int) {
+		current = label
+
+		for failures := 0; failures < 10; failures++ {
+			keys, err := actor.GetKeys([][]byte{label})
+			if err == nil {
+				return keys[0], failures
+			}
+
+			require.Regexp(t, "^(failed to read key store|failed to sign batch: "+
+				"(failed to send decrypt request|stream stopped unexpectedly)): ",
+				err.Error())
+		}
+
+		require.FailNow(t, "too many failures")
+		return nil, 0
+	}
+
+	keys := make([][]byte, len(labels))
+	failures := 0
+
+	for i, label := range labels {
+		key, n := getKey(label)
+		failures += n
+
+		err = ibe.VerifyDecryptionKey(pairingSuite, pubPoly.Commit(), label, key)
+		require.NoError(t, err)
+
+		keys[i] = key
+	}
+
+	require.Greater(t, failures, 0)
+
+	// The keys whose storage failed are requested again, and are the same.
+	streams = 0
+
+	for i, label := range labels {
+		key, _ := getKey(label)
+		require.Equal(t, keys[i], key)
+	}
+
+	require.Greater(t, streams, 0)
+}
+
+func TestPedersen_SignInvalidShare(t *testing.T) {
+	priShares := []*share.PriShare{
+		{I: 0, V: suite.Scalar().Pick(suite.RandomStream())},
+		{I: 1, V: suite.Scalar().Pick(suite.RandomStream())},
+		{I: 2, V: suite.Scalar().Pick(suite.RandomStream())},
+	}
+	priPoly, err := share.RecoverPriPoly(bn256.NewSuite().G2(), priShares, 2, 3)
+	require.NoError(t, err)
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+
+	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2)}
+
+	msg := []byte("merry christmas")
+	var tsigs [][]byte
+	for i := range priShares {
+		tsig, err := tbls.Sign(pairingSuite, priPoly.Shares(3)[i], msg)
+		require.NoError(t, err)
+		tsigs = append(tsigs, tsig)
+	}
+
+	// The first share is signing a different message.
+	badSig, err := tbls.Sign(pairingSuite, priPoly.Shares(3)[0], []byte("bad"))
+	require.NoError(t, err)
+
+	oldLog := dela.Logger
+	defer func() {
+		dela.Logger = oldLog
+	}()
+
+	logger := fake.NewLogger()
+	dela.Logger = logger.GetLogger()
+
+	actor := Actor{
+		startRes: &state{dkgState: certified, participants: participants,
+			Commits: commits, threshold: 2},
+		keys:  newKeyStore(nil, defaultKeyCacheSize),
+		audit: newAuditLog(nil),
+		addr:  fake.NewAddress(3),
+	}
+
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), makeSignReply(t, badSig)),
+		fake.NewRecvMsg(fake.NewAddress(1), makeSignReply(t, tsigs[1])),
+		fake.NewRecvMsg(fake.NewAddress(2), makeSignReply(t, tsigs[2])),
+	), fake.Sender{})
+	actor.random = suite.XOF(shareKeySeed)
+
+	sig, err := actor.Sign(msg)
+	require.NoError(t, err)
+
+	err = bls.NewPublicKeyFromPoint(pubPoly.Commit()).Verify(msg, bls.NewSignature(sig))
+	require.NoError(t, err)
+
+	entries := logger.Find(zerolog.WarnLevel, "invalid signature share from fake.Address[0]")
+	require.Len(t, entries, 1)
+	require.Contains(t, entries[0].Fields, zerolog.ErrorFieldName)
+
+	actor.keys = newKeyStore(nil, defaultKeyCacheSize)
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), makeSignReply(t, badSig)),
+		fake.NewRecvMsg(fake.NewAddress(1), makeSignReply(t, badSig)),
+		fake.NewRecvMsg(fake.NewAddress(2), makeSignReply(t, tsigs[2])),
+	), fake.Sender{})
+	actor.random = suite.XOF(shareKeySeed)
+
+	_, err = actor.Sign(msg)
+	require.EqualError(t, err, "not enough valid shares: 1 < 2")
+
+	// A share in clear is not accepted.
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewSignReply(tsigs[0])),
+		fake.NewRecvMsg(fake.NewAddress(1), makeSignReply(t, tsigs[1])),
+		fake.NewRecvMsg(fake.NewAddress(2), makeSignReply(t, badSig)),
+	), fake.Sender{})
+	actor.random = suite.XOF(shareKeySeed)
+
+	_, err = actor.Sign(msg)
+	require.EqualError(t, err, "not enough valid shares: 1 < 2")
+	require.True(t, logger.Has(zerolog.WarnLevel, "failed to decrypt share from fake.Address[0]"))
+
+	// A refusal is an empty share, which is not counted as a failure.
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewSignReply(nil)),
+		fake.NewRecvMsg(fake.NewAddress(1), makeSignReply(t, tsigs[1])),
+		fake.NewRecvMsg(fake.NewAddress(2), types.NewSignReply(nil)),
+	), fake.Sender{})
+	actor.random = suite.XOF(shareKeySeed)
+
+	_, err = actor.Sign(msg)
+	require.EqualError(t, err, "not enough valid shares: 1 < 2")
+	require.False(t, logger.Has(zerolog.WarnLevel, "failed to decrypt share from fake.Address[2]"))
+}
+
+func TestPedersen_ChainThreshold(t *testing.T) {
+	actor := Actor{
+		rpc:      fake.NewBadRPC(),
+		startRes: &state{},
+		roles:    fakeThreshold{threshold: 2},
+	}
+
+	_, err := actor.Setup(fake.NewAuthority(3, fake.NewSigner), 3)
+	require.EqualError(t, err, "threshold 3 differs from the threshold 2 of the chain")
+
+	_, err = actor.Setup(fake.NewAuthority(3, fake.NewSigner), 2)
+	require.EqualError(t, err, fake.Err("failed to stream"))
+
+	// The threshold of the chain is used when the setup does not give one.
+	threshold, err := actor.checkThreshold(0)
+	require.NoError(t, err)
+	require.Equal(t, 2, threshold)
+
+	actor.roles = fakeRoles{}
+
+	threshold, err = actor.checkThreshold(3)
+	require.NoError(t, err)
+	require.Equal(t, 3, threshold)
+
+	actor.roles = nil
+
+	threshold, err = actor.checkThreshold(1)
+	require.NoError(t, err)
+	require.Equal(t, 1, threshold)
+}
+
+func TestPedersen_Relays(t *testing.T) {
+	roles := fakeRoles{fake.NewAddress(2)}
+
+	actor := Actor{
+		rpc:      fake.NewBadRPC(),
+		startRes: &state{},
+		roles:    roles,
+	}
+
+	_, err := actor.Setup(fake.NewAuthority(3, fake.NewSigner), 2)
+	require.EqualError(t, err, "fake.Address[2] is a relay and cannot hold a key share")
+
+	_, err = actor.Setup(fake.NewAuthority(2, fake.NewSigner), 2)
+	require.EqualError(t, err, fake.Err("failed to stream"))
+
+	priShares := []*share.PriShare{
+		{I: 0, V: suite.Scalar().Pick(suite.RandomStream())},
+		{I: 1, V: suite.Scalar().Pick(suite.RandomStream())},
+	}
+	priPoly, err := share.RecoverPriPoly(bn256.NewSuite().G2(), priShares, 2, 3)
+	require.NoError(t, err)
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+
+	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2)}
+
+	actor = Actor{
+		startRes: &state{dkgState: certified, participants: participants,
+			Commits: commits, threshold: 2},
+		keys:   newKeyStore(nil, defaultKeyCacheSize),
+		audit:  newAuditLog(nil),
+		addr:   fake.NewAddress(3),
+		roles:  roles,
+		random: suite.XOF(shareKeySeed),
+	}
+
+	err = actor.Reshare(fake.NewAuthority(3, fake.NewSigner), 2)
+	require.EqualError(t, err, "fake.Address[2] is a relay and cannot hold a key share")
+
+	msg := []byte("merry christmas")
+	var tsigs [][]byte
+	for _, priShare := range priPoly.Shares(3) {
+		tsig, err := tbls.Sign(pairingSuite, priShare, msg)
+		require.NoError(t, err)
+		tsigs = append(tsigs, tsig)
+	}
+
+	oldLog := dela.Logger
+	defer func() {
+		dela.Logger = oldLog
+	}()
+
+	logger := fake.NewLogger()
+	dela.Logger = logger.GetLogger()
+
+	// Only the two holders are asked, and the share of the relay is ignored
+	// even if it is valid.
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(2), makeSignReply(t, tsigs[2])),
+		fake.NewRecvMsg(fake.NewAddress(0), makeSignReply(t, tsigs[0])),
+	), fake.Sender{})
+
+	_, err = actor.Sign(msg)
+	require.EqualError(t, err, "not enough valid shares: 1 < 2")
+	require.True(t, logger.Has(zerolog.WarnLevel, "ignored the share of the relay fake.Address[2]"))
+
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), makeSignReply(t, tsigs[0])),
+		fake.NewRecvMsg(fake.NewAddress(1), makeSignReply(t, tsigs[1])),
+	), fake.Sender{})
+	actor.random = suite.XOF(shareKeySeed)
+
+	sig, err := actor.Sign(msg)
+	require.NoError(t, err)
+
+	err = bls.NewPublicKeyFromPoint(pubPoly.Commit()).Verify(msg, bls.NewSignature(sig))
+	require.NoError(t, err)
+}
+
+func TestPedersen_Scenario(t *testing.T) {
+	// Use with MINO_TRAFFIC=log
+	// traffic.LogItems = false
+	// traffic.LogEvent = false
+	// defer func() {
+	// 	traffic.SaveItems("graph.dot", true, false)
+	// 	traffic.SaveEvents("events.dot")
+	// }()
+
+	oldLog := dela.Logger
+	defer func() {
+		dela.Logger = oldLog
+	}()
+
+	dela.Logger = dela.Logger.Level(zerolog.WarnLevel)
+
+	n := 32
+
+	minos := make([]mino.Mino, n)
+	dkgs := make([]dkg.DKG, n)
+	addrs := make([]mino.Address, n)
+
+	for i := 0; i < n; i++ {
+		addr := minogrpc.ParseAddress("127.0.0.1", 0)
+
+		m, err := minogrpc.NewMinogrpc(addr, nil, tree.NewRouter(minogrpc.NewAddressFactory()))
+		require.NoError(t, err)
+
+		defer m.GracefulStop()
+
+		minos[i] = m
+		addrs[i] = m.GetAddress()
+	}
+
+	pubkeys := make([]kyber.Point, len(minos))
+
+	for i, mi := range minos {
+		for _, m := range minos {
+			mi.(*minogrpc.Minogrpc).GetCertificateStore().Store(m.GetAddress(), m.(*minogrpc.Minogrpc).GetCertificateChain())
+		}
+
+		d, pubkey := NewPedersen(mi.(*minogrpc.Minogrpc))
+
+		dkgs[i] = d
+		pubkeys[i] = pubkey
+	}
+
+	fakeAuthority := NewAuthority(addrs, pubkeys)
+
+	message := []byte("Hello world")
+	actors := make([]dkg.Actor, n)
+	for i := 0; i < n; i++ {
+		actor, err := dkgs[i].Listen()
+		require.NoError(t, err)
+
+		actors[i] = actor
+	}
+
+	// trying to call sign before a setup
+	_, err := actors[0].Sign(message)
+	require.EqualError(t, err, "you must first initialize DKG. Did you call setup() first?")
+
+	_, err = actors[0].Setup(fakeAuthority, n)
+	require.NoError(t, err)
+
+	_, err = actors[0].Setup(fakeAuthority, n)
+	require.EqualError(t, err, "startRes is already done, only one setup call is allowed")
+
+	// every node should be able to sign
+	for i := 0; i < n; i++ {
+		_, err := actors[i].Sign(message)
+		require.NoError(t, err)
+	}
+}
+
+func TestPedersen_GetKeys_Scenario(t *testing.T) {
+	oldLog := dela.Logger
+	defer func() {
+		dela.Logger = oldLog
+	}()
+
+	dela.Logger = dela.Logger.Level(zerolog.WarnLevel)
+
+	n := 4
+
+	manager := minoch.NewManager()
+	bus := events.NewBus()
+
+	dkgs := make([]dkg.DKG, n)
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]kyber.Point, n)
+
+	for i := 0; i < n; i++ {
+		m := minoch.MustCreate(manager, fmt.Sprintf("addr %d", i))
+
+		dkgs[i], pubkeys[i] = NewPedersen(m, WithEvents(bus))
+		addrs[i] = m.GetAddress()
+	}
+
+	actors := make([]dkg.Actor, n)
+	for i := 0; i < n; i++ {
+		actor, err := dkgs[i].Listen()
+		require.NoError(t, err)
 
-	_, err := actor.Setup(fakeAuthority, 0)
-	require.EqualError(t, err, fake.Err("failed to stream"))
+		actors[i] = actor
+	}
 
-	rpc := fake.NewStreamRPC(fake.NewReceiver(), fake.NewBadSender())
-	actor.rpc = rpc
+	pubkey, err := actors[0].Setup(NewAuthority(addrs, pubkeys), n-1)
+	require.NoError(t, err)
 
-	_, err = actor.Setup(fakeAuthority, 0)
-	require.EqualError(t, err, "expected bls.PublicKey, got 'fake.PublicKey'")
+	// More labels than a batch can hold so that the request is split.
+	labels := make([][]byte, maxBatchSize+6)
+	for i := range labels {
+		labels[i] = ibe.NewBlockLabel(uint64(i))
+	}
 
-	fakeAuthority = fake.NewAuthority(2, bls.Generate)
+	// The keys of the block labels are released once the blocks are
+	// finalized.
+	require.Eventually(t, func() bool {
+		bus.Publish(events.BlockFinalized{Index: uint64(len(labels) - 1)})
 
-	_, err = actor.Setup(fakeAuthority, 1)
-	require.EqualError(t, err, fake.Err("failed to send start"))
+		for _, d := range dkgs {
+			height, _ := d.(*Pedersen).finality.Height()
+			if height < uint64(len(labels)-1) {
+				return false
+			}
+		}
 
-	rpc = fake.NewStreamRPC(fake.NewBadReceiver(), fake.Sender{})
-	actor.rpc = rpc
+		return true
+	}, time.Second, 10*time.Millisecond)
 
-	_, err = actor.Setup(fakeAuthority, 1)
-	require.EqualError(t, err, fake.Err("got an error from '%!s(<nil>)' while receiving"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	recv := fake.NewReceiver(fake.NewRecvMsg(fake.NewAddress(0), nil))
+	published := bus.Subscribe(ctx, events.WithBufferSize(len(labels)))
 
-	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+	released, err := actors[1].Sign(labels[3])
+	require.NoError(t, err)
 
-	_, err = actor.Setup(fakeAuthority, 1)
-	require.EqualError(t, err, "expected to receive a Done message, but go the following: <nil>")
+	// The requester publishes the key, with the index of the block label.
+	require.Equal(t, events.KeyReleased{Index: 3, Label: labels[3], Key: released},
+		<-published)
 
-	rpc = fake.NewStreamRPC(fake.NewReceiver(
-		fake.NewRecvMsg(fake.NewAddress(0), types.NewStartDone(suite.Point())),
-		fake.NewRecvMsg(fake.NewAddress(0), types.NewStartDone(suite.Point().Pick(suite.RandomStream()))),
-	), fake.Sender{})
-	actor.rpc = rpc
+	keys, err := actors[1].GetKeys(labels)
+	require.NoError(t, err)
+	require.Len(t, keys, len(labels))
+	require.Equal(t, released, keys[3])
 
-	_, err = actor.Setup(fakeAuthority, 1)
-	require.Error(t, err)
-	require.Regexp(t, "^the public keys does not match:", err)
+	for i, label := range labels {
+		err = ibe.VerifyDecryptionKey(pairingSuite, pubkey, label, keys[i])
+		require.NoError(t, err)
+	}
 }
 
-func TestPedersen_GetPublicKey(t *testing.T) {
+func TestPedersen_ReleaseBlock(t *testing.T) {
 	actor := Actor{
 		startRes: &state{},
 	}
 
-	_, err := actor.GetPublicKey()
-	require.EqualError(t, err, "DKG has not been initialized")
+	_, err := actor.ReleaseBlock(1, nil)
+	require.EqualError(t, err, initDkgFirst)
 
 	actor.startRes = &state{dkgState: certified}
-	_, err = actor.GetPublicKey()
-	require.NoError(t, err)
+
+	_, err = actor.ReleaseBlock(1, nil)
+	require.EqualError(t, err, "invalid labels: no label")
+
+	_, err = actor.ReleaseBlock(1, make([][]byte, maxBatchSize+1))
+	require.EqualError(t, err, "invalid labels: too many labels: 65 > 64")
+
+	_, err = actor.ReleaseBlock(1, [][]byte{ibe.NewBlockLabel(1)})
+	require.EqualError(t, err, "invalid labels: not a transaction label: "+
+		`not a transaction label: "f3b:block:1"`)
+
+	label := ibe.NewTxLabel(2, 0, []byte("tx"))
+
+	_, err = actor.ReleaseBlock(1, [][]byte{label})
+	require.EqualError(t, err, fmt.Sprintf("invalid labels: label %q is for block 2", label))
+
+	_, err = actor.ReleaseBlock(2, [][]byte{label, label})
+	require.EqualError(t, err, fmt.Sprintf("invalid labels: duplicate label %q", label))
+
+	actor.keys = newKeyStore(fake.NewBadViewDB(), defaultKeyCacheSize)
+
+	_, err = actor.ReleaseBlock(2, [][]byte{label})
+	require.EqualError(t, err, fake.Err("failed to get keys: failed to read key store: while reading db"))
 }
 
-func TestPedersen_Sign(t *testing.T) {
-	priShares := []*share.PriShare{
-		&share.PriShare{0, suite.Scalar().Pick(suite.RandomStream())},
-		&share.PriShare{1, suite.Scalar().Pick(suite.RandomStream())},
-	}
-	priPoly, err := share.RecoverPriPoly(bn256.NewSuite().G2(), priShares, 2, 2)
-	require.NoError(t, err)
-	pubPoly := priPoly.Commit(nil)
-	_, commits := pubPoly.Info()
+func TestPedersen_ReleaseBlock_Scenario(t *testing.T) {
+	oldLog := dela.Logger
+	defer func() {
+		dela.Logger = oldLog
+	}()
 
-	actor := Actor{
-		rpc: fake.NewBadRPC(),
-		startRes: &state{dkgState: certified,
-			participants: []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}, Commits: commits, threshold: 2},
+	dela.Logger = dela.Logger.Level(zerolog.FatalLevel)
+
+	n := 3
+
+	manager := minoch.NewManager()
+	bus := events.NewBus()
+
+	dkgs := make([]dkg.DKG, n)
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]kyber.Point, n)
+
+	for i := 0; i < n; i++ {
+		m := minoch.MustCreate(manager, fmt.Sprintf("addr %d", i))
+
+		dkgs[i], pubkeys[i] = NewPedersen(m, WithEvents(bus))
+		addrs[i] = m.GetAddress()
 	}
 
-	msg := []byte("merry christmas")
-	var tsigs [][]byte
-	for i := range priShares {
-		tsig, err := tbls.Sign(pairingSuite, priShares[i], msg)
+	actors := make([]dkg.Actor, n)
+	for i := 0; i < n; i++ {
+		actor, err := dkgs[i].Listen()
 		require.NoError(t, err)
-		tsigs = append(tsigs, tsig)
+
+		actors[i] = actor
 	}
 
-	_, err = tbls.Recover(pairingSuite, pubPoly, msg, tsigs, 2, 2)
+	pubkey, err := actors[0].Setup(NewAuthority(addrs, pubkeys), n)
 	require.NoError(t, err)
 
-	recv := fake.NewReceiver(
-		fake.NewRecvMsg(fake.NewAddress(0), types.NewSignReply(tsigs[0])),
-		fake.NewRecvMsg(fake.NewAddress(1), types.NewSignReply(tsigs[1])),
-	)
+	labels := [][]byte{
+		ibe.NewTxLabel(5, 0, []byte("tx0")),
+		ibe.NewTxLabel(5, 1, []byte("tx1")),
+		ibe.NewTxLabel(5, 2, []byte("tx2")),
+	}
 
-	rpc := fake.NewStreamRPC(recv, fake.Sender{})
-	actor.rpc = rpc
+	// The block of the transactions is ordered but not finalized, so neither
+	// a transaction nor the block is released.
+	bus.Publish(events.BlockFinalized{Index: 4})
 
-	sig, err := actor.Sign(msg)
+	require.Eventually(t, func() bool {
+		for _, d := range dkgs {
+			_, synced := d.(*Pedersen).finality.Height()
+			if !synced {
+				return false
+			}
+		}
+
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = actors[1].GetKeys(labels[:1])
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not enough valid shares for label")
+
+	_, err = actors[1].ReleaseBlock(5, labels)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not enough valid shares for label")
+
+	require.Eventually(t, func() bool {
+		bus.Publish(events.BlockFinalized{Index: 5})
+
+		for _, d := range dkgs {
+			height, _ := d.(*Pedersen).finality.Height()
+			if height < 5 {
+				return false
+			}
+		}
+
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	release, err := actors[1].ReleaseBlock(5, labels)
 	require.NoError(t, err)
+	require.Equal(t, uint64(5), release.Height)
+	require.Equal(t, labels, release.Labels)
+	require.Len(t, release.Keys, len(labels))
 
-	// Expect a valid signature
-	err = bls.NewPublicKeyFromPoint(pubPoly.Commit()).Verify(msg, bls.NewSignature(sig))
+	err = VerifyBlockRelease(pubkey, release)
 	require.NoError(t, err)
-}
 
-func TestPedersen_Scenario(t *testing.T) {
-	// Use with MINO_TRAFFIC=log
-	// traffic.LogItems = false
-	// traffic.LogEvent = false
-	// defer func() {
-	// 	traffic.SaveItems("graph.dot", true, false)
-	// 	traffic.SaveEvents("events.dot")
-	// }()
+	for i, label := range labels {
+		err = ibe.VerifyDecryptionKey(pairingSuite, pubkey, label, release.Keys[i])
+		require.NoError(t, err)
+	}
+
+	// The aggregate is the same whatever the order of the keys, so each key is
+	// verified against its label.
+	tampered := release
+	tampered.Keys = [][]byte{release.Keys[1], release.Keys[0], release.Keys[2]}
+
+	err = VerifyBlockRelease(pubkey, tampered)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("invalid key of label %q: ", labels[0]))
+
+	// An aggregate that misses a transaction does not prove the release of
+	// the block.
+	tampered = release
+	tampered.Aggregate, err = kyber_bls.AggregateSignatures(pairingSuite, release.Keys[:2]...)
+	require.NoError(t, err)
+
+	err = VerifyBlockRelease(pubkey, tampered)
+	require.EqualError(t, err, "invalid aggregate: bls: invalid signature")
+
+	tampered = release
+	tampered.Keys = release.Keys[:2]
 
+	err = VerifyBlockRelease(pubkey, tampered)
+	require.EqualError(t, err, "expected 3 keys, got 2")
+
+	tampered = release
+	tampered.Height = 4
+
+	err = VerifyBlockRelease(pubkey, tampered)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is for block 5")
+}
+
+func TestPedersen_Resume(t *testing.T) {
 	oldLog := dela.Logger
 	defer func() {
 		dela.Logger = oldLog
@@ -147,10 +1086,9 @@ func TestPedersen_Scenario(t *testing.T) {
 
 	dela.Logger = dela.Logger.Level(zerolog.WarnLevel)
 
-	n := 32
+	n := 3
 
-	minos := make([]mino.Mino, n)
-	dkgs := make([]dkg.DKG, n)
+	minos := make([]*minogrpc.Minogrpc, n)
 	addrs := make([]mino.Address, n)
 
 	for i := 0; i < n; i++ {
@@ -165,22 +1103,21 @@ func TestPedersen_Scenario(t *testing.T) {
 		addrs[i] = m.GetAddress()
 	}
 
-	pubkeys := make([]kyber.Point, len(minos))
+	dkgs := make([]*Pedersen, n)
+	pubkeys := make([]kyber.Point, n)
 
 	for i, mi := range minos {
 		for _, m := range minos {
-			mi.(*minogrpc.Minogrpc).GetCertificateStore().Store(m.GetAddress(), m.(*minogrpc.Minogrpc).GetCertificateChain())
+			mi.GetCertificateStore().Store(m.GetAddress(), m.GetCertificateChain())
 		}
 
-		d, pubkey := NewPedersen(mi.(*minogrpc.Minogrpc))
+		db := fake.NewInMemoryDB()
+		db.SetBucket(keyBucket, fake.NewBucket())
+		db.SetBucket(transcriptBucket, fake.NewBucket())
 
-		dkgs[i] = d
-		pubkeys[i] = pubkey
+		dkgs[i], pubkeys[i] = NewPedersen(mi, WithKeyStore(db))
 	}
 
-	fakeAuthority := NewAuthority(addrs, pubkeys)
-
-	message := []byte("Hello world")
 	actors := make([]dkg.Actor, n)
 	for i := 0; i < n; i++ {
 		actor, err := dkgs[i].Listen()
@@ -189,21 +1126,129 @@ func TestPedersen_Scenario(t *testing.T) {
 		actors[i] = actor
 	}
 
-	// trying to call sign before a setup
-	_, err := actors[0].Sign(message)
-	require.EqualError(t, err, "you must first initialize DKG. Did you call setup() first?")
+	pubkey, err := actors[0].Setup(NewAuthority(addrs, pubkeys), n)
+	require.NoError(t, err)
 
-	_, err = actors[0].Setup(fakeAuthority, n)
+	_, err = actors[0].Resume()
+	require.EqualError(t, err, "startRes is already done, nothing to resume")
+
+	// Simulate a restart of the last node with a fresh handler that reads the
+	// same database.
+	h := NewHandler(dkgs[2].privKey, addrs[2], dkgs[2].transcripts, 0, nil, nil)
+
+	restarted := &Actor{
+		startRes: h.dkgInstance.getState(),
+		instance: h.dkgInstance,
+	}
+
+	resumed, err := restarted.Resume()
 	require.NoError(t, err)
+	require.True(t, pubkey.Equal(resumed))
+	require.Equal(t, actors[2].(*Actor).instance.(*instance).privShare,
+		h.dkgInstance.(*instance).privShare)
 
-	_, err = actors[0].Setup(fakeAuthority, n)
-	require.EqualError(t, err, "startRes is already done, only one setup call is allowed")
+	h = NewHandler(dkgs[2].privKey, addrs[2], nil, 0, nil, nil)
+
+	restarted = &Actor{
+		startRes: h.dkgInstance.getState(),
+		instance: h.dkgInstance,
+	}
+
+	_, err = restarted.Resume()
+	require.EqualError(t, err, "failed to resume: no transcript store")
+}
+
+func TestPedersen_SetupWithTimeout(t *testing.T) {
+	oldLog := dela.Logger
+	defer func() {
+		dela.Logger = oldLog
+	}()
+
+	// The unreachable node produces a lot of expected errors.
+	dela.Logger = dela.Logger.Level(zerolog.Disabled)
+
+	n := 4
+
+	minos := make([]*minogrpc.Minogrpc, n)
+	addrs := make([]mino.Address, n)
 
-	// every node should be able to sign
 	for i := 0; i < n; i++ {
-		_, err := actors[i].Sign(message)
+		addr := minogrpc.ParseAddress("127.0.0.1", 0)
+
+		m, err := minogrpc.NewMinogrpc(addr, nil, tree.NewRouter(minogrpc.NewAddressFactory()))
+		require.NoError(t, err)
+
+		defer m.GracefulStop()
+
+		minos[i] = m
+		addrs[i] = m.GetAddress()
+	}
+
+	dkgs := make([]*Pedersen, n)
+	pubkeys := make([]kyber.Point, n)
+
+	for i, mi := range minos {
+		for _, m := range minos {
+			mi.GetCertificateStore().Store(m.GetAddress(), m.GetCertificateChain())
+		}
+
+		dkgs[i], pubkeys[i] = NewPedersen(mi, WithTimeout(time.Second))
+	}
+
+	actors := make([]dkg.Actor, n)
+	for i := 0; i < n; i++ {
+		actor, err := dkgs[i].Listen()
+		require.NoError(t, err)
+
+		actors[i] = actor
+	}
+
+	// The last node is offline during the whole setup.
+	minos[n-1].GracefulStop()
+
+	pubkey, err := actors[0].Setup(NewAuthority(addrs, pubkeys), n-1)
+	require.NoError(t, err)
+
+	message := []byte("Hello world")
+
+	for i := 0; i < n-1; i++ {
+		actualKey, err := actors[i].GetPublicKey()
 		require.NoError(t, err)
+		require.True(t, pubkey.Equal(actualKey))
 	}
+
+	sig, err := actors[1].Sign(message)
+	require.NoError(t, err)
+	require.NoError(t, actors[2].Verify(message, sig))
+}
+
+func TestActor_reachable(t *testing.T) {
+	actor := Actor{}
+
+	n, err := actor.reachable(fake.Sender{}.Send(nil), 3, 2)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	_, err = actor.reachable(fake.NewBadSender().Send(nil), 3, 2)
+	require.EqualError(t, err, fake.GetError().Error())
+
+	actor.timeout = time.Second
+
+	errs := make(chan error, 2)
+	errs <- fake.GetError()
+	close(errs)
+
+	n, err = actor.reachable(errs, 3, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	errs = make(chan error, 2)
+	errs <- fake.GetError()
+	errs <- fake.GetError()
+	close(errs)
+
+	_, err = actor.reachable(errs, 3, 2)
+	require.EqualError(t, err, "only 1 participants reachable for a threshold of 2")
 }
 
 func Test_Reshare_NotDone(t *testing.T) {
@@ -265,12 +1310,39 @@ func Test_Reshare_BadReceiver(t *testing.T) {
 // -----------------------------------------------------------------------------
 // Utility functions
 
+type fakeRoles []mino.Address
+
+func (r fakeRoles) IsRelay(addr mino.Address) bool {
+	for _, relay := range r {
+		if relay.Equal(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r fakeRoles) GetThreshold() int {
+	return 0
+}
+
+type fakeThreshold struct {
+	fakeRoles
+
+	threshold int
+}
+
+func (c fakeThreshold) GetThreshold() int {
+	return c.threshold
+}
+
 //
 // Collective authority
 //
 
 // CollectiveAuthority is a fake implementation of the cosi.CollectiveAuthority
 // interface.
+
 type CollectiveAuthority struct {
 	crypto.CollectiveAuthority
 	addrs   []mino.Address
@@ -336,3 +1408,42 @@ type fakeSigner struct {
 func (s fakeSigner) GetPublicKey() crypto.PublicKey {
 	return bls.NewPublicKeyFromPoint(s.pubkey)
 }
+
+// shareKeySeed is the seed of the stream from which the actor picks the
+// ephemeral key of the sign requests in the tests.
+var shareKeySeed = []byte("share key")
+
+// makeSignReply returns a reply with the share encrypted under the ephemeral
+// key picked from the seed.
+func makeSignBatchReply(t *testing.T, priShare *share.PriShare,
+	labels ...[]byte) types.SignBatchReply {
+
+	secret := suite.Scalar().Pick(suite.XOF(shareKeySeed))
+
+	pubKey, err := suite.Point().Mul(secret, nil).MarshalBinary()
+	require.NoError(t, err)
+
+	shares := make([]tbls.SigShare, len(labels))
+
+	for i, label := range labels {
+		sigShare, err := tbls.Sign(pairingSuite, priShare, label)
+		require.NoError(t, err)
+
+		shares[i], err = encryptShare(pubKey, sigShare)
+		require.NoError(t, err)
+	}
+
+	return types.NewSignBatchReply(shares)
+}
+
+func makeSignReply(t *testing.T, sigShare []byte) types.SignReply {
+	secret := suite.Scalar().Pick(suite.XOF(shareKeySeed))
+
+	pubKey, err := suite.Point().Mul(secret, nil).MarshalBinary()
+	require.NoError(t, err)
+
+	ciphertext, err := encryptShare(pubKey, sigShare)
+	require.NoError(t, err)
+
+	return types.NewSignReply(ciphertext)
+}
@@ -12,6 +12,7 @@ import (
 
 	"go.dedis.ch/dela/dkg"
 
+	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/mino/minoch"
 
@@ -157,6 +158,84 @@ func TestResharing_minoch(t *testing.T) {
 
 }
 
+// This test restarts every node of a committee, which resumes from its
+// transcript, and then redistributes the secret to a larger committee.
+func TestResharing_AfterResume(t *testing.T) {
+	n := 3
+
+	privKeys := make([]kyber.Scalar, n)
+	dbs := make([]*fake.InMemoryDB, n)
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]kyber.Point, n)
+	actors := make([]dkg.Actor, n)
+
+	minoManager := minoch.NewManager()
+
+	for i := 0; i < n; i++ {
+		privKeys[i] = suite.Scalar().Pick(suite.RandomStream())
+
+		dbs[i] = fake.NewInMemoryDB()
+		dbs[i].SetBucket(keyBucket, fake.NewBucket())
+		dbs[i].SetBucket(transcriptBucket, fake.NewBucket())
+
+		m := minoch.MustCreate(minoManager, fmt.Sprintf("addr %d", i))
+		addrs[i] = m.GetAddress()
+
+		pdkg, pubkey := NewPedersen(m, WithPrivateKey(privKeys[i]),
+			WithKeyStore(dbs[i]))
+		pubkeys[i] = pubkey
+
+		actor, err := pdkg.Listen()
+		require.NoError(t, err)
+
+		actors[i] = actor
+	}
+
+	pubkey, err := actors[0].Setup(NewAuthority(addrs, pubkeys), n)
+	require.NoError(t, err, initDkgFailed)
+
+	// The nodes restart on a new network with the same addresses, long-term
+	// keys and databases.
+	minoManager = minoch.NewManager()
+
+	for i := 0; i < n; i++ {
+		m := minoch.MustCreate(minoManager, fmt.Sprintf("addr %d", i))
+
+		pdkg, _ := NewPedersen(m, WithPrivateKey(privKeys[i]),
+			WithKeyStore(dbs[i]))
+
+		actor, err := pdkg.Listen()
+		require.NoError(t, err)
+
+		resumed, err := actor.Resume()
+		require.NoError(t, err)
+		require.True(t, pubkey.Equal(resumed))
+
+		actors[i] = actor
+	}
+
+	m := minoch.MustCreate(minoManager, "addr new")
+
+	pdkg, newPubkey := NewPedersen(m)
+
+	newActor, err := pdkg.Listen()
+	require.NoError(t, err)
+
+	co := NewAuthority(append(addrs, m.GetAddress()), append(pubkeys, newPubkey))
+
+	err = actors[0].Reshare(co, n+1)
+	require.NoError(t, err, resharingUnsuccessful)
+
+	reshared, err := newActor.GetPublicKey()
+	require.NoError(t, err)
+	require.True(t, pubkey.Equal(reshared))
+
+	// The new committee releases the keys of the same collective key.
+	sig, err := newActor.Sign([]byte(testMessage))
+	require.NoError(t, err)
+	require.NoError(t, newActor.Verify([]byte(testMessage), sig))
+}
+
 // This test creats a dkg committee then creats another committee (that can
 // share some nodes with the old committee) and then redistributes the secret to
 // the new commitee. Using minogrpc as the underlying network
@@ -0,0 +1,41 @@
+package pedersen
+
+import (
+	"time"
+
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
+)
+
+// RetentionPolicy decides when a released key is old enough to be deleted.
+// Only the block, transaction and time labels expire, as the other labels do
+// not tell when their key is not needed anymore. A zero field keeps the keys
+// of its kind of label forever.
+type RetentionPolicy struct {
+	// Blocks is the number of blocks after which the key of a block or
+	// transaction label is deleted.
+	Blocks uint64
+
+	// Age is the duration after its deadline when the key of a time label is
+	// deleted.
+	Age time.Duration
+}
+
+// Expired returns true if the key of the label can be deleted when the chain
+// is at the height and the time is now.
+func (p RetentionPolicy) Expired(label []byte, height uint64, now time.Time) bool {
+	if p.Blocks > 0 {
+		index, err := labelHeight(label)
+		if err == nil {
+			return index+p.Blocks < height
+		}
+	}
+
+	if p.Age > 0 && ibe.IsTimeLabel(label) {
+		deadline, err := ibe.ParseTimeLabel(label)
+		if err == nil {
+			return deadline.Add(p.Age).Before(now)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,31 @@
+package pedersen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/ibe"
+)
+
+func TestRetentionPolicy_Expired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	policy := RetentionPolicy{Blocks: 10, Age: time.Hour}
+
+	require.False(t, policy.Expired(ibe.NewBlockLabel(5), 15, now))
+	require.True(t, policy.Expired(ibe.NewBlockLabel(5), 16, now))
+
+	require.False(t, policy.Expired(ibe.NewTimeLabel(now.Add(-time.Hour)), 0, now))
+	require.True(t, policy.Expired(ibe.NewTimeLabel(now.Add(-2*time.Hour)), 0, now))
+
+	require.False(t, policy.Expired([]byte("label"), 100, now))
+	require.False(t, policy.Expired(ibe.NewTxLabel(5, 0, nil), 15, now))
+	require.True(t, policy.Expired(ibe.NewTxLabel(5, 0, nil), 16, now))
+
+	// The keys are kept forever by default.
+	policy = RetentionPolicy{}
+
+	require.False(t, policy.Expired(ibe.NewBlockLabel(5), 100, now))
+	require.False(t, policy.Expired(ibe.NewTimeLabel(now.Add(-time.Hour)), 0, now))
+}
@@ -1,6 +1,7 @@
 package pedersen
 
 import (
+	"go.dedis.ch/dela/dkg/pedersen_bn256/types"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/kyber/v3"
 	"golang.org/x/xerrors"
@@ -115,6 +116,26 @@ func (s *state) init(participants []mino.Address, pubkeys []kyber.Point, t int)
 	s.threshold = t
 }
 
+// restore sets the state of a certified node from the transcript of a DKG. It
+// is only allowed from the initial state.
+func (s *state) restore(transcript types.Transcript) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.dkgState != initial {
+		return xerrors.Errorf("state must be initial: %s", s.dkgState)
+	}
+
+	s.participants = transcript.GetAddresses()
+	s.pubkeys = transcript.GetPublicKeys()
+	s.threshold = transcript.GetThreshold()
+	s.distrKey = transcript.GetDistKey()
+	s.Commits = transcript.GetCommits()
+	s.dkgState = certified
+
+	return nil
+}
+
 func (s *state) getDistKey() kyber.Point {
 	s.Lock()
 	defer s.Unlock()
@@ -48,6 +48,23 @@ func TestSwitchState(t *testing.T) {
 	require.EqualError(t, err, "resharing state must switch from initial or certified: Resharing")
 }
 
+func TestState_Restore(t *testing.T) {
+	state := state{dkgState: initial}
+	transcript := makeTranscript()
+
+	err := state.restore(transcript)
+	require.NoError(t, err)
+	require.True(t, state.Done())
+	require.Equal(t, transcript.GetThreshold(), state.getThreshold())
+	require.Len(t, state.getParticipants(), 2)
+	require.Len(t, state.getPublicKeys(), 2)
+	require.Equal(t, transcript.GetDistKey(), state.getDistKey())
+	require.Len(t, state.Commits, 2)
+
+	err = state.restore(transcript)
+	require.EqualError(t, err, "state must be initial: Certified")
+}
+
 func TestCheckStateUnknown(t *testing.T) {
 	state := state{}
 
@@ -0,0 +1,188 @@
+package pedersen
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/types"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/json"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/xerrors"
+)
+
+// transcriptBucket is the name of the bucket where the transcript of the last
+// DKG is persisted.
+var transcriptBucket = []byte("dkg-transcript")
+
+// transcriptKey is the key of the transcript in the bucket. Only the last one
+// is kept as it replaces the previous ones.
+var transcriptKey = []byte("last")
+
+// transcriptInfo is the context of the derivation of the key that encrypts the
+// transcript from the long-term key.
+var transcriptInfo = []byte("dela-dkg-transcript")
+
+// transcriptStore persists the outcome of the DKG so that a node can resume
+// after a restart. It does nothing when no database is provided.
+//
+// The transcript holds the share of the node, so it is encrypted with a key
+// derived from the long-term key of the node. Only the same node can therefore
+// resume from it.
+type transcriptStore struct {
+	db      kv.DB
+	privKey kyber.Scalar
+	context serde.Context
+	factory serde.Factory
+	random  io.Reader
+}
+
+// newTranscriptStore returns a new transcript store. The database is optional.
+func newTranscriptStore(db kv.DB, privKey kyber.Scalar,
+	factory serde.Factory) *transcriptStore {
+
+	return &transcriptStore{
+		db:      db,
+		privKey: privKey,
+		context: json.NewContext(),
+		factory: factory,
+		random:  rand.Reader,
+	}
+}
+
+// Store persists the transcript.
+func (s *transcriptStore) Store(transcript types.Transcript) error {
+	if s.db == nil {
+		return nil
+	}
+
+	data, err := transcript.Serialize(s.context)
+	if err != nil {
+		return xerrors.Errorf("failed to serialize: %v", err)
+	}
+
+	aead, err := s.makeAEAD()
+	if err != nil {
+		return xerrors.Errorf("failed to create cipher: %v", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err = io.ReadFull(s.random, nonce)
+	if err != nil {
+		return xerrors.Errorf("failed to generate nonce: %v", err)
+	}
+
+	data = aead.Seal(nonce, nonce, data, transcriptKey)
+
+	err = s.db.Update(func(tx kv.WritableTx) error {
+		bucket, err := tx.GetBucketOrCreate(transcriptBucket)
+		if err != nil {
+			return xerrors.Errorf("while getting bucket: %v", err)
+		}
+
+		err = bucket.Set(transcriptKey, data)
+		if err != nil {
+			return xerrors.Errorf("while writing: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("while updating db: %v", err)
+	}
+
+	return nil
+}
+
+// Load returns the persisted transcript, or an error if there is none.
+func (s *transcriptStore) Load() (types.Transcript, error) {
+	if s.db == nil {
+		return types.Transcript{}, xerrors.New("no database")
+	}
+
+	var data []byte
+
+	err := s.db.View(func(tx kv.ReadableTx) error {
+		bucket := tx.GetBucket(transcriptBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		value := bucket.Get(transcriptKey)
+		if len(value) > 0 {
+			data = make([]byte, len(value))
+			copy(data, value)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return types.Transcript{}, xerrors.Errorf("while reading db: %v", err)
+	}
+
+	if data == nil {
+		return types.Transcript{}, xerrors.New("no transcript")
+	}
+
+	aead, err := s.makeAEAD()
+	if err != nil {
+		return types.Transcript{}, xerrors.Errorf("failed to create cipher: %v", err)
+	}
+
+	if len(data) < aead.NonceSize() {
+		return types.Transcript{}, xerrors.Errorf("transcript too short: %d", len(data))
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+
+	data, err = aead.Open(nil, nonce, ciphertext, transcriptKey)
+	if err != nil {
+		return types.Transcript{}, xerrors.Errorf("failed to decrypt: %v", err)
+	}
+
+	msg, err := s.factory.Deserialize(s.context, data)
+	if err != nil {
+		return types.Transcript{}, xerrors.Errorf("failed to deserialize: %v", err)
+	}
+
+	transcript, ok := msg.(types.Transcript)
+	if !ok {
+		return types.Transcript{}, xerrors.Errorf("invalid message '%T'", msg)
+	}
+
+	return transcript, nil
+}
+
+// makeAEAD returns the cipher of the transcript, with a key derived from the
+// long-term key.
+func (s *transcriptStore) makeAEAD() (cipher.AEAD, error) {
+	secret, err := s.privKey.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal key: %v", err)
+	}
+
+	key := make([]byte, 32)
+
+	_, err = io.ReadFull(hkdf.New(sha256.New, secret, nil, transcriptInfo), key)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive key: %v", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create block cipher: %v", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create gcm: %v", err)
+	}
+
+	return aead, nil
+}
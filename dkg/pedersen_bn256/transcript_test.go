@@ -0,0 +1,118 @@
+package pedersen
+
+import (
+	"bytes"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/dkg/pedersen_bn256/types"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+func TestTranscriptStore_StoreLoad(t *testing.T) {
+	db := fake.NewInMemoryDB()
+	factory := types.NewMessageFactory(fake.AddressFactory{})
+
+	privKey := suite.Scalar().Pick(suite.RandomStream())
+
+	store := newTranscriptStore(db, privKey, factory)
+
+	_, err := store.Load()
+	require.EqualError(t, err, "no transcript")
+
+	bucket := fake.NewBucket()
+	db.SetBucket(transcriptBucket, bucket)
+
+	_, err = store.Load()
+	require.EqualError(t, err, "no transcript")
+
+	transcript := makeTranscript()
+
+	err = store.Store(transcript)
+	require.NoError(t, err)
+
+	loaded, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, transcript.GetThreshold(), loaded.GetThreshold())
+	require.True(t, transcript.GetDistKey().Equal(loaded.GetDistKey()))
+	require.True(t, transcript.GetShare().V.Equal(loaded.GetShare().V))
+
+	// The share is not written in clear.
+	share, err := transcript.GetShare().V.MarshalBinary()
+	require.NoError(t, err)
+
+	stored := bucket.Get(transcriptKey)
+	require.False(t, bytes.Contains(stored, share))
+
+	// Another long-term key can't read the transcript.
+	other := newTranscriptStore(db, suite.Scalar().Pick(suite.RandomStream()),
+		factory)
+
+	_, err = other.Load()
+	require.EqualError(t, err, "failed to decrypt: cipher: message authentication failed")
+
+	require.NoError(t, bucket.Set(transcriptKey, []byte{1, 2, 3}))
+
+	_, err = store.Load()
+	require.EqualError(t, err, "transcript too short: 3")
+
+	require.NoError(t, store.Store(transcript))
+
+	store.factory = fake.NewBadMessageFactory()
+	_, err = store.Load()
+	require.EqualError(t, err, fake.Err("failed to deserialize"))
+
+	store.factory = fake.MessageFactory{}
+	_, err = store.Load()
+	require.EqualError(t, err, "invalid message 'fake.Message'")
+
+	store.random = iotest.ErrReader(fake.GetError())
+	err = store.Store(transcript)
+	require.EqualError(t, err, fake.Err("failed to generate nonce"))
+
+	store.context = fake.NewBadContext()
+	err = store.Store(transcript)
+	require.EqualError(t, err, "failed to serialize: couldn't encode transcript: "+
+		"format 'FakeBad' is not implemented")
+}
+
+func TestTranscriptStore_NoDB(t *testing.T) {
+	store := newTranscriptStore(nil, nil, nil)
+
+	require.NoError(t, store.Store(types.Transcript{}))
+
+	_, err := store.Load()
+	require.EqualError(t, err, "no database")
+}
+
+func TestTranscriptStore_BadDB(t *testing.T) {
+	store := newTranscriptStore(fake.NewBadUpdateDB(), suite.Scalar(), nil)
+
+	err := store.Store(makeTranscript())
+	require.EqualError(t, err, fake.Err("while updating db"))
+
+	store = newTranscriptStore(fake.NewBadViewDB(), suite.Scalar(), nil)
+
+	_, err = store.Load()
+	require.EqualError(t, err, fake.Err("while reading db"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeTranscript() types.Transcript {
+	point := suite.Point().Pick(suite.RandomStream())
+
+	return types.NewTranscript(
+		2,
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]kyber.Point{point, point},
+		point,
+		[]kyber.Point{point, point},
+		&share.PriShare{I: 1, V: suite.Scalar().Pick(suite.RandomStream())},
+	)
+}
@@ -6,6 +6,7 @@ import (
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/dela/serde/registry"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/tbls"
 	"golang.org/x/xerrors"
 )
@@ -379,7 +380,8 @@ func (s StartDone) Serialize(ctx serde.Context) ([]byte, error) {
 //
 // - implements serde.Message
 type SignRequest struct {
-	msg []byte
+	msg    []byte
+	pubKey []byte
 }
 
 // NewSignRequest creates a new signature request.
@@ -389,11 +391,26 @@ func NewSignRequest(msg []byte) SignRequest {
 	}
 }
 
+// NewEncryptedSignRequest creates a new signature request whose shares must be
+// encrypted under the public key, so that only the requester can read them.
+func NewEncryptedSignRequest(msg, pubKey []byte) SignRequest {
+	return SignRequest{
+		msg:    bytes.Clone(msg),
+		pubKey: bytes.Clone(pubKey),
+	}
+}
+
 // GetMsg returns the message being signed.
 func (req SignRequest) GetMsg() []byte {
 	return bytes.Clone(req.msg)
 }
 
+// GetPublicKey returns the marshaled public key under which the share must be
+// encrypted, or nil if the share is sent in clear.
+func (req SignRequest) GetPublicKey() []byte {
+	return bytes.Clone(req.pubKey)
+}
+
 // Serialize implements serde.Message.
 func (req SignRequest) Serialize(ctx serde.Context) ([]byte, error) {
 	format := msgFormats.Get(ctx.GetFormat())
@@ -432,6 +449,158 @@ func (resp SignReply) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// SignBatchRequest is a message sent to request the threshold signature shares
+// of several messages at once.
+//
+// - implements serde.Message
+type SignBatchRequest struct {
+	msgs   [][]byte
+	pubKey []byte
+}
+
+// NewSignBatchRequest creates a new batch of signature requests whose shares
+// must be encrypted under the public key.
+func NewSignBatchRequest(msgs [][]byte, pubKey []byte) SignBatchRequest {
+	cloned := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		cloned[i] = bytes.Clone(msg)
+	}
+
+	return SignBatchRequest{
+		msgs:   cloned,
+		pubKey: bytes.Clone(pubKey),
+	}
+}
+
+// GetMsgs returns the messages being signed.
+func (req SignBatchRequest) GetMsgs() [][]byte {
+	msgs := make([][]byte, len(req.msgs))
+	for i, msg := range req.msgs {
+		msgs[i] = bytes.Clone(msg)
+	}
+
+	return msgs
+}
+
+// GetPublicKey returns the marshaled public key under which the shares must be
+// encrypted.
+func (req SignBatchRequest) GetPublicKey() []byte {
+	return bytes.Clone(req.pubKey)
+}
+
+// Serialize implements serde.Message.
+func (req SignBatchRequest) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, req)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode sign batch request: %v", err)
+	}
+
+	return data, nil
+}
+
+// SignBatchReply is the response of a batch of signature requests, with the
+// shares in the order of the messages of the request.
+//
+// - implements serde.Message
+type SignBatchReply struct {
+	Shares []tbls.SigShare
+}
+
+// NewSignBatchReply returns a new batch reply.
+func NewSignBatchReply(shares []tbls.SigShare) SignBatchReply {
+	cloned := make([]tbls.SigShare, len(shares))
+	for i, share := range shares {
+		cloned[i] = bytes.Clone(share)
+	}
+
+	return SignBatchReply{
+		Shares: cloned,
+	}
+}
+
+// Serialize implements serde.Message.
+func (resp SignBatchReply) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, resp)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode sign batch reply: %v", err)
+	}
+
+	return data, nil
+}
+
+// Transcript is the outcome of a DKG that a node persists so that it can
+// resume after a restart without a new setup.
+//
+// - implements serde.Message
+type Transcript struct {
+	thres     int
+	addresses []mino.Address
+	pubkeys   []kyber.Point
+	distKey   kyber.Point
+	commits   []kyber.Point
+	share     *share.PriShare
+}
+
+// NewTranscript returns a new transcript.
+func NewTranscript(thres int, addrs []mino.Address, pubkeys []kyber.Point,
+	distKey kyber.Point, commits []kyber.Point, share *share.PriShare) Transcript {
+
+	return Transcript{
+		thres:     thres,
+		addresses: addrs,
+		pubkeys:   pubkeys,
+		distKey:   distKey,
+		commits:   commits,
+		share:     share,
+	}
+}
+
+// GetThreshold returns the threshold.
+func (t Transcript) GetThreshold() int {
+	return t.thres
+}
+
+// GetAddresses returns the list of addresses of the participants.
+func (t Transcript) GetAddresses() []mino.Address {
+	return emptyIfNil(t.addresses)
+}
+
+// GetPublicKeys returns the public keys of the participants.
+func (t Transcript) GetPublicKeys() []kyber.Point {
+	return emptyIfNil(t.pubkeys)
+}
+
+// GetDistKey returns the collective public key.
+func (t Transcript) GetDistKey() kyber.Point {
+	return t.distKey
+}
+
+// GetCommits returns the public commitments of the distributed polynomial.
+func (t Transcript) GetCommits() []kyber.Point {
+	return emptyIfNil(t.commits)
+}
+
+// GetShare returns the private share of the node.
+func (t Transcript) GetShare() *share.PriShare {
+	return t.share
+}
+
+// Serialize implements serde.Message.
+func (t Transcript) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, t)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode transcript: %v", err)
+	}
+
+	return data, nil
+}
+
 // AddrKey is the key for the address factory.
 type AddrKey struct{}
 
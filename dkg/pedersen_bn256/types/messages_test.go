@@ -10,6 +10,8 @@ import (
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/tbls"
 )
 
 var testCalls = &fake.Call{}
@@ -237,6 +239,15 @@ func TestStartDone_Serialize(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't encode ack"))
 }
 
+func TestSignRequest_GetPublicKey(t *testing.T) {
+	req := NewSignRequest([]byte("label"))
+	require.Nil(t, req.GetPublicKey())
+
+	req = NewEncryptedSignRequest([]byte("label"), []byte{1, 2})
+	require.Equal(t, []byte("label"), req.GetMsg())
+	require.Equal(t, []byte{1, 2}, req.GetPublicKey())
+}
+
 func TestSignRequest_Serialize(t *testing.T) {
 	req := SignRequest{}
 
@@ -248,6 +259,66 @@ func TestSignRequest_Serialize(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't encode sign request"))
 }
 
+func TestSignBatchRequest_Getters(t *testing.T) {
+	req := NewSignBatchRequest([][]byte{[]byte("a"), []byte("b")}, []byte{1})
+
+	require.Equal(t, [][]byte{[]byte("a"), []byte("b")}, req.GetMsgs())
+	require.Equal(t, []byte{1}, req.GetPublicKey())
+}
+
+func TestSignBatchRequest_Serialize(t *testing.T) {
+	req := SignBatchRequest{}
+
+	data, err := req.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = req.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode sign batch request"))
+}
+
+func TestSignBatchReply_Serialize(t *testing.T) {
+	resp := NewSignBatchReply([]tbls.SigShare{{1}, {2}})
+	require.Len(t, resp.Shares, 2)
+
+	data, err := resp.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = resp.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode sign batch reply"))
+}
+
+func TestTranscript_Getters(t *testing.T) {
+	priShare := &share.PriShare{I: 1}
+
+	transcript := NewTranscript(2, []mino.Address{fake.NewAddress(0)},
+		[]kyber.Point{fakePoint{}}, fakePoint{}, []kyber.Point{nil, nil}, priShare)
+
+	require.Equal(t, 2, transcript.GetThreshold())
+	require.Len(t, transcript.GetAddresses(), 1)
+	require.Len(t, transcript.GetPublicKeys(), 1)
+	require.Equal(t, fakePoint{}, transcript.GetDistKey())
+	require.Len(t, transcript.GetCommits(), 2)
+	require.Equal(t, priShare, transcript.GetShare())
+
+	transcript = Transcript{}
+	require.NotNil(t, transcript.GetAddresses())
+	require.NotNil(t, transcript.GetPublicKeys())
+	require.NotNil(t, transcript.GetCommits())
+}
+
+func TestTranscript_Serialize(t *testing.T) {
+	transcript := Transcript{}
+
+	data, err := transcript.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = transcript.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode transcript"))
+}
+
 func TestMessageFactory(t *testing.T) {
 	factory := NewMessageFactory(fake.AddressFactory{})
 
@@ -0,0 +1,70 @@
+// Package clock defines an abstraction of the time source so that the logic
+// depending on the time can be tested without waiting.
+package clock
+
+import "time"
+
+// Clock is the source of time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel that receives the current time once the duration
+	// has elapsed.
+	After(d time.Duration) <-chan time.Time
+
+	// NewTicker returns a ticker that sends the current time on its channel
+	// after each period.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+
+	// Stop turns off the ticker. No more ticks are sent after it returns.
+	Stop()
+}
+
+// Real is the clock of the system.
+//
+// - implements clock.Clock
+type Real struct{}
+
+// NewReal returns the clock of the system.
+func NewReal() Real {
+	return Real{}
+}
+
+// Now implements clock.Clock. It returns the local time.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// After implements clock.Clock.
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTicker implements clock.Clock.
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+// realTicker is a wrapper around the ticker of the standard library.
+//
+// - implements clock.Ticker
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+// C implements clock.Ticker.
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+// Stop implements clock.Ticker.
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
@@ -0,0 +1,36 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReal_Now(t *testing.T) {
+	before := time.Now()
+	now := NewReal().Now()
+
+	require.False(t, now.Before(before))
+}
+
+func TestReal_After(t *testing.T) {
+	select {
+	case <-NewReal().After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestReal_NewTicker(t *testing.T) {
+	ticker := NewReal().NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ticker.C():
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
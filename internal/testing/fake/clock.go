@@ -0,0 +1,141 @@
+package fake
+
+import (
+	"sync"
+	"time"
+
+	"go.dedis.ch/dela/internal/clock"
+)
+
+// Clock is a clock that only moves forward when it is told to, so that the
+// timeouts can be triggered by a test without waiting.
+//
+// - implements clock.Clock
+type Clock struct {
+	sync.Mutex
+
+	now     time.Time
+	waiters []*clockWaiter
+}
+
+// clockWaiter is a pending timer or ticker. A ticker has a period and is
+// rescheduled after each tick.
+type clockWaiter struct {
+	deadline time.Time
+	period   time.Duration
+	ch       chan time.Time
+	stopped  bool
+}
+
+// NewClock returns a clock set at the given time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now implements clock.Clock. It returns the time of the clock.
+func (c *Clock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.now
+}
+
+// After implements clock.Clock. The channel receives the time once the clock
+// is advanced past the duration.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.Lock()
+	defer c.Unlock()
+
+	w := &clockWaiter{deadline: c.now.Add(d), ch: make(chan time.Time, 1)}
+
+	if d <= 0 {
+		w.ch <- c.now
+		return w.ch
+	}
+
+	c.waiters = append(c.waiters, w)
+
+	return w.ch
+}
+
+// NewTicker implements clock.Clock. As for the standard library, ticks are
+// dropped when the receiver is too slow.
+func (c *Clock) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	w := &clockWaiter{deadline: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+
+	return clockTicker{clock: c, waiter: w}
+}
+
+// Advance moves the clock forward and fires the timers and tickers that
+// expire in the meantime.
+func (c *Clock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.waiters[:0]
+
+	for _, w := range c.waiters {
+		if w.stopped {
+			continue
+		}
+
+		for !w.deadline.After(c.now) {
+			select {
+			case w.ch <- w.deadline:
+			default:
+			}
+
+			if w.period == 0 {
+				break
+			}
+
+			w.deadline = w.deadline.Add(w.period)
+		}
+
+		if w.period > 0 || w.deadline.After(c.now) {
+			pending = append(pending, w)
+		}
+	}
+
+	c.waiters = pending
+}
+
+// Set moves the clock to the given time. It panics if the time is in the past.
+func (c *Clock) Set(t time.Time) {
+	d := t.Sub(c.Now())
+	if d < 0 {
+		panic("clock can't go backward")
+	}
+
+	c.Advance(d)
+}
+
+// clockTicker is a ticker of the fake clock.
+//
+// - implements clock.Ticker
+type clockTicker struct {
+	clock  *Clock
+	waiter *clockWaiter
+}
+
+// C implements clock.Ticker.
+func (t clockTicker) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+// Stop implements clock.Ticker.
+func (t clockTicker) Stop() {
+	t.clock.Lock()
+	t.waiter.stopped = true
+	t.clock.Unlock()
+}
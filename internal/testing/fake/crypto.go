@@ -1,10 +1,27 @@
 package fake
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
 	"hash"
 
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/xof/blake2xb"
+	"golang.org/x/xerrors"
+)
+
+// schnorrSuite and blsSuite are the suites of the seeded signers.
+var (
+	schnorrSuite = edwards25519.NewBlakeSHA256Ed25519()
+	blsSuite     = pairing.NewSuiteBn256()
 )
 
 // PublicKeyFactory is a fake implementation of a public key factory.
@@ -53,6 +70,7 @@ const SignatureByte = 0xfe
 type Signature struct {
 	crypto.Signature
 	err error
+	mac string
 }
 
 // NewBadSignature returns a signature that will return error when appropriate.
@@ -60,10 +78,11 @@ func NewBadSignature() Signature {
 	return Signature{err: fakeErr}
 }
 
-// Equal implements crypto.Signature.
+// Equal implements crypto.Signature. Signatures of a deterministic signer are
+// equal only if they sign the same message with the same secret.
 func (s Signature) Equal(o crypto.Signature) bool {
-	_, ok := o.(Signature)
-	return ok
+	other, ok := o.(Signature)
+	return ok && other.mac == s.mac
 }
 
 // Serialize implements serde.Message.
@@ -73,6 +92,10 @@ func (s Signature) Serialize(serde.Context) ([]byte, error) {
 
 // MarshalBinary implements crypto.Signature.
 func (s Signature) MarshalBinary() ([]byte, error) {
+	if s.mac != "" {
+		return []byte(s.mac), s.err
+	}
+
 	return []byte{SignatureByte}, s.err
 }
 
@@ -131,6 +154,7 @@ type PublicKey struct {
 	crypto.PublicKey
 	err       error
 	verifyErr error
+	secret    string
 }
 
 // NewBadPublicKey returns a new fake public key that returns error when
@@ -147,9 +171,19 @@ func NewInvalidPublicKey() PublicKey {
 	return PublicKey{verifyErr: fakeErr}
 }
 
-// Verify implements crypto.PublicKey.
-func (pk PublicKey) Verify([]byte, crypto.Signature) error {
-	return pk.verifyErr
+// Verify implements crypto.PublicKey. The public key of a deterministic signer
+// only accepts the signatures of that signer for the given message.
+func (pk PublicKey) Verify(msg []byte, sig crypto.Signature) error {
+	if pk.verifyErr != nil || pk.secret == "" {
+		return pk.verifyErr
+	}
+
+	other, ok := sig.(Signature)
+	if !ok || other.mac != makeMAC(pk.secret, msg) {
+		return fakeErr
+	}
+
+	return nil
 }
 
 // MarshalBinary implements encoding.BinaryMarshaler.
@@ -187,6 +221,7 @@ type Signer struct {
 	signatureFactory SignatureFactory
 	verifierFactory  VerifierFactory
 	err              error
+	secret           string
 }
 
 // NewSigner returns a new instance of the fake signer.
@@ -217,6 +252,17 @@ func NewSignerWithPublicKey(k PublicKey) Signer {
 	return Signer{publicKey: k}
 }
 
+// NewDeterministicSigner returns a fake signer that produces a MAC of the
+// message with the secret as a signature. The signatures are verified by the
+// public key of the signer, so that tests can distinguish signatures over
+// different messages.
+func NewDeterministicSigner(secret string) Signer {
+	return Signer{
+		publicKey: PublicKey{secret: secret},
+		secret:    secret,
+	}
+}
+
 // NewBadSigner returns a fake signer that will return an error when
 // appropriate.
 func NewBadSigner() Signer {
@@ -244,7 +290,11 @@ func (s Signer) GetPublicKey() crypto.PublicKey {
 }
 
 // Sign implements crypto.Signer.
-func (s Signer) Sign([]byte) (crypto.Signature, error) {
+func (s Signer) Sign(msg []byte) (crypto.Signature, error) {
+	if s.secret != "" {
+		return Signature{mac: makeMAC(s.secret, msg)}, s.err
+	}
+
 	return Signature{}, s.err
 }
 
@@ -258,8 +308,10 @@ func (s Signer) Aggregate(...crypto.Signature) (crypto.Signature, error) {
 // - implements crypto.Verifier
 type Verifier struct {
 	crypto.Verifier
-	err   error
-	count *Counter
+	err      error
+	count    *Counter
+	expected []byte
+	Call     *Call
 }
 
 // NewBadVerifier returns a verifier that will return an error when appropriate.
@@ -276,8 +328,24 @@ func NewBadVerifierWithDelay(value int) Verifier {
 	}
 }
 
+// NewVerifierWithExpectedMessage returns a verifier that returns an error
+// unless the message to verify is equal to the expected one. The calls are
+// recorded with the message and the signature.
+func NewVerifierWithExpectedMessage(msg []byte) Verifier {
+	return Verifier{
+		expected: msg,
+		Call:     NewCall(),
+	}
+}
+
 // Verify implements crypto.Verifier.
 func (v Verifier) Verify(msg []byte, s crypto.Signature) error {
+	v.Call.Add(msg, s)
+
+	if v.expected != nil && !bytes.Equal(v.expected, msg) {
+		return xerrors.Errorf("unexpected message %#x != %#x", msg, v.expected)
+	}
+
 	if !v.count.Done() {
 		v.count.Decrease()
 		return nil
@@ -405,3 +473,240 @@ func NewHashFactory(h *Hash) HashFactory {
 func (f HashFactory) New() hash.Hash {
 	return f.hash
 }
+
+// RecordingHashFactory is a hash factory that creates actual SHA-256 hashes
+// that record their inputs.
+//
+// - implements crypto.HashFactory
+type RecordingHashFactory struct {
+	Call *Call
+}
+
+// NewRecordingHashFactory returns a new recording hash factory.
+func NewRecordingHashFactory() RecordingHashFactory {
+	return RecordingHashFactory{Call: NewCall()}
+}
+
+// New implements crypto.HashFactory.
+func (f RecordingHashFactory) New() hash.Hash {
+	return recordingHash{
+		Hash: sha256.New(),
+		call: f.Call,
+	}
+}
+
+// recordingHash is a SHA-256 hash that records a copy of the inputs of Write.
+//
+// - implements hash.Hash
+type recordingHash struct {
+	hash.Hash
+	call *Call
+}
+
+// Write implements hash.Hash.
+func (h recordingHash) Write(in []byte) (int, error) {
+	h.call.Add(append([]byte{}, in...))
+
+	return h.Hash.Write(in)
+}
+
+func makeMAC(secret string, msg []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(msg)
+
+	return string(h.Sum(nil))
+}
+
+// SeededSigner is a signer with a real Kyber key pair derived from a seed, so
+// that tests get the same keys on every run. It produces either Schnorr
+// signatures on Ed25519 or BLS signatures on BN256.
+//
+// - implements crypto.Signer
+// - implements encoding.BinaryMarshaler
+type SeededSigner struct {
+	bls     bool
+	private kyber.Scalar
+	public  kyber.Point
+}
+
+// NewSeededSigner returns a signer that produces Schnorr signatures with a key
+// pair on Ed25519 derived from the seed.
+func NewSeededSigner(seed []byte) SeededSigner {
+	private := schnorrSuite.Scalar().Pick(blake2xb.New(seed))
+
+	return SeededSigner{
+		private: private,
+		public:  schnorrSuite.Point().Mul(private, nil),
+	}
+}
+
+// NewSeededBLSSigner returns a signer that produces BLS signatures with a key
+// pair on BN256 derived from the seed.
+func NewSeededBLSSigner(seed []byte) SeededSigner {
+	private := blsSuite.G2().Scalar().Pick(blake2xb.New(seed))
+
+	return SeededSigner{
+		bls:     true,
+		private: private,
+		public:  blsSuite.G2().Point().Mul(private, nil),
+	}
+}
+
+// NewSeededGenSigner returns a generator of seeded signers for the authorities.
+// The n-th signer is derived from the seed followed by n, so that every member
+// has its own key but keeps it across runs.
+func NewSeededGenSigner(seed []byte) GenSigner {
+	counter := 0
+
+	return func() crypto.Signer {
+		signer := NewSeededSigner(append(append([]byte{}, seed...), byte(counter)))
+		counter++
+
+		return signer
+	}
+}
+
+// GetPublicKeyFactory implements crypto.Signer.
+func (s SeededSigner) GetPublicKeyFactory() crypto.PublicKeyFactory {
+	return PublicKeyFactory{}
+}
+
+// GetSignatureFactory implements crypto.Signer.
+func (s SeededSigner) GetSignatureFactory() crypto.SignatureFactory {
+	return SignatureFactory{}
+}
+
+// GetPublicKey implements crypto.Signer.
+func (s SeededSigner) GetPublicKey() crypto.PublicKey {
+	return SeededPublicKey{bls: s.bls, point: s.public}
+}
+
+// Sign implements crypto.Signer.
+func (s SeededSigner) Sign(msg []byte) (crypto.Signature, error) {
+	var sig []byte
+	var err error
+
+	if s.bls {
+		sig, err = bls.Sign(blsSuite, s.private, msg)
+	} else {
+		sig, err = schnorr.Sign(schnorrSuite, s.private, msg)
+	}
+
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't sign: %v", err)
+	}
+
+	return SeededSignature{data: sig}, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the private
+// key, which can be restored by the signer of the real scheme.
+func (s SeededSigner) MarshalBinary() ([]byte, error) {
+	return s.private.MarshalBinary()
+}
+
+// SeededPublicKey is the public key of a seeded signer.
+//
+// - implements crypto.PublicKey
+type SeededPublicKey struct {
+	bls   bool
+	point kyber.Point
+}
+
+// GetPoint returns the point of the public key.
+func (pk SeededPublicKey) GetPoint() kyber.Point {
+	return pk.point
+}
+
+// Verify implements crypto.PublicKey.
+func (pk SeededPublicKey) Verify(msg []byte, sig crypto.Signature) error {
+	signature, ok := sig.(SeededSignature)
+	if !ok {
+		return xerrors.Errorf("invalid signature type '%T'", sig)
+	}
+
+	var err error
+
+	if pk.bls {
+		err = bls.Verify(blsSuite, pk.point, msg, signature.data)
+	} else {
+		err = schnorr.Verify(schnorrSuite, pk.point, msg, signature.data)
+	}
+
+	if err != nil {
+		return xerrors.Errorf("couldn't verify: %v", err)
+	}
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the same bytes
+// as the public key of the real scheme.
+func (pk SeededPublicKey) MarshalBinary() ([]byte, error) {
+	return pk.point.MarshalBinary()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (pk SeededPublicKey) MarshalText() ([]byte, error) {
+	buffer, err := pk.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't marshal: %v", err)
+	}
+
+	prefix := "schnorr"
+	if pk.bls {
+		prefix = "bls"
+	}
+
+	return []byte(fmt.Sprintf("%s:%x", prefix, buffer)), nil
+}
+
+// Equal implements crypto.PublicKey.
+func (pk SeededPublicKey) Equal(other interface{}) bool {
+	pubkey, ok := other.(SeededPublicKey)
+	return ok && pubkey.bls == pk.bls && pubkey.point.Equal(pk.point)
+}
+
+// Serialize implements serde.Message.
+func (pk SeededPublicKey) Serialize(serde.Context) ([]byte, error) {
+	return pk.MarshalBinary()
+}
+
+// String implements fmt.Stringer.
+func (pk SeededPublicKey) String() string {
+	buffer, err := pk.MarshalText()
+	if err != nil {
+		return "fake.SeededPublicKey"
+	}
+
+	return string(buffer)
+}
+
+// SeededSignature is a signature of a seeded signer.
+//
+// - implements crypto.Signature
+type SeededSignature struct {
+	data []byte
+}
+
+// Equal implements crypto.Signature.
+func (s SeededSignature) Equal(o crypto.Signature) bool {
+	other, ok := o.(SeededSignature)
+	return ok && bytes.Equal(other.data, s.data)
+}
+
+// Serialize implements serde.Message.
+func (s SeededSignature) Serialize(serde.Context) ([]byte, error) {
+	return s.data, nil
+}
+
+// MarshalBinary implements crypto.Signature. It returns the same bytes as the
+// signature of the real scheme.
+func (s SeededSignature) MarshalBinary() ([]byte, error) {
+	return s.data, nil
+}
+
+// String implements fmt.Stringer.
+func (s SeededSignature) String() string {
+	return fmt.Sprintf("fake.SeededSignature[%x]", s.data)
+}
@@ -0,0 +1,68 @@
+package fake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeededSigner(t *testing.T) {
+	signer := NewSeededSigner([]byte("seed"))
+	other := NewSeededSigner([]byte("seed"))
+
+	// The same seed gives the same key pair.
+	require.True(t, signer.GetPublicKey().Equal(other.GetPublicKey()))
+	require.False(t, signer.GetPublicKey().Equal(NewSeededSigner([]byte("other")).GetPublicKey()))
+	require.Regexp(t, "^schnorr:[0-9a-f]{64}$", signer.GetPublicKey().(SeededPublicKey).String())
+
+	data, err := signer.MarshalBinary()
+	require.NoError(t, err)
+
+	otherData, err := other.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, data, otherData)
+
+	// Schnorr signatures use a random nonce, but the signature of a signer is
+	// valid for the key of the other.
+	sig, err := signer.Sign([]byte("message"))
+	require.NoError(t, err)
+	require.NoError(t, other.GetPublicKey().Verify([]byte("message"), sig))
+	require.Error(t, other.GetPublicKey().Verify([]byte("other"), sig))
+
+	err = signer.GetPublicKey().Verify([]byte("message"), Signature{})
+	require.EqualError(t, err, "invalid signature type 'fake.Signature'")
+}
+
+func TestSeededBLSSigner(t *testing.T) {
+	signer := NewSeededBLSSigner([]byte("seed"))
+	other := NewSeededBLSSigner([]byte("seed"))
+
+	require.True(t, signer.GetPublicKey().Equal(other.GetPublicKey()))
+	require.False(t, signer.GetPublicKey().Equal(NewSeededSigner([]byte("seed")).GetPublicKey()))
+	require.Regexp(t, "^bls:[0-9a-f]+$", signer.GetPublicKey().(SeededPublicKey).String())
+
+	// BLS signatures are deterministic, so the same seed gives the same
+	// signatures.
+	sig, err := signer.Sign([]byte("message"))
+	require.NoError(t, err)
+
+	otherSig, err := other.Sign([]byte("message"))
+	require.NoError(t, err)
+	require.True(t, sig.Equal(otherSig))
+	require.NoError(t, other.GetPublicKey().Verify([]byte("message"), sig))
+	require.Error(t, other.GetPublicKey().Verify([]byte("other"), sig))
+}
+
+func TestSeededGenSigner(t *testing.T) {
+	ca := NewAuthority(3, NewSeededGenSigner([]byte("seed")))
+	other := NewAuthority(3, NewSeededGenSigner([]byte("seed")))
+
+	// Every member has its own key, which is the same across runs.
+	for i := 0; i < 3; i++ {
+		require.True(t, ca.GetSigner(i).GetPublicKey().Equal(other.GetSigner(i).GetPublicKey()))
+	}
+
+	require.False(t, ca.GetSigner(0).GetPublicKey().Equal(ca.GetSigner(1).GetPublicKey()))
+	require.True(t, ca.GetSigner(0).GetPublicKey().Equal(
+		NewSeededSigner([]byte("seed\x00")).GetPublicKey()))
+}
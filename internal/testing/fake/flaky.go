@@ -0,0 +1,151 @@
+package fake
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+)
+
+// flakiness decides if a call fails, with a probability p. The random source is
+// seeded so that a failing run can be reproduced.
+type flakiness struct {
+	sync.Mutex
+	rand *rand.Rand
+	p    float64
+}
+
+func newFlakiness(p float64, seed int64) *flakiness {
+	return &flakiness{
+		rand: rand.New(rand.NewSource(seed)),
+		p:    p,
+	}
+}
+
+func (f *flakiness) fail() bool {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.rand.Float64() < f.p
+}
+
+// FlakySender is a sender that fails randomly.
+//
+// - implements mino.Sender
+type FlakySender struct {
+	mino.Sender
+	flaky *flakiness
+}
+
+// Flaky returns a sender that fails with the probability p, or forwards the
+// message to the given sender.
+func Flaky(sender mino.Sender, p float64, seed int64) FlakySender {
+	return FlakySender{
+		Sender: sender,
+		flaky:  newFlakiness(p, seed),
+	}
+}
+
+// Send implements mino.Sender.
+func (s FlakySender) Send(msg serde.Message, addrs ...mino.Address) <-chan error {
+	if s.flaky.fail() {
+		errs := make(chan error, 1)
+		errs <- fakeErr
+		close(errs)
+
+		return errs
+	}
+
+	return s.Sender.Send(msg, addrs...)
+}
+
+// FlakyReceiver is a receiver that fails randomly.
+//
+// - implements mino.Receiver
+type FlakyReceiver struct {
+	mino.Receiver
+	flaky *flakiness
+}
+
+// NewFlakyReceiver returns a receiver that fails with the probability p, or
+// returns the next message of the given receiver.
+func NewFlakyReceiver(r mino.Receiver, p float64, seed int64) FlakyReceiver {
+	return FlakyReceiver{
+		Receiver: r,
+		flaky:    newFlakiness(p, seed),
+	}
+}
+
+// Recv implements mino.Receiver.
+func (r FlakyReceiver) Recv(ctx context.Context) (mino.Address, serde.Message, error) {
+	if r.flaky.fail() {
+		return nil, nil, fakeErr
+	}
+
+	return r.Receiver.Recv(ctx)
+}
+
+// FlakyDB is a database that fails randomly to open transactions.
+//
+// - implements kv.DB
+type FlakyDB struct {
+	kv.DB
+	flaky *flakiness
+}
+
+// NewFlakyDB returns a database that fails with the probability p, or opens
+// the transaction on the given database.
+func NewFlakyDB(db kv.DB, p float64, seed int64) FlakyDB {
+	return FlakyDB{
+		DB:    db,
+		flaky: newFlakiness(p, seed),
+	}
+}
+
+// View implements kv.DB.
+func (db FlakyDB) View(fn func(kv.ReadableTx) error) error {
+	if db.flaky.fail() {
+		return fakeErr
+	}
+
+	return db.DB.View(fn)
+}
+
+// Update implements kv.DB.
+func (db FlakyDB) Update(fn func(kv.WritableTx) error) error {
+	if db.flaky.fail() {
+		return fakeErr
+	}
+
+	return db.DB.Update(fn)
+}
+
+// FlakySigner is a signer that fails randomly to sign.
+//
+// - implements crypto.Signer
+type FlakySigner struct {
+	crypto.Signer
+	flaky *flakiness
+}
+
+// NewFlakySigner returns a signer that fails with the probability p, or signs
+// with the given signer.
+func NewFlakySigner(signer crypto.Signer, p float64, seed int64) FlakySigner {
+	return FlakySigner{
+		Signer: signer,
+		flaky:  newFlakiness(p, seed),
+	}
+}
+
+// Sign implements crypto.Signer.
+func (s FlakySigner) Sign(msg []byte) (crypto.Signature, error) {
+	if s.flaky.fail() {
+		return nil, fakeErr
+	}
+
+	return s.Signer.Sign(msg)
+}
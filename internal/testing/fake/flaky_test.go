@@ -0,0 +1,71 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/store/kv"
+)
+
+func TestFlaky_Seed(t *testing.T) {
+	// failures returns which of the calls fail.
+	failures := func(call func() error) []bool {
+		res := make([]bool, 32)
+		for i := range res {
+			res[i] = call() != nil
+		}
+
+		return res
+	}
+
+	send := func(s FlakySender) func() error {
+		return func() error { return <-s.Send(Message{}) }
+	}
+
+	view := func(db FlakyDB) func() error {
+		return func() error {
+			return db.View(func(kv.ReadableTx) error { return nil })
+		}
+	}
+
+	sign := func(s FlakySigner) func() error {
+		return func() error {
+			_, err := s.Sign(nil)
+			return err
+		}
+	}
+
+	// The same seed fails the same calls, so that a run can be replayed.
+	expected := failures(send(Flaky(Sender{}, 0.5, 1)))
+	require.Contains(t, expected, true)
+	require.Contains(t, expected, false)
+
+	require.Equal(t, expected, failures(send(Flaky(Sender{}, 0.5, 1))))
+	require.NotEqual(t, expected, failures(send(Flaky(Sender{}, 0.5, 2))))
+
+	require.Equal(t, expected, failures(view(NewFlakyDB(NewInMemoryDB(), 0.5, 1))))
+	require.Equal(t, expected, failures(sign(NewFlakySigner(NewSigner(), 0.5, 1))))
+
+	require.NotContains(t, failures(send(Flaky(Sender{}, 0, 1))), true)
+	require.NotContains(t, failures(sign(NewFlakySigner(NewSigner(), 1, 1))), false)
+
+	err := NewFlakyDB(NewInMemoryDB(), 1, 1).Update(func(kv.WritableTx) error {
+		return nil
+	})
+	require.Equal(t, fakeErr, err)
+}
+
+func TestFlakyReceiver_Recv(t *testing.T) {
+	r := NewFlakyReceiver(NewReceiver(NewRecvMsg(NewAddress(0), Message{})), 1, 1)
+
+	_, _, err := r.Recv(context.Background())
+	require.Equal(t, fakeErr, err)
+
+	r = NewFlakyReceiver(NewReceiver(NewRecvMsg(NewAddress(0), Message{})), 0, 1)
+
+	from, msg, err := r.Recv(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, NewAddress(0), from)
+	require.Equal(t, Message{}, msg)
+}
@@ -0,0 +1,106 @@
+package fake
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// LogEntry is an entry recorded by the logger.
+type LogEntry struct {
+	Level   zerolog.Level
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Logger records the entries of a zerolog logger, so that a test can check
+// that a path has been logged with the expected level and fields.
+//
+// - implements io.Writer
+type Logger struct {
+	sync.Mutex
+
+	entries []LogEntry
+}
+
+// NewLogger returns a new empty logger.
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+// GetLogger returns a zerolog logger that records its entries in the logger.
+// It can replace the logger of a component or the global one of Dela.
+func (l *Logger) GetLogger() zerolog.Logger {
+	return zerolog.New(l)
+}
+
+// Write implements io.Writer. It parses the JSON event of zerolog and records
+// it.
+func (l *Logger) Write(data []byte) (int, error) {
+	fields := make(map[string]interface{})
+
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return 0, err
+	}
+
+	entry := LogEntry{
+		Level:  zerolog.NoLevel,
+		Fields: fields,
+	}
+
+	level, ok := fields[zerolog.LevelFieldName].(string)
+	if ok {
+		entry.Level, _ = zerolog.ParseLevel(level)
+		delete(fields, zerolog.LevelFieldName)
+	}
+
+	msg, ok := fields[zerolog.MessageFieldName].(string)
+	if ok {
+		entry.Message = msg
+		delete(fields, zerolog.MessageFieldName)
+	}
+
+	l.Lock()
+	l.entries = append(l.entries, entry)
+	l.Unlock()
+
+	return len(data), nil
+}
+
+// GetEntries returns the entries recorded so far.
+func (l *Logger) GetEntries() []LogEntry {
+	l.Lock()
+	defer l.Unlock()
+
+	return append([]LogEntry{}, l.entries...)
+}
+
+// Find returns the entries of the level with the message.
+func (l *Logger) Find(level zerolog.Level, msg string) []LogEntry {
+	l.Lock()
+	defer l.Unlock()
+
+	var found []LogEntry
+
+	for _, entry := range l.entries {
+		if entry.Level == level && entry.Message == msg {
+			found = append(found, entry)
+		}
+	}
+
+	return found
+}
+
+// Has returns true if an entry of the level has the message.
+func (l *Logger) Has(level zerolog.Level, msg string) bool {
+	return len(l.Find(level, msg)) > 0
+}
+
+// Reset removes the entries recorded so far.
+func (l *Logger) Reset() {
+	l.Lock()
+	l.entries = nil
+	l.Unlock()
+}
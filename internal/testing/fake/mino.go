@@ -150,6 +150,9 @@ type CollectiveAuthority struct {
 	crypto.CollectiveAuthority
 	addrs   []mino.Address
 	signers []crypto.Signer
+	// weights is the voting weight of each member, or nil when every member
+	// has a weight of 1.
+	weights []int
 
 	Call           *Call
 	PubkeyNotFound bool
@@ -212,18 +215,97 @@ func (ca CollectiveAuthority) GetSigner(index int) crypto.Signer {
 	return ca.signers[index]
 }
 
+// GetWeight returns the voting weight of the member at the provided index.
+func (ca CollectiveAuthority) GetWeight(index int) int {
+	if ca.weights == nil {
+		return 1
+	}
+
+	return ca.weights[index]
+}
+
+// TotalWeight returns the sum of the voting weights of the members.
+func (ca CollectiveAuthority) TotalWeight() int {
+	total := 0
+	for i := range ca.signers {
+		total += ca.GetWeight(i)
+	}
+
+	return total
+}
+
+// SetWeight sets the voting weight of the member with the address. It returns
+// false if the address is not a member.
+func (ca *CollectiveAuthority) SetWeight(addr mino.Address, weight int) bool {
+	index := ca.indexOf(addr)
+	if index < 0 {
+		return false
+	}
+
+	if ca.weights == nil {
+		ca.weights = make([]int, len(ca.signers))
+		for i := range ca.weights {
+			ca.weights[i] = 1
+		}
+	}
+
+	ca.weights[index] = weight
+
+	return true
+}
+
+// Add appends a member to the authority with a weight of 1.
+func (ca *CollectiveAuthority) Add(addr mino.Address, signer crypto.Signer) {
+	ca.addrs = append(ca.addrs, addr)
+	ca.signers = append(ca.signers, signer)
+
+	if ca.weights != nil {
+		ca.weights = append(ca.weights, 1)
+	}
+}
+
+// Remove removes the member with the address from the authority. It returns
+// false if the address is not a member.
+func (ca *CollectiveAuthority) Remove(addr mino.Address) bool {
+	index := ca.indexOf(addr)
+	if index < 0 {
+		return false
+	}
+
+	// The slices are copied so that the authorities created before, for
+	// instance with Take, are not modified.
+	ca.addrs = append(append([]mino.Address{}, ca.addrs[:index]...), ca.addrs[index+1:]...)
+	ca.signers = append(append([]crypto.Signer{}, ca.signers[:index]...), ca.signers[index+1:]...)
+
+	if ca.weights != nil {
+		ca.weights = append(append([]int{}, ca.weights[:index]...), ca.weights[index+1:]...)
+	}
+
+	return true
+}
+
+func (ca CollectiveAuthority) indexOf(addr mino.Address) int {
+	for i, address := range ca.addrs {
+		if address.Equal(addr) {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // GetPublicKey implements crypto.CollectiveAuthority.
 func (ca CollectiveAuthority) GetPublicKey(addr mino.Address) (crypto.PublicKey, int) {
 	if ca.PubkeyNotFound {
 		return nil, -1
 	}
 
-	for i, address := range ca.addrs {
-		if address.Equal(addr) {
-			return ca.signers[i].GetPublicKey(), i
-		}
+	index := ca.indexOf(addr)
+	if index < 0 {
+		return nil, -1
 	}
-	return nil, -1
+
+	return ca.signers[index].GetPublicKey(), index
 }
 
 // Take implements mino.Players.
@@ -234,9 +316,15 @@ func (ca CollectiveAuthority) Take(updaters ...mino.FilterUpdater) mino.Players
 		addrs:   make([]mino.Address, len(filter.Indices)),
 		signers: make([]crypto.Signer, len(filter.Indices)),
 	}
+	if ca.weights != nil {
+		newCA.weights = make([]int, len(filter.Indices))
+	}
 	for i, k := range filter.Indices {
 		newCA.addrs[i] = ca.addrs[k]
 		newCA.signers[i] = ca.signers[k]
+		if newCA.weights != nil {
+			newCA.weights[i] = ca.weights[k]
+		}
 	}
 	return newCA
 }
@@ -363,6 +451,7 @@ type RPC struct {
 	receiver *Receiver
 	sender   Sender
 	err      error
+	onStream func(mino.Players) (mino.Sender, mino.Receiver)
 }
 
 // NewRPC returns a fake rpc.
@@ -391,6 +480,13 @@ func NewBadRPC() *RPC {
 	return rpc
 }
 
+// OnStream sets a function called for every new stream to create its sender
+// and receiver, so that concurrent streams can be scripted independently. It
+// replaces the sender and the receiver given to the constructor.
+func (rpc *RPC) OnStream(fn func(mino.Players) (mino.Sender, mino.Receiver)) {
+	rpc.onStream = fn
+}
+
 // SendResponse fills the rpc with a message.
 func (rpc *RPC) SendResponse(from mino.Address, msg serde.Message) {
 	rpc.msgs <- mino.NewResponse(from, msg)
@@ -419,6 +515,11 @@ func (rpc *RPC) Call(ctx context.Context,
 func (rpc *RPC) Stream(ctx context.Context, p mino.Players) (mino.Sender, mino.Receiver, error) {
 	rpc.Calls.Add(ctx, p)
 
+	if rpc.onStream != nil && rpc.err == nil {
+		sender, receiver := rpc.onStream(p)
+		return sender, receiver, nil
+	}
+
 	return rpc.sender, rpc.receiver, rpc.err
 }
 
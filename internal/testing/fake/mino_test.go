@@ -0,0 +1,60 @@
+package fake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/mino"
+)
+
+func TestCollectiveAuthority_Weights(t *testing.T) {
+	ca := NewAuthority(4, NewSigner)
+
+	// quorum returns true if the members at the indices hold more than two
+	// thirds of the total weight.
+	quorum := func(ca CollectiveAuthority, indices ...int) bool {
+		weight := 0
+		for _, index := range indices {
+			weight += ca.GetWeight(index)
+		}
+
+		return 3*weight > 2*ca.TotalWeight()
+	}
+
+	require.Equal(t, 1, ca.GetWeight(0))
+	require.Equal(t, 4, ca.TotalWeight())
+	require.False(t, quorum(ca, 0, 1))
+	require.True(t, quorum(ca, 0, 1, 2))
+
+	// A heavy member reaches the quorum with a single other one.
+	require.True(t, ca.SetWeight(NewAddress(0), 5))
+	require.False(t, ca.SetWeight(NewAddress(4), 5))
+	require.Equal(t, 5, ca.GetWeight(0))
+	require.Equal(t, 1, ca.GetWeight(1))
+	require.Equal(t, 8, ca.TotalWeight())
+	require.True(t, quorum(ca, 0, 1))
+	require.False(t, quorum(ca, 1, 2, 3))
+
+	// Take keeps the weights of the members it selects.
+	sub := ca.Take(mino.IndexFilter(0), mino.IndexFilter(2)).(CollectiveAuthority)
+	require.Equal(t, 5, sub.GetWeight(0))
+	require.Equal(t, 1, sub.GetWeight(1))
+	require.Equal(t, 6, sub.TotalWeight())
+	require.True(t, quorum(sub, 0))
+
+	// A new member has a weight of 1, and a removed one takes its weight
+	// away.
+	ca.Add(NewAddress(4), NewSigner())
+	require.Equal(t, 1, ca.GetWeight(4))
+	require.Equal(t, 9, ca.TotalWeight())
+
+	require.True(t, ca.Remove(NewAddress(0)))
+	require.False(t, ca.Remove(NewAddress(0)))
+	require.Equal(t, 4, ca.TotalWeight())
+	require.False(t, quorum(ca, 0, 1))
+	require.True(t, quorum(ca, 0, 1, 2))
+
+	// The authority taken before is not modified.
+	require.Equal(t, NewAddress(0), sub.GetAddress(0))
+	require.Equal(t, 6, sub.TotalWeight())
+}
@@ -7,6 +7,7 @@ package fake
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"fmt"
 	"io"
@@ -46,7 +47,9 @@ func Err(msg string) string {
 // Call is a tool to keep track of a function calls.
 type Call struct {
 	sync.Mutex
-	calls [][]interface{}
+	calls  [][]interface{}
+	notify chan struct{}
+	hooks  map[int][]func()
 }
 
 // NewCall returns a new empty call monitor.
@@ -66,6 +69,18 @@ func (c *Call) Get(n, i int) interface{} {
 	return c.calls[n][i]
 }
 
+// GetAll returns the parameters of the nth call.
+func (c *Call) GetAll(n int) []interface{} {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	return append([]interface{}{}, c.calls[n]...)
+}
+
 // Len returns the number of calls.
 func (c *Call) Len() int {
 	if c == nil {
@@ -88,6 +103,57 @@ func (c *Call) Add(args ...interface{}) {
 	defer c.Unlock()
 
 	c.calls = append(c.calls, args)
+
+	if c.notify != nil {
+		close(c.notify)
+		c.notify = nil
+	}
+
+	for _, fn := range c.hooks[len(c.calls)] {
+		fn()
+	}
+}
+
+// WaitFor waits until at least n calls are recorded, or returns an error when
+// the timeout is reached first.
+func (c *Call) WaitFor(n int, timeout time.Duration) error {
+	if c == nil {
+		return xerrors.New("no call monitor")
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		c.Lock()
+
+		num := len(c.calls)
+		if num >= n {
+			c.Unlock()
+			return nil
+		}
+
+		if c.notify == nil {
+			c.notify = make(chan struct{})
+		}
+
+		notify := c.notify
+
+		c.Unlock()
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			return xerrors.Errorf("timeout after %v: got %d calls instead of %d",
+				timeout, num, n)
+		}
+	}
+}
+
+// Assert fails the test if the nth call doesn't have the given parameters.
+func (c *Call) Assert(t *testing.T, n int, args ...interface{}) {
+	require.Greater(t, c.Len(), n, "call %d is missing", n)
+	require.Equal(t, args, c.GetAll(n), "call %d", n)
 }
 
 // Clear clears the array of calls.
@@ -99,6 +165,42 @@ func (c *Call) Clear() {
 	}
 }
 
+// ContextWithImmediateCancel returns a context that is already canceled.
+func ContextWithImmediateCancel() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	return ctx
+}
+
+// ContextCancelAfterCalls returns a context that is canceled when the nth call
+// is recorded, before the call returns, so that the code under test observes
+// the cancellation right after the nth call. Without a call monitor, the
+// context is only canceled by the returned function.
+func ContextCancelAfterCalls(call *Call, n int) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if call == nil {
+		return ctx, cancel
+	}
+
+	call.Lock()
+	defer call.Unlock()
+
+	if len(call.calls) >= n {
+		cancel()
+		return ctx, cancel
+	}
+
+	if call.hooks == nil {
+		call.hooks = make(map[int][]func())
+	}
+
+	call.hooks[n] = append(call.hooks[n], cancel)
+
+	return ctx, cancel
+}
+
 // Counter is a helper to delay errors or actions. It can be nil without panics.
 type Counter struct {
 	Value int
@@ -0,0 +1,33 @@
+package fake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextCancelAfterCalls(t *testing.T) {
+	call := &Call{}
+
+	ctx, cancel := ContextCancelAfterCalls(call, 2)
+	defer cancel()
+
+	call.Add()
+	require.NoError(t, ctx.Err())
+
+	call.Add()
+	require.Error(t, ctx.Err())
+
+	// The context is canceled right away when the calls already happened.
+	ctx, cancel = ContextCancelAfterCalls(call, 1)
+	defer cancel()
+
+	require.Error(t, ctx.Err())
+
+	// Without a call monitor, only the cancel function ends the context.
+	ctx, cancel = ContextCancelAfterCalls(nil, 0)
+	require.NoError(t, ctx.Err())
+
+	cancel()
+	require.Error(t, ctx.Err())
+}
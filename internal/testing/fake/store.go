@@ -57,9 +57,11 @@ func (snap *InMemorySnapshot) Delete(key []byte) error {
 //
 // - implements kv.DB
 type InMemoryDB struct {
-	buckets map[string]*Bucket
-	err     error
-	errView error
+	buckets   map[string]*Bucket
+	err       error
+	errView   error
+	errUpdate error
+	counter   *Counter
 }
 
 // NewInMemoryDB returns a new empty database.
@@ -86,6 +88,34 @@ func NewBadViewDB() *InMemoryDB {
 	return db
 }
 
+// NewBadViewDBWithDelay returns a new database that fails to open view
+// transactions after some calls.
+func NewBadViewDBWithDelay(delay int) *InMemoryDB {
+	db := NewInMemoryDB()
+	db.errView = fakeErr
+	db.counter = NewCounter(delay)
+
+	return db
+}
+
+// NewBadUpdateDB returns a new database that fails to open update
+// transactions.
+func NewBadUpdateDB() *InMemoryDB {
+	db := NewInMemoryDB()
+	db.errUpdate = fakeErr
+
+	return db
+}
+
+// NewBadUpdateDBWithDelay returns a new database that fails to open update
+// transactions after some calls.
+func NewBadUpdateDBWithDelay(delay int) *InMemoryDB {
+	db := NewBadUpdateDB()
+	db.counter = NewCounter(delay)
+
+	return db
+}
+
 // SetBucket allows to define a bucket in the database.
 func (db *InMemoryDB) SetBucket(name []byte, b *Bucket) {
 	db.buckets[string(name)] = b
@@ -93,15 +123,27 @@ func (db *InMemoryDB) SetBucket(name []byte, b *Bucket) {
 
 // View implements kv.DB.
 func (db *InMemoryDB) View(fn func(tx kv.ReadableTx) error) error {
-	if db.errView != nil {
+	if db.errView != nil && db.counter.Done() {
 		return db.errView
 	}
 
+	if db.errView != nil {
+		db.counter.Decrease()
+	}
+
 	return fn(dbTx{buckets: db.buckets, err: db.err})
 }
 
 // Update implements kv.DB.
 func (db *InMemoryDB) Update(fn func(tx kv.WritableTx) error) error {
+	if db.errUpdate != nil && db.counter.Done() {
+		return db.errUpdate
+	}
+
+	if db.errUpdate != nil {
+		db.counter.Decrease()
+	}
+
 	return fn(dbTx{buckets: db.buckets, err: db.err})
 }
 
@@ -142,6 +184,9 @@ func (dbTx) OnCommit(fn func()) {}
 type Bucket struct {
 	kv.Bucket
 
+	// Call records the key and the value of every write.
+	Call *Call
+
 	values     map[string][]byte
 	errSet     error
 	errDelete  error
@@ -186,6 +231,7 @@ func (b *Bucket) Get(key []byte) []byte {
 
 // Set implements kv.Bucket.
 func (b *Bucket) Set(key, value []byte) error {
+	b.Call.Add(key, value)
 	b.values[string(key)] = value
 
 	return b.errSet
@@ -0,0 +1,241 @@
+// Package bounded implements a sender that queues the messages of each
+// destination in a bounded queue.
+//
+// A stream sender usually spawns a routine for every message, so that a node
+// sending faster than a destination can receive accumulates routines until it
+// runs out of memory. The bounded sender instead keeps a queue per
+// destination, and a single routine per destination that sends the messages
+// of the queue one after the other. When a queue is full, either the new
+// message or the oldest one of the queue is dropped, according to the policy,
+// and the caller is notified with an error.
+package bounded
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// DefaultCapacity is the default maximum number of messages waiting for a
+// destination.
+const DefaultCapacity = 100
+
+// defines prometheus metrics
+var (
+	promDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dela_mino_queue_depth",
+		Help: "number of messages waiting to be sent to a destination",
+	}, []string{"destination"})
+
+	promDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dela_mino_queue_dropped",
+		Help: "total number of messages dropped because of a full queue",
+	})
+)
+
+func init() {
+	dela.PromCollectors = append(dela.PromCollectors, promDepth, promDropped)
+}
+
+// Policy defines which message is dropped when a queue is full.
+type Policy int
+
+const (
+	// DropNewest refuses the new message when the queue is full.
+	DropNewest Policy = iota
+
+	// DropOldest drops the oldest message of the queue to make room for the
+	// new one.
+	DropOldest
+)
+
+// Option is the type of option to set some fields of a sender.
+type Option func(*Sender)
+
+// WithCapacity sets the maximum number of messages waiting for a destination.
+// It must be at least one.
+func WithCapacity(capacity int) Option {
+	return func(s *Sender) {
+		s.capacity = capacity
+	}
+}
+
+// WithPolicy sets the message dropped when a queue is full.
+func WithPolicy(policy Policy) Option {
+	return func(s *Sender) {
+		s.policy = policy
+	}
+}
+
+// Sender is a decorator of a sender that sends the messages of a destination
+// one after the other, from a bounded queue.
+//
+// - implements mino.Sender
+type Sender struct {
+	sync.Mutex
+
+	sender   mino.Sender
+	capacity int
+	policy   Policy
+	queues   map[string]*queue
+}
+
+// NewSender creates a new bounded sender on top of the given sender.
+func NewSender(sender mino.Sender, opts ...Option) *Sender {
+	s := &Sender{
+		sender:   sender,
+		capacity: DefaultCapacity,
+		policy:   DropNewest,
+		queues:   make(map[string]*queue),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Depth returns the number of messages waiting to be sent to the address.
+func (s *Sender) Depth(addr mino.Address) int {
+	s.Lock()
+	defer s.Unlock()
+
+	q := s.queues[addr.String()]
+	if q == nil {
+		return 0
+	}
+
+	return len(q.items)
+}
+
+// Send implements mino.Sender. It queues the message for each address and
+// returns a channel populated with at most one error per address. The channel
+// is closed once the message is sent, or dropped, for every address.
+func (s *Sender) Send(msg serde.Message, addrs ...mino.Address) <-chan error {
+	c := &call{
+		pending: int32(len(addrs)),
+		errs:    make(chan error, len(addrs)),
+	}
+
+	if len(addrs) == 0 {
+		close(c.errs)
+		return c.errs
+	}
+
+	for _, addr := range addrs {
+		s.push(addr, item{msg: msg, call: c})
+	}
+
+	return c.errs
+}
+
+func (s *Sender) push(addr mino.Address, it item) {
+	key := addr.String()
+
+	s.Lock()
+
+	q := s.queues[key]
+	if q == nil {
+		q = &queue{addr: addr}
+		s.queues[key] = q
+	}
+
+	var dropped *item
+
+	if len(q.items) >= s.capacity {
+		if s.policy != DropOldest {
+			s.Unlock()
+
+			promDropped.Inc()
+			it.call.done(xerrors.Errorf("queue of %v is full", addr))
+
+			return
+		}
+
+		dropped = &q.items[0]
+		q.items = q.items[1:]
+	}
+
+	q.items = append(q.items, it)
+	promDepth.WithLabelValues(key).Set(float64(len(q.items)))
+
+	if !q.running {
+		q.running = true
+		go s.drain(key, q)
+	}
+
+	s.Unlock()
+
+	if dropped != nil {
+		promDropped.Inc()
+		dropped.call.done(xerrors.Errorf("message to %v dropped for a newer one", addr))
+	}
+}
+
+// drain sends the messages of the queue until it is empty. The queue is then
+// removed so that the idle destinations do not use any memory.
+func (s *Sender) drain(key string, q *queue) {
+	for {
+		s.Lock()
+
+		if len(q.items) == 0 {
+			q.running = false
+			delete(s.queues, key)
+			s.Unlock()
+
+			return
+		}
+
+		it := q.items[0]
+		q.items = q.items[1:]
+		promDepth.WithLabelValues(key).Set(float64(len(q.items)))
+
+		s.Unlock()
+
+		var first error
+
+		for err := range s.sender.Send(it.msg, q.addr) {
+			if first == nil {
+				first = err
+			}
+		}
+
+		it.call.done(first)
+	}
+}
+
+// queue is the list of messages waiting for a destination.
+type queue struct {
+	addr    mino.Address
+	items   []item
+	running bool
+}
+
+// item is a message waiting in a queue, with the call it belongs to.
+type item struct {
+	msg  serde.Message
+	call *call
+}
+
+// call is a single send to several addresses, which is done once the message
+// is sent, or dropped, for every address.
+type call struct {
+	pending int32
+	errs    chan error
+}
+
+func (c *call) done(err error) {
+	if err != nil {
+		c.errs <- err
+	}
+
+	if atomic.AddInt32(&c.pending, -1) == 0 {
+		close(c.errs)
+	}
+}
@@ -0,0 +1,190 @@
+package bounded
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+)
+
+func TestSender_Send(t *testing.T) {
+	out := newGateSender()
+	close(out.gate)
+
+	s := NewSender(out)
+
+	errs := s.Send(fakeMsg{id: 1}, fake.NewAddress(0), fake.NewAddress(1))
+	require.Empty(t, drain(t, errs))
+	require.Len(t, out.sent, 2)
+
+	errs = s.Send(fakeMsg{id: 2})
+	require.Empty(t, drain(t, errs))
+
+	// Idle destinations do not keep a queue.
+	require.Eventually(t, func() bool {
+		s.Lock()
+		defer s.Unlock()
+
+		return len(s.queues) == 0
+	}, time.Second, time.Millisecond)
+
+	s = NewSender(fake.NewBadSender())
+
+	errs = s.Send(fakeMsg{}, fake.NewAddress(0), fake.NewAddress(1))
+	require.Equal(t, []error{fake.GetError(), fake.GetError()}, drain(t, errs))
+}
+
+func TestSender_DropNewest(t *testing.T) {
+	out := newGateSender()
+
+	s := NewSender(out, WithCapacity(2))
+
+	addr := fake.NewAddress(0)
+
+	// The first message is taken by the routine of the destination, which is
+	// blocked until the gate opens.
+	first := s.Send(fakeMsg{id: 0}, addr)
+	waitDepth(t, s, addr, 0)
+
+	second := s.Send(fakeMsg{id: 1}, addr)
+	third := s.Send(fakeMsg{id: 2}, addr)
+	require.Equal(t, 2, s.Depth(addr))
+
+	errs := s.Send(fakeMsg{id: 3}, addr)
+	require.Equal(t, []string{"queue of fake.Address[0] is full"}, messages(drain(t, errs)))
+	require.Equal(t, 2, s.Depth(addr))
+
+	close(out.gate)
+
+	require.Empty(t, drain(t, first))
+	require.Empty(t, drain(t, second))
+	require.Empty(t, drain(t, third))
+
+	require.Equal(t, []int{0, 1, 2}, out.ids(3))
+}
+
+func TestSender_DropOldest(t *testing.T) {
+	out := newGateSender()
+
+	s := NewSender(out, WithCapacity(2), WithPolicy(DropOldest))
+
+	addr := fake.NewAddress(0)
+
+	first := s.Send(fakeMsg{id: 0}, addr)
+	waitDepth(t, s, addr, 0)
+
+	second := s.Send(fakeMsg{id: 1}, addr)
+	third := s.Send(fakeMsg{id: 2}, addr)
+	fourth := s.Send(fakeMsg{id: 3}, addr)
+	require.Equal(t, 2, s.Depth(addr))
+
+	require.Equal(t, []string{"message to fake.Address[0] dropped for a newer one"},
+		messages(drain(t, second)))
+
+	close(out.gate)
+
+	require.Empty(t, drain(t, first))
+	require.Empty(t, drain(t, third))
+	require.Empty(t, drain(t, fourth))
+
+	require.Equal(t, []int{0, 2, 3}, out.ids(3))
+}
+
+func TestSender_Independent(t *testing.T) {
+	out := newGateSender()
+
+	s := NewSender(out, WithCapacity(1))
+
+	// A full queue does not prevent the messages to the other destinations.
+	s.Send(fakeMsg{}, fake.NewAddress(0))
+	waitDepth(t, s, fake.NewAddress(0), 0)
+	s.Send(fakeMsg{}, fake.NewAddress(0))
+
+	errs := s.Send(fakeMsg{}, fake.NewAddress(0), fake.NewAddress(1))
+	require.Equal(t, 1, s.Depth(fake.NewAddress(1)))
+
+	close(out.gate)
+
+	require.Equal(t, []string{"queue of fake.Address[0] is full"}, messages(drain(t, errs)))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func drain(t *testing.T, errs <-chan error) []error {
+	var res []error
+
+	timeout := time.After(time.Second)
+
+	for {
+		select {
+		case err, more := <-errs:
+			if !more {
+				return res
+			}
+
+			res = append(res, err)
+		case <-timeout:
+			t.Fatal("channel not closed")
+		}
+	}
+}
+
+func waitDepth(t *testing.T, s *Sender, addr mino.Address, depth int) {
+	require.Eventually(t, func() bool {
+		return s.Depth(addr) == depth
+	}, time.Second, time.Millisecond)
+}
+
+func messages(errs []error) []string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return msgs
+}
+
+// gateSender is a sender that blocks until the gate is closed.
+type gateSender struct {
+	gate chan struct{}
+	sent chan serde.Message
+}
+
+func newGateSender() gateSender {
+	return gateSender{
+		gate: make(chan struct{}),
+		sent: make(chan serde.Message, 100),
+	}
+}
+
+func (s gateSender) Send(msg serde.Message, addrs ...mino.Address) <-chan error {
+	<-s.gate
+
+	s.sent <- msg
+
+	errs := make(chan error)
+	close(errs)
+
+	return errs
+}
+
+func (s gateSender) ids(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = (<-s.sent).(fakeMsg).id
+	}
+
+	return ids
+}
+
+type fakeMsg struct {
+	id int
+}
+
+func (fakeMsg) Serialize(serde.Context) ([]byte, error) {
+	return nil, nil
+}
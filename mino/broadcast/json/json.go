@@ -0,0 +1,94 @@
+package json
+
+import (
+	"encoding/json"
+
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/broadcast/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+func init() {
+	types.RegisterMessageFormat(serde.FormatJSON, msgFormat{})
+}
+
+// MessageJSON is the JSON representation of a broadcast message.
+type MessageJSON struct {
+	Message json.RawMessage
+	Subtree [][]byte
+}
+
+// MsgFormat is the format engine to encode and decode broadcast messages.
+//
+// - implements serde.FormatEngine
+type msgFormat struct{}
+
+// Encode implements serde.FormatEngine. It returns the JSON data of the message
+// if appropriate, otherwise an error.
+func (msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	in, ok := msg.(types.Message)
+	if !ok {
+		return nil, xerrors.Errorf("unsupported message '%T'", msg)
+	}
+
+	payload, err := in.GetMessage().Serialize(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize message: %v", err)
+	}
+
+	subtree := make([][]byte, len(in.GetSubtree()))
+
+	for i, addr := range in.GetSubtree() {
+		subtree[i], err = addr.MarshalText()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal address: %v", err)
+		}
+	}
+
+	m := MessageJSON{
+		Message: payload,
+		Subtree: subtree,
+	}
+
+	data, err := ctx.Marshal(m)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal failed: %v", err)
+	}
+
+	return data, nil
+}
+
+// Decode implements serde.FormatEngine. It returns the message associated to
+// the data if appropriate, otherwise an error.
+func (msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	m := MessageJSON{}
+	err := ctx.Unmarshal(data, &m)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal failed: %v", err)
+	}
+
+	factory := ctx.GetFactory(types.AddrKey{})
+
+	addrFac, ok := factory.(mino.AddressFactory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid address factory '%T'", factory)
+	}
+
+	msgFac := ctx.GetFactory(types.MsgKey{})
+	if msgFac == nil {
+		return nil, xerrors.New("missing message factory")
+	}
+
+	msg, err := msgFac.Deserialize(ctx, m.Message)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize message: %v", err)
+	}
+
+	subtree := make([]mino.Address, len(m.Subtree))
+	for i, raw := range m.Subtree {
+		subtree[i] = addrFac.FromText(raw)
+	}
+
+	return types.NewMessage(msg, subtree...), nil
+}
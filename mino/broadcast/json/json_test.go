@@ -0,0 +1,62 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino/broadcast/types"
+	"go.dedis.ch/dela/serde"
+)
+
+func TestMsgFormat_Encode(t *testing.T) {
+	format := msgFormat{}
+
+	ctx := fake.NewContext()
+
+	msg := types.NewMessage(fake.Message{}, fake.NewAddress(1))
+
+	data, err := format.Encode(ctx, msg)
+	require.NoError(t, err)
+	require.Equal(t, `{"Message":{},"Subtree":["AQAAAA=="]}`, string(data))
+
+	_, err = format.Encode(ctx, fake.Message{})
+	require.EqualError(t, err, "unsupported message 'fake.Message'")
+
+	_, err = format.Encode(fake.NewBadContext(), msg)
+	require.EqualError(t, err, fake.Err("failed to serialize message"))
+
+	_, err = format.Encode(ctx, types.NewMessage(fake.Message{}, fake.NewBadAddress()))
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+
+	_, err = format.Encode(fake.NewBadContextWithDelay(1), msg)
+	require.EqualError(t, err, fake.Err("marshal failed"))
+}
+
+func TestMsgFormat_Decode(t *testing.T) {
+	format := msgFormat{}
+
+	ctx := fake.NewContext()
+	ctx = serde.WithFactory(ctx, types.AddrKey{}, fake.AddressFactory{})
+	ctx = serde.WithFactory(ctx, types.MsgKey{}, fake.MessageFactory{})
+
+	msg, err := format.Decode(ctx, []byte(`{"Message":{},"Subtree":["AQAAAA=="]}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewMessage(fake.Message{}, fake.NewAddress(1)), msg)
+
+	_, err = format.Decode(fake.NewBadContext(), []byte(`{}`))
+	require.EqualError(t, err, fake.Err("unmarshal failed"))
+
+	_, err = format.Decode(fake.NewContext(), []byte(`{}`))
+	require.EqualError(t, err, "invalid address factory '<nil>'")
+
+	badCtx := serde.WithFactory(fake.NewContext(), types.AddrKey{}, fake.AddressFactory{})
+
+	_, err = format.Decode(badCtx, []byte(`{}`))
+	require.EqualError(t, err, "missing message factory")
+
+	badCtx = serde.WithFactory(badCtx, types.MsgKey{}, fake.NewBadMessageFactory())
+
+	_, err = format.Decode(badCtx, []byte(`{}`))
+	require.EqualError(t, err, fake.Err("failed to deserialize message"))
+}
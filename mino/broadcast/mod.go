@@ -0,0 +1,227 @@
+// Package broadcast implements a broadcast along a tree of the participants on
+// top of an RPC.
+//
+// Instead of contacting every participant, the node that starts a broadcast
+// splits the participants in as many groups as the fanout, and sends the
+// message to the first participant of each group with the rest of the group as
+// its subtree. A participant delivers the message to its handler, then does
+// the same with its subtree. A node therefore sends at most fanout messages
+// per broadcast, whatever the number of participants.
+//
+// When a participant does not reply, the next participant of its group takes
+// its place, so that a failed node does not cut off its subtree.
+package broadcast
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/broadcast/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultFanout = 3
+
+	// forwardTimeout is the time a node has to forward a message to its
+	// subtree.
+	forwardTimeout = 10 * time.Second
+)
+
+// Option is the type of option to set some fields of a tree.
+type Option func(*Tree)
+
+// WithFanout sets the maximum number of participants a node sends a message
+// to. It must be at least one.
+func WithFanout(fanout int) Option {
+	return func(t *Tree) {
+		t.fanout = fanout
+	}
+}
+
+// Tree is a broadcast that forwards the messages along a tree of the
+// participants.
+type Tree struct {
+	fanout int
+	h      mino.Handler
+	rpc    mino.RPC
+	logger zerolog.Logger
+}
+
+// NewTree creates a new broadcast for the RPC of the name. The messages are
+// deserialized with the factory and delivered to the handler, with the
+// address of the node that forwarded it.
+func NewTree(m mino.Mino, name string, h mino.Handler, f serde.Factory, opts ...Option) *Tree {
+	t := &Tree{
+		fanout: defaultFanout,
+		h:      h,
+		logger: dela.Logger.With().Str("addr", m.GetAddress().String()).Logger(),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if t.fanout < 1 {
+		t.fanout = 1
+	}
+
+	fac := types.NewMessageFactory(m.GetAddressFactory(), f)
+
+	t.rpc = mino.MustCreateRPC(m, name, handler{Tree: t}, fac)
+
+	return t
+}
+
+// Broadcast sends the message to the players. It returns an error with the
+// players that could not be reached, if any.
+func (t *Tree) Broadcast(ctx context.Context, msg serde.Message, players mino.Players) error {
+	addrs := make([]mino.Address, 0, players.Len())
+
+	iter := players.AddressIterator()
+	for iter.HasNext() {
+		addrs = append(addrs, iter.GetNext())
+	}
+
+	unreachable := t.spread(ctx, msg, addrs)
+	if len(unreachable) > 0 {
+		return xerrors.Errorf("failed to reach %v", unreachable)
+	}
+
+	return nil
+}
+
+// spread sends the message to one participant of each group of the addresses,
+// and returns the participants that could not be reached.
+func (t *Tree) spread(ctx context.Context, msg serde.Message, addrs []mino.Address) []mino.Address {
+	groups := split(addrs, t.fanout)
+	failed := make([][]mino.Address, len(groups))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(groups))
+
+	for i, group := range groups {
+		go func(i int, group []mino.Address) {
+			defer wg.Done()
+
+			failed[i] = t.send(ctx, msg, group)
+		}(i, group)
+	}
+
+	wg.Wait()
+
+	var unreachable []mino.Address
+	for _, addrs := range failed {
+		unreachable = append(unreachable, addrs...)
+	}
+
+	return unreachable
+}
+
+// send sends the message to the first participant of the group, which is
+// responsible for the rest of the group. If it fails, the next participant
+// takes its place. It returns the participants that failed.
+func (t *Tree) send(ctx context.Context, msg serde.Message, group []mino.Address) []mino.Address {
+	var failed []mino.Address
+
+	for len(group) > 0 {
+		child := group[0]
+		group = group[1:]
+
+		err := t.call(ctx, child, types.NewMessage(msg, group...))
+		if err == nil {
+			return failed
+		}
+
+		t.logger.Warn().Err(err).Stringer("to", child).Msg("rerouting broadcast")
+
+		failed = append(failed, child)
+	}
+
+	return failed
+}
+
+func (t *Tree) call(ctx context.Context, to mino.Address, msg types.Message) error {
+	resps, err := t.rpc.Call(ctx, msg, mino.NewAddresses(to))
+	if err != nil {
+		return xerrors.Errorf("failed to call: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return xerrors.Errorf("context done: %v", ctx.Err())
+	case resp, more := <-resps:
+		if !more {
+			return xerrors.New("no reply")
+		}
+
+		_, err = resp.GetMessageOrError()
+		if err != nil {
+			return xerrors.Errorf("failed to send: %v", err)
+		}
+
+		return nil
+	}
+}
+
+// split splits the addresses in at most n groups of similar sizes.
+func split(addrs []mino.Address, n int) [][]mino.Address {
+	if len(addrs) < n {
+		n = len(addrs)
+	}
+
+	groups := make([][]mino.Address, 0, n)
+
+	for i := 0; i < n; i++ {
+		start := i * len(addrs) / n
+		end := (i + 1) * len(addrs) / n
+
+		groups = append(groups, addrs[start:end])
+	}
+
+	return groups
+}
+
+// handler delivers the messages of a broadcast and forwards them to the
+// subtree of the node.
+//
+// - implements mino.Handler
+type handler struct {
+	*Tree
+	mino.UnsupportedHandler
+}
+
+// Process implements mino.Handler. It delivers the message to the handler of
+// the broadcast, then forwards it to the subtree. It replies once the subtree
+// is done, so that the sender does not reroute the message while it is being
+// forwarded.
+func (h handler) Process(req mino.Request) (serde.Message, error) {
+	msg, ok := req.Message.(types.Message)
+	if !ok {
+		return nil, xerrors.Errorf("unsupported message '%T'", req.Message)
+	}
+
+	_, err := h.h.Process(mino.Request{
+		Address: req.Address,
+		Message: msg.GetMessage(),
+	})
+	if err != nil {
+		h.logger.Warn().Err(err).Stringer("from", req.Address).
+			Msg("failed to process broadcast")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), forwardTimeout)
+	defer cancel()
+
+	unreachable := h.spread(ctx, msg.GetMessage(), msg.GetSubtree())
+	if len(unreachable) > 0 {
+		h.logger.Warn().Msgf("broadcast failed to reach %v", unreachable)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,201 @@
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/broadcast/types"
+	"go.dedis.ch/dela/mino/minoch"
+	"go.dedis.ch/dela/serde"
+)
+
+func TestTree_Broadcast(t *testing.T) {
+	minos, trees, recorders := makeTrees(t, 20, WithFanout(2))
+
+	players := mino.NewAddresses(addresses(minos)[1:]...)
+
+	err := trees[0].Broadcast(context.Background(), fake.Message{}, players)
+	require.NoError(t, err)
+	require.Empty(t, recorders[0].get())
+
+	sent := make(map[string]int)
+
+	for i, rec := range recorders[1:] {
+		from := rec.get()
+		require.Len(t, from, 1, "node %d", i+1)
+
+		sent[from[0].String()]++
+	}
+
+	// No node, and the root in particular, sends more than the fanout.
+	for _, n := range sent {
+		require.LessOrEqual(t, n, 2)
+	}
+
+	require.Equal(t, 2, sent[minos[0].GetAddress().String()])
+}
+
+func TestTree_Broadcast_Reroute(t *testing.T) {
+	minos, trees, recorders := makeTrees(t, 10, WithFanout(2))
+
+	// The first node of each group drops the messages, so that the next one
+	// must take its place.
+	minos[0].AddFilter(func(mino.Request) bool { return false })
+	minos[5].AddFilter(func(mino.Request) bool { return false })
+
+	players := mino.NewAddresses(addresses(minos)...)
+
+	err := trees[1].Broadcast(context.Background(), fake.Message{}, players)
+	require.EqualError(t, err,
+		fmt.Sprintf("failed to reach [%v %v]", minos[0].GetAddress(), minos[5].GetAddress()))
+
+	for i, rec := range recorders {
+		if i == 0 || i == 5 {
+			require.Empty(t, rec.get())
+		} else {
+			require.Len(t, rec.get(), 1, "node %d", i)
+		}
+	}
+}
+
+func TestTree_Broadcast_DefaultFanout(t *testing.T) {
+	minos, trees, recorders := makeTrees(t, 1, WithFanout(0))
+	require.Equal(t, 1, trees[0].fanout)
+
+	players := mino.NewAddresses(minos[0].GetAddress())
+
+	err := trees[0].Broadcast(context.Background(), fake.Message{}, players)
+	require.NoError(t, err)
+	require.Len(t, recorders[0].get(), 1)
+}
+
+func TestTree_Call(t *testing.T) {
+	tree := &Tree{rpc: fake.NewBadRPC()}
+
+	err := tree.call(context.Background(), fake.NewAddress(0), types.NewMessage(fake.Message{}))
+	require.EqualError(t, err, fake.Err("failed to call"))
+
+	rpc := fake.NewRPC()
+	rpc.SendResponseWithError(fake.NewAddress(0), fake.GetError())
+	tree.rpc = rpc
+
+	err = tree.call(context.Background(), fake.NewAddress(0), types.NewMessage(fake.Message{}))
+	require.EqualError(t, err, fake.Err("failed to send"))
+
+	rpc = fake.NewRPC()
+	rpc.Done()
+	tree.rpc = rpc
+
+	err = tree.call(context.Background(), fake.NewAddress(0), types.NewMessage(fake.Message{}))
+	require.EqualError(t, err, "no reply")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tree.rpc = fake.NewRPC()
+
+	err = tree.call(ctx, fake.NewAddress(0), types.NewMessage(fake.Message{}))
+	require.EqualError(t, err, "context done: context canceled")
+}
+
+func TestSplit(t *testing.T) {
+	addrs := []mino.Address{
+		fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2),
+		fake.NewAddress(3), fake.NewAddress(4),
+	}
+
+	groups := split(addrs, 2)
+	require.Equal(t, [][]mino.Address{addrs[:2], addrs[2:]}, groups)
+
+	groups = split(addrs, 10)
+	require.Len(t, groups, 5)
+
+	require.Empty(t, split(nil, 3))
+}
+
+func TestHandler_Process(t *testing.T) {
+	rec := &recorder{}
+	h := handler{Tree: &Tree{h: rec}}
+
+	_, err := h.Process(mino.Request{Message: fake.Message{}})
+	require.EqualError(t, err, "unsupported message 'fake.Message'")
+
+	logger := fake.NewLogger()
+	h.logger = logger.GetLogger()
+	h.h = badHandler{}
+
+	resp, err := h.Process(mino.Request{Address: fake.NewAddress(1), Message: types.NewMessage(fake.Message{})})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+	require.Len(t, logger.GetEntries(), 1)
+	require.Equal(t, "failed to process broadcast", logger.GetEntries()[0].Message)
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+// recorder is a handler that records the address of the messages it receives.
+//
+// - implements mino.Handler
+type recorder struct {
+	mino.UnsupportedHandler
+
+	sync.Mutex
+	from []mino.Address
+}
+
+func (r *recorder) Process(req mino.Request) (serde.Message, error) {
+	r.Lock()
+	r.from = append(r.from, req.Address)
+	r.Unlock()
+
+	return nil, nil
+}
+
+func (r *recorder) get() []mino.Address {
+	r.Lock()
+	defer r.Unlock()
+
+	return append([]mino.Address{}, r.from...)
+}
+
+// badHandler is a handler that fails to process the messages.
+//
+// - implements mino.Handler
+type badHandler struct {
+	mino.UnsupportedHandler
+}
+
+func (badHandler) Process(mino.Request) (serde.Message, error) {
+	return nil, fake.GetError()
+}
+
+func makeTrees(t *testing.T, n int, opts ...Option) ([]*minoch.Minoch, []*Tree, []*recorder) {
+	manager := minoch.NewManager()
+
+	minos := make([]*minoch.Minoch, n)
+	trees := make([]*Tree, n)
+	recorders := make([]*recorder, n)
+
+	for i := range minos {
+		minos[i] = minoch.MustCreate(manager, fmt.Sprintf("node%d", i))
+		recorders[i] = &recorder{}
+		trees[i] = NewTree(minos[i], "test", recorders[i], fake.MessageFactory{}, opts...)
+	}
+
+	return minos, trees, recorders
+}
+
+func addresses(minos []*minoch.Minoch) []mino.Address {
+	addrs := make([]mino.Address, len(minos))
+	for i, m := range minos {
+		addrs[i] = m.GetAddress()
+	}
+
+	return addrs
+}
@@ -0,0 +1,98 @@
+// Package types implements the message that carries a broadcast along a tree.
+//
+// The message has been implemented in this isolated package so that it does
+// not create cycle imports when importing the serde formats.
+package types
+
+import (
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/registry"
+	"golang.org/x/xerrors"
+)
+
+var msgFormats = registry.NewSimpleRegistry()
+
+// RegisterMessageFormat registers the engine for the provided format.
+func RegisterMessageFormat(c serde.Format, f serde.FormatEngine) {
+	msgFormats.Register(c, f)
+}
+
+// Message is the message sent to a node of the tree. It contains the message of
+// the broadcast and the subtree the node is responsible for.
+//
+// - implements serde.Message
+type Message struct {
+	msg     serde.Message
+	subtree []mino.Address
+}
+
+// NewMessage creates a new message to forward to the subtree.
+func NewMessage(msg serde.Message, subtree ...mino.Address) Message {
+	return Message{
+		msg:     msg,
+		subtree: subtree,
+	}
+}
+
+// GetMessage returns the message of the broadcast.
+func (m Message) GetMessage() serde.Message {
+	return m.msg
+}
+
+// GetSubtree returns the addresses the receiver must forward the message to.
+func (m Message) GetSubtree() []mino.Address {
+	return append([]mino.Address{}, m.subtree...)
+}
+
+// Serialize implements serde.Message. It returns the serialized data of the
+// message.
+func (m Message) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, m)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return data, nil
+}
+
+// AddrKey is the key of the address factory.
+type AddrKey struct{}
+
+// MsgKey is the key of the factory of the message of the broadcast.
+type MsgKey struct{}
+
+// MessageFactory is the factory to deserialize the messages of a broadcast.
+//
+// - implements serde.Factory
+type MessageFactory struct {
+	addrFac mino.AddressFactory
+	msgFac  serde.Factory
+}
+
+// NewMessageFactory creates a new message factory. The message of the
+// broadcast is deserialized with the given factory.
+func NewMessageFactory(addrFac mino.AddressFactory, msgFac serde.Factory) MessageFactory {
+	return MessageFactory{
+		addrFac: addrFac,
+		msgFac:  msgFac,
+	}
+}
+
+// Deserialize implements serde.Factory. It returns the message of the data if
+// appropriate, otherwise an error.
+func (f MessageFactory) Deserialize(ctx serde.Context, data []byte) (serde.Message, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	ctx = serde.WithFactory(ctx, AddrKey{}, f.addrFac)
+	ctx = serde.WithFactory(ctx, MsgKey{}, f.msgFac)
+
+	msg, err := format.Decode(ctx, data)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	return msg, nil
+}
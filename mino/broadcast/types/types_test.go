@@ -0,0 +1,43 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func init() {
+	RegisterMessageFormat(fake.GoodFormat, fake.Format{Msg: Message{}})
+	RegisterMessageFormat(fake.BadFormat, fake.NewBadFormat())
+}
+
+func TestMessage_Getters(t *testing.T) {
+	msg := NewMessage(fake.Message{}, fake.NewAddress(1), fake.NewAddress(2))
+
+	require.Equal(t, fake.Message{}, msg.GetMessage())
+	require.Len(t, msg.GetSubtree(), 2)
+	require.Equal(t, fake.NewAddress(2), msg.GetSubtree()[1])
+}
+
+func TestMessage_Serialize(t *testing.T) {
+	msg := NewMessage(fake.Message{})
+
+	data, err := msg.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = msg.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("failed to encode"))
+}
+
+func TestMessageFactory_Deserialize(t *testing.T) {
+	fac := NewMessageFactory(fake.AddressFactory{}, fake.MessageFactory{})
+
+	msg, err := fac.Deserialize(fake.NewContext(), nil)
+	require.NoError(t, err)
+	require.Equal(t, Message{}, msg)
+
+	_, err = fac.Deserialize(fake.NewBadContext(), nil)
+	require.EqualError(t, err, fake.Err("failed to decode"))
+}
@@ -24,20 +24,39 @@ const (
 // communication approach by sending a rumor to all the known participants.
 //
 // - implements gossip.Gossiper
+// - implements gossip.Reporter
 type Flat struct {
 	sync.RWMutex
 	mino         mino.Mino
 	rumorFactory serde.Factory
 	ch           chan Rumor
+	scores       *Scores
+}
+
+// FlatOption is the type of option to set some fields of a flat gossiper.
+type FlatOption func(*Flat)
+
+// WithScores is an option to score the peers and ban temporarily the ones that
+// send too many rumors, or too many invalid ones.
+func WithScores(cfg ScoreConfig) FlatOption {
+	return func(flat *Flat) {
+		flat.scores = NewScores(cfg)
+	}
 }
 
 // NewFlat creates a new instance of a flat gossip protocol.
-func NewFlat(m mino.Mino, f serde.Factory) *Flat {
-	return &Flat{
+func NewFlat(m mino.Mino, f serde.Factory, opts ...FlatOption) *Flat {
+	flat := &Flat{
 		mino:         m,
 		rumorFactory: f,
 		ch:           make(chan Rumor, 100),
 	}
+
+	for _, opt := range opts {
+		opt(flat)
+	}
+
+	return flat
 }
 
 // Listen implements gossip.Gossiper. It creates the RPC and starts to listen
@@ -59,6 +78,14 @@ func (flat *Flat) Rumors() <-chan Rumor {
 	return flat.ch
 }
 
+// Report implements gossip.Reporter. It updates the score of the peer that sent
+// the rumor, if the peers are scored.
+func (flat *Flat) Report(rumor Rumor, valid bool) {
+	if flat.scores != nil {
+		flat.scores.Report(rumor, valid)
+	}
+}
+
 // flatActor is the actor returned by the gossiper that provide the primitives
 // to send a rumor.
 //
@@ -137,6 +164,10 @@ func (h handler) Process(req mino.Request) (serde.Message, error) {
 		return nil, xerrors.Errorf("unexpected rumor of type '%T'", req.Message)
 	}
 
+	if h.scores != nil && !h.scores.Received(req.Address, rumor) {
+		return nil, xerrors.Errorf("rumor dropped from %v", req.Address)
+	}
+
 	h.ch <- rumor
 
 	return nil, nil
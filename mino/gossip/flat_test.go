@@ -3,6 +3,7 @@ package gossip
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -89,6 +90,34 @@ func TestHandler_Process(t *testing.T) {
 	h.rumorFactory = fake.MessageFactory{}
 	_, err = h.Process(mino.Request{Message: fake.Message{}})
 	require.EqualError(t, err, "unexpected rumor of type 'fake.Message'")
+
+	h.scores = NewScores(ScoreConfig{MaxRate: 0, Window: time.Second})
+	_, err = h.Process(mino.Request{Address: fake.NewAddress(0), Message: fakeRumor{}})
+	require.EqualError(t, err, "rumor dropped from fake.Address[0]")
+}
+
+func TestFlat_Report(t *testing.T) {
+	gossiper := NewFlat(nil, nil)
+
+	// Nothing happens when the peers are not scored.
+	gossiper.Report(fakeRumor{}, false)
+
+	gossiper = NewFlat(nil, nil, WithScores(ScoreConfig{
+		MaxRate:    10,
+		Window:     time.Second,
+		MinReports: 10,
+	}))
+
+	addr := fake.NewAddress(0)
+
+	h := handler{Flat: gossiper}
+	gossiper.ch = make(chan Rumor, 1)
+
+	_, err := h.Process(mino.Request{Address: addr, Message: fakeRumor{}})
+	require.NoError(t, err)
+
+	gossiper.Report(fakeRumor{}, false)
+	require.Equal(t, 1, gossiper.scores.Get(addr).Invalid)
 }
 
 // -----------------------------------------------------------------------------
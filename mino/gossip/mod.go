@@ -42,3 +42,10 @@ type Gossiper interface {
 	// Listen starts to listen for rumors and returns a gossip actor.
 	Listen() (Actor, error)
 }
+
+// Reporter is implemented by the gossipers that score the peers from the
+// validity of the rumors they send.
+type Reporter interface {
+	// Report tells if the rumor was valid once it has been processed.
+	Report(rumor Rumor, valid bool)
+}
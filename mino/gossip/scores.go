@@ -0,0 +1,236 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/internal/clock"
+	"go.dedis.ch/dela/mino"
+)
+
+// maxOrigins is the number of rumors for which the sender is remembered so
+// that a report can be attributed.
+const maxOrigins = 10000
+
+// defines prometheus metrics
+var (
+	promInvalidRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dela_gossip_peer_invalid_ratio",
+		Help: "ratio of invalid rumors sent by a peer",
+	}, []string{"peer"})
+
+	promBans = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dela_gossip_peer_bans",
+		Help: "total number of temporary bans of peers",
+	})
+
+	promDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dela_gossip_dropped_rumors",
+		Help: "total number of rumors dropped from banned peers",
+	})
+)
+
+func init() {
+	dela.PromCollectors = append(dela.PromCollectors, promInvalidRatio,
+		promBans, promDropped)
+}
+
+// ScoreConfig is the set of parameters that decide when a peer is banned.
+type ScoreConfig struct {
+	// MaxRate is the maximum number of rumors a peer can send during a window
+	// before being banned.
+	MaxRate int
+
+	// Window is the duration of the window of the rate.
+	Window time.Duration
+
+	// MaxInvalidRatio is the maximum ratio of invalid rumors a peer can send
+	// before being banned.
+	MaxInvalidRatio float64
+
+	// MinReports is the number of reports required before the ratio of
+	// invalid rumors is considered.
+	MinReports int
+
+	// BanDuration is the duration of a ban.
+	BanDuration time.Duration
+}
+
+// DefaultScoreConfig is a configuration that only bans peers that obviously
+// misbehave.
+var DefaultScoreConfig = ScoreConfig{
+	MaxRate:         1000,
+	Window:          time.Second,
+	MaxInvalidRatio: 0.5,
+	MinReports:      20,
+	BanDuration:     time.Minute,
+}
+
+// PeerScore is the score of a peer.
+type PeerScore struct {
+	// Rumors is the number of rumors received in the current window.
+	Rumors int
+
+	// Valid and Invalid are the numbers of rumors reported since the last ban.
+	Valid   int
+	Invalid int
+
+	// BannedUntil is the end of the ban, or zero if the peer was never banned.
+	BannedUntil time.Time
+}
+
+// Scores keeps the score of the peers from the rate and the validity of their
+// rumors, and bans temporarily the ones that misbehave.
+type Scores struct {
+	sync.Mutex
+
+	cfg   ScoreConfig
+	clock clock.Clock
+
+	peers   map[string]*peerScore
+	origins map[string]string
+	queue   []string
+}
+
+type peerScore struct {
+	PeerScore
+	windowStart time.Time
+}
+
+// NewScores creates new empty scores.
+func NewScores(cfg ScoreConfig) *Scores {
+	return &Scores{
+		cfg:     cfg,
+		clock:   clock.NewReal(),
+		peers:   make(map[string]*peerScore),
+		origins: make(map[string]string),
+	}
+}
+
+// Received records a rumor from the address and returns false if the rumor
+// must be dropped because the peer is banned.
+func (s *Scores) Received(addr mino.Address, rumor Rumor) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	key := addrKey(addr)
+	peer := s.get(key)
+	now := s.clock.Now()
+
+	if now.Before(peer.BannedUntil) {
+		promDropped.Inc()
+		return false
+	}
+
+	if now.Sub(peer.windowStart) >= s.cfg.Window {
+		peer.windowStart = now
+		peer.Rumors = 0
+	}
+
+	peer.Rumors++
+
+	if peer.Rumors > s.cfg.MaxRate {
+		s.ban(peer, key, "rate")
+		promDropped.Inc()
+		return false
+	}
+
+	s.remember(string(rumor.GetID()), key)
+
+	return true
+}
+
+// Report updates the score of the peer that sent the rumor. It does nothing if
+// the sender is not known anymore.
+func (s *Scores) Report(rumor Rumor, valid bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	key, found := s.origins[string(rumor.GetID())]
+	if !found {
+		return
+	}
+
+	peer := s.get(key)
+
+	if valid {
+		peer.Valid++
+	} else {
+		peer.Invalid++
+	}
+
+	total := peer.Valid + peer.Invalid
+	ratio := float64(peer.Invalid) / float64(total)
+
+	promInvalidRatio.WithLabelValues(key).Set(ratio)
+
+	if total >= s.cfg.MinReports && ratio > s.cfg.MaxInvalidRatio {
+		s.ban(peer, key, "invalid")
+	}
+}
+
+// Get returns the score of the peer.
+func (s *Scores) Get(addr mino.Address) PeerScore {
+	s.Lock()
+	defer s.Unlock()
+
+	peer, found := s.peers[addrKey(addr)]
+	if !found {
+		return PeerScore{}
+	}
+
+	return peer.PeerScore
+}
+
+func (s *Scores) get(key string) *peerScore {
+	peer, found := s.peers[key]
+	if !found {
+		peer = &peerScore{}
+		s.peers[key] = peer
+	}
+
+	return peer
+}
+
+// ban bans the peer and resets its counters so that it starts with a clean
+// score after the ban.
+func (s *Scores) ban(peer *peerScore, key, reason string) {
+	peer.BannedUntil = s.clock.Now().Add(s.cfg.BanDuration)
+	peer.Valid = 0
+	peer.Invalid = 0
+
+	promBans.Inc()
+	promInvalidRatio.WithLabelValues(key).Set(0)
+
+	dela.Logger.Warn().
+		Str("peer", key).
+		Str("reason", reason).
+		Time("until", peer.BannedUntil).
+		Msg("peer banned")
+}
+
+// remember keeps the sender of a rumor, and forgets the oldest one when the
+// limit is reached.
+func (s *Scores) remember(id, key string) {
+	_, found := s.origins[id]
+	if !found {
+		s.queue = append(s.queue, id)
+	}
+
+	s.origins[id] = key
+
+	if len(s.queue) > maxOrigins {
+		delete(s.origins, s.queue[0])
+		s.queue = s.queue[1:]
+	}
+}
+
+func addrKey(addr mino.Address) string {
+	if addr == nil {
+		return ""
+	}
+
+	return addr.String()
+}
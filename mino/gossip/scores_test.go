@@ -0,0 +1,116 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/serde"
+)
+
+func TestScores_Rate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := fake.NewClock(now)
+
+	scores := NewScores(ScoreConfig{
+		MaxRate:     2,
+		Window:      time.Second,
+		BanDuration: time.Minute,
+	})
+	scores.clock = clock
+
+	alice := fake.NewAddress(0)
+	bob := fake.NewAddress(1)
+
+	require.True(t, scores.Received(alice, idRumor(1)))
+	require.True(t, scores.Received(alice, idRumor(2)))
+	require.True(t, scores.Received(bob, idRumor(3)))
+
+	clock.Advance(time.Second)
+
+	// The window is reset.
+	require.True(t, scores.Received(alice, idRumor(4)))
+	require.True(t, scores.Received(alice, idRumor(5)))
+	require.False(t, scores.Received(alice, idRumor(6)))
+	require.Equal(t, now.Add(time.Second+time.Minute), scores.Get(alice).BannedUntil)
+
+	clock.Advance(time.Second)
+	require.False(t, scores.Received(alice, idRumor(7)))
+	require.True(t, scores.Received(bob, idRumor(8)))
+
+	clock.Advance(time.Minute)
+	require.True(t, scores.Received(alice, idRumor(9)))
+	require.Equal(t, 1, scores.Get(alice).Rumors)
+
+	require.Equal(t, PeerScore{}, scores.Get(fake.NewAddress(2)))
+	require.True(t, scores.Received(nil, idRumor(10)))
+}
+
+func TestScores_Report(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := fake.NewClock(now)
+
+	scores := NewScores(ScoreConfig{
+		MaxRate:         100,
+		Window:          time.Second,
+		MaxInvalidRatio: 0.5,
+		MinReports:      4,
+		BanDuration:     time.Minute,
+	})
+	scores.clock = clock
+
+	alice := fake.NewAddress(0)
+
+	for i := byte(0); i < 4; i++ {
+		require.True(t, scores.Received(alice, idRumor(i)))
+	}
+
+	// Unknown rumors are ignored.
+	scores.Report(idRumor(0xff), false)
+
+	scores.Report(idRumor(0), true)
+	scores.Report(idRumor(1), false)
+	scores.Report(idRumor(2), false)
+	require.Equal(t, 1, scores.Get(alice).Valid)
+	require.Equal(t, 2, scores.Get(alice).Invalid)
+	require.True(t, scores.Get(alice).BannedUntil.IsZero())
+
+	scores.Report(idRumor(3), false)
+	require.Equal(t, now.Add(time.Minute), scores.Get(alice).BannedUntil)
+	require.Zero(t, scores.Get(alice).Invalid)
+
+	require.False(t, scores.Received(alice, idRumor(4)))
+}
+
+func TestScores_Remember(t *testing.T) {
+	scores := NewScores(DefaultScoreConfig)
+
+	for i := 0; i < maxOrigins+1; i++ {
+		scores.remember(string(rune(i)), "alice")
+	}
+
+	require.Len(t, scores.origins, maxOrigins)
+	require.Len(t, scores.queue, maxOrigins)
+
+	_, found := scores.origins[string(rune(0))]
+	require.False(t, found)
+
+	// A rumor received twice is remembered once.
+	scores.remember(string(rune(maxOrigins)), "bob")
+	require.Len(t, scores.queue, maxOrigins)
+	require.Equal(t, "bob", scores.origins[string(rune(maxOrigins))])
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type idRumor byte
+
+func (r idRumor) GetID() []byte {
+	return []byte{byte(r)}
+}
+
+func (r idRumor) Serialize(serde.Context) ([]byte, error) {
+	return nil, nil
+}
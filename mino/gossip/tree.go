@@ -0,0 +1,131 @@
+package gossip
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/broadcast"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// Tree is an implementation of a message passing protocol that spreads a rumor
+// along a tree of the participants, so that a node sends a rumor to at most
+// fanout participants instead of all of them.
+//
+// The peers are not scored, as a rumor is received from the node that
+// forwarded it, which is not necessarily the one that created it.
+//
+// - implements gossip.Gossiper
+type Tree struct {
+	mino         mino.Mino
+	rumorFactory serde.Factory
+	fanout       int
+	ch           chan Rumor
+}
+
+// NewTree creates a new instance of a tree gossip protocol with the given
+// fanout.
+func NewTree(m mino.Mino, f serde.Factory, fanout int) *Tree {
+	return &Tree{
+		mino:         m,
+		rumorFactory: f,
+		fanout:       fanout,
+		ch:           make(chan Rumor, 100),
+	}
+}
+
+// Listen implements gossip.Gossiper. It creates the broadcast and starts to
+// listen for incoming rumors while spreading its own ones.
+func (tree *Tree) Listen() (Actor, error) {
+	h := treeHandler{Tree: tree}
+
+	actor := &treeActor{
+		logger: dela.Logger.With().Str("addr", tree.mino.GetAddress().String()).Logger(),
+		broadcast: broadcast.NewTree(tree.mino, "treegossip", h, tree.rumorFactory,
+			broadcast.WithFanout(tree.fanout)),
+	}
+
+	return actor, nil
+}
+
+// Rumors implements gossip.Gossiper. It returns the channel that is populated
+// with new rumors.
+func (tree *Tree) Rumors() <-chan Rumor {
+	return tree.ch
+}
+
+// treeActor is the actor returned by the tree gossiper that provides the
+// primitives to send a rumor.
+//
+// - implements gossip.Actor
+type treeActor struct {
+	sync.Mutex
+
+	logger    zerolog.Logger
+	broadcast *broadcast.Tree
+	players   mino.Players
+}
+
+// SetPlayers implements gossip.Actor. It changes the set of participants where
+// the rumors will be sent.
+func (a *treeActor) SetPlayers(players mino.Players) {
+	a.Lock()
+	a.players = players
+	a.Unlock()
+}
+
+// Add implements gossip.Actor. It spreads the rumor to the players.
+func (a *treeActor) Add(rumor Rumor) error {
+	a.Lock()
+	players := a.players
+	a.Unlock()
+
+	if players == nil {
+		// Drop rumors if the network is empty.
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rumorTimeout)
+	defer cancel()
+
+	err := a.broadcast.Broadcast(ctx, rumor, players)
+	if err != nil {
+		a.logger.Warn().Err(err).Msg("rumor not sent")
+	}
+
+	return nil
+}
+
+// Close implements gossip.Actor. It stops the gossip actor.
+func (a *treeActor) Close() error {
+	a.Lock()
+	a.players = nil
+	a.Unlock()
+
+	return nil
+}
+
+// treeHandler processes the rumors delivered by the broadcast.
+//
+// - implements mino.Handler
+type treeHandler struct {
+	*Tree
+	mino.UnsupportedHandler
+}
+
+// Process implements mino.Handler. It notifies the new rumor if appropriate and
+// does not return anything.
+func (h treeHandler) Process(req mino.Request) (serde.Message, error) {
+	rumor, ok := req.Message.(Rumor)
+	if !ok {
+		return nil, xerrors.Errorf("unexpected rumor of type '%T'", req.Message)
+	}
+
+	h.ch <- rumor
+
+	return nil, nil
+}
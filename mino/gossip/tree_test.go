@@ -0,0 +1,89 @@
+package gossip
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/minoch"
+)
+
+func TestTree_Scenario(t *testing.T) {
+	manager := minoch.NewManager()
+
+	n := 7
+	gossipers := make([]*Tree, n)
+	actors := make([]Actor, n)
+	addrs := make([]mino.Address, n)
+
+	for i := range gossipers {
+		m := minoch.MustCreate(manager, fmt.Sprintf("node%d", i))
+
+		gossipers[i] = NewTree(m, fakeRumorFactory{}, 2)
+		addrs[i] = m.GetAddress()
+
+		actor, err := gossipers[i].Listen()
+		require.NoError(t, err)
+
+		actors[i] = actor
+	}
+
+	actors[0].SetPlayers(mino.NewAddresses(addrs...))
+
+	err := actors[0].Add(fakeRumor{})
+	require.NoError(t, err)
+
+	for i, gossiper := range gossipers {
+		select {
+		case rumor := <-gossiper.Rumors():
+			require.Equal(t, fakeRumor{}, rumor)
+		case <-time.After(time.Second):
+			t.Fatalf("node %d did not receive the rumor", i)
+		}
+
+		require.Empty(t, gossiper.Rumors())
+	}
+}
+
+func TestTreeActor_Add(t *testing.T) {
+	gossiper := NewTree(minoch.MustCreate(minoch.NewManager(), "node"), fakeRumorFactory{}, 2)
+
+	actor, err := gossiper.Listen()
+	require.NoError(t, err)
+
+	// Drop rumors if the network is empty.
+	err = actor.Add(fakeRumor{})
+	require.NoError(t, err)
+
+	logger := fake.NewLogger()
+	actor.(*treeActor).logger = logger.GetLogger()
+
+	// The player is not known by the manager of the gossiper.
+	other := minoch.MustCreate(minoch.NewManager(), "other")
+	actor.SetPlayers(mino.NewAddresses(other.GetAddress()))
+
+	err = actor.Add(fakeRumor{})
+	require.NoError(t, err)
+	require.True(t, logger.Has(zerolog.WarnLevel, "rumor not sent"))
+
+	require.NoError(t, actor.Close())
+	require.Nil(t, actor.(*treeActor).players)
+}
+
+func TestTreeHandler_Process(t *testing.T) {
+	h := treeHandler{
+		Tree: NewTree(nil, nil, 1),
+	}
+
+	resp, err := h.Process(mino.Request{Message: fakeRumor{}})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+	require.Len(t, h.ch, 1)
+
+	_, err = h.Process(mino.Request{Message: fake.Message{}})
+	require.EqualError(t, err, "unexpected rumor of type 'fake.Message'")
+}
@@ -102,7 +102,7 @@ func ExampleRPC_Stream() {
 	// Output: B Hello World!
 }
 
-func ExampleRPC_OpentracingDemo() {
+func ExampleRPC_opentracingDemo() {
 	N := 20
 	minos := make([]*Minogrpc, N)
 	rpcs := make([]mino.RPC, N)
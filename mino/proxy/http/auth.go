@@ -0,0 +1,222 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const bearerPrefix = "Bearer "
+
+// Option is the type of option to create the proxy.
+type Option func(*options)
+
+type options struct {
+	tokens map[string]string
+	rate   float64
+	burst  int
+	audit  io.Writer
+}
+
+// WithTokens sets the API tokens of the clients allowed to use the proxy,
+// mapped to the name of their client. A request must then present a known
+// token in its "Authorization: Bearer <token>" header.
+func WithTokens(tokens map[string]string) Option {
+	return func(opts *options) {
+		opts.tokens = tokens
+	}
+}
+
+// WithRateLimit limits the number of requests per second of each client, with
+// a burst of requests allowed above the rate. Without tokens, a client is
+// identified by its remote host.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(opts *options) {
+		opts.rate = rate
+		opts.burst = burst
+	}
+}
+
+// WithAuditLog writes an entry for every request of the clients to the
+// writer, whether it is accepted or not.
+func WithAuditLog(out io.Writer) Option {
+	return func(opts *options) {
+		opts.audit = out
+	}
+}
+
+// authenticate is a utility function that rejects the requests without a
+// known token, and adds the name of the client to the context of the others.
+func authenticate(tokens map[string]string, audit zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, ok := identify(tokens, r)
+			if !ok {
+				auditEntry(audit, r, "", http.StatusUnauthorized)
+
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), clientKey, client)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// identify returns the name of the client of the request. Every token is
+// compared in constant time so that the response time does not leak a
+// valid one.
+func identify(tokens map[string]string, r *http.Request) (string, bool) {
+	if len(tokens) == 0 {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return r.RemoteAddr, true
+		}
+
+		return host, true
+	}
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return "", false
+	}
+
+	token := []byte(strings.TrimPrefix(header, bearerPrefix))
+
+	client := ""
+	found := false
+
+	for known, name := range tokens {
+		if subtle.ConstantTimeCompare([]byte(known), token) == 1 {
+			client = name
+			found = true
+		}
+	}
+
+	return client, found
+}
+
+// limiting is a utility function that rejects the requests of a client above
+// its rate.
+func limiting(l *limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, _ := r.Context().Value(clientKey).(string)
+
+			if !l.allow(client) {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// auditing is a utility function that writes an audit entry once a request
+// of an authenticated client is served.
+func auditing(audit zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			client, _ := r.Context().Value(clientKey).(string)
+			auditEntry(audit, r, client, rec.status)
+		})
+	}
+}
+
+func auditEntry(audit zerolog.Logger, r *http.Request, client string, status int) {
+	requestID, ok := r.Context().Value(requestIDKey).(string)
+	if !ok {
+		requestID = "unknown"
+	}
+
+	audit.Log().Str("requestID", requestID).
+		Str("client", client).
+		Str("method", r.Method).
+		Str("url", r.URL.Path).
+		Str("remoteAddr", r.RemoteAddr).
+		Int("status", status).Msg("")
+}
+
+// statusRecorder is a response writer that remembers the status code of the
+// response.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// limiter is a token bucket per client. A bucket is refilled at the rate per
+// second up to the burst, and a request takes a token from it.
+type limiter struct {
+	sync.Mutex
+
+	rate    float64
+	burst   float64
+	now     func() time.Time
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int, now func() time.Time) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		now:     now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (l *limiter) allow(client string) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+
+	b := l.buckets[client]
+	if b == nil {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(l.burst, b.tokens+elapsed*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	out := new(bytes.Buffer)
+	audit := zerolog.New(out)
+
+	tokens := map[string]string{"abc": "alice", "def": "bob"}
+
+	var client string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client = r.Context().Value(clientKey).(string)
+	})
+
+	handler := authenticate(tokens, audit)(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/fake", nil)
+	req.Header.Set("Authorization", "Bearer def")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "bob", client)
+	require.Empty(t, out.String())
+
+	req.Header.Set("Authorization", "Bearer xxx")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+	require.Contains(t, out.String(), `"status":401`)
+
+	req.Header.Set("Authorization", "Basic abc")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAuthenticate_NoTokens(t *testing.T) {
+	var client string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client = r.Context().Value(clientKey).(string)
+	})
+
+	handler := authenticate(nil, zerolog.Nop())(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/fake", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, "10.0.0.1", client)
+
+	req.RemoteAddr = "pipe"
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, "pipe", client)
+}
+
+func TestAuditing(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	handler := authenticate(nil, zerolog.Nop())(auditing(zerolog.New(out))(next))
+
+	req := httptest.NewRequest(http.MethodPost, "/fake", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, `{"requestID":"unknown","client":"10.0.0.1","method":"POST",`+
+		`"url":"/fake","remoteAddr":"10.0.0.1:1234","status":418}`+"\n", out.String())
+}
+
+func TestLimiting(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newLimiter(1, 2, func() time.Time { return now })
+
+	handler := authenticate(nil, zerolog.Nop())(limiting(l)(http.HandlerFunc(fakeHandler)))
+
+	serve := func(addr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/fake", nil)
+		req.RemoteAddr = addr
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("10.0.0.1:1"))
+	require.Equal(t, http.StatusOK, serve("10.0.0.1:2"))
+	require.Equal(t, http.StatusTooManyRequests, serve("10.0.0.1:3"))
+
+	// Another client has its own bucket.
+	require.Equal(t, http.StatusOK, serve("10.0.0.2:1"))
+
+	now = now.Add(time.Second)
+	require.Equal(t, http.StatusOK, serve("10.0.0.1:4"))
+	require.Equal(t, http.StatusTooManyRequests, serve("10.0.0.1:5"))
+}
+
+func TestNewLimiter_MinimumBurst(t *testing.T) {
+	l := newLimiter(1, 0, time.Now)
+	require.Equal(t, 1.0, l.burst)
+}
@@ -1,12 +1,16 @@
 package controller
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/mino/proxy"
 	"go.dedis.ch/dela/mino/proxy/http"
@@ -14,7 +18,7 @@ import (
 )
 
 var defaultRetry = 10
-var proxyFac func(string) proxy.Proxy = http.NewHTTP
+var proxyFac func(string, ...http.Option) proxy.Proxy = http.NewHTTP
 
 type startAction struct{}
 
@@ -24,7 +28,12 @@ func (a startAction) Execute(ctx node.Context) error {
 
 	addr := ctx.Flags.String("clientaddr")
 
-	proxyhttp := proxyFac(addr)
+	opts, err := makeOptions(ctx.Flags)
+	if err != nil {
+		return xerrors.Errorf("failed to configure proxy: %v", err)
+	}
+
+	proxyhttp := proxyFac(addr, opts...)
 
 	ctx.Injector.Inject(proxyhttp)
 
@@ -45,6 +54,77 @@ func (a startAction) Execute(ctx node.Context) error {
 	return nil
 }
 
+// makeOptions returns the options of the proxy set by the flags.
+func makeOptions(flags cli.Flags) ([]http.Option, error) {
+	var opts []http.Option
+
+	if flags.String("tokens") != "" {
+		tokens, err := readTokens(flags.String("tokens"))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read tokens: %v", err)
+		}
+
+		opts = append(opts, http.WithTokens(tokens))
+	}
+
+	if flags.Int("ratelimit") > 0 {
+		opts = append(opts, http.WithRateLimit(float64(flags.Int("ratelimit")),
+			flags.Int("burst")))
+	}
+
+	if flags.String("auditlog") != "" {
+		file, err := os.OpenFile(flags.String("auditlog"),
+			os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to open audit log: %v", err)
+		}
+
+		opts = append(opts, http.WithAuditLog(file))
+	}
+
+	return opts, nil
+}
+
+// readTokens reads the tokens of the clients from a file with one
+// "<client> <token>" pair per line. Empty lines and lines starting with '#'
+// are ignored.
+func readTokens(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to open: %v", err)
+	}
+
+	defer file.Close()
+
+	tokens := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, xerrors.Errorf("invalid line %d", n)
+		}
+
+		tokens[fields[1]] = fields[0]
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to scan: %v", err)
+	}
+
+	if len(tokens) == 0 {
+		return nil, xerrors.Errorf("no token in %s", path)
+	}
+
+	return tokens, nil
+}
+
 type promAction struct{}
 
 // Execute implements node.ActionTemplate. It registers the Prometheus handler.
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net"
 	nhttp "net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -70,6 +72,76 @@ func TestStartAction_Error(t *testing.T) {
 	require.Error(t, err, "failed to start proxy server")
 }
 
+func TestStartAction_BadTokens(t *testing.T) {
+	flags := make(node.FlagSet)
+	flags["tokens"] = "/does/not/exist"
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    flags,
+		Out:      new(bytes.Buffer),
+	}
+
+	action := startAction{}
+	err := action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to configure proxy: failed to read tokens: "+
+		"failed to open: ", err.Error())
+}
+
+func TestMakeOptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tokens")
+
+	err := os.WriteFile(path, []byte("# clients\nalice abc\n\nbob def\n"), 0600)
+	require.NoError(t, err)
+
+	flags := make(node.FlagSet)
+
+	opts, err := makeOptions(flags)
+	require.NoError(t, err)
+	require.Len(t, opts, 0)
+
+	flags["tokens"] = path
+	flags["ratelimit"] = 5
+	flags["burst"] = 10
+	flags["auditlog"] = filepath.Join(dir, "audit.log")
+
+	opts, err = makeOptions(flags)
+	require.NoError(t, err)
+	require.Len(t, opts, 3)
+
+	flags["auditlog"] = dir
+
+	_, err = makeOptions(flags)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to open audit log: ", err.Error())
+}
+
+func TestReadTokens(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tokens")
+
+	err := os.WriteFile(path, []byte("alice abc\nbob def\n"), 0600)
+	require.NoError(t, err)
+
+	tokens, err := readTokens(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"abc": "alice", "def": "bob"}, tokens)
+
+	err = os.WriteFile(path, []byte("alice\n"), 0600)
+	require.NoError(t, err)
+
+	_, err = readTokens(path)
+	require.EqualError(t, err, "invalid line 1")
+
+	err = os.WriteFile(path, []byte("# no token\n"), 0600)
+	require.NoError(t, err)
+
+	_, err = readTokens(path)
+	require.EqualError(t, err, "no token in "+path)
+}
+
 func TestPromAction_Happy(t *testing.T) {
 	out := new(bytes.Buffer)
 	flags := make(node.FlagSet)
@@ -145,7 +217,7 @@ func TestPromAction_ErrorCollector(t *testing.T) {
 // -----------------------------------------------------------------------------
 // Utility functions
 
-func newFake(addr string) proxy.Proxy {
+func newFake(addr string, opts ...http.Option) proxy.Proxy {
 	return &fakeProxy{}
 }
 
@@ -10,6 +10,8 @@ const defaultAddr = "127.0.0.1:8080"
 
 const defaultProm = "/metrics"
 
+const defaultBurst = 10
+
 // NewController returns a new minimal initializer
 func NewController() node.Initializer {
 	return minimal{}
@@ -32,6 +34,24 @@ func (m minimal) SetCommands(builder node.Builder) {
 		Required: false,
 		Usage:    "the address of the http client",
 		Value:    defaultAddr,
+	}, cli.StringFlag{
+		Name:     "tokens",
+		Required: false,
+		Usage: "path to a file of '<client> <token>' lines. If set, the " +
+			"requests must present a token as 'Authorization: Bearer <token>'",
+	}, cli.IntFlag{
+		Name:     "ratelimit",
+		Required: false,
+		Usage:    "the number of requests per second of a client, 0 for no limit",
+	}, cli.IntFlag{
+		Name:     "burst",
+		Required: false,
+		Usage:    "the number of requests a client can send above the rate",
+		Value:    defaultBurst,
+	}, cli.StringFlag{
+		Name:     "auditlog",
+		Required: false,
+		Usage:    "path to a file where the requests of the clients are logged",
 	})
 	sub.SetAction(builder.MakeAction(startAction{}))
 
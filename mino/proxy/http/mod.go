@@ -17,6 +17,7 @@ type key int
 
 const (
 	requestIDKey key = 0
+	clientKey    key = 1
 )
 
 var (
@@ -40,21 +41,40 @@ func setLogLevel() {
 }
 
 // NewHTTP creates a new proxy http
-func NewHTTP(listenAddr string) proxy.Proxy {
+func NewHTTP(listenAddr string, opts ...Option) proxy.Proxy {
+	o := options{}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	logger := dela.Logger.With().Timestamp().Str("role", "http proxy").Logger().
 		Level(defaultLevel)
 
+	audit := zerolog.Nop()
+	if o.audit != nil {
+		audit = zerolog.New(o.audit).With().Timestamp().Logger()
+	}
+
 	nextRequestID := func() string {
 		return fmt.Sprintf("%d", time.Now().UnixNano())
 	}
 
 	mux := http.NewServeMux()
 
+	var handler http.Handler = mux
+
+	if o.rate > 0 {
+		handler = limiting(newLimiter(o.rate, o.burst, time.Now))(handler)
+	}
+
+	handler = authenticate(o.tokens, audit)(auditing(audit)(handler))
+
 	return &HTTP{
 		mux: mux,
 		server: &http.Server{
 			Addr:    listenAddr,
-			Handler: tracing(nextRequestID)(logging(logger)(mux)),
+			Handler: tracing(nextRequestID)(logging(logger)(handler)),
 		},
 		logger:     logger,
 		listenAddr: listenAddr,
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -44,6 +45,36 @@ func TestHTTP_Listen(t *testing.T) {
 	require.Equal(t, "hello", string(output))
 }
 
+func TestHTTP_Options(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	proxy := NewHTTP("", WithTokens(map[string]string{"abc": "alice"}),
+		WithRateLimit(1, 1), WithAuditLog(out))
+
+	proxy.RegisterHandler("/fake", fakeHandler)
+
+	handler := proxy.(*HTTP).server.Handler
+
+	req := httptest.NewRequest(http.MethodGet, "/fake", nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req.Header.Set("Authorization", "Bearer abc")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, "hello", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	require.Contains(t, out.String(), `"client":"alice"`)
+	require.Equal(t, 3, bytes.Count(out.Bytes(), []byte("\n")))
+}
+
 func TestHTPP_Listen_EmptyAddr(t *testing.T) {
 	// in this case it will use a random free port
 	proxy := NewHTTP("")
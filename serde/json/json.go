@@ -10,8 +10,11 @@ import (
 	// that an import of the JSON context engine will import the definitions.
 	_ "go.dedis.ch/dela/core/access/darc/json"
 	_ "go.dedis.ch/dela/core/ordering/cosipbft/authority/json"
+	_ "go.dedis.ch/dela/core/ordering/cosipbft/discovery/json"
+	_ "go.dedis.ch/dela/core/ordering/cosipbft/enrollment/json"
 	_ "go.dedis.ch/dela/core/ordering/cosipbft/blocksync/json"
 	_ "go.dedis.ch/dela/core/ordering/cosipbft/json"
+	_ "go.dedis.ch/dela/core/store/blob/json"
 	_ "go.dedis.ch/dela/core/txn/signed/json"
 	_ "go.dedis.ch/dela/core/validation/simple/json"
 	_ "go.dedis.ch/dela/cosi/json"
@@ -19,6 +22,7 @@ import (
 	_ "go.dedis.ch/dela/crypto/bls/json"
 	_ "go.dedis.ch/dela/crypto/ed25519/json"
 	_ "go.dedis.ch/dela/dkg/pedersen_bn256/json"
+	_ "go.dedis.ch/dela/mino/broadcast/json"
 	_ "go.dedis.ch/dela/mino/router/tree/json"
 	"go.dedis.ch/dela/serde"
 )
@@ -149,7 +149,8 @@ func newDelaNode(t require.TestingT, path string, port int) dela {
 	err = tree.Load()
 	require.NoError(t, err)
 
-	genstore := blockstore.NewGenesisDiskStore(db, types.NewGenesisFactory(rosterFac))
+	genstore := blockstore.NewGenesisDiskStore(db, types.NewGenesisFactory(rosterFac,
+		cosi.GetPublicKeyFactory()))
 
 	err = genstore.Load()
 	require.NoError(t, err)
@@ -215,7 +216,7 @@ func (c cosiDelaNode) Setup(delas ...dela) {
 
 	type extendedService interface {
 		GetRoster() (authority.Authority, error)
-		Setup(ctx context.Context, ca crypto.CollectiveAuthority) error
+		Setup(ctx context.Context, ca crypto.CollectiveAuthority, opts ...types.GenesisOption) error
 	}
 
 	// make roster